@@ -2,65 +2,344 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fullcycle-auction_go/configuration/config"
 	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/infra/api/grpc_server"
 	"fullcycle-auction_go/internal/infra/api/web/controller/auction_controller"
+	"fullcycle-auction_go/internal/infra/api/web/controller/auth_controller"
 	"fullcycle-auction_go/internal/infra/api/web/controller/bid_controller"
+	"fullcycle-auction_go/internal/infra/api/web/controller/health_controller"
+	"fullcycle-auction_go/internal/infra/api/web/controller/live_feed_controller"
 	"fullcycle-auction_go/internal/infra/api/web/controller/user_controller"
+	"fullcycle-auction_go/internal/infra/api/web/live_feed"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/infra/auth"
 	"fullcycle-auction_go/internal/infra/database/auction"
 	"fullcycle-auction_go/internal/infra/database/bid"
 	"fullcycle-auction_go/internal/infra/database/user"
+	"fullcycle-auction_go/internal/infra/event"
+	"fullcycle-auction_go/internal/infra/notification"
 	"fullcycle-auction_go/internal/usecase/auction_usecase"
 	"fullcycle-auction_go/internal/usecase/bid_usecase"
 	"fullcycle-auction_go/internal/usecase/user_usecase"
-	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"google.golang.org/grpc"
 	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
+const envFile = "cmd/auction/.env"
+
 func main() {
-	ctx := context.Background()
+	// A signal during startup, while waiting for mongodb for instance, cancels
+	// ctx so the process exits at once.
+	ctx, stopStartup := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopStartup()
+
+	cfg, err := config.Load(envFile)
+	if err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+
+	if err := logger.SetLevel(cfg.LogLevel); err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+	logger.Info("Starting with " + cfg.String())
+	auction.SetAuctionInterval(cfg.Auction.Interval)
+
+	databaseConnection, err := mongodb.NewMongoDBConnection(ctx, cfg.Mongo)
+	if err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+
+	queryReadPreference, err := mongodb.NewReadPreference(cfg.Mongo.ReadPreference)
+	if err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+
+	tokenService, err := auth.NewTokenService(cfg.Auth)
+	if err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+
+	router, err := newRouter(cfg.Server.TrustedProxies)
+	if err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+	router.Use(middleware.RequestId(), middleware.HandleErrors(), middleware.Authenticate(tokenService),
+		middleware.Compress(cfg.Server.CompressionMinSize))
+
+	userRepository := user.NewUserRepository(databaseConnection)
+	cachedUserRepository := user.CacheUserLookups(userRepository, cfg.UserCache)
+
+	liveFeedHub := live_feed.NewHub(
+		live_feed.WithBufferSize(cfg.LiveFeed.BufferSize),
+		live_feed.WithHistory(cfg.LiveFeed.HistorySize, cfg.LiveFeed.HistoryRetention))
+
+	userController, authController, bidController, auctionsController, liveFeedController, auctionRepository,
+		bidRepository, userUseCase, bidUseCase, notificationDispatcher, grpcServer := initDependencies(
+		databaseConnection, queryReadPreference, cachedUserRepository, liveFeedHub, tokenService, cfg)
+	router.Use(middleware.LoadUser(cachedUserRepository))
+
+	if err := ensureIndexes(ctx, auctionRepository, bidRepository, userRepository); err != nil {
+		if cfg.Server.IndexCreationFailOnError {
+			log.Fatal(err.Error())
+			return
+		}
+
+		log.Println("WARNING: index creation failed, queries may scan whole collections:", err.Error())
+	}
+
+	if cfg.Admin.Email != "" {
+		if err := userUseCase.SeedAdmin(ctx, cfg.Admin.Email, cfg.Admin.Password); err != nil {
+			log.Println("WARNING: could not seed the initial admin:", err.Error())
+		}
+	}
+
+	if err := migrateAmounts(ctx, auctionRepository, bidRepository); err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+
+	if err := auctionRepository.BackfillEndTimes(ctx); err != nil {
+		log.Fatal(err.Error())
+		return
+	}
 
-	if err := godotenv.Load("cmd/auction/.env"); err != nil {
-		log.Fatal("Error trying to load env variables")
+	if err := bidRepository.MigrateTimestampsToMillis(ctx); err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+
+	if err := auctionRepository.StartAutoCloseRecovery(ctx); err != nil {
+		log.Fatal(err.Error())
 		return
 	}
+	auctionRepository.StartChangeStreamSync()
+
+	healthController := health_controller.NewHealthController(
+		databaseConnection.Client(), auctionRepository, cfg.Server.ReadinessPingTimeout)
+	registerRoutes(router, newRateLimits(cfg.RateLimit), healthController, userController, authController, bidController, auctionsController, liveFeedController)
+
+	server := &http.Server{
+		Addr:    ":" + cfg.Server.HTTPPort,
+		Handler: router,
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err.Error())
+		}
+	}()
 
-	databaseConnection, err := mongodb.NewMongoDBConnection(ctx)
+	grpcListener, err := net.Listen("tcp", ":"+cfg.Server.GRPCPort)
 	if err != nil {
 		log.Fatal(err.Error())
 		return
 	}
 
-	router := gin.Default()
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			log.Fatal(err.Error())
+		}
+	}()
 
-	userController, bidController, auctionsController := initDependencies(databaseConnection)
+	go reloadConfigurationOnHangup()
 
-	router.GET("/auction", auctionsController.FindAuctions)
-	router.GET("/auction/:auctionId", auctionsController.FindAuctionById)
-	router.POST("/auction", auctionsController.CreateAuction)
-	router.GET("/auction/winner/:auctionId", auctionsController.FindWinningBidByAuctionId)
-	router.POST("/bid", bidController.CreateBid)
-	router.GET("/bid/:auctionId", bidController.FindBidByAuctionId)
-	router.GET("/user/:userId", userController.FindUserById)
+	stopStartup()
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
 
-	router.Run(":8080")
+	healthController.StartDraining()
+	// Drain long enough for the load balancer to see /readyz fail and stop
+	// routing here.
+	time.Sleep(cfg.Server.ShutdownDrainDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Ending the live feeds first lets their streaming handlers return, which
+	// Shutdown waits for.
+	liveFeedHub.Close()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println("Error trying to shutdown http server:", err.Error())
+	}
+
+	if err := stopGrpcServer(shutdownCtx, grpcServer); err != nil {
+		log.Println("Error trying to shutdown grpc server:", err.Error())
+	}
+
+	if err := bidUseCase.Close(shutdownCtx); err != nil {
+		log.Println("Error trying to flush pending bids:", err.Error())
+	}
+
+	if err := auctionRepository.Shutdown(shutdownCtx); err != nil {
+		log.Println("Error trying to shutdown auction auto-close:", err.Error())
+	}
+
+	if err := notificationDispatcher.Close(shutdownCtx); err != nil {
+		log.Println("Error trying to deliver pending notifications:", err.Error())
+	}
 }
 
-func initDependencies(database *mongo.Database) (
+func initDependencies(
+	database *mongo.Database,
+	queryReadPreference *readpref.ReadPref,
+	userRepository user_entity.UserRepositoryInterface,
+	liveFeedHub *live_feed.Hub,
+	tokenService *auth.TokenService,
+	cfg *config.Config) (
 	userController *user_controller.UserController,
+	authController *auth_controller.AuthController,
 	bidController *bid_controller.BidController,
-	auctionController *auction_controller.AuctionController) {
+	auctionController *auction_controller.AuctionController,
+	liveFeedController *live_feed_controller.LiveFeedController,
+	auctionRepository *auction.AuctionRepository,
+	bidRepository *bid.BidRepository,
+	userUseCase user_usecase.UserUseCaseInterface,
+	bidUseCase bid_usecase.BidUseCaseInterface,
+	notificationDispatcher *notification.NotificationDispatcher,
+	grpcServer *grpc.Server) {
+
+	auctionRepositoryOptions := []auction.AuctionRepositoryOption{
+		auction.WithQueryReadPreference(queryReadPreference),
+		auction.WithCloseStrategy(cfg.Close.Strategy, cfg.Close.SweepInterval),
+		auction.WithCloseWorkers(cfg.Close.Workers, cfg.Close.QueueSize),
+		auction.WithCloseMaxAttempts(cfg.Close.MaxAttempts),
+		auction.WithStatsTimeout(cfg.Auction.StatsTimeout),
+		auction.WithStreamBatchSize(cfg.Auction.StreamBatchSize),
+	}
+	if cfg.Close.LeaderElectionEnabled {
+		auctionRepositoryOptions = append(auctionRepositoryOptions, auction.WithLeaderElection(cfg.Close.LeaderLeaseTTL))
+	}
+	if cfg.Auction.ChangeStreamEnabled {
+		auctionRepositoryOptions = append(auctionRepositoryOptions, auction.WithChangeStreamSync())
+	}
+	auctionRepository = auction.NewAuctionRepository(database, auctionRepositoryOptions...)
+	bidRepository = bid.NewBidRepository(database, auctionRepository,
+		bid.WithIdempotencyKeyTTL(cfg.Bid.IdempotencyKeyTTL),
+		bid.WithStreamBatchSize(cfg.Bid.StreamBatchSize))
+
+	userUseCase = user_usecase.NewUserUseCase(userRepository)
+	auctionRepository.RegisterCloseListener(userUseCase.OnAuctionClosed)
+
+	userController = user_controller.NewUserController(userUseCase)
+	authController = auth_controller.NewAuthController(userUseCase, tokenService)
+	channelPublisher := event.NewChannelPublisher(event.WithBufferSize(cfg.Notification.EventBufferSize))
+	notificationDispatcher = notification.NewNotificationDispatcher(
+		userRepository, cfg.Notification, notification.NewLogChannel())
+	go notificationDispatcher.Run(context.Background(), channelPublisher.Events())
+	eventPublisher := event.NewInstrumentedPublisher(event.NewFanOutPublisher(channelPublisher, liveFeedHub))
+	instrumentedAuctionRepository := auction.InstrumentAuctionRepository(auctionRepository)
+	instrumentedBidRepository := bid.NewInstrumentedBidRepository(bidRepository)
 
-	auctionRepository := auction.NewAuctionRepository(database)
-	bidRepository := bid.NewBidRepository(database, auctionRepository)
-	userRepository := user.NewUserRepository(database)
+	auctionUseCase := auction_usecase.NewAuctionUseCase(
+		instrumentedAuctionRepository, instrumentedBidRepository, userRepository, eventPublisher,
+		auction_usecase.WithDurationLimits(cfg.Auction.MinDuration, cfg.Auction.MaxDuration),
+		auction_usecase.WithMaxExtension(cfg.Auction.MaxExtension),
+		auction_usecase.WithMaxBulkAuctions(cfg.Auction.BulkMaxItems),
+		auction_usecase.WithVersionConflictMaxAttempts(cfg.Auction.VersionConflictMaxAttempts),
+		auction_usecase.WithWatchlistTiming(cfg.Watchlist.EndingWindow, cfg.Watchlist.NotifyInterval),
+		auction_usecase.WithEventRelayInterval(cfg.Auction.EventRelayInterval))
+	auctionRepository.RegisterCloseListener(auctionUseCase.OnAuctionClosed)
+	go auctionUseCase.RunWatchlistNotifier(context.Background())
+	go auctionUseCase.RunEventRelay(context.Background())
 
-	userController = user_controller.NewUserController(
-		user_usecase.NewUserUseCase(userRepository))
-	auctionController = auction_controller.NewAuctionController(
-		auction_usecase.NewAuctionUseCase(auctionRepository, bidRepository))
-	bidController = bid_controller.NewBidController(bid_usecase.NewBidUseCase(bidRepository))
+	auctionController = auction_controller.NewAuctionController(auctionUseCase)
+	liveFeedController = live_feed_controller.NewLiveFeedController(auctionUseCase, liveFeedHub,
+		live_feed_controller.WithWriteTimeout(cfg.LiveFeed.WriteTimeout),
+		live_feed_controller.WithStreamLimits(cfg.LiveFeed.SSEMaxStreamsPerAuction, cfg.LiveFeed.SSEHeartbeatInterval))
+	bidUseCase = bid_usecase.NewInstrumentedBidUseCase(bid_usecase.NewBidUseCase(
+		instrumentedBidRepository, instrumentedAuctionRepository, userRepository, eventPublisher,
+		bid_usecase.WithBatching(cfg.Bid.MaxBatchSize, cfg.Bid.BatchInsertInterval),
+		bid_usecase.WithMinIncrement(cfg.Bid.MinIncrement),
+		bid_usecase.WithSnipeExtension(cfg.Auction.SnipeWindow, cfg.Auction.SnipeExtension),
+		bid_usecase.WithRateLimiter(cfg.Bid.RateLimit.NewLimiter()),
+		bid_usecase.WithBulkLimits(cfg.Bid.BulkMaxItems, cfg.Bid.BulkTimeout),
+		bid_usecase.WithRetractionWindow(cfg.Bid.RetractionWindow)))
+	bidController = bid_controller.NewBidController(bidUseCase)
+	userUseCase.RegisterBanListener(bidUseCase.OnUserBanned)
+	auctionUseCase.RegisterBidFlusher(bidUseCase.Flush)
+
+	grpcServer = grpc_server.NewServer(auctionUseCase, bidUseCase, liveFeedHub, tokenService)
 
 	return
 }
+
+// stopGrpcServer lets the calls in flight finish, and cuts them off once ctx
+// is done.
+func stopGrpcServer(ctx context.Context, grpcServer *grpc.Server) error {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		grpcServer.Stop()
+		return ctx.Err()
+	}
+}
+
+func ensureIndexes(
+	ctx context.Context,
+	auctionRepository *auction.AuctionRepository,
+	bidRepository *bid.BidRepository,
+	userRepository *user.UserRepository) error {
+	indexCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if err := auctionRepository.EnsureIndexes(indexCtx); err != nil {
+		return err
+	}
+
+	if err := bidRepository.EnsureIndexes(indexCtx); err != nil {
+		return err
+	}
+
+	return userRepository.EnsureIndexes(indexCtx)
+}
+
+func migrateAmounts(
+	ctx context.Context,
+	auctionRepository *auction.AuctionRepository,
+	bidRepository *bid.BidRepository) error {
+	if err := auctionRepository.MigrateAmountsToDecimal(ctx); err != nil {
+		return err
+	}
+
+	return bidRepository.MigrateAmountsToDecimal(ctx)
+}
+
+func reloadConfigurationOnHangup() {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	for range hangup {
+		cfg, err := config.Load(envFile)
+		if err != nil {
+			log.Println("Error trying to reload configuration:", err.Error())
+			continue
+		}
+		auction.SetAuctionInterval(cfg.Auction.Interval)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fullcycle-auction_go/configuration/config"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/infra/api/web/controller/auction_controller"
+	"fullcycle-auction_go/internal/infra/api/web/controller/auth_controller"
+	"fullcycle-auction_go/internal/infra/api/web/controller/bid_controller"
+	"fullcycle-auction_go/internal/infra/api/web/controller/health_controller"
+	"fullcycle-auction_go/internal/infra/api/web/controller/live_feed_controller"
+	"fullcycle-auction_go/internal/infra/api/web/controller/user_controller"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/infra/api/web/openapi"
+	"fullcycle-auction_go/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimits are the policies attached to the routes: reads are cheap,
+// writes are not, and logins are kept slow to make guessing passwords costly.
+type rateLimits struct {
+	read, write, login *middleware.RateLimitPolicy
+}
+
+func newRateLimits(cfg config.RateLimitConfig) rateLimits {
+	return rateLimits{
+		read:  middleware.NewRateLimitPolicy("read", cfg.Read.NewLimiter()),
+		write: middleware.NewRateLimitPolicy("write", cfg.Write.NewLimiter()),
+		login: middleware.NewRateLimitPolicy("login", cfg.Login.NewLimiter()),
+	}
+}
+
+const apiV1Prefix = "/api/v1"
+
+// newRouter only believes the X-Forwarded-For header of trustedProxies, so a
+// client cannot pick its own IP and with it a fresh rate limit bucket.
+func newRouter(trustedProxies []string) (*gin.Engine, error) {
+	router := gin.Default()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+
+	return router, nil
+}
+
+// registerRoutes is kept apart from main so the OpenAPI document can be
+// checked against the real routes. The API is served under /api/v1 and, until
+// clients move, under its old unprefixed paths marked as deprecated. Probes,
+// metrics and the docs stay unversioned.
+func registerRoutes(
+	router gin.IRouter,
+	limits rateLimits,
+	healthController *health_controller.HealthController,
+	userController *user_controller.UserController,
+	authController *auth_controller.AuthController,
+	bidController *bid_controller.BidController,
+	auctionsController *auction_controller.AuctionController,
+	liveFeedController *live_feed_controller.LiveFeedController) {
+	router.GET("/healthz", healthController.Healthz)
+	router.GET("/readyz", healthController.Readyz)
+	router.GET("/metrics", gin.WrapH(metrics.Default))
+	router.GET("/swagger/*any", middleware.RateLimit(limits.read), openapi.Handler(openapi.NewDocument()))
+
+	api := apiRoutes{
+		limits:             limits,
+		userController:     userController,
+		authController:     authController,
+		bidController:      bidController,
+		auctionsController: auctionsController,
+		liveFeedController: liveFeedController,
+	}
+	api.register(router.Group(apiV1Prefix))
+	api.register(router.Group("", middleware.Deprecated(apiV1Prefix)))
+}
+
+type apiRoutes struct {
+	limits             rateLimits
+	userController     *user_controller.UserController
+	authController     *auth_controller.AuthController
+	bidController      *bid_controller.BidController
+	auctionsController *auction_controller.AuctionController
+	liveFeedController *live_feed_controller.LiveFeedController
+}
+
+// register declares the groups once, each with its middleware in the order
+// it runs: the rate limit first, then authentication, then the role.
+func (a apiRoutes) register(api *gin.RouterGroup) {
+	reads := api.Group("", middleware.RateLimit(a.limits.read))
+	writes := api.Group("", middleware.RateLimit(a.limits.write))
+	userReads := reads.Group("", middleware.RequireUser())
+	userWrites := writes.Group("", middleware.RequireUser())
+	adminReads := userReads.Group("", middleware.RequireRole(user_entity.AdminRole))
+	adminWrites := userWrites.Group("", middleware.RequireRole(user_entity.AdminRole))
+	logins := api.Group("/auth", middleware.RateLimit(a.limits.login))
+
+	logins.POST("/login", a.authController.Login)
+	logins.POST("/refresh", a.authController.Refresh)
+
+	reads.GET("/auction", a.auctionsController.FindAuctions)
+	reads.GET("/auction/ending-soon", a.auctionsController.FindAuctionsExpiringSoon)
+	reads.GET("/auction/:auctionId", a.auctionsController.FindAuctionById)
+	reads.GET("/auction/winner/:auctionId", a.auctionsController.FindWinningBidByAuctionId)
+	reads.GET("/auction/:auctionId/winner", a.auctionsController.FindAuctionWinner)
+	userReads.GET("/auction/:auctionId/bids/export", a.auctionsController.ExportBids)
+	userWrites.POST("/auction", a.auctionsController.CreateAuction)
+	userWrites.POST("/auction/bulk", a.auctionsController.CreateAuctions)
+	userWrites.POST("/auction/:auctionId/close", a.auctionsController.CloseAuction)
+	userWrites.DELETE("/auction/:auctionId", a.auctionsController.CancelAuction)
+	userWrites.POST("/auction/:auctionId/rating", a.auctionsController.RateSeller)
+	userWrites.PATCH("/auction/:auctionId", a.auctionsController.UpdateAuction)
+	userWrites.PATCH("/auction/:auctionId/extend", a.auctionsController.ExtendAuction)
+
+	reads.GET("/auction/:auctionId/events", a.liveFeedController.StreamAuctionEvents)
+	reads.GET("/ws/auction/:auctionId", a.liveFeedController.FollowAuction)
+
+	reads.GET("/bid/:auctionId", a.bidController.FindBidByAuctionId)
+	userWrites.POST("/bid", a.bidController.CreateBid)
+	userWrites.POST("/bid/bulk", middleware.RequireRole(user_entity.PartnerRole, user_entity.AdminRole),
+		a.bidController.CreateBids)
+	userWrites.DELETE("/bid/:bidId", a.bidController.RetractBid)
+
+	reads.GET("/user/:userId", a.userController.FindUserById)
+	writes.POST("/user", a.userController.CreateUser)
+	userWrites.PATCH("/user/me", a.userController.UpdateMe)
+	userReads.GET("/user/me/auctions", a.auctionsController.FindMyAuctions)
+	userReads.GET("/user/me/bids", a.bidController.FindMyBids)
+	userReads.GET("/user/me/wallet/transactions", a.userController.FindMyWalletTransactions)
+	userReads.GET("/user/me/watchlist", a.auctionsController.FindWatchedAuctions)
+	userWrites.POST("/user/me/watchlist/:auctionId", a.auctionsController.AddToWatchlist)
+	userWrites.DELETE("/user/me/watchlist/:auctionId", a.auctionsController.RemoveFromWatchlist)
+
+	adminReads.GET("/auction/stats", a.auctionsController.GetAuctionStats)
+	adminWrites.POST("/admin/auction/bid-count/reconcile", a.auctionsController.ReconcileBidCounts)
+	adminWrites.POST("/admin/auction/:auctionId/force-close", a.auctionsController.ForceCloseAuction)
+	adminWrites.POST("/auction/:auctionId/delete", a.auctionsController.DeleteAuction)
+	adminWrites.POST("/auction/:auctionId/pause", a.auctionsController.PauseAuction)
+	adminWrites.POST("/auction/:auctionId/resume", a.auctionsController.ResumeAuction)
+	adminWrites.POST("/admin/user/:userId/wallet/credit", a.userController.CreditWallet)
+	adminWrites.POST("/admin/user/:userId/wallet/debit", a.userController.DebitWallet)
+	adminWrites.PUT("/admin/user/:userId/role", a.userController.SetUserRole)
+	adminWrites.POST("/user/:userId/suspend", a.userController.SuspendUser)
+}
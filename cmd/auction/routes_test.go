@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fullcycle-auction_go/internal/infra/api/web/controller/auction_controller"
+	"fullcycle-auction_go/internal/infra/api/web/controller/auth_controller"
+	"fullcycle-auction_go/internal/infra/api/web/controller/bid_controller"
+	"fullcycle-auction_go/internal/infra/api/web/controller/health_controller"
+	"fullcycle-auction_go/internal/infra/api/web/controller/live_feed_controller"
+	"fullcycle-auction_go/internal/infra/api/web/controller/user_controller"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/infra/api/web/openapi"
+	"fullcycle-auction_go/internal/ratelimit"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var unversionedPaths = []string{"/healthz", "/readyz", "/metrics", "/swagger/*any"}
+
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	registerRoutes(router,
+		rateLimits{},
+		health_controller.NewHealthController(nil, nil, 0),
+		user_controller.NewUserController(nil),
+		auth_controller.NewAuthController(nil, nil),
+		bid_controller.NewBidController(nil),
+		auction_controller.NewAuctionController(nil),
+		live_feed_controller.NewLiveFeedController(nil, nil))
+
+	return router
+}
+
+func isDeprecatedAlias(path string) bool {
+	return !strings.HasPrefix(path, apiV1Prefix+"/") && !containsPath(unversionedPaths, path)
+}
+
+func containsPath(paths []string, path string) bool {
+	for _, candidate := range paths {
+		if candidate == path {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestEveryV1RouteHasADeprecatedAliasAndViceVersa(t *testing.T) {
+	versioned := make(map[string]bool)
+	aliases := make(map[string]bool)
+	for _, route := range newTestRouter().Routes() {
+		switch {
+		case strings.HasPrefix(route.Path, apiV1Prefix+"/"):
+			versioned[route.Method+" "+strings.TrimPrefix(route.Path, apiV1Prefix)] = true
+		case isDeprecatedAlias(route.Path):
+			aliases[route.Method+" "+route.Path] = true
+		}
+	}
+
+	assert.NotEmpty(t, versioned)
+	for key := range versioned {
+		assert.True(t, aliases[key], "%s has no deprecated alias", key)
+	}
+	for key := range aliases {
+		assert.True(t, versioned[key], "the alias %s has no %s route", key, apiV1Prefix)
+	}
+}
+
+func TestAuctionManagementRoutesRequireAuthentication(t *testing.T) {
+	router := newTestRouter()
+	auctionPath := apiV1Prefix + "/auction/4b1d7c3e-8f1a-4c55-9d7e-2f6b0f4f7a10"
+
+	for _, route := range []struct{ method, path string }{
+		{http.MethodPatch, auctionPath},
+		{http.MethodPatch, auctionPath + "/extend"},
+		{http.MethodPost, auctionPath + "/delete"},
+		{http.MethodPost, auctionPath + "/pause"},
+		{http.MethodPost, auctionPath + "/resume"},
+	} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(route.method, route.path, strings.NewReader("{}")))
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, "%s %s", route.method, route.path)
+	}
+}
+
+func TestOpenAPIDocumentMatchesRoutes(t *testing.T) {
+	router := newTestRouter()
+
+	documented := make(map[string]*openapi.Operation)
+	for path, item := range openapi.NewDocument().Paths {
+		for method, operation := range item {
+			documented[strings.ToUpper(method)+" "+path] = operation
+		}
+	}
+
+	for _, route := range router.Routes() {
+		if strings.HasPrefix(route.Path, "/swagger/") || isDeprecatedAlias(route.Path) {
+			continue
+		}
+
+		key := route.Method + " " + openapi.PathFromGin(route.Path)
+		operation, ok := documented[key]
+		if !assert.True(t, ok, "route %s is missing from the OpenAPI document", key) {
+			continue
+		}
+		delete(documented, key)
+
+		for _, segment := range strings.Split(route.Path, "/") {
+			if strings.HasPrefix(segment, ":") {
+				assert.True(t, hasPathParameter(operation, segment[1:]),
+					"route %s does not document the %s parameter", key, segment[1:])
+			}
+		}
+	}
+
+	for key := range documented {
+		t.Errorf("the OpenAPI document describes %s, which is not a route", key)
+	}
+}
+
+func hasPathParameter(operation *openapi.Operation, name string) bool {
+	for _, parameter := range operation.Parameters {
+		if parameter.In == "path" && parameter.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestSpoofedForwardedForDoesNotGetAFreshRateLimitBucket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	newLimitedRouter := func(policyName string, trustedProxies []string) *gin.Engine {
+		router, err := newRouter(trustedProxies)
+		assert.NoError(t, err)
+
+		policy := middleware.NewRateLimitPolicy(policyName,
+			ratelimit.NewInMemoryLimiter(ratelimit.Rate{Events: 1, Per: time.Minute}, 1, fakeclock.New(time.Now())))
+		router.GET("/limited", middleware.RateLimit(policy), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return router
+	}
+	request := func(router *gin.Engine, forwardedFor string) int {
+		recorder := httptest.NewRecorder()
+		httpRequest := httptest.NewRequest(http.MethodGet, "/limited", nil)
+		httpRequest.RemoteAddr = "10.0.0.1:1234"
+		httpRequest.Header.Set("X-Forwarded-For", forwardedFor)
+		router.ServeHTTP(recorder, httpRequest)
+		return recorder.Code
+	}
+
+	router := newLimitedRouter("test_direct", nil)
+	assert.Equal(t, http.StatusOK, request(router, "203.0.113.1"))
+	assert.Equal(t, http.StatusTooManyRequests, request(router, "203.0.113.2"))
+
+	behindProxy := newLimitedRouter("test_behind_proxy", []string{"10.0.0.1"})
+	assert.Equal(t, http.StatusOK, request(behindProxy, "203.0.113.1"))
+	assert.Equal(t, http.StatusOK, request(behindProxy, "203.0.113.2"))
+	assert.Equal(t, http.StatusTooManyRequests, request(behindProxy, "203.0.113.1"))
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"time"
+)
+
+const (
+	defaultAuctionInterval    = 5 * time.Minute
+	defaultMinAuctionInterval = 10 * time.Second
+	defaultMaxAuctionInterval = 30 * 24 * time.Hour
+)
+
+func (l *loader) auctionInterval() time.Duration {
+	minInterval := l.duration("AUCTION_MIN_INTERVAL", defaultMinAuctionInterval, 0)
+	maxInterval := l.duration("AUCTION_MAX_INTERVAL", defaultMaxAuctionInterval, 0)
+	interval := l.duration("AUCTION_INTERVAL", defaultAuctionInterval, 0)
+
+	if interval < minInterval || interval > maxInterval {
+		_, source := l.lookup("AUCTION_INTERVAL")
+		l.invalid("%s must be between %s and %s, got %s", source, minInterval, maxInterval, interval)
+		return defaultAuctionInterval
+	}
+
+	return interval
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLoadValidatesTheAuctionInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{name: "default", value: "", expected: 5 * time.Minute},
+		{name: "valid", value: "20s", expected: 20 * time.Second},
+		{name: "typo", value: "5minutes", wantErr: true},
+		{name: "below minimum", value: "1ns", wantErr: true},
+		{name: "above maximum", value: "8760h", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setValidEnv(t)
+			t.Setenv("AUCTION_INTERVAL", tt.value)
+
+			cfg, err := Load()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.Auction.Interval)
+		})
+	}
+}
@@ -0,0 +1,464 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/internal/money"
+	"fullcycle-auction_go/internal/ratelimit"
+	"github.com/joho/godotenv"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const redacted = "[redacted]"
+
+// Config is the configuration read once at startup. Components get their
+// settings from it through their constructors and never read the environment
+// themselves.
+type Config struct {
+	Mongo        MongoConfig
+	Auction      AuctionConfig
+	Close        CloseConfig
+	Bid          BidConfig
+	UserCache    UserCacheConfig
+	LiveFeed     LiveFeedConfig
+	Notification NotificationConfig
+	Watchlist    WatchlistConfig
+	RateLimit    RateLimitConfig
+	Server       ServerConfig
+	Auth         AuthConfig
+	Admin        AdminConfig
+
+	LogLevel string
+}
+
+type MongoConfig struct {
+	URL            string
+	Database       string
+	ReadPreference string
+
+	MaxPoolSize            uint64
+	MinPoolSize            uint64
+	ConnectTimeout         time.Duration
+	ServerSelectionTimeout time.Duration
+	// SocketTimeout bounds every read and write on a connection; zero waits
+	// for as long as the operation takes.
+	SocketTimeout time.Duration
+	// StartupTimeout is how long startup keeps retrying to reach MongoDB.
+	StartupTimeout time.Duration
+}
+
+type AuctionConfig struct {
+	Interval time.Duration
+
+	MinDuration  time.Duration
+	MaxDuration  time.Duration
+	MaxExtension time.Duration
+	// SnipeWindow is how close to the end a bid extends the auction, by
+	// SnipeExtension.
+	SnipeWindow    time.Duration
+	SnipeExtension time.Duration
+
+	BulkMaxItems               int
+	VersionConflictMaxAttempts int
+	EventRelayInterval         time.Duration
+	StatsTimeout               time.Duration
+	StreamBatchSize            int
+	ChangeStreamEnabled        bool
+}
+
+// CloseConfig tunes how expired auctions are closed.
+type CloseConfig struct {
+	Strategy      string
+	SweepInterval time.Duration
+	Workers       int
+	QueueSize     int
+	MaxAttempts   int
+
+	LeaderElectionEnabled bool
+	LeaderLeaseTTL        time.Duration
+}
+
+type BidConfig struct {
+	MaxBatchSize        int
+	BatchInsertInterval time.Duration
+
+	// MinIncrement applies to auctions that set none of their own.
+	MinIncrement      money.Amount
+	RateLimit         ratelimit.Policy
+	BulkMaxItems      int
+	BulkTimeout       time.Duration
+	RetractionWindow  time.Duration
+	IdempotencyKeyTTL time.Duration
+	StreamBatchSize   int
+}
+
+// UserCacheConfig caches user lookups in memory when Enabled.
+type UserCacheConfig struct {
+	Enabled bool
+	Size    int
+	TTL     time.Duration
+}
+
+// LiveFeedConfig tunes the WebSocket and SSE feeds of the auctions.
+type LiveFeedConfig struct {
+	BufferSize       int
+	HistorySize      int
+	HistoryRetention time.Duration
+	WriteTimeout     time.Duration
+
+	SSEMaxStreamsPerAuction int
+	SSEHeartbeatInterval    time.Duration
+}
+
+type NotificationConfig struct {
+	// EventBufferSize is how many events wait for the dispatcher before new
+	// ones are dropped.
+	EventBufferSize int
+	QueueSize       int
+	Workers         int
+	SendTimeout     time.Duration
+}
+
+type WatchlistConfig struct {
+	EndingWindow   time.Duration
+	NotifyInterval time.Duration
+}
+
+// RateLimitConfig holds the limits of the HTTP routes, per client.
+type RateLimitConfig struct {
+	Read  ratelimit.Policy
+	Write ratelimit.Policy
+	Login ratelimit.Policy
+}
+
+type ServerConfig struct {
+	HTTPPort             string
+	GRPCPort             string
+	ShutdownDrainDelay   time.Duration
+	CompressionMinSize   int
+	ReadinessPingTimeout time.Duration
+	// TrustedProxies are the addresses or CIDR ranges whose X-Forwarded-For
+	// header is believed when finding the client IP. None are trusted by
+	// default, so the client IP is the address of the connection.
+	TrustedProxies []string
+
+	IndexCreationFailOnError bool
+}
+
+type AuthConfig struct {
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// AdminConfig seeds the first admin when Email is set.
+type AdminConfig struct {
+	Email    string
+	Password string
+}
+
+// Error lists every variable that is missing or invalid, so a deploy can be
+// fixed in one go.
+type Error struct {
+	Problems []string
+}
+
+func (e *Error) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// Load reads the environment after adding the variables of envFiles that are
+// not set yet. Missing files are skipped, since the variables may come from
+// the environment alone. Settings the environment leaves empty are read from
+// the config file, and those it leaves empty fall back to their defaults.
+//
+// Load can be called again to reload, and then reads envFiles afresh.
+func Load(envFiles ...string) (*Config, error) {
+	if err := loadEnvFiles(envFiles); err != nil {
+		return nil, err
+	}
+
+	l, err := newLoader()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{
+		Mongo: MongoConfig{
+			URL:      l.mongoURL("MONGODB_URL"),
+			Database: l.required("MONGODB_DB"),
+			ReadPreference: l.oneOf("MONGODB_READ_PREFERENCE", "primary",
+				"primary", "primaryPreferred", "secondary", "secondaryPreferred", "nearest"),
+
+			MaxPoolSize:            uint64(l.integer("MONGODB_MAX_POOL_SIZE", 100, 1)),
+			MinPoolSize:            uint64(l.integer("MONGODB_MIN_POOL_SIZE", 0, 0)),
+			ConnectTimeout:         l.duration("MONGODB_CONNECT_TIMEOUT", 10*time.Second, time.Millisecond),
+			ServerSelectionTimeout: l.duration("MONGODB_SERVER_SELECTION_TIMEOUT", 5*time.Second, time.Millisecond),
+			SocketTimeout:          l.duration("MONGODB_SOCKET_TIMEOUT", 0, 0),
+			StartupTimeout:         l.duration("MONGODB_STARTUP_TIMEOUT", time.Minute, 0),
+		},
+		Auction: AuctionConfig{
+			Interval: l.auctionInterval(),
+
+			MinDuration:    l.duration("AUCTION_MIN_DURATION", 10*time.Second, 0),
+			MaxDuration:    l.duration("AUCTION_MAX_DURATION", 30*24*time.Hour, time.Second),
+			MaxExtension:   l.duration("AUCTION_MAX_EXTENSION", 24*time.Hour, 0),
+			SnipeWindow:    l.duration("AUCTION_SNIPE_WINDOW", 30*time.Second, 0),
+			SnipeExtension: l.duration("AUCTION_SNIPE_EXTENSION", 30*time.Second, 0),
+
+			BulkMaxItems:               l.integer("AUCTION_BULK_MAX_ITEMS", 500, 1),
+			VersionConflictMaxAttempts: l.integer("AUCTION_VERSION_CONFLICT_MAX_ATTEMPTS", 3, 1),
+			EventRelayInterval:         l.duration("AUCTION_EVENT_RELAY_INTERVAL", 5*time.Second, time.Millisecond),
+			StatsTimeout:               l.duration("AUCTION_STATS_TIMEOUT", 10*time.Second, time.Millisecond),
+			StreamBatchSize:            l.integer("AUCTION_STREAM_BATCH_SIZE", 500, 1),
+			ChangeStreamEnabled:        l.boolean("AUCTION_CHANGE_STREAM_ENABLED", false),
+		},
+		Close: CloseConfig{
+			Strategy:      l.oneOf("CLOSE_STRATEGY", "timer", "timer", "sweep"),
+			SweepInterval: l.duration("SWEEP_INTERVAL", time.Minute, time.Millisecond),
+			Workers:       l.integer("CLOSE_WORKERS", 8, 1),
+			QueueSize:     l.integer("CLOSE_QUEUE_SIZE", 1024, 1),
+			MaxAttempts:   l.integer("AUCTION_CLOSE_MAX_ATTEMPTS", 5, 1),
+
+			LeaderElectionEnabled: l.boolean("AUCTION_LEADER_ELECTION_ENABLED", false),
+			LeaderLeaseTTL:        l.duration("AUCTION_LEADER_LEASE_TTL", 15*time.Second, time.Second),
+		},
+		Bid: BidConfig{
+			MaxBatchSize:        l.integer("MAX_BATCH_SIZE", 5, 1),
+			BatchInsertInterval: l.duration("BATCH_INSERT_INTERVAL", 3*time.Minute, time.Millisecond),
+
+			MinIncrement: l.amount("BID_MIN_INCREMENT"),
+			RateLimit: l.rateLimit("BID_RATE_LIMIT", "BID_RATE_BURST",
+				ratelimit.Rate{Events: 5, Per: time.Second}, 10),
+			BulkMaxItems:      l.integer("BID_BULK_MAX_ITEMS", 100, 1),
+			BulkTimeout:       l.duration("BID_BULK_TIMEOUT", 10*time.Second, time.Millisecond),
+			RetractionWindow:  l.duration("BID_RETRACTION_WINDOW", time.Minute, 0),
+			IdempotencyKeyTTL: l.duration("BID_IDEMPOTENCY_KEY_TTL", 24*time.Hour, time.Second),
+			StreamBatchSize:   l.integer("BID_STREAM_BATCH_SIZE", 500, 1),
+		},
+		UserCache: UserCacheConfig{
+			Enabled: l.boolean("USER_CACHE_ENABLED", false),
+			Size:    l.integer("USER_CACHE_SIZE", 10000, 1),
+			TTL:     l.duration("USER_CACHE_TTL", 30*time.Second, time.Millisecond),
+		},
+		LiveFeed: LiveFeedConfig{
+			BufferSize:       l.integer("LIVE_FEED_BUFFER_SIZE", 64, 1),
+			HistorySize:      l.integer("LIVE_FEED_HISTORY_SIZE", 100, 1),
+			HistoryRetention: l.duration("LIVE_FEED_HISTORY_RETENTION", 5*time.Minute, time.Second),
+			WriteTimeout:     l.duration("LIVE_FEED_WRITE_TIMEOUT", 10*time.Second, time.Millisecond),
+
+			SSEMaxStreamsPerAuction: l.integer("SSE_MAX_STREAMS_PER_AUCTION", 500, 1),
+			SSEHeartbeatInterval:    l.duration("SSE_HEARTBEAT_INTERVAL", 15*time.Second, time.Second),
+		},
+		Notification: NotificationConfig{
+			EventBufferSize: l.integer("EVENT_BUFFER_SIZE", 1000, 1),
+			QueueSize:       l.integer("NOTIFICATION_QUEUE_SIZE", 1000, 1),
+			Workers:         l.integer("NOTIFICATION_WORKERS", 4, 1),
+			SendTimeout:     l.duration("NOTIFICATION_SEND_TIMEOUT", 10*time.Second, time.Millisecond),
+		},
+		Watchlist: WatchlistConfig{
+			EndingWindow:   l.duration("WATCHLIST_ENDING_WINDOW", time.Hour, time.Second),
+			NotifyInterval: l.duration("WATCHLIST_NOTIFY_INTERVAL", time.Minute, time.Second),
+		},
+		RateLimit: RateLimitConfig{
+			Read: l.rateLimit("RATE_LIMIT_READ", "RATE_LIMIT_READ_BURST",
+				ratelimit.Rate{Events: 20, Per: time.Second}, 40),
+			Write: l.rateLimit("RATE_LIMIT_WRITE", "RATE_LIMIT_WRITE_BURST",
+				ratelimit.Rate{Events: 2, Per: time.Second}, 10),
+			Login: l.rateLimit("RATE_LIMIT_LOGIN", "RATE_LIMIT_LOGIN_BURST",
+				ratelimit.Rate{Events: 5, Per: time.Minute}, 5),
+		},
+		Server: ServerConfig{
+			HTTPPort:             l.port("HTTP_PORT", "8080"),
+			GRPCPort:             l.port("GRPC_PORT", "50051"),
+			ShutdownDrainDelay:   l.duration("SHUTDOWN_DRAIN_DELAY", 5*time.Second, 0),
+			CompressionMinSize:   l.integer("COMPRESSION_MIN_SIZE", 1024, 0),
+			ReadinessPingTimeout: l.duration("READINESS_PING_TIMEOUT", 2*time.Second, time.Millisecond),
+			TrustedProxies:       l.networks("TRUSTED_PROXIES"),
+
+			IndexCreationFailOnError: l.boolean("INDEX_CREATION_FAIL_ON_ERROR", true),
+		},
+		Auth: AuthConfig{
+			JWTSecret:       l.required("JWT_SECRET"),
+			AccessTokenTTL:  l.duration("JWT_ACCESS_TOKEN_TTL", 15*time.Minute, time.Second),
+			RefreshTokenTTL: l.duration("JWT_REFRESH_TOKEN_TTL", 7*24*time.Hour, time.Second),
+		},
+		Admin: AdminConfig{
+			Email:    l.string("ADMIN_EMAIL"),
+			Password: l.string("ADMIN_PASSWORD"),
+		},
+		LogLevel: l.oneOf("LOG_LEVEL", "info", "debug", "info", "warn", "error"),
+	}
+
+	if cfg.Mongo.MinPoolSize > cfg.Mongo.MaxPoolSize {
+		l.invalid("MONGODB_MIN_POOL_SIZE (%d) cannot be above MONGODB_MAX_POOL_SIZE (%d)",
+			cfg.Mongo.MinPoolSize, cfg.Mongo.MaxPoolSize)
+	}
+	if cfg.Auction.MinDuration > cfg.Auction.MaxDuration {
+		l.invalid("AUCTION_MIN_DURATION (%s) cannot be above AUCTION_MAX_DURATION (%s)",
+			cfg.Auction.MinDuration, cfg.Auction.MaxDuration)
+	}
+	if cfg.Admin.Email != "" && cfg.Admin.Password == "" {
+		l.invalid("ADMIN_PASSWORD is required when ADMIN_EMAIL is set")
+	}
+
+	if err := l.err(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// String describes the configuration for the startup log, with secrets and
+// the credentials of the Mongo URL redacted.
+func (c *Config) String() string {
+	settings := []string{
+		"mongo.url=" + redactURL(c.Mongo.URL),
+		"mongo.database=" + c.Mongo.Database,
+		"mongo.read_preference=" + c.Mongo.ReadPreference,
+		"mongo.max_pool_size=" + strconv.FormatUint(c.Mongo.MaxPoolSize, 10),
+		"mongo.min_pool_size=" + strconv.FormatUint(c.Mongo.MinPoolSize, 10),
+		"mongo.connect_timeout=" + c.Mongo.ConnectTimeout.String(),
+		"mongo.server_selection_timeout=" + c.Mongo.ServerSelectionTimeout.String(),
+		"mongo.socket_timeout=" + c.Mongo.SocketTimeout.String(),
+		"mongo.startup_timeout=" + c.Mongo.StartupTimeout.String(),
+		"auction.interval=" + c.Auction.Interval.String(),
+		"auction.min_duration=" + c.Auction.MinDuration.String(),
+		"auction.max_duration=" + c.Auction.MaxDuration.String(),
+		"auction.max_extension=" + c.Auction.MaxExtension.String(),
+		"auction.snipe_window=" + c.Auction.SnipeWindow.String(),
+		"auction.snipe_extension=" + c.Auction.SnipeExtension.String(),
+		"auction.bulk_max_items=" + strconv.Itoa(c.Auction.BulkMaxItems),
+		"auction.version_conflict_max_attempts=" + strconv.Itoa(c.Auction.VersionConflictMaxAttempts),
+		"auction.event_relay_interval=" + c.Auction.EventRelayInterval.String(),
+		"auction.stats_timeout=" + c.Auction.StatsTimeout.String(),
+		"auction.stream_batch_size=" + strconv.Itoa(c.Auction.StreamBatchSize),
+		"auction.change_stream_enabled=" + strconv.FormatBool(c.Auction.ChangeStreamEnabled),
+		"close.strategy=" + c.Close.Strategy,
+		"close.sweep_interval=" + c.Close.SweepInterval.String(),
+		"close.workers=" + strconv.Itoa(c.Close.Workers),
+		"close.queue_size=" + strconv.Itoa(c.Close.QueueSize),
+		"close.max_attempts=" + strconv.Itoa(c.Close.MaxAttempts),
+		"close.leader_election_enabled=" + strconv.FormatBool(c.Close.LeaderElectionEnabled),
+		"close.leader_lease_ttl=" + c.Close.LeaderLeaseTTL.String(),
+		"bid.max_batch_size=" + strconv.Itoa(c.Bid.MaxBatchSize),
+		"bid.batch_insert_interval=" + c.Bid.BatchInsertInterval.String(),
+		"bid.min_increment=" + c.Bid.MinIncrement.String(),
+		"bid.rate_limit=" + rateText(c.Bid.RateLimit),
+		"bid.rate_burst=" + strconv.Itoa(c.Bid.RateLimit.Burst),
+		"bid.bulk_max_items=" + strconv.Itoa(c.Bid.BulkMaxItems),
+		"bid.bulk_timeout=" + c.Bid.BulkTimeout.String(),
+		"bid.retraction_window=" + c.Bid.RetractionWindow.String(),
+		"bid.idempotency_key_ttl=" + c.Bid.IdempotencyKeyTTL.String(),
+		"bid.stream_batch_size=" + strconv.Itoa(c.Bid.StreamBatchSize),
+		"user_cache.enabled=" + strconv.FormatBool(c.UserCache.Enabled),
+		"user_cache.size=" + strconv.Itoa(c.UserCache.Size),
+		"user_cache.ttl=" + c.UserCache.TTL.String(),
+		"live_feed.buffer_size=" + strconv.Itoa(c.LiveFeed.BufferSize),
+		"live_feed.history_size=" + strconv.Itoa(c.LiveFeed.HistorySize),
+		"live_feed.history_retention=" + c.LiveFeed.HistoryRetention.String(),
+		"live_feed.write_timeout=" + c.LiveFeed.WriteTimeout.String(),
+		"live_feed.sse_max_streams_per_auction=" + strconv.Itoa(c.LiveFeed.SSEMaxStreamsPerAuction),
+		"live_feed.sse_heartbeat_interval=" + c.LiveFeed.SSEHeartbeatInterval.String(),
+		"notification.event_buffer_size=" + strconv.Itoa(c.Notification.EventBufferSize),
+		"notification.queue_size=" + strconv.Itoa(c.Notification.QueueSize),
+		"notification.workers=" + strconv.Itoa(c.Notification.Workers),
+		"notification.send_timeout=" + c.Notification.SendTimeout.String(),
+		"watchlist.ending_window=" + c.Watchlist.EndingWindow.String(),
+		"watchlist.notify_interval=" + c.Watchlist.NotifyInterval.String(),
+		"rate_limit.read=" + rateText(c.RateLimit.Read),
+		"rate_limit.read_burst=" + strconv.Itoa(c.RateLimit.Read.Burst),
+		"rate_limit.write=" + rateText(c.RateLimit.Write),
+		"rate_limit.write_burst=" + strconv.Itoa(c.RateLimit.Write.Burst),
+		"rate_limit.login=" + rateText(c.RateLimit.Login),
+		"rate_limit.login_burst=" + strconv.Itoa(c.RateLimit.Login.Burst),
+		"server.http_port=" + c.Server.HTTPPort,
+		"server.grpc_port=" + c.Server.GRPCPort,
+		"server.shutdown_drain_delay=" + c.Server.ShutdownDrainDelay.String(),
+		"server.compression_min_size=" + strconv.Itoa(c.Server.CompressionMinSize),
+		"server.readiness_ping_timeout=" + c.Server.ReadinessPingTimeout.String(),
+		"server.trusted_proxies=" + strings.Join(c.Server.TrustedProxies, ","),
+		"server.index_creation_fail_on_error=" + strconv.FormatBool(c.Server.IndexCreationFailOnError),
+		"auth.jwt_secret=" + redact(c.Auth.JWTSecret),
+		"auth.access_token_ttl=" + c.Auth.AccessTokenTTL.String(),
+		"auth.refresh_token_ttl=" + c.Auth.RefreshTokenTTL.String(),
+		"admin.email=" + c.Admin.Email,
+		"admin.password=" + redact(c.Admin.Password),
+		"log_level=" + c.LogLevel,
+	}
+
+	return strings.Join(settings, " ")
+}
+
+// rateText writes the rate of policy the way it is configured, such as 5/s.
+func rateText(policy ratelimit.Policy) string {
+	if policy.Disabled {
+		return "off"
+	}
+
+	unit := map[time.Duration]string{time.Second: "s", time.Minute: "m", time.Hour: "h"}[policy.Rate.Per]
+	return strconv.FormatFloat(policy.Rate.Events, 'g', -1, 64) + "/" + unit
+}
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+
+	return redacted
+}
+
+func redactURL(value string) string {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return redacted
+	}
+
+	return parsed.Redacted()
+}
+
+// envFileValues remembers the variables Load took from env files, so that a
+// reload reads the files again rather than keeping what they said at startup.
+// Variables set by anything else, such as the container, are left alone.
+var (
+	envFileValues      = map[string]string{}
+	envFileValuesMutex sync.Mutex
+)
+
+func loadEnvFiles(envFiles []string) error {
+	envFileValuesMutex.Lock()
+	defer envFileValuesMutex.Unlock()
+
+	for key, value := range envFileValues {
+		if os.Getenv(key) == value {
+			os.Unsetenv(key)
+		}
+		delete(envFileValues, key)
+	}
+
+	for _, envFile := range envFiles {
+		values, err := godotenv.Read(envFile)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error trying to load %s: %w", envFile, err)
+		}
+
+		for key, value := range values {
+			if _, set := os.LookupEnv(key); set {
+				continue
+			}
+
+			os.Setenv(key, value)
+			envFileValues[key] = value
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"gopkg.in/yaml.v3"
+	"os"
+	"sort"
+	"strings"
+)
+
+const defaultConfigFile = "config.yaml"
+
+// fileKeys maps every variable to its key in the config file. Nested YAML
+// maps are joined with dots, so mongo.url is url under mongo.
+var fileKeys = map[string]string{
+	"MONGODB_URL":                           "mongo.url",
+	"MONGODB_DB":                            "mongo.database",
+	"MONGODB_READ_PREFERENCE":               "mongo.read_preference",
+	"MONGODB_MAX_POOL_SIZE":                 "mongo.max_pool_size",
+	"MONGODB_MIN_POOL_SIZE":                 "mongo.min_pool_size",
+	"MONGODB_CONNECT_TIMEOUT":               "mongo.connect_timeout",
+	"MONGODB_SERVER_SELECTION_TIMEOUT":      "mongo.server_selection_timeout",
+	"MONGODB_SOCKET_TIMEOUT":                "mongo.socket_timeout",
+	"MONGODB_STARTUP_TIMEOUT":               "mongo.startup_timeout",
+	"AUCTION_INTERVAL":                      "auction.interval",
+	"AUCTION_MIN_INTERVAL":                  "auction.min_interval",
+	"AUCTION_MAX_INTERVAL":                  "auction.max_interval",
+	"AUCTION_MIN_DURATION":                  "auction.min_duration",
+	"AUCTION_MAX_DURATION":                  "auction.max_duration",
+	"AUCTION_MAX_EXTENSION":                 "auction.max_extension",
+	"AUCTION_SNIPE_WINDOW":                  "auction.snipe_window",
+	"AUCTION_SNIPE_EXTENSION":               "auction.snipe_extension",
+	"AUCTION_BULK_MAX_ITEMS":                "auction.bulk_max_items",
+	"AUCTION_VERSION_CONFLICT_MAX_ATTEMPTS": "auction.version_conflict_max_attempts",
+	"AUCTION_EVENT_RELAY_INTERVAL":          "auction.event_relay_interval",
+	"AUCTION_STATS_TIMEOUT":                 "auction.stats_timeout",
+	"AUCTION_STREAM_BATCH_SIZE":             "auction.stream_batch_size",
+	"AUCTION_CHANGE_STREAM_ENABLED":         "auction.change_stream_enabled",
+	"CLOSE_STRATEGY":                        "close.strategy",
+	"SWEEP_INTERVAL":                        "close.sweep_interval",
+	"CLOSE_WORKERS":                         "close.workers",
+	"CLOSE_QUEUE_SIZE":                      "close.queue_size",
+	"AUCTION_CLOSE_MAX_ATTEMPTS":            "close.max_attempts",
+	"AUCTION_LEADER_ELECTION_ENABLED":       "close.leader_election_enabled",
+	"AUCTION_LEADER_LEASE_TTL":              "close.leader_lease_ttl",
+	"MAX_BATCH_SIZE":                        "bid.max_batch_size",
+	"BATCH_INSERT_INTERVAL":                 "bid.batch_insert_interval",
+	"BID_MIN_INCREMENT":                     "bid.min_increment",
+	"BID_RATE_LIMIT":                        "bid.rate_limit",
+	"BID_RATE_LIMIT_BURST":                  "bid.rate_limit_burst",
+	"BID_BULK_MAX_ITEMS":                    "bid.bulk_max_items",
+	"BID_BULK_TIMEOUT":                      "bid.bulk_timeout",
+	"BID_RETRACTION_WINDOW":                 "bid.retraction_window",
+	"BID_IDEMPOTENCY_KEY_TTL":               "bid.idempotency_key_ttl",
+	"BID_STREAM_BATCH_SIZE":                 "bid.stream_batch_size",
+	"USER_CACHE_ENABLED":                    "user_cache.enabled",
+	"USER_CACHE_SIZE":                       "user_cache.size",
+	"USER_CACHE_TTL":                        "user_cache.ttl",
+	"LIVE_FEED_BUFFER_SIZE":                 "live_feed.buffer_size",
+	"LIVE_FEED_HISTORY_SIZE":                "live_feed.history_size",
+	"LIVE_FEED_HISTORY_RETENTION":           "live_feed.history_retention",
+	"LIVE_FEED_WRITE_TIMEOUT":               "live_feed.write_timeout",
+	"SSE_MAX_STREAMS_PER_AUCTION":           "live_feed.sse_max_streams_per_auction",
+	"SSE_HEARTBEAT_INTERVAL":                "live_feed.sse_heartbeat_interval",
+	"EVENT_BUFFER_SIZE":                     "notification.event_buffer_size",
+	"NOTIFICATION_QUEUE_SIZE":               "notification.queue_size",
+	"NOTIFICATION_WORKERS":                  "notification.workers",
+	"NOTIFICATION_SEND_TIMEOUT":             "notification.send_timeout",
+	"WATCHLIST_ENDING_WINDOW":               "watchlist.ending_window",
+	"WATCHLIST_NOTIFY_INTERVAL":             "watchlist.notify_interval",
+	"RATE_LIMIT_READ":                       "rate_limit.read",
+	"RATE_LIMIT_READ_BURST":                 "rate_limit.read_burst",
+	"RATE_LIMIT_WRITE":                      "rate_limit.write",
+	"RATE_LIMIT_WRITE_BURST":                "rate_limit.write_burst",
+	"RATE_LIMIT_LOGIN":                      "rate_limit.login",
+	"RATE_LIMIT_LOGIN_BURST":                "rate_limit.login_burst",
+	"HTTP_PORT":                             "server.http_port",
+	"GRPC_PORT":                             "server.grpc_port",
+	"SHUTDOWN_DRAIN_DELAY":                  "server.shutdown_drain_delay",
+	"COMPRESSION_MIN_SIZE":                  "server.compression_min_size",
+	"READINESS_PING_TIMEOUT":                "server.readiness_ping_timeout",
+	"TRUSTED_PROXIES":                       "server.trusted_proxies",
+	"INDEX_CREATION_FAIL_ON_ERROR":          "server.index_creation_fail_on_error",
+	"JWT_SECRET":                            "auth.jwt_secret",
+	"JWT_ACCESS_TOKEN_TTL":                  "auth.access_token_ttl",
+	"JWT_REFRESH_TOKEN_TTL":                 "auth.refresh_token_ttl",
+	"ADMIN_EMAIL":                           "admin.email",
+	"ADMIN_PASSWORD":                        "admin.password",
+	"LOG_LEVEL":                             "log_level",
+}
+
+// readConfigFile reads the file named by CONFIG_FILE, or config.yaml when it
+// is not set and the file exists. Keys that match no setting are logged as a
+// warning, since they are most likely typos.
+func readConfigFile() (map[string]string, error) {
+	path := os.Getenv("CONFIG_FILE")
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigFile
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error trying to read config file %s: %w", path, err)
+	}
+
+	values, unknown, err := parseConfigFile(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	if len(unknown) > 0 {
+		logger.Warn(fmt.Sprintf("Config file %s has unknown keys, which are ignored: %s",
+			path, strings.Join(unknown, ", ")))
+	}
+
+	return values, nil
+}
+
+// parseConfigFile returns the values of the file by key, and the keys that
+// match no setting, sorted.
+func parseConfigFile(content []byte) (map[string]string, []string, error) {
+	var document map[string]interface{}
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, nil, err
+	}
+
+	values := make(map[string]string)
+	flatten("", document, values)
+
+	known := make(map[string]bool, len(fileKeys))
+	for _, key := range fileKeys {
+		known[key] = true
+	}
+
+	var unknown []string
+	for key := range values {
+		if !known[key] {
+			unknown = append(unknown, key)
+			delete(values, key)
+		}
+	}
+	sort.Strings(unknown)
+
+	return values, unknown, nil
+}
+
+func flatten(prefix string, node map[string]interface{}, values map[string]string) {
+	for key, value := range node {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			flatten(key, typed, values)
+		case nil:
+			values[key] = ""
+		default:
+			values[key] = fmt.Sprint(typed)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestEnvironmentOverridesTheConfigFileWhichOverridesDefaults(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("MONGODB_DB", "")
+	t.Setenv("AUCTION_INTERVAL", "")
+	t.Setenv("CONFIG_FILE", writeConfigFile(t, `
+mongo:
+  database: from_file
+auction:
+  interval: 45s
+bid:
+  max_batch_size: 20
+server:
+  grpc_port: 50060
+  index_creation_fail_on_error: false
+`))
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "from_file", cfg.Mongo.Database, "the file fills what the environment leaves empty")
+	assert.Equal(t, 45*time.Second, cfg.Auction.Interval)
+	assert.Equal(t, 50, cfg.Bid.MaxBatchSize, "the environment wins over the file")
+	assert.Equal(t, "50052", cfg.Server.GRPCPort, "the environment wins over the file")
+	assert.False(t, cfg.Server.IndexCreationFailOnError)
+	assert.Equal(t, 3*time.Minute, cfg.Bid.BatchInsertInterval, "defaults fill the rest")
+	assert.Equal(t, "8080", cfg.Server.HTTPPort, "defaults fill the rest")
+}
+
+func TestConfigFileValuesAreValidatedUnderTheirKey(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("MAX_BATCH_SIZE", "")
+	t.Setenv("CONFIG_FILE", writeConfigFile(t, "bid:\n  max_batch_size: lots\n"))
+
+	_, err := Load()
+	var configErr *Error
+	assert.True(t, errors.As(err, &configErr))
+	assert.Equal(t, []string{
+		`invalid bid.max_batch_size (config file) value "lots": not a whole number`,
+	}, configErr.Problems)
+}
+
+func TestAMissingConfigFileIsOnlyAnErrorWhenNamed(t *testing.T) {
+	setValidEnv(t)
+
+	_, err := Load()
+	assert.NoError(t, err)
+
+	t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+	_, err = Load()
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestParseConfigFileReportsUnknownKeys(t *testing.T) {
+	values, unknown, err := parseConfigFile([]byte(`
+mongo:
+  url: mongodb://mongodb:27017
+  databse: auctions
+log_levl: debug
+auction:
+  interval: 1m
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"log_levl", "mongo.databse"}, unknown)
+	assert.Equal(t, map[string]string{
+		"mongo.url":        "mongodb://mongodb:27017",
+		"auction.interval": "1m",
+	}, values)
+
+	_, _, err = parseConfigFile([]byte("mongo: [unclosed"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,243 @@
+package config
+
+import (
+	"fmt"
+	"fullcycle-auction_go/internal/money"
+	"fullcycle-auction_go/internal/ratelimit"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// loader reads every setting from the environment first, then from the
+// config file, then falls back to its default. It keeps every problem it
+// finds so the remaining settings are still checked.
+type loader struct {
+	fileValues map[string]string
+	problems   []string
+}
+
+func newLoader() (*loader, error) {
+	fileValues, err := readConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	return &loader{fileValues: fileValues}, nil
+}
+
+// lookup returns the value of the variable and where it came from, to name
+// it in problems.
+func (l *loader) lookup(name string) (string, string) {
+	if value := os.Getenv(name); value != "" {
+		return value, name
+	}
+
+	if fileKey, ok := fileKeys[name]; ok {
+		if value := l.fileValues[fileKey]; value != "" {
+			return value, fileKey + " (config file)"
+		}
+	}
+
+	return "", name
+}
+
+func (l *loader) string(name string) string {
+	value, _ := l.lookup(name)
+	return value
+}
+
+func (l *loader) invalid(format string, args ...interface{}) {
+	l.problems = append(l.problems, fmt.Sprintf(format, args...))
+}
+
+func (l *loader) err() error {
+	if len(l.problems) == 0 {
+		return nil
+	}
+
+	return &Error{Problems: l.problems}
+}
+
+func (l *loader) required(name string) string {
+	value, _ := l.lookup(name)
+	if value == "" {
+		l.invalid("%s is required", requiredName(name))
+	}
+
+	return value
+}
+
+func (l *loader) mongoURL(name string) string {
+	value := l.required(name)
+	if value == "" {
+		return value
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "mongodb" && parsed.Scheme != "mongodb+srv") {
+		_, source := l.lookup(name)
+		l.invalid("%s must be a mongodb:// or mongodb+srv:// URL", source)
+	}
+
+	return value
+}
+
+// requiredName names both places a required value can be set.
+func requiredName(name string) string {
+	if fileKey, ok := fileKeys[name]; ok {
+		return fmt.Sprintf("%s (or %s in the config file)", name, fileKey)
+	}
+
+	return name
+}
+
+func (l *loader) oneOf(name, fallback string, allowed ...string) string {
+	value, source := l.lookup(name)
+	if value == "" {
+		return fallback
+	}
+
+	for _, candidate := range allowed {
+		if value == candidate {
+			return value
+		}
+	}
+
+	l.invalid("invalid %s value %q: must be one of %v", source, value, allowed)
+	return fallback
+}
+
+func (l *loader) duration(name string, fallback, min time.Duration) time.Duration {
+	value, source := l.lookup(name)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		l.invalid("invalid %s value %q: %s", source, value, err)
+		return fallback
+	}
+	if duration < min {
+		l.invalid("%s must be at least %s, got %s", source, min, duration)
+		return fallback
+	}
+
+	return duration
+}
+
+func (l *loader) integer(name string, fallback, min int) int {
+	value, source := l.lookup(name)
+	if value == "" {
+		return fallback
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		l.invalid("invalid %s value %q: not a whole number", source, value)
+		return fallback
+	}
+	if number < min {
+		l.invalid("%s must be at least %d, got %d", source, min, number)
+		return fallback
+	}
+
+	return number
+}
+
+func (l *loader) boolean(name string, fallback bool) bool {
+	value, source := l.lookup(name)
+	if value == "" {
+		return fallback
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		l.invalid("invalid %s value %q: must be true or false", source, value)
+		return fallback
+	}
+
+	return enabled
+}
+
+func (l *loader) port(name, fallback string) string {
+	value, source := l.lookup(name)
+	if value == "" {
+		return fallback
+	}
+
+	if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+		l.invalid("invalid %s value %q: must be a port between 1 and 65535", source, value)
+		return fallback
+	}
+
+	return value
+}
+
+func (l *loader) amount(name string) money.Amount {
+	value, source := l.lookup(name)
+	if value == "" {
+		return 0
+	}
+
+	amount, err := money.Parse(value)
+	if err != nil || amount < 0 {
+		l.invalid("invalid %s value %q: must be an amount of at least 0", source, value)
+		return 0
+	}
+
+	return amount
+}
+
+// networks reads a comma separated list of IP addresses and CIDR ranges.
+func (l *loader) networks(name string) []string {
+	value, source := l.lookup(name)
+	if value == "" {
+		return nil
+	}
+
+	var networks []string
+	for _, network := range strings.Split(value, ",") {
+		network = strings.TrimSpace(network)
+		if network == "" {
+			continue
+		}
+
+		if net.ParseIP(network) == nil {
+			if _, _, err := net.ParseCIDR(network); err != nil {
+				l.invalid("invalid %s value %q: must be an IP address or a CIDR range", source, network)
+				continue
+			}
+		}
+		networks = append(networks, network)
+	}
+
+	return networks
+}
+
+// rateLimit reads a rate such as 5/s from name and its burst from
+// burstName. A rate of off disables the limit.
+func (l *loader) rateLimit(
+	name, burstName string, fallback ratelimit.Rate, fallbackBurst int) ratelimit.Policy {
+	policy := ratelimit.Policy{Rate: fallback}
+
+	value, source := l.lookup(name)
+	switch {
+	case value == "off":
+		policy.Disabled = true
+	case value != "":
+		rate, err := ratelimit.ParseRate(value)
+		if err != nil {
+			l.invalid("invalid %s value %q: %s, or off", source, value, err)
+			break
+		}
+		policy.Rate = rate
+	}
+
+	policy.Burst = l.integer(burstName, fallbackBurst, 1)
+	return policy
+}
@@ -2,32 +2,112 @@ package mongodb
 
 import (
 	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/config"
 	"fullcycle-auction_go/configuration/logger"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"os"
+	"time"
 )
 
 const (
-	MONGODB_URL = "MONGODB_URL"
-	MONGODB_DB  = "MONGODB_DB"
+	firstRetryDelay = 500 * time.Millisecond
+	maxRetryDelay   = 10 * time.Second
 )
 
-func NewMongoDBConnection(ctx context.Context) (*mongo.Database, error) {
-	mongoURL := os.Getenv(MONGODB_URL)
-	mongoDatabase := os.Getenv(MONGODB_DB)
+// NewMongoDBConnection retries with a growing delay until MongoDB answers a
+// ping or StartupTimeout has passed, so the API can start before the
+// database is ready. Cancelling ctx stops the retries at once.
+func NewMongoDBConnection(ctx context.Context, mongoConfig config.MongoConfig) (*mongo.Database, error) {
+	deadline := time.Now().Add(mongoConfig.StartupTimeout)
+	delay := firstRetryDelay
 
-	client, err := mongo.Connect(
-		ctx, options.Client().ApplyURI(mongoURL))
+	for attempt := 1; ; attempt++ {
+		client, err := connect(ctx, mongoConfig)
+		if err == nil {
+			logger.InfoContext(ctx, fmt.Sprintf(
+				"Connected to mongodb: pool of %d to %d connections, connect timeout %s, "+
+					"server selection timeout %s, socket timeout %s, retryable writes on",
+				mongoConfig.MinPoolSize, mongoConfig.MaxPoolSize, mongoConfig.ConnectTimeout,
+				mongoConfig.ServerSelectionTimeout, socketTimeoutText(mongoConfig.SocketTimeout)))
+
+			return client.Database(mongoConfig.Database), nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			logger.ErrorContext(ctx, fmt.Sprintf(
+				"Error trying to connect to mongodb database, giving up after %d attempts", attempt), err)
+			return nil, err
+		}
+
+		if delay > remaining {
+			delay = remaining
+		}
+		logger.WarnContext(ctx, fmt.Sprintf("Attempt %d to connect to mongodb failed, retrying in %s: %s",
+			attempt, delay, err))
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay = nextRetryDelay(delay)
+	}
+}
+
+// connect disconnects the client again when the ping fails, so a failed
+// attempt leaves no pool behind.
+func connect(ctx context.Context, mongoConfig config.MongoConfig) (*mongo.Client, error) {
+	client, err := mongo.Connect(ctx, newClientOptions(mongoConfig))
 	if err != nil {
-		logger.Error("Error trying to connect to mongodb database", err)
 		return nil, err
 	}
 
 	if err := client.Ping(ctx, nil); err != nil {
-		logger.Error("Error trying to ping mongodb database", err)
+		client.Disconnect(context.Background())
 		return nil, err
 	}
 
-	return client.Database(mongoDatabase), nil
+	return client, nil
+}
+
+func nextRetryDelay(delay time.Duration) time.Duration {
+	if delay*2 > maxRetryDelay {
+		return maxRetryDelay
+	}
+
+	return delay * 2
+}
+
+// newClientOptions applies the settings after the URL, so they win over the
+// same options given as URL parameters.
+func newClientOptions(mongoConfig config.MongoConfig) *options.ClientOptions {
+	clientOptions := options.Client().
+		ApplyURI(mongoConfig.URL).
+		SetPoolMonitor(NewPoolMonitor()).
+		SetMaxPoolSize(mongoConfig.MaxPoolSize).
+		SetMinPoolSize(mongoConfig.MinPoolSize).
+		SetConnectTimeout(mongoConfig.ConnectTimeout).
+		SetServerSelectionTimeout(mongoConfig.ServerSelectionTimeout).
+		SetRetryWrites(true)
+
+	if mongoConfig.SocketTimeout > 0 {
+		clientOptions.SetSocketTimeout(mongoConfig.SocketTimeout)
+	}
+
+	return clientOptions
+}
+
+func socketTimeoutText(socketTimeout time.Duration) string {
+	if socketTimeout <= 0 {
+		return "off"
+	}
+
+	return socketTimeout.String()
 }
@@ -0,0 +1,70 @@
+package mongodb
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/config"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestClientOptionsApplyThePoolAndTimeouts(t *testing.T) {
+	clientOptions := newClientOptions(config.MongoConfig{
+		URL:                    "mongodb://mongodb:27017/?maxPoolSize=5",
+		MaxPoolSize:            50,
+		MinPoolSize:            5,
+		ConnectTimeout:         3 * time.Second,
+		ServerSelectionTimeout: 2 * time.Second,
+	})
+
+	assert.NoError(t, clientOptions.Validate())
+	assert.Equal(t, uint64(50), *clientOptions.MaxPoolSize)
+	assert.Equal(t, uint64(5), *clientOptions.MinPoolSize)
+	assert.Equal(t, 3*time.Second, *clientOptions.ConnectTimeout)
+	assert.Equal(t, 2*time.Second, *clientOptions.ServerSelectionTimeout)
+	assert.True(t, *clientOptions.RetryWrites)
+	assert.Nil(t, clientOptions.SocketTimeout)
+
+	clientOptions = newClientOptions(config.MongoConfig{URL: "mongodb://mongodb:27017", SocketTimeout: time.Minute})
+	assert.Equal(t, time.Minute, *clientOptions.SocketTimeout)
+}
+
+func TestConnectionRetriesUntilTheStartupTimeout(t *testing.T) {
+	mongoConfig := config.MongoConfig{
+		URL:                    "mongodb://127.0.0.1:1",
+		Database:               "auctions",
+		MaxPoolSize:            1,
+		ConnectTimeout:         50 * time.Millisecond,
+		ServerSelectionTimeout: 50 * time.Millisecond,
+		StartupTimeout:         600 * time.Millisecond,
+	}
+
+	started := time.Now()
+	database, err := NewMongoDBConnection(context.Background(), mongoConfig)
+	assert.Nil(t, database)
+	assert.Error(t, err)
+	assert.GreaterOrEqual(t, time.Since(started), mongoConfig.StartupTimeout)
+	assert.Less(t, time.Since(started), 5*time.Second)
+}
+
+func TestConnectionStopsRetryingWhenTheContextIsCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	_, err := NewMongoDBConnection(ctx, config.MongoConfig{
+		URL:                    "mongodb://127.0.0.1:1",
+		MaxPoolSize:            1,
+		ConnectTimeout:         50 * time.Millisecond,
+		ServerSelectionTimeout: 50 * time.Millisecond,
+		StartupTimeout:         time.Minute,
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(started), 5*time.Second)
+}
+
+func TestRetryDelayDoublesUpToTheCap(t *testing.T) {
+	assert.Equal(t, time.Second, nextRetryDelay(firstRetryDelay))
+	assert.Equal(t, 8*time.Second, nextRetryDelay(4*time.Second))
+	assert.Equal(t, maxRetryDelay, nextRetryDelay(8*time.Second))
+}
@@ -0,0 +1,69 @@
+package mongodb
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/money"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"math/big"
+)
+
+// DecimalFromAmount leaves zero as the empty decimal so omitempty fields stay
+// out of the document.
+func DecimalFromAmount(amount money.Amount) primitive.Decimal128 {
+	if amount == 0 {
+		return primitive.Decimal128{}
+	}
+
+	decimal, _ := primitive.ParseDecimal128FromBigInt(big.NewInt(int64(amount)), -2)
+	return decimal
+}
+
+func AmountFromDecimal(decimal primitive.Decimal128) money.Amount {
+	if decimal.IsZero() {
+		return 0
+	}
+
+	value, exp, err := decimal.BigInt()
+	if err != nil {
+		return 0
+	}
+
+	for ; exp > -2; exp-- {
+		value.Mul(value, big.NewInt(10))
+	}
+	if exp < -2 {
+		divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-2-exp)), nil)
+		half := new(big.Int).Quo(divisor, big.NewInt(2))
+		if value.Sign() < 0 {
+			half.Neg(half)
+		}
+		value.Quo(value.Add(value, half), divisor)
+	}
+
+	return money.Amount(value.Int64())
+}
+
+// ConvertFieldsToDecimal rewrites numeric fields stored as doubles or integers
+// into Decimal128 rounded to cents. It is safe to run more than once.
+func ConvertFieldsToDecimal(
+	ctx context.Context, collection *mongo.Collection, fields ...string) (int64, error) {
+	var converted int64
+	for _, field := range fields {
+		filter := bson.M{field: bson.M{"$type": bson.A{"double", "int", "long"}}}
+		update := mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{
+				field: bson.M{"$round": bson.A{bson.M{"$toDecimal": "$" + field}, 2}},
+			}}},
+		}
+
+		result, err := collection.UpdateMany(ctx, filter, update)
+		if err != nil {
+			return converted, err
+		}
+		converted += result.ModifiedCount
+	}
+
+	return converted, nil
+}
@@ -0,0 +1,35 @@
+package mongodb
+
+import (
+	"fullcycle-auction_go/internal/money"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"testing"
+)
+
+func TestDecimalRoundTrip(t *testing.T) {
+	for _, amount := range []money.Amount{1, 1999, 10000, -250} {
+		decimal := DecimalFromAmount(amount)
+		assert.Equal(t, amount.String(), decimal.String())
+		assert.Equal(t, amount, AmountFromDecimal(decimal))
+	}
+
+	assert.True(t, DecimalFromAmount(0).IsZero())
+	assert.Equal(t, money.Amount(0), AmountFromDecimal(primitive.Decimal128{}))
+}
+
+func TestAmountFromDecimalScalesToCents(t *testing.T) {
+	tests := map[string]money.Amount{
+		"20":      2000,
+		"19.9":    1990,
+		"19.999":  2000,
+		"19.994":  1999,
+		"-19.995": -2000,
+	}
+
+	for value, expected := range tests {
+		decimal, err := primitive.ParseDecimal128(value)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, AmountFromDecimal(decimal), value)
+	}
+}
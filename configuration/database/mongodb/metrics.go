@@ -0,0 +1,49 @@
+package mongodb
+
+import (
+	"fullcycle-auction_go/internal/metrics"
+	"go.mongodb.org/mongo-driver/event"
+	"time"
+)
+
+var (
+	repositoryCallDuration = metrics.Default.NewHistogramVec(
+		"repository_call_duration_seconds", "Latency of repository calls.", nil, "repository", "method")
+
+	poolConnectionsOpen = metrics.Default.NewGauge(
+		"mongodb_pool_connections_open", "Connections currently open in the MongoDB pool.")
+	poolConnectionsInUse = metrics.Default.NewGauge(
+		"mongodb_pool_connections_in_use", "Connections currently checked out of the MongoDB pool.")
+	poolCheckoutFailures = metrics.Default.NewCounter(
+		"mongodb_pool_checkout_failures_total", "Requests that could not get a connection from the MongoDB pool.")
+	poolCleared = metrics.Default.NewCounter(
+		"mongodb_pool_cleared_total", "Times the MongoDB pool was cleared after a server error.")
+)
+
+// ObserveCall records the latency of a repository method, meant to be
+// deferred with the time the call started.
+func ObserveCall(repository, method string, start time.Time) {
+	repositoryCallDuration.ObserveSince(start, repository, method)
+}
+
+// NewPoolMonitor keeps the connection pool gauges up to date.
+func NewPoolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(poolEvent *event.PoolEvent) {
+			switch poolEvent.Type {
+			case event.ConnectionCreated:
+				poolConnectionsOpen.Inc()
+			case event.ConnectionClosed:
+				poolConnectionsOpen.Dec()
+			case event.GetSucceeded:
+				poolConnectionsInUse.Inc()
+			case event.ConnectionReturned:
+				poolConnectionsInUse.Dec()
+			case event.GetFailed:
+				poolCheckoutFailures.Inc()
+			case event.PoolCleared:
+				poolCleared.Inc()
+			}
+		},
+	}
+}
@@ -0,0 +1,21 @@
+package mongodb
+
+import (
+	"fmt"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// NewReadPreference turns a mode such as secondaryPreferred into a read
+// preference, primary when empty.
+func NewReadPreference(modeName string) (*readpref.ReadPref, error) {
+	if modeName == "" {
+		return readpref.Primary(), nil
+	}
+
+	mode, err := readpref.ModeFromString(modeName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid read preference %q: %w", modeName, err)
+	}
+
+	return readpref.New(mode)
+}
@@ -0,0 +1,57 @@
+package mongodb
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"sync"
+)
+
+var transactionSupport sync.Map
+
+// WithTransaction runs fn inside a multi-document transaction when the server
+// supports it. Standalone servers cannot run transactions, so fn runs without
+// one and the writes are not atomic.
+func WithTransaction(
+	ctx context.Context, database *mongo.Database, fn func(ctx context.Context) error) error {
+	if !SupportsTransactions(ctx, database) {
+		return fn(ctx)
+	}
+
+	session, err := database.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionCtx)
+	})
+
+	return err
+}
+
+func SupportsTransactions(ctx context.Context, database *mongo.Database) bool {
+	client := database.Client()
+	if supported, ok := transactionSupport.Load(client); ok {
+		return supported.(bool)
+	}
+
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := database.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		logger.ErrorContext(ctx, "Error trying to detect mongodb topology", err)
+		return false
+	}
+
+	supported := hello.SetName != "" || hello.Msg == "isdbgrid"
+	if !supported {
+		logger.WarnContext(ctx, "MongoDB is not a replica set, multi-document writes will run without a transaction")
+	}
+
+	transactionSupport.Store(client, supported)
+	return supported
+}
@@ -6,12 +6,13 @@ import (
 )
 
 var (
-	log *zap.Logger
+	log   *zap.Logger
+	level = zap.NewAtomicLevelAt(zap.InfoLevel)
 )
 
 func init() {
 	logConfiguration := zap.Config{
-		Level:    zap.NewAtomicLevelAt(zap.InfoLevel),
+		Level:    level,
 		Encoding: "json",
 		EncoderConfig: zapcore.EncoderConfig{
 			MessageKey:   "message",
@@ -26,11 +27,21 @@ func init() {
 	log, _ = logConfiguration.Build()
 }
 
+// SetLevel changes the lowest level written: debug, info, warn or error.
+func SetLevel(name string) error {
+	return level.UnmarshalText([]byte(name))
+}
+
 func Info(message string, tags ...zap.Field) {
 	log.Info(message, tags...)
 	log.Sync()
 }
 
+func Warn(message string, tags ...zap.Field) {
+	log.Warn(message, tags...)
+	log.Sync()
+}
+
 func Error(message string, err error, tags ...zap.Field) {
 	tags = append(tags, zap.NamedError("error", err))
 	log.Error(message, tags...)
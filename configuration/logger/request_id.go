@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"context"
+	"go.uber.org/zap"
+)
+
+type requestIdKey struct{}
+
+// WithRequestId returns a copy of ctx whose log calls are tagged with
+// requestId.
+func WithRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, requestId)
+}
+
+func RequestId(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	requestId, _ := ctx.Value(requestIdKey{}).(string)
+	return requestId
+}
+
+func InfoContext(ctx context.Context, message string, tags ...zap.Field) {
+	Info(message, withRequestId(ctx, tags)...)
+}
+
+func WarnContext(ctx context.Context, message string, tags ...zap.Field) {
+	Warn(message, withRequestId(ctx, tags)...)
+}
+
+func ErrorContext(ctx context.Context, message string, err error, tags ...zap.Field) {
+	Error(message, err, withRequestId(ctx, tags)...)
+}
+
+func withRequestId(ctx context.Context, tags []zap.Field) []zap.Field {
+	if requestId := RequestId(ctx); requestId != "" {
+		return append(tags, zap.String("request_id", requestId))
+	}
+
+	return tags
+}
@@ -1,20 +1,27 @@
 package rest_err
 
 import (
+	"fmt"
 	"fullcycle-auction_go/internal/internal_error"
 	"net/http"
+	"time"
 )
 
+// RestErr is the body of every error response. Status is the HTTP status it
+// is sent with; Code names the kind of error for clients to switch on.
 type RestErr struct {
-	Message string   `json:"message"`
-	Err     string   `json:"err"`
-	Code    int      `json:"code"`
-	Causes  []Causes `json:"causes"`
+	Status     int      `json:"-"`
+	Code       string   `json:"code"`
+	Message    string   `json:"message"`
+	Details    []Causes `json:"details"`
+	RequestId  string   `json:"request_id,omitempty"`
+	RetryAfter int64    `json:"retry_after_seconds,omitempty"`
 }
 
 type Causes struct {
-	Field   string `json:"field"`
-	Message string `json:"message"`
+	Field      string `json:"field"`
+	Constraint string `json:"constraint,omitempty"`
+	Message    string `json:"message"`
 }
 
 func (r *RestErr) Error() string {
@@ -22,39 +29,116 @@ func (r *RestErr) Error() string {
 }
 
 func ConvertError(internalError *internal_error.InternalError) *RestErr {
+	var restErr *RestErr
 	switch internalError.Err {
-	case "bad_request":
-		return NewBadRequestError(internalError.Error())
-	case "not_found":
-		return NewNotFoundError(internalError.Error())
+	case internal_error.ErrBadRequest:
+		restErr = NewBadRequestError(internalError.Error())
+	case internal_error.ErrValidation:
+		restErr = NewUnprocessableEntityError(internalError.Error())
+	case internal_error.ErrNotFound:
+		restErr = NewNotFoundError(internalError.Error())
+	case internal_error.ErrConflict, internal_error.ErrVersionConflict:
+		restErr = NewConflictError(internalError.Error())
+	case internal_error.ErrForbidden:
+		restErr = NewForbiddenError(internalError.Error())
+	case internal_error.ErrUnauthorized:
+		restErr = NewUnauthorizedError(internalError.Error())
+	case internal_error.ErrTooManyRequests:
+		restErr = NewTooManyRequestsError(internalError.Error(), internalError.RetryAfter)
 	default:
-		return NewInternalServerError(internalError.Error())
+		restErr = NewInternalServerError(internalError.Error())
 	}
+
+	for _, failure := range internalError.Failures {
+		field := failure.Field
+		if field == "" {
+			field = fmt.Sprintf("[%d]", failure.Index)
+		}
+
+		restErr.Details = append(restErr.Details, Causes{
+			Field:   field,
+			Message: failure.Message,
+		})
+	}
+
+	for _, violation := range internalError.Violations {
+		restErr.Details = append(restErr.Details, Causes{
+			Field:      violation.Field,
+			Constraint: violation.Constraint,
+			Message:    violation.Message,
+		})
+	}
+
+	return restErr
 }
 
 func NewBadRequestError(message string, causes ...Causes) *RestErr {
 	return &RestErr{
+		Status:  http.StatusBadRequest,
+		Code:    internal_error.ErrBadRequest,
 		Message: message,
-		Err:     "bad_request",
-		Code:    http.StatusBadRequest,
-		Causes:  causes,
+		Details: causes,
+	}
+}
+
+// NewUnprocessableEntityError answers a well-formed request whose fields
+// break validation rules, each of them listed in details.
+func NewUnprocessableEntityError(message string, causes ...Causes) *RestErr {
+	return &RestErr{
+		Status:  http.StatusUnprocessableEntity,
+		Code:    internal_error.ErrValidation,
+		Message: message,
+		Details: causes,
 	}
 }
 
 func NewInternalServerError(message string) *RestErr {
 	return &RestErr{
+		Status:  http.StatusInternalServerError,
+		Code:    internal_error.ErrInternalServer,
 		Message: message,
-		Err:     "internal_server",
-		Code:    http.StatusInternalServerError,
-		Causes:  nil,
 	}
 }
 
 func NewNotFoundError(message string) *RestErr {
 	return &RestErr{
+		Status:  http.StatusNotFound,
+		Code:    internal_error.ErrNotFound,
+		Message: message,
+	}
+}
+
+func NewConflictError(message string) *RestErr {
+	return &RestErr{
+		Status:  http.StatusConflict,
+		Code:    internal_error.ErrConflict,
+		Message: message,
+	}
+}
+
+func NewUnauthorizedError(message string) *RestErr {
+	return &RestErr{
+		Status:  http.StatusUnauthorized,
+		Code:    internal_error.ErrUnauthorized,
 		Message: message,
-		Err:     "not_found",
-		Code:    http.StatusNotFound,
-		Causes:  nil,
+	}
+}
+
+func NewForbiddenError(message string) *RestErr {
+	return &RestErr{
+		Status:  http.StatusForbidden,
+		Code:    internal_error.ErrForbidden,
+		Message: message,
+	}
+}
+
+// NewTooManyRequestsError rounds retryAfter up to whole seconds, the unit of
+// the Retry-After header.
+func NewTooManyRequestsError(message string, retryAfter time.Duration) *RestErr {
+	return &RestErr{
+		Status:     http.StatusTooManyRequests,
+		Code:       internal_error.ErrTooManyRequests,
+		Message:    message,
+		RetryAfter: int64((retryAfter + time.Second - 1) / time.Second),
 	}
 }
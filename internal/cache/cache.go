@@ -0,0 +1,11 @@
+package cache
+
+import "context"
+
+// Cache keeps values by key for a while. A value may be gone at any time, so
+// Get reports whether it found one.
+type Cache interface {
+	Get(ctx context.Context, key string) (interface{}, bool)
+	Set(ctx context.Context, key string, value interface{})
+	Delete(ctx context.Context, key string)
+}
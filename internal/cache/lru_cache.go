@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"fullcycle-auction_go/internal/clock"
+	"sync"
+	"time"
+)
+
+// LRUCache keeps up to size values in the process for ttl each, dropping the
+// least recently used one when it is full.
+type LRUCache struct {
+	clock clock.Clock
+	size  int
+	ttl   time.Duration
+
+	mutex sync.Mutex
+	items map[string]*list.Element
+	order *list.List
+}
+
+type entry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+func NewLRUCache(size int, ttl time.Duration, cacheClock clock.Clock) *LRUCache {
+	if size < 1 {
+		size = 1
+	}
+
+	return &LRUCache{
+		clock: cacheClock,
+		size:  size,
+		ttl:   ttl,
+		items: make(map[string]*list.Element),
+		order: list.New(),
+	}
+}
+
+func (c *LRUCache) Get(ctx context.Context, key string) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	cached := element.Value.(*entry)
+	if !c.clock.Now().Before(cached.expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return cached.value, true
+}
+
+func (c *LRUCache) Set(ctx context.Context, key string, value interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	expiresAt := c.clock.Now().Add(c.ttl)
+	if element, ok := c.items[key]; ok {
+		element.Value = &entry{key: key, value: value, expiresAt: expiresAt}
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *LRUCache) Delete(ctx context.Context, key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.remove(element)
+	}
+}
+
+func (c *LRUCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *LRUCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.items, element.Value.(*entry).key)
+}
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLRUCacheDropsTheLeastRecentlyUsedValue(t *testing.T) {
+	ctx := context.Background()
+	lruCache := NewLRUCache(2, time.Minute, fakeclock.New(time.Now()))
+
+	lruCache.Set(ctx, "first", 1)
+	lruCache.Set(ctx, "second", 2)
+	_, ok := lruCache.Get(ctx, "first")
+	assert.True(t, ok)
+
+	lruCache.Set(ctx, "third", 3)
+
+	_, ok = lruCache.Get(ctx, "second")
+	assert.False(t, ok)
+	value, ok := lruCache.Get(ctx, "first")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, 2, lruCache.Len())
+}
+
+func TestLRUCacheExpiresValuesAfterTheirTTL(t *testing.T) {
+	ctx := context.Background()
+	fakeClock := fakeclock.New(time.Now())
+	lruCache := NewLRUCache(10, time.Minute, fakeClock)
+
+	lruCache.Set(ctx, "user", "ana")
+	fakeClock.Advance(59 * time.Second)
+	_, ok := lruCache.Get(ctx, "user")
+	assert.True(t, ok)
+
+	fakeClock.Advance(time.Second)
+	_, ok = lruCache.Get(ctx, "user")
+	assert.False(t, ok)
+	assert.Equal(t, 0, lruCache.Len())
+
+	lruCache.Set(ctx, "user", "ana")
+	lruCache.Delete(ctx, "user")
+	_, ok = lruCache.Get(ctx, "user")
+	assert.False(t, ok)
+}
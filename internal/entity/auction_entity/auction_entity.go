@@ -3,13 +3,86 @@ package auction_entity
 import (
 	"context"
 	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
 	"github.com/google/uuid"
 	"time"
 )
 
+type AuctionOption func(auction *Auction)
+
+func WithDuration(duration time.Duration) AuctionOption {
+	return func(auction *Auction) {
+		auction.Duration = duration
+	}
+}
+
+func WithStartTime(startTime time.Time) AuctionOption {
+	return func(auction *Auction) {
+		auction.StartTime = startTime
+	}
+}
+
+func WithIdempotencyKey(idempotencyKey string) AuctionOption {
+	return func(auction *Auction) {
+		auction.IdempotencyKey = idempotencyKey
+	}
+}
+
+func WithSellerId(sellerId string) AuctionOption {
+	return func(auction *Auction) {
+		auction.SellerId = sellerId
+	}
+}
+
+func WithMinIncrement(minIncrement money.Amount) AuctionOption {
+	return func(auction *Auction) {
+		auction.MinIncrement = minIncrement
+	}
+}
+
+func WithStartingPrice(startingPrice money.Amount) AuctionOption {
+	return func(auction *Auction) {
+		auction.StartingPrice = startingPrice
+	}
+}
+
+func WithReservePrice(reservePrice money.Amount) AuctionOption {
+	return func(auction *Auction) {
+		auction.ReservePrice = reservePrice
+	}
+}
+
+func WithBuyNowPrice(buyNowPrice money.Amount) AuctionOption {
+	return func(auction *Auction) {
+		auction.BuyNowPrice = buyNowPrice
+	}
+}
+
+func WithAuctionType(auctionType AuctionType) AuctionOption {
+	return func(auction *Auction) {
+		if auctionType != "" {
+			auction.Type = auctionType
+		}
+	}
+}
+
+// WithDescendingPrice configures a dutch auction, whose price starts at
+// startPrice and drops by decrement at every interval until it reaches
+// floorPrice.
+func WithDescendingPrice(
+	startPrice, floorPrice, decrement money.Amount, interval time.Duration) AuctionOption {
+	return func(auction *Auction) {
+		auction.StartPrice = startPrice
+		auction.FloorPrice = floorPrice
+		auction.PriceDecrement = decrement
+		auction.DecrementInterval = interval
+	}
+}
+
 func CreateAuction(
 	productName, category, description string,
-	condition ProductCondition) (*Auction, *internal_error.InternalError) {
+	condition ProductCondition,
+	opts ...AuctionOption) (*Auction, *internal_error.InternalError) {
 	auction := &Auction{
 		Id:          uuid.New().String(),
 		ProductName: productName,
@@ -17,9 +90,22 @@ func CreateAuction(
 		Description: description,
 		Condition:   condition,
 		Status:      Active,
+		Type:        OpenAuction,
 		Timestamp:   time.Now(),
 	}
 
+	for _, opt := range opts {
+		opt(auction)
+	}
+
+	if auction.StartTime.After(auction.Timestamp) {
+		auction.Status = Scheduled
+	}
+
+	if auction.IsDutch() {
+		auction.CurrentPrice = auction.StartPrice
+	}
+
 	if err := auction.Validate(); err != nil {
 		return nil, err
 	}
@@ -28,33 +114,351 @@ func CreateAuction(
 }
 
 func (au *Auction) Validate() *internal_error.InternalError {
-	if len(au.ProductName) <= 1 ||
-		len(au.Category) <= 2 ||
-		len(au.Description) <= 10 && (au.Condition != New &&
-			au.Condition != Refurbished &&
-			au.Condition != Used) {
-		return internal_error.NewBadRequestError("invalid auction object")
+	if violations := au.Violations(); len(violations) > 0 {
+		return internal_error.NewValidationError("Invalid auction", violations)
 	}
 
 	return nil
 }
 
+// Violations lists every rule the auction breaks, named after the fields of
+// the API, so callers can report them all in one answer.
+func (au *Auction) Violations() []internal_error.FieldViolation {
+	var violations []internal_error.FieldViolation
+	violate := func(field, constraint, message string) {
+		violations = append(violations, internal_error.FieldViolation{
+			Field: field, Constraint: constraint, Message: message})
+	}
+
+	if len(au.ProductName) <= 1 {
+		violate("product_name", "min", "product_name must have at least 2 characters")
+	}
+	if len(au.Category) <= 2 {
+		violate("category", "min", "category must have at least 3 characters")
+	}
+	if len(au.Description) < 10 {
+		violate("description", "min", "description must have at least 10 characters")
+	}
+	if au.Condition != 0 && !au.Condition.IsValid() {
+		violate("condition", "oneof", "condition must be 1 (new), 2 (used) or 3 (refurbished)")
+	}
+	if au.Type != OpenAuction && au.Type != SealedAuction && au.Type != DutchAuction {
+		violate("auction_type", "oneof", "auction_type must be open, sealed or dutch")
+	}
+	if au.Duration < 0 {
+		violate("duration_seconds", "min", "duration_seconds cannot be negative")
+	}
+
+	for _, price := range []struct {
+		field  string
+		amount money.Amount
+	}{
+		{"min_increment", au.MinIncrement},
+		{"starting_price", au.StartingPrice},
+		{"reserve_price", au.ReservePrice},
+		{"buy_now_price", au.BuyNowPrice},
+	} {
+		if price.amount < 0 {
+			violate(price.field, "min", price.field+" cannot be negative")
+		} else if price.amount > 0 && au.IsDutch() && price.field != "min_increment" {
+			violate(price.field, "excluded", "dutch auctions are priced with start_price and floor_price")
+		}
+	}
+
+	if au.BuyNowPrice > 0 && au.BuyNowPrice < au.ReservePrice {
+		violate("buy_now_price", "gtefield", "Buy now price cannot be lower than the reserve price")
+	}
+	if au.BuyNowPrice > 0 && au.BuyNowPrice < au.StartingPrice {
+		violate("buy_now_price", "gtefield", "Buy now price cannot be lower than the starting price")
+	}
+
+	if au.IsDutch() {
+		if au.StartPrice <= 0 {
+			violate("start_price", "required", "dutch auctions need a start_price")
+		}
+		if au.FloorPrice < 0 {
+			violate("floor_price", "min", "floor_price cannot be negative")
+		} else if au.StartPrice > 0 && au.FloorPrice >= au.StartPrice {
+			violate("floor_price", "ltfield", "floor_price must be lower than start_price")
+		}
+		if au.PriceDecrement <= 0 {
+			violate("price_decrement", "required", "dutch auctions need a price_decrement")
+		}
+		if au.DecrementInterval <= 0 {
+			violate("decrement_interval_seconds", "required", "dutch auctions need a decrement_interval_seconds")
+		}
+	}
+
+	return violations
+}
+
 type Auction struct {
 	Id          string
+	SellerId    string
 	ProductName string
 	Category    string
 	Description string
 	Condition   ProductCondition
 	Status      AuctionStatus
+	Type        AuctionType
 	Timestamp   time.Time
+	StartTime   time.Time
+	EndTime     time.Time
+	Duration    time.Duration
+	Extension   time.Duration
+	// SnipeExtension is the time late bids added. It does not count
+	// against the cap on manual extensions.
+	SnipeExtension time.Duration
+	ClosedAt       time.Time
+	CloseReason    string
+	DeletedAt      time.Time
+	Version        int64
+
+	MinIncrement         money.Amount
+	StartingPrice        money.Amount
+	CurrentHighestAmount money.Amount
+	CurrentHighestUserId string
+	ReservePrice         money.Amount
+	ReserveMet           *bool
+	BuyNowPrice          money.Amount
+	WinnerUserId         string
+	WinningAmount        money.Amount
+	WinnerResolved       bool
+	BidCount             int64
+
+	StartPrice        money.Amount
+	FloorPrice        money.Amount
+	PriceDecrement    money.Amount
+	DecrementInterval time.Duration
+	CurrentPrice      money.Amount
+
+	IdempotencyKey string
+}
+
+// MinimumBid is the lowest amount a bid stepping over the current highest one
+// by step may offer, never less than the starting price.
+func (au *Auction) MinimumBid(step money.Amount) money.Amount {
+	minimum := au.CurrentHighestAmount + step
+	if minimum < au.StartingPrice {
+		return au.StartingPrice
+	}
+
+	return minimum
+}
+
+// IsBuyNow reports whether amount buys the auction outright. Buy-now is no
+// longer on offer once the leading bid has reached the buy-now price, so such
+// an auction only takes ordinary bids.
+func (au *Auction) IsBuyNow(amount money.Amount) bool {
+	return au.BuyNowPrice > 0 && amount >= au.BuyNowPrice && au.CurrentHighestAmount < au.BuyNowPrice
+}
+
+func (au *Auction) IsSealed() bool {
+	return au.Type == SealedAuction
+}
+
+// HidesBids reports whether the amounts bid so far must stay hidden, which is
+// the case for a sealed auction until it closes.
+func (au *Auction) HidesBids() bool {
+	return au.IsSealed() && au.Status != Completed
+}
+
+func (au *Auction) IsDutch() bool {
+	return au.Type == DutchAuction
+}
+
+// PriceAt is the asking price of a dutch auction at the given time. It only
+// depends on the opening time, so it can be recomputed after a restart.
+func (au *Auction) PriceAt(at time.Time) money.Amount {
+	drops := au.priceDropsAt(at)
+	if maxDrops := int64((au.StartPrice - au.FloorPrice) / au.PriceDecrement); drops > maxDrops {
+		return au.FloorPrice
+	}
+
+	price := au.StartPrice - money.Amount(drops)*au.PriceDecrement
+	if price < au.FloorPrice {
+		return au.FloorPrice
+	}
+
+	return price
+}
+
+// NextPriceDropAt returns when the price of a dutch auction drops next, or the
+// zero time once it has reached the floor.
+func (au *Auction) NextPriceDropAt(after time.Time) time.Time {
+	if au.PriceAt(after) <= au.FloorPrice {
+		return time.Time{}
+	}
+
+	return au.OpensAt().Add(time.Duration(au.priceDropsAt(after)+1) * au.DecrementInterval)
+}
+
+func (au *Auction) priceDropsAt(at time.Time) int64 {
+	if au.DecrementInterval <= 0 || au.PriceDecrement <= 0 || at.Before(au.OpensAt()) {
+		return 0
+	}
+
+	return int64(at.Sub(au.OpensAt()) / au.DecrementInterval)
+}
+
+func (au *Auction) HasReserve() bool {
+	return au.ReservePrice > 0
+}
+
+func (au *Auction) IsReserveMet() bool {
+	if au.ReserveMet != nil {
+		return *au.ReserveMet
+	}
+
+	return au.CurrentHighestAmount >= au.ReservePrice
+}
+
+type FindAuctionsOptions struct {
+	Page           int
+	PageSize       int
+	UseCursor      bool
+	Cursor         string
+	Sort           string
+	Query          string
+	CreatedFrom    time.Time
+	CreatedTo      time.Time
+	IncludeDeleted bool
+}
+
+type AuctionPage struct {
+	Auctions   []Auction
+	Total      int64
+	NextCursor string
+}
+
+type AuctionSummary struct {
+	Id            string
+	ProductName   string
+	Category      string
+	Status        AuctionStatus
+	Type          AuctionType
+	EndTime       time.Time
+	StartingPrice money.Amount
+	HighestBid    *money.Amount
+	BidCount      int64
+}
+
+type AuctionSummaryPage struct {
+	Summaries  []AuctionSummary
+	Total      int64
+	NextCursor string
+}
+
+// WatchedAuctionEnding tells a user that an auction they watch is about to end.
+type WatchedAuctionEnding struct {
+	AuctionId string
+	UserId    string
+	EndTime   time.Time
+}
+
+// PlacedBid is what accepting a bid changed. Extended reports that the bid
+// landed inside the snipe window and pushed the end of the auction to EndTime.
+type PlacedBid struct {
+	PreviousLeaderUserId string
+	PreviousAmount       money.Amount
+	Sequence             int64
+	Extended             bool
+	EndTime              time.Time
+}
+
+// SnipeExtension pushes the end of an auction back by Extension when a bid is
+// accepted less than Window before it. A zero Window never extends.
+type SnipeExtension struct {
+	Window    time.Duration
+	Extension time.Duration
+}
+
+type AuctionUpdate struct {
+	ProductName string
+	Category    string
+	Description string
+}
+
+type AuctionStats struct {
+	Total           int64
+	ByStatus        map[AuctionStatus]int64
+	ByCategory      map[string]int64
+	AverageDuration time.Duration
+}
+
+// AuditEntry records an operator action on an auction. There is at most one
+// entry per auction and action, so its id is derived from both.
+type AuditEntry struct {
+	Id             string
+	AuctionId      string
+	Action         string
+	ActorId        string
+	Reason         string
+	PreviousStatus AuctionStatus
+	Timestamp      time.Time
+}
+
+const AuditActionForceClose = "force_close"
+
+// OutboxEvent is an auction event stored in the same write as the change it
+// describes and not handed to the event publisher yet.
+type OutboxEvent struct {
+	Id        string
+	AuctionId string
+	Type      string
+	Timestamp time.Time
+}
+
+func NewAuditEntry(auction Auction, action, actorId, reason string) AuditEntry {
+	return AuditEntry{
+		Id:             auction.Id + ":" + action,
+		AuctionId:      auction.Id,
+		Action:         action,
+		ActorId:        actorId,
+		Reason:         reason,
+		PreviousStatus: auction.Status,
+		Timestamp:      time.Now(),
+	}
 }
 
 type ProductCondition int
+
+func (pc ProductCondition) IsValid() bool {
+	return pc == New || pc == Used || pc == Refurbished
+}
+
 type AuctionStatus int
+type AuctionType string
+
+func (au *Auction) OpensAt() time.Time {
+	if au.StartTime.IsZero() {
+		return au.Timestamp
+	}
+
+	return au.StartTime
+}
 
 const (
 	Active AuctionStatus = iota
 	Completed
+	Scheduled
+	Cancelled
+	Paused
+)
+
+const (
+	OpenAuction   AuctionType = "open"
+	SealedAuction AuctionType = "sealed"
+	DutchAuction  AuctionType = "dutch"
+)
+
+const (
+	CloseReasonExpired   = "expired"
+	CloseReasonManual    = "manual"
+	CloseReasonCancelled = "cancelled"
+	CloseReasonBuyNow    = "buy_now"
+	CloseReasonAccepted  = "price_accepted"
+	CloseReasonForced    = "forced"
 )
 
 const (
@@ -68,14 +472,141 @@ type AuctionRepositoryInterface interface {
 		ctx context.Context,
 		auctionEntity *Auction) *internal_error.InternalError
 
+	CreateAuctions(
+		ctx context.Context,
+		auctionEntities []*Auction) *internal_error.InternalError
+
 	FindAuctions(
 		ctx context.Context,
 		status AuctionStatus,
-		category, productName string) ([]Auction, *internal_error.InternalError)
+		category, productName string,
+		options FindAuctionsOptions) (*AuctionPage, *internal_error.InternalError)
+
+	FindAuctionSummaries(
+		ctx context.Context,
+		status AuctionStatus,
+		category, productName string,
+		options FindAuctionsOptions) (*AuctionSummaryPage, *internal_error.InternalError)
 
 	FindAuctionById(
 		ctx context.Context, id string) (*Auction, *internal_error.InternalError)
 
+	FindAuctionByIdFromPrimary(
+		ctx context.Context, id string) (*Auction, *internal_error.InternalError)
+
+	GetAuctionStats(
+		ctx context.Context) (*AuctionStats, *internal_error.InternalError)
+
+	FindAuctionsBySeller(
+		ctx context.Context,
+		sellerId string,
+		statusFilter []AuctionStatus) ([]Auction, *internal_error.InternalError)
+
+	FindAuctionsExpiringSoon(
+		ctx context.Context,
+		within time.Duration,
+		limit int) ([]Auction, *internal_error.InternalError)
+
+	FindAuctionsByIds(
+		ctx context.Context, auctionIds []string) ([]Auction, *internal_error.InternalError)
+
+	AddToWatchlist(
+		ctx context.Context, userId, auctionId string) *internal_error.InternalError
+
+	RemoveFromWatchlist(
+		ctx context.Context, userId, auctionId string) *internal_error.InternalError
+
+	FindWatchedAuctions(
+		ctx context.Context, userId string) ([]AuctionSummary, *internal_error.InternalError)
+
+	ClaimEndingWatches(
+		ctx context.Context, within time.Duration) ([]WatchedAuctionEnding, *internal_error.InternalError)
+
 	FindOpenAuctions(
 		ctx context.Context) ([]Auction, *internal_error.InternalError)
+
+	BuyNow(
+		ctx context.Context,
+		auctionId, bidId, userId string,
+		amount money.Amount) (*Auction, int64, *internal_error.InternalError)
+
+	AcceptDutchPrice(
+		ctx context.Context,
+		auctionId, bidId, userId string,
+		amount money.Amount) (*Auction, int64, *internal_error.InternalError)
+
+	GetAuctionSeller(
+		ctx context.Context, auctionId string) (string, *internal_error.InternalError)
+
+	PlaceHighestBid(
+		ctx context.Context,
+		auctionId, userId string,
+		amount, minIncrement money.Amount,
+		snipeExtension SnipeExtension) (PlacedBid, *internal_error.InternalError)
+
+	ReplaceHighestBid(
+		ctx context.Context,
+		auctionId, fromUserId string,
+		fromAmount money.Amount,
+		toUserId string,
+		toAmount money.Amount) *internal_error.InternalError
+
+	ForEachOpenAuction(
+		ctx context.Context,
+		fn func(Auction) error) *internal_error.InternalError
+
+	CloseAuctionById(
+		ctx context.Context,
+		auctionId string,
+		expectedVersion int64) *internal_error.InternalError
+
+	CancelAuction(
+		ctx context.Context,
+		auctionId, reason string,
+		expectedVersion int64) *internal_error.InternalError
+
+	ForceCloseAuction(
+		ctx context.Context,
+		auctionId string,
+		expectedVersion int64,
+		auditEntry AuditEntry) (*Auction, *internal_error.InternalError)
+
+	FindAuditEntry(
+		ctx context.Context, auctionId, action string) (*AuditEntry, *internal_error.InternalError)
+
+	ExtendAuction(
+		ctx context.Context,
+		auctionEntity Auction,
+		extra time.Duration) (*Auction, *internal_error.InternalError)
+
+	UpdateAuction(
+		ctx context.Context,
+		auctionId string,
+		fields AuctionUpdate) (*Auction, *internal_error.InternalError)
+
+	SoftDeleteAuction(
+		ctx context.Context, auctionId string) *internal_error.InternalError
+
+	PauseAuction(
+		ctx context.Context, auctionId string) *internal_error.InternalError
+
+	ResumeAuction(
+		ctx context.Context, auctionId string) (*Auction, *internal_error.InternalError)
+
+	RecordSealedBid(
+		ctx context.Context,
+		auctionId string,
+		snipeExtension SnipeExtension) (PlacedBid, *internal_error.InternalError)
+
+	DecrementBidCount(
+		ctx context.Context, auctionId string) *internal_error.InternalError
+
+	ReconcileBidCounts(
+		ctx context.Context) (int64, *internal_error.InternalError)
+
+	FindUnpublishedEvents(
+		ctx context.Context, limit int64) ([]OutboxEvent, *internal_error.InternalError)
+
+	MarkEventPublished(
+		ctx context.Context, eventId string) *internal_error.InternalError
 }
@@ -0,0 +1,78 @@
+package auction_entity
+
+import (
+	"fullcycle-auction_go/internal/money"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDutchAuctionPriceFollowsTheScheduleFromItsOpening(t *testing.T) {
+	opensAt := time.Now().Add(time.Hour)
+	auction, err := CreateAuction(
+		"bicycle",
+		"sports",
+		"road bicycle in good shape",
+		Used,
+		WithAuctionType(DutchAuction),
+		WithStartTime(opensAt),
+		WithDescendingPrice(10000, 7000, 1250, time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, money.Amount(10000), auction.CurrentPrice)
+
+	tests := []struct {
+		at       time.Duration
+		price    money.Amount
+		nextDrop time.Duration
+	}{
+		{at: -time.Minute, price: 10000, nextDrop: time.Minute},
+		{at: 0, price: 10000, nextDrop: time.Minute},
+		{at: 90 * time.Second, price: 8750, nextDrop: 2 * time.Minute},
+		{at: 2 * time.Minute, price: 7500, nextDrop: 3 * time.Minute},
+		{at: 3 * time.Minute, price: 7000},
+		{at: 24 * time.Hour, price: 7000},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.price, auction.PriceAt(opensAt.Add(tt.at)), tt.at)
+
+		nextDrop := auction.NextPriceDropAt(opensAt.Add(tt.at))
+		if tt.nextDrop == 0 {
+			assert.True(t, nextDrop.IsZero(), tt.at)
+		} else {
+			assert.Equal(t, opensAt.Add(tt.nextDrop), nextDrop, tt.at)
+		}
+	}
+}
+
+func TestDutchAuctionRequiresADescendingPrice(t *testing.T) {
+	_, err := CreateAuction(
+		"bicycle",
+		"sports",
+		"road bicycle in good shape",
+		Used,
+		WithAuctionType(DutchAuction),
+		WithDescendingPrice(5000, 7000, 100, time.Minute))
+	assert.NotNil(t, err)
+
+	_, err = CreateAuction(
+		"bicycle",
+		"sports",
+		"road bicycle in good shape",
+		Used,
+		WithAuctionType(DutchAuction),
+		WithDescendingPrice(10000, 7000, 100, 0))
+	assert.NotNil(t, err)
+}
+
+func TestCreateAuctionReportsEveryViolation(t *testing.T) {
+	_, err := CreateAuction("", "sports", "road bicycle in good shape", ProductCondition(9))
+	assert.NotNil(t, err)
+	assert.True(t, err.IsValidation())
+
+	var fields []string
+	for _, violation := range err.Violations {
+		fields = append(fields, violation.Field+":"+violation.Constraint)
+	}
+	assert.Equal(t, []string{"product_name:min", "condition:oneof"}, fields)
+}
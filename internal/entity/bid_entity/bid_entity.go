@@ -3,6 +3,7 @@ package bid_entity
 import (
 	"context"
 	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
 	"github.com/google/uuid"
 	"time"
 )
@@ -11,17 +12,58 @@ type Bid struct {
 	Id        string
 	UserId    string
 	AuctionId string
-	Amount    float64
+	Amount    money.Amount
 	Timestamp time.Time
+	Sequence  int64
+	Auto      bool
+	Retracted bool
+	Voided    bool
+
+	IdempotencyKey string
+}
+
+type MaxBid struct {
+	UserId    string
+	AuctionId string
+	MaxAmount money.Amount
+	Timestamp time.Time
+}
+
+const (
+	BidOrderAmount        = "amount"
+	BidOrderChronological = "chronological"
+	BidOrderNewest        = "newest"
+)
+
+type FindBidsOptions struct {
+	Limit  int64
+	Offset int64
+	Order  string
+	UserId string
 }
 
-func CreateBid(userId, auctionId string, amount float64) (*Bid, *internal_error.InternalError) {
+// BidIdempotencyRecord is what an earlier request with the same idempotency
+// key produced. Bid is nil when that request was accepted without placing a
+// bid, and Completed is false while it is still being processed.
+type BidIdempotencyRecord struct {
+	Completed bool
+	Bid       *Bid
+}
+
+type BidPage struct {
+	Bids  []Bid
+	Total int64
+}
+
+// CreateBid creates a bid accepted at timestamp.
+func CreateBid(
+	userId, auctionId string, amount money.Amount, timestamp time.Time) (*Bid, *internal_error.InternalError) {
 	bid := &Bid{
 		Id:        uuid.New().String(),
 		UserId:    userId,
 		AuctionId: auctionId,
 		Amount:    amount,
-		Timestamp: time.Now(),
+		Timestamp: timestamp,
 	}
 
 	if err := bid.Validate(); err != nil {
@@ -32,25 +74,86 @@ func CreateBid(userId, auctionId string, amount float64) (*Bid, *internal_error.
 }
 
 func (b *Bid) Validate() *internal_error.InternalError {
-	if err := uuid.Validate(b.UserId); err != nil {
-		return internal_error.NewBadRequestError("UserId is not a valid id")
-	} else if err := uuid.Validate(b.AuctionId); err != nil {
-		return internal_error.NewBadRequestError("AuctionId is not a valid id")
-	} else if b.Amount <= 0 {
-		return internal_error.NewBadRequestError("Amount is not a valid value")
+	if violations := b.Violations(); len(violations) > 0 {
+		return internal_error.NewValidationError("Invalid bid", violations)
 	}
 
 	return nil
 }
 
+// Violations lists every rule the bid breaks, named after the fields of the
+// API.
+func (b *Bid) Violations() []internal_error.FieldViolation {
+	var violations []internal_error.FieldViolation
+	if err := uuid.Validate(b.UserId); err != nil {
+		violations = append(violations, internal_error.FieldViolation{
+			Field: "user_id", Constraint: "uuid", Message: "user_id is not a valid id"})
+	}
+	if err := uuid.Validate(b.AuctionId); err != nil {
+		violations = append(violations, internal_error.FieldViolation{
+			Field: "auction_id", Constraint: "uuid", Message: "auction_id is not a valid id"})
+	}
+	if b.Amount <= 0 {
+		violations = append(violations, internal_error.FieldViolation{
+			Field: "amount", Constraint: "gt", Message: "amount must be greater than zero"})
+	}
+
+	return violations
+}
+
 type BidEntityRepository interface {
 	CreateBid(
 		ctx context.Context,
 		bidEntities []Bid) *internal_error.InternalError
 
 	FindBidByAuctionId(
-		ctx context.Context, auctionId string) ([]Bid, *internal_error.InternalError)
+		ctx context.Context,
+		auctionId string,
+		options FindBidsOptions) (*BidPage, *internal_error.InternalError)
+
+	FindBidsByUserId(
+		ctx context.Context,
+		userId string,
+		options FindBidsOptions) (*BidPage, *internal_error.InternalError)
+
+	ForEachBidByAuctionId(
+		ctx context.Context,
+		auctionId string,
+		fn func(Bid) error) *internal_error.InternalError
 
 	FindWinningBidByAuctionId(
 		ctx context.Context, auctionId string) (*Bid, *internal_error.InternalError)
+
+	FindHighestBidByAuctionId(
+		ctx context.Context, auctionId string) (*Bid, *internal_error.InternalError)
+
+	FindBidById(
+		ctx context.Context, bidId string) (*Bid, *internal_error.InternalError)
+
+	RetractBid(
+		ctx context.Context, bid Bid) *internal_error.InternalError
+
+	FindAuctionIdsByUserId(
+		ctx context.Context, userId string) ([]string, *internal_error.InternalError)
+
+	VoidBidsByUserId(
+		ctx context.Context, userId string, auctionIds []string) *internal_error.InternalError
+
+	SaveMaxBid(
+		ctx context.Context, maxBid MaxBid) *internal_error.InternalError
+
+	FindMaxBidsByAuctionId(
+		ctx context.Context, auctionId string) ([]MaxBid, *internal_error.InternalError)
+
+	DeleteMaxBid(
+		ctx context.Context, auctionId, userId string) *internal_error.InternalError
+
+	ReserveIdempotencyKey(
+		ctx context.Context, userId, idempotencyKey string) (*BidIdempotencyRecord, *internal_error.InternalError)
+
+	CompleteIdempotencyKey(
+		ctx context.Context, userId, idempotencyKey string, bid *Bid) *internal_error.InternalError
+
+	ReleaseIdempotencyKey(
+		ctx context.Context, userId, idempotencyKey string) *internal_error.InternalError
 }
@@ -0,0 +1,107 @@
+package event_entity
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/money"
+	"time"
+)
+
+const (
+	OutbidEventName               = "outbid"
+	AuctionWonEventName           = "auction_won"
+	WatchedAuctionEndingEventName = "watched_auction_ending"
+	BidAcceptedEventName          = "bid_accepted"
+	AuctionExtendedEventName      = "auction_extended"
+	AuctionClosedEventName        = "auction_closed"
+	AuctionCreatedEventName       = "auction_created"
+)
+
+type Event interface {
+	Name() string
+}
+
+type OutbidEvent struct {
+	AuctionId            string       `json:"auction_id"`
+	PreviousLeaderUserId string       `json:"previous_leader_user_id"`
+	NewAmount            money.Amount `json:"new_amount"`
+	Timestamp            time.Time    `json:"timestamp"`
+}
+
+func (OutbidEvent) Name() string {
+	return OutbidEventName
+}
+
+type AuctionWonEvent struct {
+	AuctionId    string       `json:"auction_id"`
+	WinnerUserId string       `json:"winner_user_id"`
+	Amount       money.Amount `json:"amount"`
+	Timestamp    time.Time    `json:"timestamp"`
+}
+
+func (AuctionWonEvent) Name() string {
+	return AuctionWonEventName
+}
+
+type WatchedAuctionEndingEvent struct {
+	AuctionId string    `json:"auction_id"`
+	UserId    string    `json:"user_id"`
+	EndTime   time.Time `json:"end_time"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func (WatchedAuctionEndingEvent) Name() string {
+	return WatchedAuctionEndingEventName
+}
+
+// BidAcceptedEvent leaves the bidder and amount empty on sealed auctions.
+type BidAcceptedEvent struct {
+	AuctionId string       `json:"auction_id"`
+	BidId     string       `json:"bid_id"`
+	UserId    string       `json:"user_id,omitempty"`
+	Amount    money.Amount `json:"amount,omitempty"`
+	Auto      bool         `json:"auto,omitempty"`
+	Timestamp time.Time    `json:"timestamp"`
+}
+
+func (BidAcceptedEvent) Name() string {
+	return BidAcceptedEventName
+}
+
+type AuctionExtendedEvent struct {
+	AuctionId string    `json:"auction_id"`
+	EndTime   time.Time `json:"end_time"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func (AuctionExtendedEvent) Name() string {
+	return AuctionExtendedEventName
+}
+
+// AuctionClosedEvent is published when an auction stops taking bids, either
+// completed or cancelled.
+type AuctionClosedEvent struct {
+	AuctionId     string       `json:"auction_id"`
+	CloseReason   string       `json:"close_reason"`
+	WinnerUserId  string       `json:"winner_user_id,omitempty"`
+	WinningAmount money.Amount `json:"winning_amount,omitempty"`
+	Timestamp     time.Time    `json:"timestamp"`
+}
+
+func (AuctionClosedEvent) Name() string {
+	return AuctionClosedEventName
+}
+
+// AuctionCreatedEvent is relayed from the outbox written together with the
+// auction, so it is published at least once for every stored auction.
+type AuctionCreatedEvent struct {
+	AuctionId string    `json:"auction_id"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func (AuctionCreatedEvent) Name() string {
+	return AuctionCreatedEventName
+}
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event)
+}
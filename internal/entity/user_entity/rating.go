@@ -0,0 +1,40 @@
+package user_entity
+
+import (
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+// Rating is the score the winner of an auction gave its seller. Each auction
+// can be rated once.
+type Rating struct {
+	Id        string
+	AuctionId string
+	RaterId   string
+	SellerId  string
+	Stars     int
+	Comment   string
+	Timestamp time.Time
+}
+
+func NewRating(auctionId, raterId, sellerId string, stars int, comment string) (*Rating, *internal_error.InternalError) {
+	if stars < 1 || stars > 5 {
+		return nil, internal_error.NewFieldBadRequestError(
+			"Invalid rating", "stars", "stars must be between 1 and 5")
+	}
+	if raterId == sellerId {
+		return nil, internal_error.NewBadRequestError("Sellers cannot rate themselves")
+	}
+
+	return &Rating{
+		Id:        uuid.New().String(),
+		AuctionId: auctionId,
+		RaterId:   raterId,
+		SellerId:  sellerId,
+		Stars:     stars,
+		Comment:   strings.TrimSpace(comment),
+		Timestamp: time.Now(),
+	}, nil
+}
@@ -3,14 +3,159 @@ package user_entity
 import (
 	"context"
 	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"time"
 )
 
+type Role string
+
+const (
+	AdminRole  Role = "admin"
+	SellerRole Role = "seller"
+	BuyerRole  Role = "buyer"
+
+	// PartnerRole is held by integrations that bid on behalf of other users.
+	PartnerRole Role = "partner"
+)
+
+func (r Role) IsValid() bool {
+	return r == AdminRole || r == SellerRole || r == BuyerRole || r == PartnerRole
+}
+
 type User struct {
-	Id   string
-	Name string
+	Id           string
+	Name         string
+	Email        string
+	PasswordHash string
+	Role         Role
+	Balance      *money.Amount
+	HeldAmount   money.Amount
+
+	Status         UserStatus
+	StatusReason   string
+	SuspendedUntil time.Time
+
+	NotificationPreferences NotificationPreferences
+	UpdatedAt               time.Time
+
+	AverageRating float64
+	RatingCount   int64
+}
+
+func CreateUser(name, email, password string, role Role) (*User, *internal_error.InternalError) {
+	if role == "" {
+		role = BuyerRole
+	}
+	if !role.IsValid() {
+		return nil, internal_error.NewFieldBadRequestError(
+			"Invalid user role", "role", "role must be admin, seller or buyer")
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, internal_error.NewFieldBadRequestError(
+			"Invalid user password", "password", err.Error())
+	}
+
+	return &User{
+		Id:           uuid.New().String(),
+		Name:         strings.TrimSpace(name),
+		Email:        NormalizeEmail(email),
+		PasswordHash: string(passwordHash),
+		Role:         role,
+		Status:       ActiveStatus,
+
+		NotificationPreferences: DefaultNotificationPreferences(),
+	}, nil
+}
+
+// NormalizeEmail lowercases the address so the unique index treats
+// differently cased spellings as the same account.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func (u *User) HasRole(roles ...Role) bool {
+	for _, role := range roles {
+		if u.Role == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (u *User) PasswordMatches(password string) bool {
+	if u.PasswordHash == "" {
+		return false
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) == nil
+}
+
+// Available returns how much the user can still commit to bids, or nil when
+// the user has no spending limit.
+func (u *User) Available() *money.Amount {
+	if u.Balance == nil {
+		return nil
+	}
+
+	available := *u.Balance - u.HeldAmount
+	if available < 0 {
+		available = 0
+	}
+
+	return &available
 }
 
 type UserRepositoryInterface interface {
+	CreateUser(
+		ctx context.Context, userEntity *User) *internal_error.InternalError
+
+	FindUserByEmail(
+		ctx context.Context, email string) (*User, *internal_error.InternalError)
+
+	SetRole(
+		ctx context.Context, userId string, role Role) *internal_error.InternalError
+
+	SetStatus(
+		ctx context.Context,
+		userId string,
+		status UserStatus,
+		reason string,
+		until time.Time) *internal_error.InternalError
+
 	FindUserById(
 		ctx context.Context, userId string) (*User, *internal_error.InternalError)
+
+	UpdateUser(
+		ctx context.Context,
+		userId string,
+		fields map[string]interface{}) (*User, *internal_error.InternalError)
+
+	Credit(
+		ctx context.Context, userId string, amount money.Amount) *internal_error.InternalError
+
+	Debit(
+		ctx context.Context, userId string, amount money.Amount) *internal_error.InternalError
+
+	HoldFunds(
+		ctx context.Context, userId, auctionId string, amount money.Amount) *internal_error.InternalError
+
+	ReleaseFunds(
+		ctx context.Context, userId, auctionId string, amount money.Amount) *internal_error.InternalError
+
+	CaptureFunds(
+		ctx context.Context, userId, auctionId string, amount money.Amount) *internal_error.InternalError
+
+	RateSeller(
+		ctx context.Context, rating Rating) *internal_error.InternalError
+
+	FindWalletTransactions(
+		ctx context.Context,
+		userId string,
+		limit, offset int64) (*WalletTransactionPage, *internal_error.InternalError)
 }
@@ -0,0 +1,36 @@
+package user_entity
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCreateUserHashesThePassword(t *testing.T) {
+	user, err := CreateUser(" Ana ", "Ana@Example.com ", "s3cret-pass", "")
+	assert.Nil(t, err)
+
+	assert.Equal(t, "Ana", user.Name)
+	assert.Equal(t, "ana@example.com", user.Email)
+	assert.Equal(t, BuyerRole, user.Role)
+	assert.NotEqual(t, "s3cret-pass", user.PasswordHash)
+	assert.True(t, user.PasswordMatches("s3cret-pass"))
+	assert.False(t, user.PasswordMatches("wrong-pass"))
+}
+
+func TestCreateUserRejectsUnknownRoles(t *testing.T) {
+	_, err := CreateUser("Ana", "ana@example.com", "s3cret-pass", "owner")
+	assert.NotNil(t, err)
+	assert.Equal(t, "role", err.Failures[0].Field)
+}
+
+func TestSuspensionLiftsOnceItExpires(t *testing.T) {
+	now := time.Now()
+	user := &User{Status: SuspendedStatus, StatusReason: "chargeback", SuspendedUntil: now.Add(time.Hour)}
+
+	assert.Equal(t, SuspendedStatus, user.StatusAt(now))
+	assert.NotNil(t, user.EnsureCanBid(now))
+
+	assert.Equal(t, ActiveStatus, user.StatusAt(now.Add(time.Hour)))
+	assert.Nil(t, user.EnsureCanBid(now.Add(time.Hour)))
+}
@@ -0,0 +1,23 @@
+package user_entity
+
+// NotificationPreferences says which events the user wants to be told about.
+// Every notification is on until the user turns it off.
+type NotificationPreferences struct {
+	Outbid               bool
+	WatchedAuctionEnding bool
+	AuctionWon           bool
+}
+
+func DefaultNotificationPreferences() NotificationPreferences {
+	return NotificationPreferences{Outbid: true, WatchedAuctionEnding: true, AuctionWon: true}
+}
+
+// Fields UpdateUser accepts. Anything else on the user, such as the role,
+// status or wallet, has its own operation.
+const (
+	NameField                              = "name"
+	EmailField                             = "email"
+	OutbidNotificationsField               = "notification_preferences.outbid"
+	WatchedAuctionEndingNotificationsField = "notification_preferences.watched_auction_ending"
+	AuctionWonNotificationsField           = "notification_preferences.auction_won"
+)
@@ -0,0 +1,48 @@
+package user_entity
+
+import (
+	"fmt"
+	"fullcycle-auction_go/internal/internal_error"
+	"time"
+)
+
+type UserStatus string
+
+const (
+	ActiveStatus    UserStatus = "active"
+	SuspendedStatus UserStatus = "suspended"
+	BannedStatus    UserStatus = "banned"
+)
+
+func (s UserStatus) IsValid() bool {
+	return s == ActiveStatus || s == SuspendedStatus || s == BannedStatus
+}
+
+// StatusAt returns the status the user has at now. A suspension with an
+// expiry lifts by itself once it is reached.
+func (u *User) StatusAt(now time.Time) UserStatus {
+	if u.Status == "" {
+		return ActiveStatus
+	}
+	if u.Status == SuspendedStatus && !u.SuspendedUntil.IsZero() && !now.Before(u.SuspendedUntil) {
+		return ActiveStatus
+	}
+
+	return u.Status
+}
+
+func (u *User) EnsureCanBid(now time.Time) *internal_error.InternalError {
+	switch u.StatusAt(now) {
+	case BannedStatus:
+		return internal_error.NewForbiddenError(fmt.Sprintf("Your account is banned: %s", u.StatusReason))
+	case SuspendedStatus:
+		if u.SuspendedUntil.IsZero() {
+			return internal_error.NewForbiddenError(fmt.Sprintf("Your account is suspended: %s", u.StatusReason))
+		}
+
+		return internal_error.NewForbiddenError(fmt.Sprintf(
+			"Your account is suspended until %s: %s", u.SuspendedUntil.UTC().Format(time.RFC3339), u.StatusReason))
+	}
+
+	return nil
+}
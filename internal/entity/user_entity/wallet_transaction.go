@@ -0,0 +1,45 @@
+package user_entity
+
+import (
+	"fullcycle-auction_go/internal/money"
+	"github.com/google/uuid"
+	"time"
+)
+
+type WalletTransactionType string
+
+const (
+	WalletCredit  WalletTransactionType = "credit"
+	WalletDebit   WalletTransactionType = "debit"
+	WalletHold    WalletTransactionType = "hold"
+	WalletRelease WalletTransactionType = "release"
+	WalletCapture WalletTransactionType = "capture"
+)
+
+// WalletTransaction records one change to a user's wallet. Captures turn a
+// hold into a debit when the user wins the auction it was taken for.
+type WalletTransaction struct {
+	Id        string
+	UserId    string
+	Type      WalletTransactionType
+	Amount    money.Amount
+	AuctionId string
+	Timestamp time.Time
+}
+
+type WalletTransactionPage struct {
+	Transactions []WalletTransaction
+	Total        int64
+}
+
+func NewWalletTransaction(
+	userId, auctionId string, transactionType WalletTransactionType, amount money.Amount) WalletTransaction {
+	return WalletTransaction{
+		Id:        uuid.New().String(),
+		UserId:    userId,
+		Type:      transactionType,
+		Amount:    amount,
+		AuctionId: auctionId,
+		Timestamp: time.Now(),
+	}
+}
@@ -0,0 +1,261 @@
+package grpc_server
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/api/web/live_feed"
+	"fullcycle-auction_go/internal/infra/api/web/validation"
+	"fullcycle-auction_go/internal/money"
+	"fullcycle-auction_go/internal/usecase/auction_usecase"
+	"fullcycle-auction_go/internal/usecase/bid_usecase"
+	auction_v1 "fullcycle-auction_go/proto/auction/v1"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
+)
+
+const minSearchQueryLength = 3
+
+type auctionService struct {
+	auction_v1.UnimplementedAuctionServiceServer
+
+	auctionUseCase auction_usecase.AuctionUseCaseInterface
+	bidUseCase     bid_usecase.BidUseCaseInterface
+	hub            *live_feed.Hub
+}
+
+func (s *auctionService) CreateAuction(
+	ctx context.Context, request *auction_v1.CreateAuctionRequest) (*auction_v1.CreateAuctionResponse, error) {
+	sellerId := userId(ctx)
+	if sellerId == "" {
+		return nil, status.Error(codes.Unauthenticated, "Authentication is required")
+	}
+
+	auctionInput := auction_usecase.AuctionInputDTO{
+		ProductName:       request.ProductName,
+		Category:          request.Category,
+		Description:       request.Description,
+		Condition:         auction_usecase.ProductCondition(request.Condition),
+		Duration:          request.DurationSeconds,
+		AuctionType:       request.AuctionType,
+		MinIncrement:      money.Amount(request.MinIncrementCents),
+		StartingPrice:     money.Amount(request.StartingPriceCents),
+		ReservePrice:      money.Amount(request.ReservePriceCents),
+		BuyNowPrice:       money.Amount(request.BuyNowPriceCents),
+		StartPrice:        money.Amount(request.StartPriceCents),
+		FloorPrice:        money.Amount(request.FloorPriceCents),
+		PriceDecrement:    money.Amount(request.PriceDecrementCents),
+		DecrementInterval: request.DecrementIntervalSeconds,
+		IdempotencyKey:    request.IdempotencyKey,
+		SellerId:          sellerId,
+	}
+	if request.StartTime > 0 {
+		auctionInput.StartTime = time.Unix(request.StartTime, 0)
+	}
+
+	if err := validate(auctionInput); err != nil {
+		return nil, err
+	}
+
+	auctionOutput, err := s.auctionUseCase.CreateAuction(ctx, auctionInput)
+	if err != nil {
+		return nil, ConvertError(err)
+	}
+
+	return &auction_v1.CreateAuctionResponse{Id: auctionOutput.Id}, nil
+}
+
+func (s *auctionService) FindAuctionById(
+	ctx context.Context, request *auction_v1.FindAuctionByIdRequest) (*auction_v1.Auction, error) {
+	if err := uuid.Validate(request.Id); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "id is not a valid UUID")
+	}
+
+	auctionOutput, err := s.auctionUseCase.FindAuctionById(ctx, request.Id, userId(ctx))
+	if err != nil {
+		return nil, ConvertError(err)
+	}
+
+	return toAuctionMessage(*auctionOutput), nil
+}
+
+func (s *auctionService) ListAuctions(
+	ctx context.Context, request *auction_v1.ListAuctionsRequest) (*auction_v1.ListAuctionsResponse, error) {
+	findInput := auction_usecase.FindAuctionsInputDTO{
+		Page:      int(request.Page),
+		PageSize:  int(request.PageSize),
+		UseCursor: request.UseCursor,
+		Cursor:    request.Cursor,
+		Sort:      request.Sort,
+		Query:     strings.TrimSpace(request.Query),
+	}
+	if request.Query != "" && len(findInput.Query) < minSearchQueryLength {
+		return nil, status.Error(codes.InvalidArgument,
+			fmt.Sprintf("query must have at least %d characters", minSearchQueryLength))
+	}
+	if findInput.UseCursor && findInput.Page > 0 {
+		return nil, status.Error(codes.InvalidArgument, "page cannot be combined with cursor")
+	}
+
+	auctionPage, err := s.auctionUseCase.FindAuctions(ctx,
+		auction_usecase.AuctionStatus(request.Status), request.Category, request.ProductName, findInput)
+	if err != nil {
+		return nil, ConvertError(err)
+	}
+
+	response := &auction_v1.ListAuctionsResponse{
+		Total:      auctionPage.Total,
+		Page:       int32(auctionPage.Page),
+		PageSize:   int32(auctionPage.PageSize),
+		NextCursor: auctionPage.NextCursor,
+	}
+	for _, auctionOutput := range auctionPage.Items {
+		response.Items = append(response.Items, toAuctionMessage(auctionOutput))
+	}
+
+	return response, nil
+}
+
+func (s *auctionService) CreateBid(
+	ctx context.Context, request *auction_v1.CreateBidRequest) (*auction_v1.CreateBidResponse, error) {
+	bidderId := userId(ctx)
+	if bidderId == "" {
+		return nil, status.Error(codes.Unauthenticated, "Authentication is required")
+	}
+
+	bidOutput, err := s.bidUseCase.CreateBid(ctx, bid_usecase.BidInputDTO{
+		UserId:         bidderId,
+		AuctionId:      request.AuctionId,
+		Amount:         money.Amount(request.AmountCents),
+		MaxAmount:      money.Amount(request.MaxAmountCents),
+		IdempotencyKey: request.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, ConvertError(err)
+	}
+
+	response := &auction_v1.CreateBidResponse{}
+	if bidOutput != nil {
+		response.Bid = &auction_v1.Bid{
+			Id:          bidOutput.Id,
+			UserId:      bidOutput.UserId,
+			AuctionId:   bidOutput.AuctionId,
+			AmountCents: int64(bidOutput.Amount),
+			Timestamp:   bidOutput.Timestamp.Unix(),
+			Sequence:    bidOutput.Sequence,
+			Auto:        bidOutput.Auto,
+		}
+	}
+
+	return response, nil
+}
+
+// WatchAuction streams the live feed of an auction until it closes, resuming
+// after last_event_id like the SSE endpoint does.
+func (s *auctionService) WatchAuction(
+	request *auction_v1.WatchAuctionRequest, stream auction_v1.AuctionService_WatchAuctionServer) error {
+	ctx := stream.Context()
+
+	if err := uuid.Validate(request.AuctionId); err != nil {
+		return status.Error(codes.InvalidArgument, "auction_id is not a valid UUID")
+	}
+
+	subscription, err := s.hub.Subscribe(request.AuctionId, live_feed.ResumeAfter(request.LastEventId))
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	defer s.hub.Unsubscribe(subscription)
+
+	auctionOutput, findErr := s.auctionUseCase.FindAuctionById(ctx, request.AuctionId, userId(ctx))
+	if findErr != nil {
+		return ConvertError(findErr)
+	}
+
+	closed := auctionOutput.Status == auction_usecase.AuctionStatus(auction_entity.Completed) ||
+		auctionOutput.Status == auction_usecase.AuctionStatus(auction_entity.Cancelled)
+
+	for {
+		if closed && len(subscription.Frames()) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case frame, ok := <-subscription.Frames():
+			if !ok {
+				return nil
+			}
+
+			if err := stream.Send(&auction_v1.AuctionEvent{
+				Id:        frame.Id,
+				Type:      frame.Type,
+				AuctionId: request.AuctionId,
+				Data:      frame.Data,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func validate(input interface{}) error {
+	if err := binding.Validator.ValidateStruct(input); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		message := restErr.Message
+		for _, cause := range restErr.Details {
+			message += fmt.Sprintf("; %s: %s", cause.Field, cause.Message)
+		}
+
+		return status.Error(codes.InvalidArgument, message)
+	}
+
+	return nil
+}
+
+func toAuctionMessage(auctionOutput auction_usecase.AuctionOutputDTO) *auction_v1.Auction {
+	auction := &auction_v1.Auction{
+		Id:                   auctionOutput.Id,
+		SellerId:             auctionOutput.SellerId,
+		ProductName:          auctionOutput.ProductName,
+		Category:             auctionOutput.Category,
+		Description:          auctionOutput.Description,
+		Condition:            int32(auctionOutput.Condition),
+		Status:               int32(auctionOutput.Status),
+		AuctionType:          auctionOutput.AuctionType,
+		Timestamp:            auctionOutput.Timestamp.Unix(),
+		StartTime:            unixOrZero(auctionOutput.StartTime),
+		EndTime:              unixOrZero(auctionOutput.EndTime),
+		CloseReason:          auctionOutput.CloseReason,
+		MinIncrementCents:    int64(auctionOutput.MinIncrement),
+		StartingPriceCents:   int64(auctionOutput.StartingPrice),
+		BuyNowPriceCents:     int64(auctionOutput.BuyNowPrice),
+		CurrentHighestUserId: auctionOutput.CurrentHighestUserId,
+		BidCount:             auctionOutput.BidCount,
+		WinnerUserId:         auctionOutput.WinnerUserId,
+	}
+	if auctionOutput.ClosedAt != nil {
+		auction.ClosedAt = auctionOutput.ClosedAt.Unix()
+	}
+	if auctionOutput.CurrentHighestAmount != nil {
+		auction.CurrentHighestAmountCents = int64(*auctionOutput.CurrentHighestAmount)
+	}
+	if auctionOutput.WinningAmount != nil {
+		auction.WinningAmountCents = int64(*auctionOutput.WinningAmount)
+	}
+
+	return auction
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
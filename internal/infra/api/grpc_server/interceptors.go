@@ -0,0 +1,171 @@
+package grpc_server
+
+import (
+	"context"
+	"errors"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/infra/auth"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
+)
+
+type userIdKey struct{}
+
+// contextStream replaces the context of a server stream, so stream
+// interceptors can pass values down like unary ones do.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// RequestIdUnaryInterceptor keeps the caller's x-request-id, or generates
+// one, and echoes it back in the response headers.
+func RequestIdUnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(withRequestId(ctx), req)
+}
+
+func RequestIdStreamInterceptor(
+	srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	return handler(srv, &contextStream{ServerStream: stream, ctx: withRequestId(stream.Context())})
+}
+
+func withRequestId(ctx context.Context) context.Context {
+	requestId := incomingMetadata(ctx, "x-request-id")
+	if requestId == "" {
+		requestId = uuid.New().String()
+	}
+
+	grpc.SetHeader(ctx, metadata.Pairs("x-request-id", requestId))
+	return logger.WithRequestId(ctx, requestId)
+}
+
+func RequestId(ctx context.Context) string {
+	return logger.RequestId(ctx)
+}
+
+func LoggingUnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	logCall(ctx, info.FullMethod, start, err)
+	return resp, err
+}
+
+func LoggingStreamInterceptor(
+	srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, stream)
+
+	logCall(stream.Context(), info.FullMethod, start, err)
+	return err
+}
+
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.String("code", code.String()),
+		zap.Duration("duration", time.Since(start)),
+	}
+	if code == codes.Internal || code == codes.Unknown {
+		logger.ErrorContext(ctx, "gRPC call failed", err, fields...)
+	} else {
+		logger.InfoContext(ctx, "gRPC call finished", fields...)
+	}
+}
+
+type AccessTokenVerifier interface {
+	VerifyAccessToken(token string) (*auth.Claims, error)
+}
+
+// AuthUnaryInterceptor identifies the caller from the bearer token in the
+// authorization metadata. Calls without one stay anonymous.
+func AuthUnaryInterceptor(tokenVerifier AccessTokenVerifier) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, tokenVerifier)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func AuthStreamInterceptor(tokenVerifier AccessTokenVerifier) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), tokenVerifier)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, tokenVerifier AccessTokenVerifier) (context.Context, error) {
+	authorization := incomingMetadata(ctx, "authorization")
+	if authorization == "" {
+		return ctx, nil
+	}
+
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, status.Error(codes.Unauthenticated, "The authorization metadata must hold a Bearer token")
+	}
+
+	claims, err := tokenVerifier.VerifyAccessToken(token)
+	if errors.Is(err, auth.ErrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "The access token has expired")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "The access token is invalid")
+	}
+
+	return context.WithValue(ctx, userIdKey{}, claims.Subject), nil
+}
+
+func incomingMetadata(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+func userId(ctx context.Context) string {
+	id, _ := ctx.Value(userIdKey{}).(string)
+	return id
+}
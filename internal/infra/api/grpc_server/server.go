@@ -0,0 +1,34 @@
+package grpc_server
+
+import (
+	"fullcycle-auction_go/internal/infra/api/web/live_feed"
+	"fullcycle-auction_go/internal/usecase/auction_usecase"
+	"fullcycle-auction_go/internal/usecase/bid_usecase"
+	auction_v1 "fullcycle-auction_go/proto/auction/v1"
+	"google.golang.org/grpc"
+)
+
+// NewServer serves auction.v1.AuctionService on top of the same use cases as
+// the REST API. Every call gets a request id, is logged and is authenticated
+// with tokenVerifier.
+func NewServer(
+	auctionUseCase auction_usecase.AuctionUseCaseInterface,
+	bidUseCase bid_usecase.BidUseCaseInterface,
+	hub *live_feed.Hub,
+	tokenVerifier AccessTokenVerifier,
+	opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(
+			RequestIdUnaryInterceptor, LoggingUnaryInterceptor, AuthUnaryInterceptor(tokenVerifier)),
+		grpc.ChainStreamInterceptor(
+			RequestIdStreamInterceptor, LoggingStreamInterceptor, AuthStreamInterceptor(tokenVerifier)))
+
+	server := grpc.NewServer(opts...)
+	auction_v1.RegisterAuctionServiceServer(server, &auctionService{
+		auctionUseCase: auctionUseCase,
+		bidUseCase:     bidUseCase,
+		hub:            hub,
+	})
+
+	return server
+}
@@ -0,0 +1,206 @@
+package grpc_server
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"fullcycle-auction_go/internal/infra/api/web/live_feed"
+	"fullcycle-auction_go/internal/infra/auth"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"fullcycle-auction_go/internal/usecase/auction_usecase"
+	"fullcycle-auction_go/internal/usecase/bid_usecase"
+	auction_v1 "fullcycle-auction_go/proto/auction/v1"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+type fakeAuctionUseCase struct {
+	auction_usecase.AuctionUseCaseInterface
+	auctions map[string]auction_usecase.AuctionOutputDTO
+	created  auction_usecase.AuctionInputDTO
+}
+
+func (f *fakeAuctionUseCase) CreateAuction(
+	ctx context.Context,
+	auctionInput auction_usecase.AuctionInputDTO) (*auction_usecase.AuctionOutputDTO, *internal_error.InternalError) {
+	f.created = auctionInput
+	return &auction_usecase.AuctionOutputDTO{Id: "created"}, nil
+}
+
+func (f *fakeAuctionUseCase) FindAuctionById(
+	ctx context.Context, id, callerId string) (*auction_usecase.AuctionOutputDTO, *internal_error.InternalError) {
+	auctionOutput, ok := f.auctions[id]
+	if !ok {
+		return nil, internal_error.NewNotFoundError("Auction not found")
+	}
+	return &auctionOutput, nil
+}
+
+type fakeBidUseCase struct {
+	bid_usecase.BidUseCaseInterface
+}
+
+type grpcTestServer struct {
+	client         auction_v1.AuctionServiceClient
+	conn           *grpc.ClientConn
+	auctionUseCase *fakeAuctionUseCase
+	hub            *live_feed.Hub
+	tokenService   *auth.TokenService
+}
+
+func newGrpcTestServer(t *testing.T, auctions ...auction_usecase.AuctionOutputDTO) *grpcTestServer {
+	auctionUseCase := &fakeAuctionUseCase{auctions: map[string]auction_usecase.AuctionOutputDTO{}}
+	for _, auctionOutput := range auctions {
+		auctionUseCase.auctions[auctionOutput.Id] = auctionOutput
+	}
+
+	hub := live_feed.NewHub()
+	tokenService := auth.NewTokenServiceWithClock(
+		[]byte("test-secret"), time.Minute, time.Hour, fakeclock.New(time.Now()))
+	server := NewServer(auctionUseCase, &fakeBidUseCase{}, hub, tokenService)
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return &grpcTestServer{
+		client:         auction_v1.NewAuctionServiceClient(conn),
+		conn:           conn,
+		auctionUseCase: auctionUseCase,
+		hub:            hub,
+		tokenService:   tokenService,
+	}
+}
+
+func withMetadata(pairs ...string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), pairs...)
+}
+
+func TestCreateAuctionUsesCallerAsSeller(t *testing.T) {
+	server := newGrpcTestServer(t)
+
+	sellerId := uuid.New().String()
+	tokens, _ := server.tokenService.Issue(sellerId)
+	var header metadata.MD
+	created, err := server.client.CreateAuction(
+		withMetadata("authorization", "Bearer "+tokens.AccessToken, "x-request-id", "request-1"),
+		&auction_v1.CreateAuctionRequest{
+			ProductName: "mouse",
+			Category:    "peripherals",
+			Description: "mouse gamer rgb",
+		}, grpc.Header(&header))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"request-1"}, header.Get("x-request-id"))
+	assert.Equal(t, sellerId, server.auctionUseCase.created.SellerId)
+	assert.Equal(t, "created", created.Id)
+}
+
+func TestCreateAuctionRejectsInvalidFields(t *testing.T) {
+	server := newGrpcTestServer(t)
+
+	tokens, _ := server.tokenService.Issue(uuid.New().String())
+	var header metadata.MD
+	_, err := server.client.CreateAuction(withMetadata("authorization", "Bearer "+tokens.AccessToken),
+		&auction_v1.CreateAuctionRequest{ProductName: "mouse"}, grpc.Header(&header))
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "category: ")
+	assert.NotEmpty(t, header.Get("x-request-id"))
+}
+
+func TestCreateBidRequiresAValidToken(t *testing.T) {
+	server := newGrpcTestServer(t)
+
+	request := &auction_v1.CreateBidRequest{AuctionId: uuid.New().String(), AmountCents: 100}
+
+	_, err := server.client.CreateBid(context.Background(), request)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	tokens, _ := server.tokenService.Issue(uuid.New().String())
+	_, err = server.client.CreateBid(withMetadata("authorization", "Bearer "+tokens.RefreshToken), request)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, "The access token is invalid", status.Convert(err).Message())
+}
+
+func TestFindAuctionByIdMapsNotFound(t *testing.T) {
+	server := newGrpcTestServer(t)
+
+	auctionOutput, err := server.client.FindAuctionById(context.Background(),
+		&auction_v1.FindAuctionByIdRequest{Id: uuid.New().String()})
+
+	assert.Nil(t, auctionOutput)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "Auction not found", status.Convert(err).Message())
+}
+
+func TestUnknownMethodIsUnimplemented(t *testing.T) {
+	server := newGrpcTestServer(t)
+
+	err := server.conn.Invoke(context.Background(), "/auction.v1.AuctionService/DeleteAuction",
+		&auction_v1.FindAuctionByIdRequest{}, &auction_v1.Auction{})
+
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestWatchAuctionStreamsUntilTheAuctionCloses(t *testing.T) {
+	auctionId := uuid.New().String()
+	server := newGrpcTestServer(t, auction_usecase.AuctionOutputDTO{
+		Id:     auctionId,
+		Status: auction_usecase.AuctionStatus(auction_entity.Completed),
+	})
+
+	ctx := context.Background()
+	server.hub.Publish(ctx, event_entity.BidAcceptedEvent{AuctionId: auctionId, BidId: "first"})
+	server.hub.Publish(ctx, event_entity.AuctionExtendedEvent{AuctionId: auctionId})
+	server.hub.Publish(ctx, event_entity.AuctionClosedEvent{AuctionId: auctionId, CloseReason: "expired"})
+
+	stream, err := server.client.WatchAuction(ctx,
+		&auction_v1.WatchAuctionRequest{AuctionId: auctionId, LastEventId: 1})
+	assert.Nil(t, err)
+
+	var types []string
+	for {
+		auctionEvent, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		assert.Equal(t, auctionId, auctionEvent.AuctionId)
+		types = append(types, auctionEvent.Type)
+	}
+	assert.Equal(t, []string{event_entity.AuctionExtendedEventName, event_entity.AuctionClosedEventName}, types)
+}
+
+func TestWatchAuctionRejectsAMalformedToken(t *testing.T) {
+	server := newGrpcTestServer(t)
+
+	stream, err := server.client.WatchAuction(withMetadata("authorization", "Basic dXNlcjpwYXNz"),
+		&auction_v1.WatchAuctionRequest{AuctionId: uuid.New().String()})
+	assert.Nil(t, err)
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,44 @@
+package grpc_server
+
+import (
+	"fmt"
+	"fullcycle-auction_go/internal/internal_error"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ConvertError maps use case errors to the closest canonical status.
+func ConvertError(internalError *internal_error.InternalError) error {
+	message := internalError.Error()
+	for _, failure := range internalError.Failures {
+		message += fmt.Sprintf("; %s: %s", failure.Field, failure.Message)
+	}
+	for _, violation := range internalError.Violations {
+		message += fmt.Sprintf("; %s: %s", violation.Field, violation.Message)
+	}
+
+	return status.Error(codeOf(internalError.Err), message)
+}
+
+func codeOf(kind string) codes.Code {
+	switch kind {
+	case internal_error.ErrBadRequest, internal_error.ErrValidation:
+		return codes.InvalidArgument
+	case internal_error.ErrNotFound:
+		return codes.NotFound
+	case internal_error.ErrConflict:
+		return codes.FailedPrecondition
+	case internal_error.ErrVersionConflict:
+		return codes.Aborted
+	case internal_error.ErrForbidden:
+		return codes.PermissionDenied
+	case internal_error.ErrUnauthorized:
+		return codes.Unauthenticated
+	case internal_error.ErrTooManyRequests:
+		return codes.ResourceExhausted
+	case internal_error.ErrInternalServer, internal_error.ErrBulkWrite:
+		return codes.Internal
+	}
+
+	return codes.Unknown
+}
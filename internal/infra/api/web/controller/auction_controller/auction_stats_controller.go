@@ -0,0 +1,17 @@
+package auction_controller
+
+import (
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (u *AuctionController) GetAuctionStats(c *gin.Context) {
+	auctionStats, err := u.auctionUseCase.GetAuctionStats(middleware.RequestContext(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, auctionStats)
+}
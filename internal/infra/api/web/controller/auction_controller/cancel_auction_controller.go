@@ -0,0 +1,39 @@
+package auction_controller
+
+import (
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/infra/api/web/validation"
+	"fullcycle-auction_go/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func (u *AuctionController) CancelAuction(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.Error(errRest)
+		return
+	}
+
+	var cancelInputDTO auction_usecase.CancelAuctionInputDTO
+	if err := c.ShouldBindJSON(&cancelInputDTO); err != nil {
+		c.Error(validation.ValidateErr(err))
+		return
+	}
+
+	if err := u.auctionUseCase.CancelAuction(
+		middleware.RequestContext(c), auctionId, middleware.UserId(c), middleware.IsAdmin(c), cancelInputDTO); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
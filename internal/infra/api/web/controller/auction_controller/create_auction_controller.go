@@ -1,8 +1,7 @@
 package auction_controller
 
 import (
-	"context"
-	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
 	"fullcycle-auction_go/internal/infra/api/web/validation"
 	"fullcycle-auction_go/internal/usecase/auction_usecase"
 	"github.com/gin-gonic/gin"
@@ -23,17 +22,16 @@ func (u *AuctionController) CreateAuction(c *gin.Context) {
 	var auctionInputDTO auction_usecase.AuctionInputDTO
 
 	if err := c.ShouldBindJSON(&auctionInputDTO); err != nil {
-		restErr := validation.ValidateErr(err)
-
-		c.JSON(restErr.Code, restErr)
+		c.Error(validation.ValidateErr(err))
 		return
 	}
 
-	err := u.auctionUseCase.CreateAuction(context.Background(), auctionInputDTO)
-	if err != nil {
-		restErr := rest_err.ConvertError(err)
+	auctionInputDTO.IdempotencyKey = c.GetHeader("Idempotency-Key")
+	auctionInputDTO.SellerId = middleware.UserId(c)
 
-		c.JSON(restErr.Code, restErr)
+	_, err := u.auctionUseCase.CreateAuction(middleware.RequestContext(c), auctionInputDTO)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -0,0 +1,30 @@
+package auction_controller
+
+import (
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/infra/api/web/validation"
+	"fullcycle-auction_go/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (u *AuctionController) CreateAuctions(c *gin.Context) {
+	var auctionInputDTOs []auction_usecase.AuctionInputDTO
+
+	if err := c.ShouldBindJSON(&auctionInputDTOs); err != nil {
+		c.Error(validation.ValidateErr(err))
+		return
+	}
+
+	for index := range auctionInputDTOs {
+		auctionInputDTOs[index].SellerId = middleware.UserId(c)
+	}
+
+	results, err := u.auctionUseCase.CreateAuctions(middleware.RequestContext(c), auctionInputDTOs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusMultiStatus, results)
+}
@@ -0,0 +1,30 @@
+package auction_controller
+
+import (
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func (u *AuctionController) DeleteAuction(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.Error(errRest)
+		return
+	}
+
+	if err := u.auctionUseCase.DeleteAuction(middleware.RequestContext(c), auctionId, middleware.IsAdmin(c)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
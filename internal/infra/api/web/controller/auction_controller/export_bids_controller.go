@@ -0,0 +1,138 @@
+package auction_controller
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/usecase/bid_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	exportFormatCSV  = "csv"
+	exportFormatJSON = "json"
+)
+
+func (u *AuctionController) ExportBids(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.Error(errRest)
+		return
+	}
+
+	format := c.DefaultQuery("format", exportFormatCSV)
+	if format != exportFormatCSV && format != exportFormatJSON {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "format",
+			Message: "format must be csv or json",
+		})
+
+		c.Error(errRest)
+		return
+	}
+
+	exporter := newBidExporter(c, auctionId, format)
+	err := u.auctionUseCase.ExportBids(
+		middleware.RequestContext(c), auctionId, middleware.UserId(c), middleware.IsAdmin(c), exporter.write)
+	if err != nil && !exporter.started {
+		c.Error(err)
+		return
+	}
+	if err != nil {
+		c.Abort()
+		return
+	}
+
+	exporter.finish()
+}
+
+// bidExporter only sends the response headers with the first bid, so the
+// request can still fail with a proper status until then.
+type bidExporter struct {
+	c         *gin.Context
+	auctionId string
+	format    string
+	started   bool
+	csv       *csv.Writer
+}
+
+func newBidExporter(c *gin.Context, auctionId, format string) *bidExporter {
+	return &bidExporter{c: c, auctionId: auctionId, format: format}
+}
+
+func (e *bidExporter) start() error {
+	e.started = true
+
+	contentType := "text/csv"
+	if e.format == exportFormatJSON {
+		contentType = "application/json"
+	}
+	e.c.Header("Content-Type", contentType)
+	e.c.Header("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="auction-%s-bids.%s"`, e.auctionId, e.format))
+	e.c.Status(http.StatusOK)
+
+	if e.format == exportFormatJSON {
+		_, err := io.WriteString(e.c.Writer, "[")
+		return err
+	}
+
+	e.csv = csv.NewWriter(e.c.Writer)
+	return e.csv.Write([]string{"user_id", "amount", "timestamp", "sequence", "retracted"})
+}
+
+func (e *bidExporter) write(bid bid_usecase.BidOutputDTO) error {
+	separator := ","
+	if !e.started {
+		separator = ""
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+
+	if e.format == exportFormatJSON {
+		line, err := json.Marshal(bid)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(e.c.Writer, separator+string(line))
+		return err
+	}
+
+	return e.csv.Write([]string{
+		bid.UserId,
+		bid.Amount.String(),
+		bid.Timestamp.UTC().Format(time.RFC3339),
+		strconv.FormatInt(bid.Sequence, 10),
+		strconv.FormatBool(bid.Retracted),
+	})
+}
+
+func (e *bidExporter) finish() {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return
+		}
+	}
+
+	if e.format == exportFormatJSON {
+		io.WriteString(e.c.Writer, "]")
+		return
+	}
+
+	e.csv.Flush()
+}
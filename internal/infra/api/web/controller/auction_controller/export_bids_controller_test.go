@@ -0,0 +1,105 @@
+package auction_controller
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/usecase/auction_usecase"
+	"fullcycle-auction_go/internal/usecase/bid_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeExportAuctionUseCase struct {
+	auction_usecase.AuctionUseCaseInterface
+	bids []bid_usecase.BidOutputDTO
+	err  *internal_error.InternalError
+}
+
+func (f *fakeExportAuctionUseCase) ExportBids(
+	ctx context.Context,
+	auctionId, callerId string,
+	callerIsAdmin bool,
+	write func(bid_usecase.BidOutputDTO) error) *internal_error.InternalError {
+	if f.err != nil {
+		return f.err
+	}
+
+	for _, bid := range f.bids {
+		if err := write(bid); err != nil {
+			return internal_error.NewInternalServerError(err.Error())
+		}
+	}
+
+	return nil
+}
+
+func TestExportBidsStreamsTheRequestedFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auctionId := uuid.New().String()
+	timestamp := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	useCase := &fakeExportAuctionUseCase{bids: []bid_usecase.BidOutputDTO{
+		{Id: "first", UserId: "alice", Amount: 1050, Timestamp: timestamp, Sequence: 1},
+		{Id: "second", UserId: "bob", Amount: 1200, Timestamp: timestamp, Sequence: 2, Retracted: true},
+	}}
+
+	router := gin.New()
+	router.Use(middleware.HandleErrors())
+	router.GET("/auction/:auctionId/bids/export", NewAuctionController(useCase).ExportBids)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(
+		http.MethodGet, "/auction/"+auctionId+"/bids/export", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/csv", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="auction-`+auctionId+`-bids.csv"`,
+		recorder.Header().Get("Content-Disposition"))
+	assert.Equal(t, "user_id,amount,timestamp,sequence,retracted\n"+
+		"alice,10.50,2024-03-01T12:30:00Z,1,false\n"+
+		"bob,12.00,2024-03-01T12:30:00Z,2,true\n", recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(
+		http.MethodGet, "/auction/"+auctionId+"/bids/export?format=json", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.JSONEq(t, `[
+		{"id":"first","user_id":"alice","auction_id":"","amount":"10.50","timestamp":"2024-03-01T12:30:00Z","sequence":1},
+		{"id":"second","user_id":"bob","auction_id":"","amount":"12.00","timestamp":"2024-03-01T12:30:00Z","sequence":2,"retracted":true}
+	]`, recorder.Body.String())
+
+	useCase.bids = nil
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(
+		http.MethodGet, "/auction/"+auctionId+"/bids/export?format=json", nil))
+	assert.Equal(t, "[]", recorder.Body.String())
+}
+
+func TestExportBidsRejectsCallersBeforeStreaming(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	useCase := &fakeExportAuctionUseCase{
+		err: internal_error.NewForbiddenError("Only the seller can export the bids of this auction"),
+	}
+
+	router := gin.New()
+	router.Use(middleware.HandleErrors())
+	router.GET("/auction/:auctionId/bids/export", NewAuctionController(useCase).ExportBids)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(
+		http.MethodGet, "/auction/"+uuid.New().String()+"/bids/export", nil))
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("Content-Disposition"))
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(
+		http.MethodGet, "/auction/"+uuid.New().String()+"/bids/export?format=xml", nil))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
@@ -1,15 +1,20 @@
 package auction_controller
 
 import (
-	"context"
+	"fmt"
 	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
 	"fullcycle-auction_go/internal/usecase/auction_usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
+const minSearchQueryLength = 3
+
 func (u *AuctionController) FindAuctionById(c *gin.Context) {
 	auctionId := c.Param("auctionId")
 
@@ -19,20 +24,53 @@ func (u *AuctionController) FindAuctionById(c *gin.Context) {
 			Message: "Invalid UUID value",
 		})
 
-		c.JSON(errRest.Code, errRest)
+		c.Error(errRest)
 		return
 	}
 
-	auctionData, err := u.auctionUseCase.FindAuctionById(context.Background(), auctionId)
+	callerId := middleware.UserId(c)
+	auctionData, err := u.auctionUseCase.FindAuctionById(
+		middleware.RequestContext(c), auctionId, callerId)
 	if err != nil {
-		errRest := rest_err.ConvertError(err)
-		c.JSON(errRest.Code, errRest)
+		c.Error(err)
+		return
+	}
+
+	etag := auctionETag(auctionData, callerId)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Vary", "Authorization")
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
 		return
 	}
 
 	c.JSON(http.StatusOK, auctionData)
 }
 
+// auctionETag changes with every write to the auction, and differs for the
+// seller, who also sees the reserve price.
+func auctionETag(auctionData *auction_usecase.AuctionOutputDTO, callerId string) string {
+	view := "public"
+	if callerId != "" && callerId == auctionData.SellerId {
+		view = "owner"
+	}
+
+	return fmt.Sprintf(`W/"%s-%d-%s"`, auctionData.Id, auctionData.Version, view)
+}
+
+// etagMatches compares weakly, as If-None-Match requires.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (u *AuctionController) FindAuctions(c *gin.Context) {
 	status := c.Query("status")
 	category := c.Query("category")
@@ -41,15 +79,32 @@ func (u *AuctionController) FindAuctions(c *gin.Context) {
 	statusNumber, errConv := strconv.Atoi(status)
 	if errConv != nil {
 		errRest := rest_err.NewBadRequestError("Error trying to validate auction status param")
-		c.JSON(errRest.Code, errRest)
+		c.Error(errRest)
 		return
 	}
 
-	auctions, err := u.auctionUseCase.FindAuctions(context.Background(),
-		auction_usecase.AuctionStatus(statusNumber), category, productName)
+	findInput, errRest := parseFindAuctionsInput(c)
+	if errRest != nil {
+		c.Error(errRest)
+		return
+	}
+
+	if c.Query("summary") == "true" {
+		summaries, err := u.auctionUseCase.FindAuctionSummaries(middleware.RequestContext(c),
+			auction_usecase.AuctionStatus(statusNumber), category, productName, findInput)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, summaries)
+		return
+	}
+
+	auctions, err := u.auctionUseCase.FindAuctions(middleware.RequestContext(c),
+		auction_usecase.AuctionStatus(statusNumber), category, productName, findInput)
 	if err != nil {
-		errRest := rest_err.ConvertError(err)
-		c.JSON(errRest.Code, errRest)
+		c.Error(err)
 		return
 	}
 
@@ -65,16 +120,115 @@ func (u *AuctionController) FindWinningBidByAuctionId(c *gin.Context) {
 			Message: "Invalid UUID value",
 		})
 
-		c.JSON(errRest.Code, errRest)
+		c.Error(errRest)
 		return
 	}
 
-	auctionData, err := u.auctionUseCase.FindWinningBidByAuctionId(context.Background(), auctionId)
+	auctionData, err := u.auctionUseCase.FindWinningBidByAuctionId(middleware.RequestContext(c), auctionId)
 	if err != nil {
-		errRest := rest_err.ConvertError(err)
-		c.JSON(errRest.Code, errRest)
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, auctionData)
 }
+
+func (u *AuctionController) FindAuctionWinner(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.Error(errRest)
+		return
+	}
+
+	winnerData, err := u.auctionUseCase.FindAuctionWinner(middleware.RequestContext(c), auctionId)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, winnerData)
+}
+
+func parseFindAuctionsInput(c *gin.Context) (auction_usecase.FindAuctionsInputDTO, *rest_err.RestErr) {
+	var findInput auction_usecase.FindAuctionsInputDTO
+	findInput.Cursor, findInput.UseCursor = c.GetQuery("cursor")
+	findInput.Sort = c.Query("sort")
+	findInput.IncludeDeleted = c.Query("include_deleted") == "true" && middleware.IsAdmin(c)
+
+	if query, ok := c.GetQuery("q"); ok {
+		findInput.Query = strings.TrimSpace(query)
+		if len(findInput.Query) < minSearchQueryLength {
+			return findInput, rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+				Field:   "q",
+				Message: fmt.Sprintf("q must have at least %d characters", minSearchQueryLength),
+			})
+		}
+	}
+
+	if page := c.Query("page"); page != "" {
+		if findInput.UseCursor {
+			return findInput, rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+				Field:   "cursor",
+				Message: "cursor and page cannot be used together",
+			})
+		}
+
+		pageNumber, err := strconv.Atoi(page)
+		if err != nil {
+			return findInput, rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+				Field:   "page",
+				Message: "page must be a number",
+			})
+		}
+		findInput.Page = pageNumber
+	}
+
+	if pageSize := c.Query("page_size"); pageSize != "" {
+		pageSizeNumber, err := strconv.Atoi(pageSize)
+		if err != nil {
+			return findInput, rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+				Field:   "page_size",
+				Message: "page_size must be a number",
+			})
+		}
+		findInput.PageSize = pageSizeNumber
+	}
+
+	if createdFrom := c.Query("created_from"); createdFrom != "" {
+		parsed, err := time.Parse(time.RFC3339, createdFrom)
+		if err != nil {
+			return findInput, rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+				Field:   "created_from",
+				Message: "created_from must be an RFC3339 timestamp",
+			})
+		}
+		findInput.CreatedFrom = parsed
+	}
+
+	if createdTo := c.Query("created_to"); createdTo != "" {
+		parsed, err := time.Parse(time.RFC3339, createdTo)
+		if err != nil {
+			return findInput, rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+				Field:   "created_to",
+				Message: "created_to must be an RFC3339 timestamp",
+			})
+		}
+		findInput.CreatedTo = parsed
+	}
+
+	if !findInput.CreatedFrom.IsZero() && !findInput.CreatedTo.IsZero() &&
+		!findInput.CreatedFrom.Before(findInput.CreatedTo) {
+		return findInput, rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "created_from",
+			Message: "created_from must be before created_to",
+		})
+	}
+
+	return findInput, nil
+}
@@ -0,0 +1,313 @@
+package auction_controller
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"fullcycle-auction_go/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakeAuctionUseCase struct {
+	auction_usecase.AuctionUseCaseInterface
+
+	findAuctionById func(id string) (*auction_usecase.AuctionOutputDTO, *internal_error.InternalError)
+	findWinningBid  func(id string) (*auction_usecase.WinningInfoOutputDTO, *internal_error.InternalError)
+	findWinner      func(id string) (*auction_usecase.AuctionWinnerOutputDTO, *internal_error.InternalError)
+	findAuctions    func(findInput auction_usecase.FindAuctionsInputDTO) (
+		*auction_usecase.AuctionPageOutputDTO, *internal_error.InternalError)
+}
+
+func (f *fakeAuctionUseCase) FindAuctions(
+	ctx context.Context,
+	status auction_usecase.AuctionStatus,
+	category, productName string,
+	findInput auction_usecase.FindAuctionsInputDTO) (*auction_usecase.AuctionPageOutputDTO, *internal_error.InternalError) {
+	return f.findAuctions(findInput)
+}
+
+func (f *fakeAuctionUseCase) FindAuctionById(
+	ctx context.Context, id, callerId string) (*auction_usecase.AuctionOutputDTO, *internal_error.InternalError) {
+	return f.findAuctionById(id)
+}
+
+func (f *fakeAuctionUseCase) FindWinningBidByAuctionId(
+	ctx context.Context, id string) (*auction_usecase.WinningInfoOutputDTO, *internal_error.InternalError) {
+	return f.findWinningBid(id)
+}
+
+func (f *fakeAuctionUseCase) FindAuctionWinner(
+	ctx context.Context, id string) (*auction_usecase.AuctionWinnerOutputDTO, *internal_error.InternalError) {
+	return f.findWinner(id)
+}
+
+func newTestRouter(useCase auction_usecase.AuctionUseCaseInterface) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	controller := NewAuctionController(useCase)
+	router := gin.New()
+	router.Use(middleware.HandleErrors())
+	router.GET("/auction", controller.FindAuctions)
+	router.GET("/auction/:auctionId", controller.FindAuctionById)
+	router.GET("/auction/winner/:auctionId", controller.FindWinningBidByAuctionId)
+	router.GET("/auction/:auctionId/winner", controller.FindAuctionWinner)
+
+	return router
+}
+
+func TestFindAuctionByIdStatusCodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		auctionId  string
+		err        *internal_error.InternalError
+		wantStatus int
+	}{
+		{name: "found", auctionId: uuid.New().String(), wantStatus: http.StatusOK},
+		{name: "invalid id", auctionId: "not-a-uuid", wantStatus: http.StatusBadRequest},
+		{
+			name:       "not found",
+			auctionId:  uuid.New().String(),
+			err:        internal_error.NewNotFoundError("auction not found"),
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "internal error",
+			auctionId:  uuid.New().String(),
+			err:        internal_error.NewInternalServerError("database unavailable"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(&fakeAuctionUseCase{
+				findAuctionById: func(id string) (*auction_usecase.AuctionOutputDTO, *internal_error.InternalError) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &auction_usecase.AuctionOutputDTO{Id: id}, nil
+				},
+			})
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/auction/"+tt.auctionId, nil))
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+		})
+	}
+}
+
+func TestFindAuctionByIdRevalidatesWithETag(t *testing.T) {
+	auctionId := uuid.New().String()
+	version := int64(3)
+	router := newTestRouter(&fakeAuctionUseCase{
+		findAuctionById: func(id string) (*auction_usecase.AuctionOutputDTO, *internal_error.InternalError) {
+			return &auction_usecase.AuctionOutputDTO{Id: id, Version: version}, nil
+		},
+	})
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/auction/"+auctionId, nil)
+		if ifNoneMatch != "" {
+			request.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	first := get("")
+	etag := first.Header().Get("ETag")
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.True(t, strings.HasPrefix(etag, `W/"`))
+	assert.Equal(t, "no-cache", first.Header().Get("Cache-Control"))
+
+	notModified := get(`"other", ` + etag)
+	assert.Equal(t, http.StatusNotModified, notModified.Code)
+	assert.Empty(t, notModified.Body.String())
+	assert.Equal(t, etag, notModified.Header().Get("ETag"))
+
+	version++
+	changed := get(etag)
+	assert.Equal(t, http.StatusOK, changed.Code)
+	assert.NotEqual(t, etag, changed.Header().Get("ETag"))
+}
+
+func TestFindWinningBidByAuctionIdReturnsNotFound(t *testing.T) {
+	router := newTestRouter(&fakeAuctionUseCase{
+		findWinningBid: func(id string) (*auction_usecase.WinningInfoOutputDTO, *internal_error.InternalError) {
+			return nil, internal_error.NewNotFoundError("auction not found")
+		},
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/auction/winner/"+uuid.New().String(), nil))
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestFindAuctionWinnerStates(t *testing.T) {
+	amount := money.Amount(7500)
+	tests := []struct {
+		name       string
+		winner     *auction_usecase.AuctionWinnerOutputDTO
+		err        *internal_error.InternalError
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "not found",
+			err:        internal_error.NewNotFoundError("auction not found"),
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "still open",
+			err:        internal_error.NewConflictError("Auction is still open, it has no winner yet"),
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name: "no winner",
+			winner: &auction_usecase.AuctionWinnerOutputDTO{
+				AuctionId:      "auction",
+				NoWinnerReason: auction_usecase.NoWinnerReasonReserveNotMet,
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"auction_id":"auction","has_winner":false,"winner":null,"amount":null,` +
+				`"no_winner_reason":"reserve_not_met"}`,
+		},
+		{
+			name: "winner",
+			winner: &auction_usecase.AuctionWinnerOutputDTO{
+				AuctionId: "auction",
+				HasWinner: true,
+				Winner:    &auction_usecase.WinnerUserDTO{Id: "winner", Name: "Alice"},
+				Amount:    &amount,
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"auction_id":"auction","has_winner":true,"winner":{"id":"winner","name":"Alice"},` +
+				`"amount":"75.00"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(&fakeAuctionUseCase{
+				findWinner: func(id string) (*auction_usecase.AuctionWinnerOutputDTO, *internal_error.InternalError) {
+					return tt.winner, tt.err
+				},
+			})
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(
+				http.MethodGet, "/auction/"+uuid.New().String()+"/winner", nil))
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestOnlyAdminsCanListSoftDeletedAuctions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name           string
+		user           *user_entity.User
+		includeDeleted bool
+	}{
+		{name: "anonymous"},
+		{name: "buyer", user: &user_entity.User{Id: "buyer", Role: user_entity.BuyerRole}},
+		{name: "admin", user: &user_entity.User{Id: "admin", Role: user_entity.AdminRole}, includeDeleted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/auction?include_deleted=true", nil)
+			if tt.user != nil {
+				c.Set("userId", tt.user.Id)
+				c.Set("user", tt.user)
+			}
+
+			findInput, err := parseFindAuctionsInput(c)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.includeDeleted, findInput.IncludeDeleted)
+		})
+	}
+}
+
+func TestFindAuctionsRejectsInvalidQueryParameters(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		err   *internal_error.InternalError
+	}{
+		{name: "status is not a number", query: "status=active"},
+		{name: "page is not a number", query: "status=0&page=two"},
+		{name: "page size is not a number", query: "status=0&page_size=lots"},
+		{name: "page with cursor", query: "status=0&page=2&cursor=abc"},
+		{name: "search query too short", query: "status=0&q=ab"},
+		{name: "created_from is not RFC3339", query: "status=0&created_from=yesterday"},
+		{name: "created_to is not RFC3339", query: "status=0&created_to=2024-13-01"},
+		{
+			name:  "created range reversed",
+			query: "status=0&created_from=2024-02-01T00:00:00Z&created_to=2024-01-01T00:00:00Z",
+		},
+		{
+			name:  "rejected by the use case",
+			query: "status=0&page=100000",
+			err:   internal_error.NewBadRequestError("page must be between 1 and 1000"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			router := newTestRouter(&fakeAuctionUseCase{
+				findAuctions: func(auction_usecase.FindAuctionsInputDTO) (
+					*auction_usecase.AuctionPageOutputDTO, *internal_error.InternalError) {
+					called = true
+					return nil, tt.err
+				},
+			})
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/auction?"+tt.query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			assert.Equal(t, tt.err != nil, called)
+		})
+	}
+}
+
+func TestFindAuctionsParsesPaginationAndFilters(t *testing.T) {
+	var received auction_usecase.FindAuctionsInputDTO
+	router := newTestRouter(&fakeAuctionUseCase{
+		findAuctions: func(findInput auction_usecase.FindAuctionsInputDTO) (
+			*auction_usecase.AuctionPageOutputDTO, *internal_error.InternalError) {
+			received = findInput
+			return &auction_usecase.AuctionPageOutputDTO{Items: []auction_usecase.AuctionOutputDTO{}}, nil
+		},
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet,
+		"/auction?status=0&page=2&page_size=50&sort=name_asc&q=%20camera%20&created_from=2024-01-01T00:00:00Z", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, 2, received.Page)
+	assert.Equal(t, 50, received.PageSize)
+	assert.Equal(t, "name_asc", received.Sort)
+	assert.Equal(t, "camera", received.Query)
+	assert.Equal(t, int64(1704067200), received.CreatedFrom.Unix())
+	assert.False(t, received.UseCursor)
+}
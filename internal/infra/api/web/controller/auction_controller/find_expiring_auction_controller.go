@@ -0,0 +1,42 @@
+package auction_controller
+
+import (
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func (u *AuctionController) FindAuctionsExpiringSoon(c *gin.Context) {
+	within, err := time.ParseDuration(c.DefaultQuery("within", "30m"))
+	if err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "within",
+			Message: "within must be a duration such as 30m",
+		})
+
+		c.Error(errRest)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "limit",
+			Message: "limit must be a number",
+		})
+
+		c.Error(errRest)
+		return
+	}
+
+	auctions, errUseCase := u.auctionUseCase.FindAuctionsExpiringSoon(middleware.RequestContext(c), within, limit)
+	if errUseCase != nil {
+		c.Error(errUseCase)
+		return
+	}
+
+	c.JSON(http.StatusOK, auctions)
+}
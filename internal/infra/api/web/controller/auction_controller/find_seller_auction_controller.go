@@ -0,0 +1,32 @@
+package auction_controller
+
+import (
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+func (u *AuctionController) FindMyAuctions(c *gin.Context) {
+	var statusFilter []auction_usecase.AuctionStatus
+	if status := c.Query("status"); status != "" {
+		statusNumber, err := strconv.Atoi(status)
+		if err != nil {
+			errRest := rest_err.NewBadRequestError("Error trying to validate auction status param")
+			c.Error(errRest)
+			return
+		}
+		statusFilter = append(statusFilter, auction_usecase.AuctionStatus(statusNumber))
+	}
+
+	auctions, err := u.auctionUseCase.FindAuctionsBySeller(
+		middleware.RequestContext(c), middleware.UserId(c), statusFilter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, auctions)
+}
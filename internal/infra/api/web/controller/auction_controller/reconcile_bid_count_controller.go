@@ -0,0 +1,17 @@
+package auction_controller
+
+import (
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (u *AuctionController) ReconcileBidCounts(c *gin.Context) {
+	reconciliation, err := u.auctionUseCase.ReconcileBidCounts(middleware.RequestContext(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reconciliation)
+}
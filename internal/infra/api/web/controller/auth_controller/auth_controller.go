@@ -0,0 +1,116 @@
+package auth_controller
+
+import (
+	"errors"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/infra/api/web/validation"
+	"fullcycle-auction_go/internal/infra/auth"
+	"fullcycle-auction_go/internal/usecase/user_usecase"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type AuthController struct {
+	userUseCase  user_usecase.UserUseCaseInterface
+	tokenService *auth.TokenService
+}
+
+func NewAuthController(
+	userUseCase user_usecase.UserUseCaseInterface, tokenService *auth.TokenService) *AuthController {
+	return &AuthController{
+		userUseCase:  userUseCase,
+		tokenService: tokenService,
+	}
+}
+
+type RefreshInputDTO struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type TokenOutputDTO struct {
+	AccessToken      string                     `json:"access_token"`
+	TokenType        string                     `json:"token_type"`
+	ExpiresIn        int64                      `json:"expires_in"`
+	RefreshToken     string                     `json:"refresh_token"`
+	RefreshExpiresIn int64                      `json:"refresh_expires_in"`
+	User             user_usecase.UserOutputDTO `json:"user"`
+}
+
+func (u *AuthController) Login(c *gin.Context) {
+	var loginInputDTO user_usecase.LoginInputDTO
+
+	if err := c.ShouldBindJSON(&loginInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		middleware.AbortWithError(c, restErr)
+		return
+	}
+
+	userData, err := u.userUseCase.Login(middleware.RequestContext(c), loginInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		middleware.AbortWithError(c, restErr)
+		return
+	}
+
+	u.issueTokens(c, *userData)
+}
+
+// Refresh exchanges a refresh token for a new pair. Refresh tokens are not
+// stored, so they stay valid until they expire, but a banned or deleted user
+// cannot renew them.
+func (u *AuthController) Refresh(c *gin.Context) {
+	var refreshInputDTO RefreshInputDTO
+
+	if err := c.ShouldBindJSON(&refreshInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		middleware.AbortWithError(c, restErr)
+		return
+	}
+
+	claims, errToken := u.tokenService.VerifyRefreshToken(refreshInputDTO.RefreshToken)
+	if errToken != nil {
+		message := "The refresh token is invalid"
+		if errors.Is(errToken, auth.ErrExpiredToken) {
+			message = "The refresh token has expired, log in again"
+		}
+
+		restErr := rest_err.NewUnauthorizedError(message)
+		middleware.AbortWithError(c, restErr)
+		return
+	}
+
+	userData, err := u.userUseCase.RenewLogin(middleware.RequestContext(c), claims.Subject)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		middleware.AbortWithError(c, restErr)
+		return
+	}
+
+	u.issueTokens(c, *userData)
+}
+
+func (u *AuthController) issueTokens(c *gin.Context, userData user_usecase.UserOutputDTO) {
+	tokens, err := u.tokenService.Issue(userData.Id)
+	if err != nil {
+		logger.Error("Error trying to issue tokens", err)
+
+		restErr := rest_err.NewInternalServerError("Error trying to issue tokens")
+		middleware.AbortWithError(c, restErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, TokenOutputDTO{
+		AccessToken:      tokens.AccessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(tokens.AccessExpiresAt.Sub(tokens.IssuedAt).Seconds()),
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresIn: int64(tokens.RefreshExpiresAt.Sub(tokens.IssuedAt).Seconds()),
+		User:             userData,
+	})
+}
@@ -1,8 +1,7 @@
 package bid_controller
 
 import (
-	"context"
-	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
 	"fullcycle-auction_go/internal/infra/api/web/validation"
 	"fullcycle-auction_go/internal/usecase/bid_usecase"
 	"github.com/gin-gonic/gin"
@@ -23,19 +22,23 @@ func (u *BidController) CreateBid(c *gin.Context) {
 	var bidInputDTO bid_usecase.BidInputDTO
 
 	if err := c.ShouldBindJSON(&bidInputDTO); err != nil {
-		restErr := validation.ValidateErr(err)
-
-		c.JSON(restErr.Code, restErr)
+		c.Error(validation.ValidateErr(err))
 		return
 	}
 
-	err := u.bidUseCase.CreateBid(context.Background(), bidInputDTO)
+	bidInputDTO.UserId = middleware.UserId(c)
+	bidInputDTO.IdempotencyKey = c.GetHeader("Idempotency-Key")
+
+	bidOutput, err := u.bidUseCase.CreateBid(middleware.RequestContext(c), bidInputDTO)
 	if err != nil {
-		restErr := rest_err.ConvertError(err)
+		c.Error(err)
+		return
+	}
 
-		c.JSON(restErr.Code, restErr)
+	if bidOutput == nil {
+		c.Status(http.StatusCreated)
 		return
 	}
 
-	c.Status(http.StatusCreated)
+	c.JSON(http.StatusCreated, bidOutput)
 }
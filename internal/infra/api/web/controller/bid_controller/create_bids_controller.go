@@ -0,0 +1,26 @@
+package bid_controller
+
+import (
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/infra/api/web/validation"
+	"fullcycle-auction_go/internal/usecase/bid_usecase"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (u *BidController) CreateBids(c *gin.Context) {
+	var bidInputDTOs []bid_usecase.BulkBidInputDTO
+
+	if err := c.ShouldBindJSON(&bidInputDTOs); err != nil {
+		c.Error(validation.ValidateErr(err))
+		return
+	}
+
+	results, err := u.bidUseCase.CreateBids(middleware.RequestContext(c), bidInputDTOs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusMultiStatus, results)
+}
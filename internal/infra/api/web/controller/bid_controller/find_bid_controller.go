@@ -1,11 +1,13 @@
 package bid_controller
 
 import (
-	"context"
 	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/usecase/bid_usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"net/http"
+	"strconv"
 )
 
 func (u *BidController) FindBidByAuctionId(c *gin.Context) {
@@ -17,16 +19,66 @@ func (u *BidController) FindBidByAuctionId(c *gin.Context) {
 			Message: "Invalid UUID value",
 		})
 
-		c.JSON(errRest.Code, errRest)
+		c.Error(errRest)
 		return
 	}
 
-	bidOutputList, err := u.bidUseCase.FindBidByAuctionId(context.Background(), auctionId)
+	findInput, restErr := parseFindBidsInput(c)
+	if restErr != nil {
+		c.Error(restErr)
+		return
+	}
+	findInput.CallerId = middleware.UserId(c)
+
+	bidPage, err := u.bidUseCase.FindBidByAuctionId(middleware.RequestContext(c), auctionId, findInput)
 	if err != nil {
-		errRest := rest_err.ConvertError(err)
-		c.JSON(errRest.Code, errRest)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bidPage)
+}
+
+func (u *BidController) FindMyBids(c *gin.Context) {
+	findInput, restErr := parseFindBidsInput(c)
+	if restErr != nil {
+		c.Error(restErr)
 		return
 	}
 
-	c.JSON(http.StatusOK, bidOutputList)
+	bidPage, err := u.bidUseCase.FindBidsByUserId(middleware.RequestContext(c), middleware.UserId(c), findInput)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bidPage)
+}
+
+func parseFindBidsInput(c *gin.Context) (bid_usecase.FindBidsInputDTO, *rest_err.RestErr) {
+	findInput := bid_usecase.FindBidsInputDTO{Order: c.Query("order")}
+
+	if limit := c.Query("limit"); limit != "" {
+		limitNumber, err := strconv.Atoi(limit)
+		if err != nil {
+			return findInput, rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+				Field:   "limit",
+				Message: "limit must be a number",
+			})
+		}
+		findInput.Limit = limitNumber
+	}
+
+	if offset := c.Query("offset"); offset != "" {
+		offsetNumber, err := strconv.Atoi(offset)
+		if err != nil {
+			return findInput, rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+				Field:   "offset",
+				Message: "offset must be a number",
+			})
+		}
+		findInput.Offset = offsetNumber
+	}
+
+	return findInput, nil
 }
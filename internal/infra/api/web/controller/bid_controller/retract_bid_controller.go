@@ -0,0 +1,30 @@
+package bid_controller
+
+import (
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func (u *BidController) RetractBid(c *gin.Context) {
+	bidId := c.Param("bidId")
+
+	if err := uuid.Validate(bidId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "bidId",
+			Message: "Invalid UUID value",
+		})
+
+		c.Error(errRest)
+		return
+	}
+
+	if err := u.bidUseCase.RetractBid(middleware.RequestContext(c), bidId, middleware.UserId(c)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,100 @@
+package health_controller
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.uber.org/zap"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	MongoDBCheck      = "mongodb"
+	AutoCloseCheck    = "auto_close_scheduler"
+	ShuttingDownCheck = "shutting_down"
+
+	checkOk           = "ok"
+	statusReady       = "ready"
+	statusUnavailable = "unavailable"
+)
+
+type DatabasePinger interface {
+	Ping(ctx context.Context, rp *readpref.ReadPref) error
+}
+
+type AutoCloseMonitor interface {
+	AutoCloseRunning() bool
+}
+
+type HealthController struct {
+	database    DatabasePinger
+	autoClose   AutoCloseMonitor
+	pingTimeout time.Duration
+	draining    atomic.Bool
+}
+
+func NewHealthController(
+	database DatabasePinger, autoClose AutoCloseMonitor, pingTimeout time.Duration) *HealthController {
+	return &HealthController{
+		database:    database,
+		autoClose:   autoClose,
+		pingTimeout: pingTimeout,
+	}
+}
+
+type ReadinessOutputDTO struct {
+	Status  string            `json:"status"`
+	Checks  map[string]string `json:"checks"`
+	Failing []string          `json:"failing,omitempty"`
+}
+
+// Healthz only tells that the process answers; it must not depend on
+// MongoDB, or an outage there would get every pod restarted.
+func (u *HealthController) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": checkOk})
+}
+
+// Readyz tells the load balancer whether to send traffic here.
+func (u *HealthController) Readyz(c *gin.Context) {
+	readiness := ReadinessOutputDTO{Status: statusReady, Checks: make(map[string]string)}
+	fail := func(check, reason string) {
+		readiness.Checks[check] = reason
+		readiness.Failing = append(readiness.Failing, check)
+	}
+
+	if u.draining.Load() {
+		fail(ShuttingDownCheck, "the server is shutting down")
+	}
+
+	pingCtx, cancel := context.WithTimeout(c.Request.Context(), u.pingTimeout)
+	defer cancel()
+	if err := u.database.Ping(pingCtx, readpref.Primary()); err != nil {
+		logger.WarnContext(c.Request.Context(), "Readiness check could not ping mongodb", zap.Error(err))
+		fail(MongoDBCheck, err.Error())
+	} else {
+		readiness.Checks[MongoDBCheck] = checkOk
+	}
+
+	if !u.autoClose.AutoCloseRunning() {
+		fail(AutoCloseCheck, "the auto-close scheduler is not running")
+	} else {
+		readiness.Checks[AutoCloseCheck] = checkOk
+	}
+
+	if len(readiness.Failing) > 0 {
+		readiness.Status = statusUnavailable
+		c.JSON(http.StatusServiceUnavailable, readiness)
+		return
+	}
+
+	c.JSON(http.StatusOK, readiness)
+}
+
+// StartDraining makes Readyz fail from now on, so the load balancer stops
+// sending requests before the server stops accepting them.
+func (u *HealthController) StartDraining() {
+	u.draining.Store(true)
+}
@@ -0,0 +1,90 @@
+package health_controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeDatabase struct {
+	err error
+}
+
+func (f *fakeDatabase) Ping(ctx context.Context, rp *readpref.ReadPref) error {
+	return f.err
+}
+
+type fakeAutoClose struct {
+	running bool
+}
+
+func (f *fakeAutoClose) AutoCloseRunning() bool {
+	return f.running
+}
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name        string
+		pingErr     error
+		running     bool
+		draining    bool
+		wantStatus  int
+		wantFailing []string
+	}{
+		{name: "ready", running: true, wantStatus: http.StatusOK},
+		{
+			name:        "mongodb down",
+			pingErr:     errors.New("server selection timeout"),
+			running:     true,
+			wantStatus:  http.StatusServiceUnavailable,
+			wantFailing: []string{MongoDBCheck},
+		},
+		{
+			name:        "scheduler stopped",
+			wantStatus:  http.StatusServiceUnavailable,
+			wantFailing: []string{AutoCloseCheck},
+		},
+		{
+			name:        "shutting down",
+			running:     true,
+			draining:    true,
+			wantStatus:  http.StatusServiceUnavailable,
+			wantFailing: []string{ShuttingDownCheck},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthController := NewHealthController(
+				&fakeDatabase{err: tt.pingErr}, &fakeAutoClose{running: tt.running}, time.Second)
+			if tt.draining {
+				healthController.StartDraining()
+			}
+
+			router := gin.New()
+			router.GET("/readyz", healthController.Readyz)
+			router.GET("/healthz", healthController.Healthz)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			var readiness ReadinessOutputDTO
+			assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &readiness))
+			assert.Equal(t, tt.wantFailing, readiness.Failing)
+
+			recorder = httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			assert.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+}
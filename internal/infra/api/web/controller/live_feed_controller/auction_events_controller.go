@@ -0,0 +1,139 @@
+package live_feed_controller
+
+import (
+	"fmt"
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"fullcycle-auction_go/internal/infra/api/web/live_feed"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	streamRetryAfter = 5 * time.Second
+
+	defaultMaxStreamsPerAuction = 500
+	defaultHeartbeatInterval    = 15 * time.Second
+)
+
+// WithStreamLimits caps the Server-Sent Events streams of each auction at
+// maxStreamsPerAuction and keeps idle ones open with a heartbeat every
+// heartbeatInterval.
+func WithStreamLimits(maxStreamsPerAuction int, heartbeatInterval time.Duration) LiveFeedControllerOption {
+	return func(u *LiveFeedController) {
+		u.maxStreamsPerAuction = maxStreamsPerAuction
+		u.heartbeatInterval = heartbeatInterval
+	}
+}
+
+var sseEventNames = map[string]string{
+	event_entity.BidAcceptedEventName:     "bid",
+	event_entity.AuctionExtendedEventName: "extended",
+	event_entity.AuctionClosedEventName:   "closed",
+}
+
+// StreamAuctionEvents streams the feed of one auction as Server-Sent Events,
+// for clients that cannot open a WebSocket. A client reconnecting with
+// Last-Event-ID first gets the events it missed.
+func (u *LiveFeedController) StreamAuctionEvents(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		middleware.AbortWithError(c, errRest)
+		return
+	}
+
+	lastEventId, errRest := parseLastEventId(c)
+	if errRest != nil {
+		middleware.AbortWithError(c, errRest)
+		return
+	}
+
+	subscription, err := u.hub.Subscribe(
+		auctionId, live_feed.ResumeAfter(lastEventId), live_feed.LimitSubscribers(u.maxStreamsPerAuction))
+	if err != nil {
+		errRest := rest_err.NewTooManyRequestsError(err.Error(), streamRetryAfter)
+		middleware.AbortWithError(c, errRest)
+		return
+	}
+	defer u.hub.Unsubscribe(subscription)
+
+	auctionData, findErr := u.auctionUseCase.FindAuctionById(middleware.RequestContext(c), auctionId, middleware.UserId(c))
+	if findErr != nil {
+		errRest := rest_err.ConvertError(findErr)
+		middleware.AbortWithError(c, errRest)
+		return
+	}
+
+	closed := auctionData.Status == auction_usecase.AuctionStatus(auction_entity.Completed) ||
+		auctionData.Status == auction_usecase.AuctionStatus(auction_entity.Cancelled)
+	if closed && len(subscription.Frames()) == 0 {
+		// 204 tells EventSource clients to stop reconnecting.
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(u.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+			c.Writer.Flush()
+		case frame, ok := <-subscription.Frames():
+			if !ok {
+				return
+			}
+
+			fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", frame.Id, sseEventNames[frame.Type], frame.Data)
+			c.Writer.Flush()
+
+			if closed && len(subscription.Frames()) == 0 {
+				return
+			}
+		}
+	}
+}
+
+// parseLastEventId also accepts a last_event_id query parameter, for clients
+// that cannot set headers on reconnect.
+func parseLastEventId(c *gin.Context) (uint64, *rest_err.RestErr) {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	lastEventId, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "Last-Event-ID",
+			Message: "Last-Event-ID must be a positive number",
+		})
+	}
+
+	return lastEventId, nil
+}
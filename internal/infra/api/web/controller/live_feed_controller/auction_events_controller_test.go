@@ -0,0 +1,90 @@
+package live_feed_controller
+
+import (
+	"bufio"
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"fullcycle-auction_go/internal/infra/api/web/live_feed"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newEventsTestServer(
+	status auction_entity.AuctionStatus, hub *live_feed.Hub, opts ...LiveFeedControllerOption) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+
+	controller := NewLiveFeedController(&fakeAuctionUseCase{status: status}, hub, opts...)
+	router := gin.New()
+	router.GET("/auction/:auctionId/events", controller.StreamAuctionEvents)
+
+	return httptest.NewServer(router)
+}
+
+func readEvents(response *http.Response) []string {
+	var lines []string
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "id:") || strings.HasPrefix(line, "event:") {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func TestAuctionEventsResumeAfterLastEventId(t *testing.T) {
+	hub := live_feed.NewHub()
+	server := newEventsTestServer(auction_entity.Active, hub)
+	defer server.Close()
+
+	auctionId := uuid.New().String()
+	ctx := context.Background()
+	hub.Publish(ctx, event_entity.BidAcceptedEvent{AuctionId: auctionId, BidId: "first"})
+	hub.Publish(ctx, event_entity.AuctionExtendedEvent{AuctionId: auctionId})
+
+	request, _ := http.NewRequest(http.MethodGet, server.URL+"/auction/"+auctionId+"/events", nil)
+	request.Header.Set("Last-Event-ID", "1")
+	response, err := http.DefaultClient.Do(request)
+	assert.Nil(t, err)
+	defer response.Body.Close()
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+
+	hub.Publish(ctx, event_entity.AuctionClosedEvent{AuctionId: auctionId, CloseReason: "expired"})
+
+	assert.Equal(t, []string{"id: 2", "event: extended", "id: 3", "event: closed"}, readEvents(response))
+}
+
+func TestAuctionEventsOfAClosedAuctionWithNothingToReplay(t *testing.T) {
+	server := newEventsTestServer(auction_entity.Completed, live_feed.NewHub())
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/auction/" + uuid.New().String() + "/events")
+	assert.Nil(t, err)
+	defer response.Body.Close()
+
+	assert.Equal(t, http.StatusNoContent, response.StatusCode)
+}
+
+func TestAuctionEventsLimitStreamsPerAuction(t *testing.T) {
+	hub := live_feed.NewHub()
+	server := newEventsTestServer(auction_entity.Active, hub, WithStreamLimits(1, defaultHeartbeatInterval))
+	defer server.Close()
+
+	auctionId := uuid.New().String()
+	_, err := hub.Subscribe(auctionId)
+	assert.Nil(t, err)
+
+	response, err := http.Get(server.URL + "/auction/" + auctionId + "/events")
+	assert.Nil(t, err)
+	defer response.Body.Close()
+
+	assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+	assert.Equal(t, "5", response.Header.Get("Retry-After"))
+}
@@ -0,0 +1,114 @@
+package live_feed_controller
+
+import (
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/api/web/live_feed"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/net/websocket"
+	"net/http"
+	"time"
+)
+
+const defaultWriteTimeout = 10 * time.Second
+
+type LiveFeedController struct {
+	auctionUseCase       auction_usecase.AuctionUseCaseInterface
+	hub                  *live_feed.Hub
+	writeTimeout         time.Duration
+	maxStreamsPerAuction int
+	heartbeatInterval    time.Duration
+}
+
+type LiveFeedControllerOption func(*LiveFeedController)
+
+// WithWriteTimeout drops a WebSocket client that takes longer than timeout to
+// accept a frame.
+func WithWriteTimeout(timeout time.Duration) LiveFeedControllerOption {
+	return func(u *LiveFeedController) {
+		u.writeTimeout = timeout
+	}
+}
+
+func NewLiveFeedController(
+	auctionUseCase auction_usecase.AuctionUseCaseInterface,
+	hub *live_feed.Hub,
+	opts ...LiveFeedControllerOption) *LiveFeedController {
+	u := &LiveFeedController{
+		auctionUseCase:       auctionUseCase,
+		hub:                  hub,
+		writeTimeout:         defaultWriteTimeout,
+		maxStreamsPerAuction: defaultMaxStreamsPerAuction,
+		heartbeatInterval:    defaultHeartbeatInterval,
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+
+	return u
+}
+
+// FollowAuction upgrades to a WebSocket that streams the bids and status
+// changes of one auction until it closes.
+func (u *LiveFeedController) FollowAuction(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		middleware.AbortWithError(c, errRest)
+		return
+	}
+
+	// Subscribing before reading the auction means a close that lands in
+	// between still reaches this client.
+	subscription, _ := u.hub.Subscribe(auctionId)
+	defer u.hub.Unsubscribe(subscription)
+
+	auctionData, err := u.auctionUseCase.FindAuctionById(middleware.RequestContext(c), auctionId, middleware.UserId(c))
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		middleware.AbortWithError(c, errRest)
+		return
+	}
+
+	if auctionData.Status == auction_usecase.AuctionStatus(auction_entity.Completed) ||
+		auctionData.Status == auction_usecase.AuctionStatus(auction_entity.Cancelled) {
+		errRest := rest_err.NewConflictError("Auction is already closed")
+		middleware.AbortWithError(c, errRest)
+		return
+	}
+
+	server := websocket.Server{
+		Handshake: func(config *websocket.Config, r *http.Request) error { return nil },
+		Handler: func(conn *websocket.Conn) {
+			u.stream(conn, subscription)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+func (u *LiveFeedController) stream(conn *websocket.Conn, subscription *live_feed.Subscription) {
+	go func() {
+		var discarded string
+		for {
+			if err := websocket.Message.Receive(conn, &discarded); err != nil {
+				u.hub.Unsubscribe(subscription)
+				return
+			}
+		}
+	}()
+
+	for frame := range subscription.Frames() {
+		conn.SetWriteDeadline(time.Now().Add(u.writeTimeout))
+		if err := websocket.Message.Send(conn, string(frame.Message)); err != nil {
+			return
+		}
+	}
+}
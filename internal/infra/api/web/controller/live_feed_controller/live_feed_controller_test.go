@@ -0,0 +1,82 @@
+package live_feed_controller
+
+import (
+	"context"
+	"encoding/json"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"fullcycle-auction_go/internal/infra/api/web/live_feed"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/usecase/auction_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeAuctionUseCase struct {
+	auction_usecase.AuctionUseCaseInterface
+	status auction_entity.AuctionStatus
+}
+
+func (f *fakeAuctionUseCase) FindAuctionById(
+	ctx context.Context, id, callerId string) (*auction_usecase.AuctionOutputDTO, *internal_error.InternalError) {
+	return &auction_usecase.AuctionOutputDTO{Id: id, Status: auction_usecase.AuctionStatus(f.status)}, nil
+}
+
+func newTestServer(status auction_entity.AuctionStatus, hub *live_feed.Hub) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+
+	controller := NewLiveFeedController(&fakeAuctionUseCase{status: status}, hub)
+	router := gin.New()
+	router.GET("/ws/auction/:auctionId", controller.FollowAuction)
+
+	return httptest.NewServer(router)
+}
+
+func TestLiveFeedStreamsEventsAndEndsWithAuctionClosed(t *testing.T) {
+	hub := live_feed.NewHub()
+	server := newTestServer(auction_entity.Active, hub)
+	defer server.Close()
+
+	auctionId := uuid.New().String()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/auction/" + auctionId
+	conn, err := websocket.Dial(url, "", server.URL)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	// The subscription is registered before the upgrade completes.
+	hub.Publish(context.Background(), event_entity.BidAcceptedEvent{AuctionId: auctionId, BidId: "bid", Amount: 100})
+	hub.Publish(context.Background(), event_entity.AuctionClosedEvent{AuctionId: auctionId, CloseReason: "expired"})
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var types []string
+	for {
+		var frame string
+		if err := websocket.Message.Receive(conn, &frame); err != nil {
+			break
+		}
+
+		var message map[string]interface{}
+		assert.Nil(t, json.Unmarshal([]byte(frame), &message))
+		types = append(types, message["type"].(string))
+	}
+
+	assert.Equal(t, []string{event_entity.BidAcceptedEventName, event_entity.AuctionClosedEventName}, types)
+}
+
+func TestLiveFeedRejectsClosedAuctions(t *testing.T) {
+	server := newTestServer(auction_entity.Completed, live_feed.NewHub())
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/ws/auction/" + uuid.New().String())
+	assert.Nil(t, err)
+	defer response.Body.Close()
+
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+}
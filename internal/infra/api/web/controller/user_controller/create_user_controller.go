@@ -0,0 +1,31 @@
+package user_controller
+
+import (
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/infra/api/web/validation"
+	"fullcycle-auction_go/internal/usecase/user_usecase"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (u *UserController) CreateUser(c *gin.Context) {
+	var userInputDTO user_usecase.UserInputDTO
+
+	if err := c.ShouldBindJSON(&userInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		middleware.AbortWithError(c, restErr)
+		return
+	}
+
+	userData, err := u.userUseCase.CreateUser(middleware.RequestContext(c), userInputDTO)
+	if err != nil {
+		restErr := rest_err.ConvertError(err)
+
+		middleware.AbortWithError(c, restErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, userData)
+}
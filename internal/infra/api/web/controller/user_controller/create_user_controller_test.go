@@ -0,0 +1,64 @@
+package user_controller
+
+import (
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreateUserStatusCodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        *internal_error.InternalError
+		wantStatus int
+	}{
+		{
+			name:       "created",
+			body:       `{"name":"Ana","email":"ana@example.com","password":"s3cret-pass"}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "name too short",
+			body:       `{"name":"A","email":"ana@example.com","password":"s3cret-pass"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "invalid email",
+			body:       `{"name":"Ana","email":"not-an-email","password":"s3cret-pass"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "password too short",
+			body:       `{"name":"Ana","email":"ana@example.com","password":"short"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "duplicate email",
+			body:       `{"name":"Ana","email":"ana@example.com","password":"s3cret-pass"}`,
+			err:        internal_error.NewConflictError("A user with this email already exists"),
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/user", NewUserController(&fakeUserUseCase{err: tt.err}).CreateUser)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/user", strings.NewReader(tt.body))
+			request.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(recorder, request)
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			assert.NotContains(t, recorder.Body.String(), `"password":`)
+		})
+	}
+}
@@ -1,8 +1,8 @@
 package user_controller
 
 import (
-	"context"
 	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
 	"fullcycle-auction_go/internal/usecase/user_usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -28,14 +28,14 @@ func (u *UserController) FindUserById(c *gin.Context) {
 			Message: "Invalid UUID value",
 		})
 
-		c.JSON(errRest.Code, errRest)
+		middleware.AbortWithError(c, errRest)
 		return
 	}
 
-	userData, err := u.userUseCase.FindUserById(context.Background(), userId)
+	userData, err := u.userUseCase.FindUserById(middleware.RequestContext(c), userId)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
-		c.JSON(errRest.Code, errRest)
+		middleware.AbortWithError(c, errRest)
 		return
 	}
 
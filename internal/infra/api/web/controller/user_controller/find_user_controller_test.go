@@ -0,0 +1,68 @@
+package user_controller
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/usecase/user_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeUserUseCase struct {
+	user_usecase.UserUseCaseInterface
+	err *internal_error.InternalError
+}
+
+func (f *fakeUserUseCase) CreateUser(
+	ctx context.Context, userInput user_usecase.UserInputDTO) (*user_usecase.UserOutputDTO, *internal_error.InternalError) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &user_usecase.UserOutputDTO{Id: uuid.New().String(), Name: userInput.Name, Email: userInput.Email}, nil
+}
+
+func (f *fakeUserUseCase) FindUserById(
+	ctx context.Context, id string) (*user_usecase.UserOutputDTO, *internal_error.InternalError) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &user_usecase.UserOutputDTO{Id: id, Name: "user"}, nil
+}
+
+func TestFindUserByIdStatusCodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		userId     string
+		err        *internal_error.InternalError
+		wantStatus int
+	}{
+		{name: "found", userId: uuid.New().String(), wantStatus: http.StatusOK},
+		{name: "invalid id", userId: "not-a-uuid", wantStatus: http.StatusBadRequest},
+		{
+			name:       "not found",
+			userId:     uuid.New().String(),
+			err:        internal_error.NewNotFoundError("user not found"),
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/user/:userId", NewUserController(&fakeUserUseCase{err: tt.err}).FindUserById)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/user/"+tt.userId, nil))
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+		})
+	}
+}
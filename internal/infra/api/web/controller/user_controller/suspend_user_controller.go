@@ -0,0 +1,42 @@
+package user_controller
+
+import (
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/infra/api/web/validation"
+	"fullcycle-auction_go/internal/usecase/user_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func (u *UserController) SuspendUser(c *gin.Context) {
+	userId := c.Param("userId")
+
+	if err := uuid.Validate(userId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "userId",
+			Message: "Invalid UUID value",
+		})
+
+		middleware.AbortWithError(c, errRest)
+		return
+	}
+
+	var suspendInputDTO user_usecase.SuspendUserInputDTO
+	if err := c.ShouldBindJSON(&suspendInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		middleware.AbortWithError(c, restErr)
+		return
+	}
+
+	statusData, err := u.userUseCase.SuspendUser(middleware.RequestContext(c), userId, suspendInputDTO)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		middleware.AbortWithError(c, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusData)
+}
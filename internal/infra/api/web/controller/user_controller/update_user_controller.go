@@ -0,0 +1,29 @@
+package user_controller
+
+import (
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/infra/api/web/validation"
+	"fullcycle-auction_go/internal/usecase/user_usecase"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (u *UserController) UpdateMe(c *gin.Context) {
+	var updateInputDTO user_usecase.UpdateUserInputDTO
+	if err := c.ShouldBindJSON(&updateInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		middleware.AbortWithError(c, restErr)
+		return
+	}
+
+	userData, err := u.userUseCase.UpdateUser(middleware.RequestContext(c), middleware.UserId(c), updateInputDTO)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		middleware.AbortWithError(c, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, userData)
+}
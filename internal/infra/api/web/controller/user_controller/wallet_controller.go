@@ -0,0 +1,99 @@
+package user_controller
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/infra/api/web/validation"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"fullcycle-auction_go/internal/usecase/user_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+)
+
+func (u *UserController) FindMyWalletTransactions(c *gin.Context) {
+	findInput := user_usecase.FindWalletTransactionsInputDTO{}
+
+	if limit := c.Query("limit"); limit != "" {
+		limitNumber, err := strconv.Atoi(limit)
+		if err != nil {
+			errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+				Field:   "limit",
+				Message: "limit must be a number",
+			})
+			middleware.AbortWithError(c, errRest)
+			return
+		}
+		findInput.Limit = limitNumber
+	}
+
+	if offset := c.Query("offset"); offset != "" {
+		offsetNumber, err := strconv.Atoi(offset)
+		if err != nil {
+			errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+				Field:   "offset",
+				Message: "offset must be a number",
+			})
+			middleware.AbortWithError(c, errRest)
+			return
+		}
+		findInput.Offset = offsetNumber
+	}
+
+	transactionPage, err := u.userUseCase.FindWalletTransactions(
+		middleware.RequestContext(c), middleware.UserId(c), findInput)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		middleware.AbortWithError(c, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, transactionPage)
+}
+
+func (u *UserController) CreditWallet(c *gin.Context) {
+	u.adjustWallet(c, u.userUseCase.CreditWallet)
+}
+
+func (u *UserController) DebitWallet(c *gin.Context) {
+	u.adjustWallet(c, u.userUseCase.DebitWallet)
+}
+
+func (u *UserController) adjustWallet(
+	c *gin.Context,
+	adjust func(
+		ctx context.Context,
+		userId string,
+		amount money.Amount) (*user_usecase.WalletOutputDTO, *internal_error.InternalError)) {
+	userId := c.Param("userId")
+
+	if err := uuid.Validate(userId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "userId",
+			Message: "Invalid UUID value",
+		})
+
+		middleware.AbortWithError(c, errRest)
+		return
+	}
+
+	var adjustmentInputDTO user_usecase.WalletAdjustmentInputDTO
+	if err := c.ShouldBindJSON(&adjustmentInputDTO); err != nil {
+		restErr := validation.ValidateErr(err)
+
+		middleware.AbortWithError(c, restErr)
+		return
+	}
+
+	walletData, err := adjust(middleware.RequestContext(c), userId, adjustmentInputDTO.Amount)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		middleware.AbortWithError(c, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, walletData)
+}
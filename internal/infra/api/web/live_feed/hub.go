@@ -0,0 +1,288 @@
+package live_feed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBufferSize       = 64
+	defaultHistorySize      = 100
+	defaultHistoryRetention = 5 * time.Minute
+)
+
+var ErrTooManySubscribers = errors.New("too many clients are following this auction")
+
+// Message is the JSON frame sent to WebSocket clients.
+type Message struct {
+	Id        uint64             `json:"id"`
+	Type      string             `json:"type"`
+	AuctionId string             `json:"auction_id"`
+	Data      event_entity.Event `json:"data"`
+}
+
+// Frame is one event of an auction feed. Ids grow per auction, so a client
+// can resume after the last one it saw.
+type Frame struct {
+	Id      uint64
+	Type    string
+	Data    []byte
+	Message []byte
+}
+
+// Subscription receives the frames of one auction. Its channel is closed when
+// the auction closes, the client falls behind or the hub shuts down.
+type Subscription struct {
+	auctionId string
+	frames    chan Frame
+}
+
+func (s *Subscription) Frames() <-chan Frame {
+	return s.frames
+}
+
+type SubscribeOption func(*subscribeOptions)
+
+type subscribeOptions struct {
+	lastEventId    uint64
+	maxSubscribers int
+}
+
+// ResumeAfter replays the recent frames that came after lastEventId.
+func ResumeAfter(lastEventId uint64) SubscribeOption {
+	return func(options *subscribeOptions) {
+		options.lastEventId = lastEventId
+	}
+}
+
+// LimitSubscribers refuses the subscription when the auction already has
+// maxSubscribers clients.
+func LimitSubscribers(maxSubscribers int) SubscribeOption {
+	return func(options *subscribeOptions) {
+		options.maxSubscribers = maxSubscribers
+	}
+}
+
+type auctionFeed struct {
+	subscriptions map[*Subscription]struct{}
+	history       []Frame
+	lastId        uint64
+	closed        bool
+}
+
+// Hub fans auction events out to the clients following each auction and
+// keeps the latest frames of every auction for clients that reconnect. It is
+// an event publisher itself, so publishing never waits on a client: a client
+// whose buffer is full is dropped.
+type Hub struct {
+	mutex            sync.Mutex
+	feeds            map[string]*auctionFeed
+	bufferSize       int
+	historySize      int
+	historyRetention time.Duration
+	closed           bool
+}
+
+type HubOption func(*Hub)
+
+// WithBufferSize sets how many frames a client may fall behind before it is
+// dropped.
+func WithBufferSize(bufferSize int) HubOption {
+	return func(h *Hub) {
+		h.bufferSize = bufferSize
+	}
+}
+
+// WithHistory keeps the latest size frames of every auction, for up to
+// retention, to replay to clients that reconnect.
+func WithHistory(size int, retention time.Duration) HubOption {
+	return func(h *Hub) {
+		h.historySize = size
+		h.historyRetention = retention
+	}
+}
+
+func NewHub(options ...HubOption) *Hub {
+	h := &Hub{
+		feeds:            make(map[string]*auctionFeed),
+		bufferSize:       defaultBufferSize,
+		historySize:      defaultHistorySize,
+		historyRetention: defaultHistoryRetention,
+	}
+	for _, option := range options {
+		option(h)
+	}
+
+	return h
+}
+
+func (h *Hub) Subscribe(auctionId string, options ...SubscribeOption) (*Subscription, error) {
+	var subscribe subscribeOptions
+	for _, option := range options {
+		option(&subscribe)
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	feed := h.feed(auctionId)
+	if subscribe.maxSubscribers > 0 && len(feed.subscriptions) >= subscribe.maxSubscribers {
+		return nil, ErrTooManySubscribers
+	}
+
+	var replay []Frame
+	if subscribe.lastEventId > 0 && subscribe.lastEventId <= feed.lastId {
+		for _, frame := range feed.history {
+			if frame.Id > subscribe.lastEventId {
+				replay = append(replay, frame)
+			}
+		}
+	}
+
+	subscription := &Subscription{auctionId: auctionId, frames: make(chan Frame, h.bufferSize+len(replay))}
+	for _, frame := range replay {
+		subscription.frames <- frame
+	}
+
+	if h.closed || feed.closed {
+		close(subscription.frames)
+		return subscription, nil
+	}
+
+	feed.subscriptions[subscription] = struct{}{}
+	return subscription, nil
+}
+
+func (h *Hub) Unsubscribe(subscription *Subscription) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.remove(subscription)
+}
+
+func (h *Hub) Publish(ctx context.Context, event event_entity.Event) {
+	auctionId, final, ok := auctionOf(event)
+	if !ok {
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	feed := h.feed(auctionId)
+	if feed.closed {
+		return
+	}
+
+	frame, err := newFrame(feed.lastId+1, auctionId, event)
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to encode live feed event for auction %s", auctionId), err)
+		return
+	}
+
+	feed.lastId = frame.Id
+	feed.history = append(feed.history, frame)
+	if len(feed.history) > h.historySize {
+		feed.history = feed.history[len(feed.history)-h.historySize:]
+	}
+
+	for subscription := range feed.subscriptions {
+		select {
+		case subscription.frames <- frame:
+		default:
+			logger.WarnContext(ctx, fmt.Sprintf("Dropping slow live feed client of auction %s", auctionId))
+			h.remove(subscription)
+		}
+	}
+
+	if final {
+		feed.closed = true
+		for subscription := range feed.subscriptions {
+			h.remove(subscription)
+		}
+		time.AfterFunc(h.historyRetention, func() { h.forget(auctionId, feed) })
+	}
+}
+
+// Close ends every subscription, which closes their sockets.
+func (h *Hub) Close() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.closed = true
+	for _, feed := range h.feeds {
+		for subscription := range feed.subscriptions {
+			h.remove(subscription)
+		}
+	}
+}
+
+func (h *Hub) feed(auctionId string) *auctionFeed {
+	feed, ok := h.feeds[auctionId]
+	if !ok {
+		feed = &auctionFeed{subscriptions: make(map[*Subscription]struct{})}
+		h.feeds[auctionId] = feed
+	}
+
+	return feed
+}
+
+// forget drops the history of a closed auction once reconnecting clients no
+// longer need it.
+func (h *Hub) forget(auctionId string, feed *auctionFeed) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.feeds[auctionId] == feed {
+		delete(h.feeds, auctionId)
+	}
+}
+
+func (h *Hub) remove(subscription *Subscription) {
+	feed, ok := h.feeds[subscription.auctionId]
+	if !ok {
+		return
+	}
+	if _, ok := feed.subscriptions[subscription]; !ok {
+		return
+	}
+
+	delete(feed.subscriptions, subscription)
+	close(subscription.frames)
+	if len(feed.subscriptions) == 0 && len(feed.history) == 0 {
+		delete(h.feeds, subscription.auctionId)
+	}
+}
+
+func newFrame(id uint64, auctionId string, event event_entity.Event) (Frame, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return Frame{}, err
+	}
+
+	message, err := json.Marshal(Message{Id: id, Type: event.Name(), AuctionId: auctionId, Data: event})
+	if err != nil {
+		return Frame{}, err
+	}
+
+	return Frame{Id: id, Type: event.Name(), Data: data, Message: message}, nil
+}
+
+func auctionOf(event event_entity.Event) (auctionId string, final bool, ok bool) {
+	switch e := event.(type) {
+	case event_entity.BidAcceptedEvent:
+		return e.AuctionId, false, true
+	case event_entity.AuctionExtendedEvent:
+		return e.AuctionId, false, true
+	case event_entity.AuctionClosedEvent:
+		return e.AuctionId, true, true
+	}
+
+	return "", false, false
+}
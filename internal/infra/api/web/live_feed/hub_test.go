@@ -0,0 +1,95 @@
+package live_feed
+
+import (
+	"context"
+	"encoding/json"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func receiveAll(subscription *Subscription) []Message {
+	var messages []Message
+	for frame := range subscription.Frames() {
+		var message struct {
+			Id        uint64 `json:"id"`
+			Type      string `json:"type"`
+			AuctionId string `json:"auction_id"`
+		}
+		json.Unmarshal(frame.Message, &message)
+		messages = append(messages, Message{Id: message.Id, Type: message.Type, AuctionId: message.AuctionId})
+	}
+	return messages
+}
+
+func TestHubFansOutEventsUntilTheAuctionCloses(t *testing.T) {
+	hub := NewHub()
+	first, _ := hub.Subscribe("auction")
+	second, _ := hub.Subscribe("auction")
+	other, _ := hub.Subscribe("other")
+
+	ctx := context.Background()
+	hub.Publish(ctx, event_entity.BidAcceptedEvent{AuctionId: "auction", BidId: "bid", Amount: 100})
+	hub.Publish(ctx, event_entity.OutbidEvent{AuctionId: "auction", PreviousLeaderUserId: "alice"})
+	hub.Publish(ctx, event_entity.AuctionExtendedEvent{AuctionId: "auction"})
+	hub.Publish(ctx, event_entity.AuctionClosedEvent{AuctionId: "auction", CloseReason: "expired"})
+
+	expected := []Message{
+		{Id: 1, Type: event_entity.BidAcceptedEventName, AuctionId: "auction"},
+		{Id: 2, Type: event_entity.AuctionExtendedEventName, AuctionId: "auction"},
+		{Id: 3, Type: event_entity.AuctionClosedEventName, AuctionId: "auction"},
+	}
+	assert.Equal(t, expected, receiveAll(first))
+	assert.Equal(t, expected, receiveAll(second))
+	assert.Empty(t, other.Frames())
+
+	hub.Close()
+	assert.Empty(t, receiveAll(other))
+}
+
+func TestHubDropsSlowClients(t *testing.T) {
+	hub := NewHub(WithBufferSize(1))
+	slow, _ := hub.Subscribe("auction")
+
+	ctx := context.Background()
+	hub.Publish(ctx, event_entity.BidAcceptedEvent{AuctionId: "auction", BidId: "first"})
+	hub.Publish(ctx, event_entity.BidAcceptedEvent{AuctionId: "auction", BidId: "second"})
+
+	assert.Len(t, receiveAll(slow), 1)
+
+	hub.Unsubscribe(slow)
+	hub.Publish(ctx, event_entity.BidAcceptedEvent{AuctionId: "auction", BidId: "third"})
+}
+
+func TestHubReplaysFramesAfterTheLastEventId(t *testing.T) {
+	hub := NewHub()
+
+	ctx := context.Background()
+	hub.Publish(ctx, event_entity.BidAcceptedEvent{AuctionId: "auction", BidId: "first"})
+	hub.Publish(ctx, event_entity.BidAcceptedEvent{AuctionId: "auction", BidId: "second"})
+
+	resumed, err := hub.Subscribe("auction", ResumeAfter(1))
+	assert.Nil(t, err)
+	hub.Publish(ctx, event_entity.AuctionClosedEvent{AuctionId: "auction"})
+
+	messages := receiveAll(resumed)
+	assert.Equal(t, []uint64{2, 3}, []uint64{messages[0].Id, messages[1].Id})
+
+	afterClose, err := hub.Subscribe("auction", ResumeAfter(2))
+	assert.Nil(t, err)
+	assert.Equal(t, []Message{{Id: 3, Type: event_entity.AuctionClosedEventName, AuctionId: "auction"}},
+		receiveAll(afterClose))
+}
+
+func TestHubLimitsSubscribersPerAuction(t *testing.T) {
+	hub := NewHub()
+
+	_, err := hub.Subscribe("auction", LimitSubscribers(1))
+	assert.Nil(t, err)
+
+	_, err = hub.Subscribe("auction", LimitSubscribers(1))
+	assert.Equal(t, ErrTooManySubscribers, err)
+
+	_, err = hub.Subscribe("other", LimitSubscribers(1))
+	assert.Nil(t, err)
+}
@@ -0,0 +1,11 @@
+package middleware
+
+import (
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"github.com/gin-gonic/gin"
+)
+
+// IsAdmin reports whether the authenticated user is an administrator.
+func IsAdmin(c *gin.Context) bool {
+	return HasRole(c, user_entity.AdminRole)
+}
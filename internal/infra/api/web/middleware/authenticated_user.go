@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/auth"
+	"github.com/gin-gonic/gin"
+	"strings"
+)
+
+const userIdKey = "userId"
+
+type AccessTokenVerifier interface {
+	VerifyAccessToken(token string) (*auth.Claims, error)
+}
+
+// Authenticate identifies the caller from the bearer token. Requests without
+// one stay anonymous, but an invalid or expired token is refused so the
+// client knows to log in or refresh it.
+func Authenticate(tokenVerifier AccessTokenVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authorization := c.GetHeader("Authorization")
+		if authorization == "" {
+			c.Next()
+			return
+		}
+
+		token, ok := bearerToken(authorization)
+		if !ok {
+			abortUnauthorized(c, "invalid_request", "The Authorization header must hold a Bearer token")
+			return
+		}
+
+		claims, err := tokenVerifier.VerifyAccessToken(token)
+		if errors.Is(err, auth.ErrExpiredToken) {
+			abortUnauthorized(c, "invalid_token", "The access token has expired, refresh it at /auth/refresh")
+			return
+		}
+		if err != nil {
+			abortUnauthorized(c, "invalid_token", "The access token is invalid")
+			return
+		}
+
+		c.Set(userIdKey, claims.Subject)
+		c.Next()
+	}
+}
+
+func bearerToken(authorization string) (string, bool) {
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+
+	return token, true
+}
+
+func abortUnauthorized(c *gin.Context, code, message string) {
+	c.Header("WWW-Authenticate", fmt.Sprintf(`Bearer error=%q, error_description=%q`, code, message))
+
+	errRest := rest_err.NewUnauthorizedError(message)
+	AbortWithError(c, errRest)
+}
+
+func RequireUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if UserId(c) == "" {
+			c.Header("WWW-Authenticate", "Bearer")
+
+			errRest := rest_err.NewUnauthorizedError("Authentication is required")
+			AbortWithError(c, errRest)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func UserId(c *gin.Context) string {
+	return c.GetString(userIdKey)
+}
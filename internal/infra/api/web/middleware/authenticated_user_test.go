@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/auth"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	clock := fakeclock.New(time.Now())
+	tokenService := auth.NewTokenServiceWithClock([]byte("test-secret"), time.Minute, time.Hour, clock)
+	tokens, _ := tokenService.Issue("user-id")
+
+	router := gin.New()
+	router.Use(Authenticate(tokenService))
+	router.GET("/me", RequireUser(), func(c *gin.Context) {
+		c.String(http.StatusOK, UserId(c))
+	})
+
+	request := func(authorization string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		httpRequest := httptest.NewRequest(http.MethodGet, "/me", nil)
+		if authorization != "" {
+			httpRequest.Header.Set("Authorization", authorization)
+		}
+		router.ServeHTTP(recorder, httpRequest)
+		return recorder
+	}
+
+	response := request("Bearer " + tokens.AccessToken)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "user-id", response.Body.String())
+
+	response = request("")
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Equal(t, "Bearer", response.Header().Get("WWW-Authenticate"))
+
+	response = request("Basic dXNlcjpwYXNz")
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+
+	clock.Advance(time.Minute)
+	response = request("Bearer " + tokens.AccessToken)
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Contains(t, response.Header().Get("WWW-Authenticate"), `error="invalid_token"`)
+
+	var restErr rest_err.RestErr
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &restErr))
+	assert.Equal(t, "unauthorized", restErr.Code)
+	assert.Contains(t, restErr.Message, "expired")
+}
@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Compress gzips GET responses of at least minSize bytes for clients that
+// accept it. Streams, websocket upgrades and responses that are already
+// encoded or hold compressed media are sent as they are.
+func Compress(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet || c.GetHeader("Upgrade") != "" ||
+			!acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &compressWriter{ResponseWriter: original, minSize: minSize}
+		c.Writer = writer
+
+		c.Next()
+
+		writer.finish()
+		c.Writer = original
+	}
+}
+
+func acceptsGzip(acceptEncoding string) bool {
+	for _, coding := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(coding, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "gzip" && name != "*" {
+			continue
+		}
+
+		quality := 1.0
+		if key, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(key) == "q" {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				quality = parsed
+			}
+		}
+		return quality > 0
+	}
+
+	return false
+}
+
+// compressWriter holds the body back until it reaches minSize, so small
+// responses are not worth the gzip overhead.
+type compressWriter struct {
+	gin.ResponseWriter
+	minSize int
+
+	buffer      bytes.Buffer
+	gzipWriter  *gzip.Writer
+	passthrough bool
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	switch {
+	case w.gzipWriter != nil:
+		return w.gzipWriter.Write(data)
+	case w.passthrough:
+		return w.ResponseWriter.Write(data)
+	case w.buffer.Len() == 0 && !w.compressible():
+		w.passthrough = true
+		return w.ResponseWriter.Write(data)
+	case w.buffer.Len() == 0:
+		w.Header().Add("Vary", "Accept-Encoding")
+	}
+
+	w.buffer.Write(data)
+	if w.buffer.Len() >= w.minSize {
+		if err := w.startGzip(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(data), nil
+}
+
+func (w *compressWriter) WriteString(data string) (int, error) {
+	return w.Write([]byte(data))
+}
+
+func (w *compressWriter) Written() bool {
+	return w.ResponseWriter.Written() || w.buffer.Len() > 0
+}
+
+// Flush sends what is held back uncompressed unless gzip already started,
+// since a handler that flushes wants its bytes on the wire now.
+func (w *compressWriter) Flush() {
+	if w.gzipWriter != nil {
+		w.gzipWriter.Flush()
+	} else if w.buffer.Len() > 0 {
+		w.passthrough = true
+		w.ResponseWriter.Write(w.buffer.Bytes())
+		w.buffer.Reset()
+	}
+
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) compressible() bool {
+	status := w.ResponseWriter.Status()
+	if status == http.StatusNoContent || status == http.StatusNotModified || status < http.StatusOK {
+		return false
+	}
+
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	switch {
+	case strings.HasPrefix(contentType, "text/event-stream"):
+		return false
+	case strings.HasPrefix(contentType, "text/"):
+		return true
+	default:
+		return strings.Contains(contentType, "json") || strings.Contains(contentType, "xml") ||
+			strings.Contains(contentType, "javascript")
+	}
+}
+
+func (w *compressWriter) startGzip() error {
+	header := w.Header()
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+
+	w.gzipWriter = gzip.NewWriter(w.ResponseWriter)
+	_, err := w.gzipWriter.Write(w.buffer.Bytes())
+	w.buffer.Reset()
+	return err
+}
+
+func (w *compressWriter) finish() {
+	if w.gzipWriter != nil {
+		w.gzipWriter.Close()
+		return
+	}
+
+	if w.buffer.Len() > 0 {
+		w.ResponseWriter.Write(w.buffer.Bytes())
+		w.buffer.Reset()
+	}
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCompressTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Compress(1024))
+	router.GET("/large", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"bids": strings.Repeat("bid ", 1000)})
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	router.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.String(http.StatusOK, strings.Repeat("data: tick\n\n", 200))
+	})
+	router.GET("/archive", func(c *gin.Context) {
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "text/csv", []byte(strings.Repeat("x", 2048)))
+	})
+
+	return router
+}
+
+func get(router *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		request.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestCompressGzipsLargeResponsesForClientsThatAcceptIt(t *testing.T) {
+	recorder := get(newCompressTestRouter(), "/large", "br, gzip;q=0.8")
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", recorder.Header().Get("Vary"))
+
+	reader, err := gzip.NewReader(recorder.Body)
+	assert.Nil(t, err)
+	body, err := io.ReadAll(reader)
+	assert.Nil(t, err)
+	assert.Contains(t, string(body), `{"bids":"bid bid`)
+}
+
+func TestCompressLeavesResponsesAlone(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+	}{
+		{name: "no Accept-Encoding", path: "/large"},
+		{name: "gzip refused", path: "/large", acceptEncoding: "gzip;q=0, identity"},
+		{name: "small response", path: "/small", acceptEncoding: "gzip"},
+		{name: "event stream", path: "/stream", acceptEncoding: "gzip"},
+	}
+
+	router := newCompressTestRouter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := get(router, tt.path, tt.acceptEncoding)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Empty(t, recorder.Header().Get("Content-Encoding"))
+			assert.NotEmpty(t, recorder.Body.String())
+		})
+	}
+
+	recorder := get(router, "/archive", "gzip")
+	assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+	assert.Equal(t, strings.Repeat("x", 2048), recorder.Body.String())
+}
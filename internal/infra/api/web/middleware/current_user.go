@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/gin-gonic/gin"
+)
+
+const userKey = "user"
+
+type UserFinder interface {
+	FindUserById(ctx context.Context, userId string) (*user_entity.User, *internal_error.InternalError)
+}
+
+// LoadUser puts the authenticated user in the context. Callers that are not
+// registered keep their id but have no user, so they act as buyers. It must
+// run after Authenticate.
+func LoadUser(userFinder UserFinder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userId := UserId(c)
+		if userId == "" {
+			c.Next()
+			return
+		}
+
+		userEntity, err := userFinder.FindUserById(c.Request.Context(), userId)
+		if err == nil {
+			c.Set(userKey, userEntity)
+		} else if !err.IsNotFound() {
+			logger.Error(fmt.Sprintf("Error trying to load authenticated user %s", userId), err)
+		}
+
+		c.Next()
+	}
+}
+
+func CurrentUser(c *gin.Context) *user_entity.User {
+	userEntity, _ := c.Get(userKey)
+	currentUser, _ := userEntity.(*user_entity.User)
+	return currentUser
+}
+
+// HasRole reports whether the authenticated user has one of roles, as stored
+// on the user.
+func HasRole(c *gin.Context, roles ...user_entity.Role) bool {
+	userId := UserId(c)
+	if userId == "" {
+		return false
+	}
+
+	currentUser := CurrentUser(c)
+	if currentUser == nil {
+		currentUser = &user_entity.User{Id: userId, Role: user_entity.BuyerRole}
+	}
+
+	return currentUser.HasRole(roles...)
+}
+
+// RequireRole only lets through users with one of roles. It must run after
+// RequireUser.
+func RequireRole(roles ...user_entity.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasRole(c, roles...) {
+			errRest := rest_err.NewForbiddenError("You are not allowed to perform this action")
+			AbortWithError(c, errRest)
+			return
+		}
+
+		c.Next()
+	}
+}
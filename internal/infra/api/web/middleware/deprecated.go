@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+const DeprecationHeader = "Deprecation"
+
+// Deprecated marks the response of an old route and points the client to
+// the same path under successorPrefix.
+func Deprecated(successorPrefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header(DeprecationHeader, "true")
+		c.Header("Link", "<"+successorPrefix+c.Request.URL.Path+`>; rel="successor-version"`)
+
+		c.Next()
+	}
+}
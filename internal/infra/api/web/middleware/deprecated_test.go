@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDeprecatedPointsToTheSuccessorRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	handler := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	router.Group("/api/v1").GET("/auction/:auctionId", handler)
+	router.Group("", Deprecated("/api/v1")).GET("/auction/:auctionId", handler)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/auction/42?status=0", nil))
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.Equal(t, "true", recorder.Header().Get(DeprecationHeader))
+	assert.Equal(t, `</api/v1/auction/42>; rel="successor-version"`, recorder.Header().Get("Link"))
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/auction/42", nil))
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.Empty(t, recorder.Header().Get(DeprecationHeader))
+	assert.Empty(t, recorder.Header().Get("Link"))
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"errors"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// HandleErrors answers with the last error a handler attached with c.Error,
+// so the mapping of error kinds to statuses lives in one place. Handlers that
+// already wrote a response are left alone.
+func HandleErrors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		AbortWithError(c, toRestErr(c, c.Errors.Last().Err))
+	}
+}
+
+// AbortWithError writes restErr tagged with the request id.
+func AbortWithError(c *gin.Context, restErr *rest_err.RestErr) {
+	restErr.RequestId = RequestIdOf(c)
+	if restErr.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.FormatInt(restErr.RetryAfter, 10))
+	}
+
+	c.AbortWithStatusJSON(restErr.Status, restErr)
+}
+
+func toRestErr(c *gin.Context, err error) *rest_err.RestErr {
+	var restErr *rest_err.RestErr
+	if errors.As(err, &restErr) {
+		return restErr
+	}
+
+	var internalError *internal_error.InternalError
+	if errors.As(err, &internalError) {
+		restErr = rest_err.ConvertError(internalError)
+		if restErr.Status == http.StatusInternalServerError && internalError.Cause != nil {
+			logger.ErrorContext(RequestContext(c), internalError.Message, internalError.Cause)
+		}
+		return restErr
+	}
+
+	logger.ErrorContext(RequestContext(c), "Unexpected error handling request", err)
+	return rest_err.NewInternalServerError("Unexpected error handling request")
+}
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandleErrorsMapsErrorKindsToStatuses(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantCode    string
+		wantDetails []rest_err.Causes
+	}{
+		{
+			name:       "not found",
+			err:        internal_error.NewNotFoundError("Auction not found"),
+			wantStatus: http.StatusNotFound,
+			wantCode:   internal_error.ErrNotFound,
+		},
+		{
+			name:        "bad request with fields",
+			err:         internal_error.NewFieldBadRequestError("Invalid auction", "condition", "unknown condition"),
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    internal_error.ErrBadRequest,
+			wantDetails: []rest_err.Causes{{Field: "condition", Message: "unknown condition"}},
+		},
+		{
+			name: "aggregated field violations",
+			err: internal_error.NewValidationError("Invalid auction", []internal_error.FieldViolation{
+				{Field: "product_name", Constraint: "min", Message: "product_name must have at least 2 characters"},
+				{Field: "condition", Constraint: "oneof", Message: "condition must be 0, 1, 2 or 3"},
+			}),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   internal_error.ErrValidation,
+			wantDetails: []rest_err.Causes{
+				{Field: "product_name", Constraint: "min", Message: "product_name must have at least 2 characters"},
+				{Field: "condition", Constraint: "oneof", Message: "condition must be 0, 1, 2 or 3"},
+			},
+		},
+		{
+			name:       "version conflict",
+			err:        internal_error.NewVersionConflictError("Auction changed"),
+			wantStatus: http.StatusConflict,
+			wantCode:   internal_error.ErrConflict,
+		},
+		{
+			name:       "unauthorized",
+			err:        internal_error.NewUnauthorizedError("Log in first"),
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   internal_error.ErrUnauthorized,
+		},
+		{
+			name: "wrapped internal error",
+			err: fmt.Errorf("closing: %w", internal_error.NewInternalServerError("Error trying to close auction").
+				WithCause(errors.New("connection reset"))),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   internal_error.ErrInternalServer,
+		},
+		{
+			name:        "validation",
+			err:         rest_err.NewBadRequestError("Invalid field values", rest_err.Causes{Field: "name"}),
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    internal_error.ErrBadRequest,
+			wantDetails: []rest_err.Causes{{Field: "name"}},
+		},
+		{
+			name:       "unknown error",
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   internal_error.ErrInternalServer,
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(RequestId(), HandleErrors())
+			router.GET("/fail", func(c *gin.Context) {
+				c.Error(tt.err)
+			})
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/fail", nil)
+			request.Header.Set(RequestIdHeader, "ticket-7")
+			router.ServeHTTP(recorder, request)
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+
+			var restErr rest_err.RestErr
+			assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &restErr))
+			assert.Equal(t, tt.wantCode, restErr.Code)
+			assert.Equal(t, tt.wantDetails, restErr.Details)
+			assert.Equal(t, "ticket-7", restErr.RequestId)
+		})
+	}
+}
+
+func TestHandleErrorsSetsRetryAfterAndLeavesWrittenResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(HandleErrors())
+	router.GET("/slow-down", func(c *gin.Context) {
+		c.Error(internal_error.NewTooManyRequestsError("Too many bids", 1500*time.Millisecond))
+	})
+	router.GET("/written", func(c *gin.Context) {
+		c.String(http.StatusOK, "partial export")
+		c.Error(internal_error.NewInternalServerError("Export interrupted"))
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/slow-down", nil))
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "2", recorder.Header().Get("Retry-After"))
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/written", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "partial export", recorder.Body.String())
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"fmt"
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/metrics"
+	"fullcycle-auction_go/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitPolicy is a named limit shared by the routes it is attached to.
+// The limiter is the store of the buckets; the in-memory one only sees the
+// requests of its own instance, so deployments with several instances should
+// plug in a shared one.
+type RateLimitPolicy struct {
+	name     string
+	limiter  ratelimit.Limiter
+	allowed  *metrics.Counter
+	rejected *metrics.Counter
+}
+
+func NewRateLimitPolicy(name string, limiter ratelimit.Limiter) *RateLimitPolicy {
+	return &RateLimitPolicy{
+		name:    name,
+		limiter: limiter,
+		allowed: metrics.Default.NewCounter(
+			fmt.Sprintf("http_rate_limit_%s_allowed_total", name),
+			fmt.Sprintf("Requests let through by the %s rate limit.", name)),
+		rejected: metrics.Default.NewCounter(
+			fmt.Sprintf("http_rate_limit_%s_rejected_total", name),
+			fmt.Sprintf("Requests rejected by the %s rate limit.", name)),
+	}
+}
+
+// RateLimit answers 429 with Retry-After once the caller runs out of tokens.
+// Authenticated callers are limited by user id, everyone else by client IP.
+// It must run after Authenticate. A nil policy or limiter lets everything
+// through.
+func RateLimit(policy *RateLimitPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if policy == nil || policy.limiter == nil {
+			c.Next()
+			return
+		}
+
+		allowed, retryAfter := policy.limiter.Allow(c.Request.Context(), policy.key(c))
+		if !allowed {
+			policy.rejected.Inc()
+
+			AbortWithError(c, rest_err.NewTooManyRequestsError("Too many requests, slow down", retryAfter))
+			return
+		}
+
+		policy.allowed.Inc()
+		c.Next()
+	}
+}
+
+func (p *RateLimitPolicy) key(c *gin.Context) string {
+	if userId := UserId(c); userId != "" {
+		return p.name + ":user:" + userId
+	}
+
+	return p.name + ":ip:" + c.ClientIP()
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"fullcycle-auction_go/internal/infra/auth"
+	"fullcycle-auction_go/internal/ratelimit"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimitKeysByUserOrClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	clock := fakeclock.New(time.Now())
+	tokenService := auth.NewTokenServiceWithClock([]byte("test-secret"), time.Hour, time.Hour, clock)
+	tokens, _ := tokenService.Issue("user-id")
+	policy := NewRateLimitPolicy("test_keys",
+		ratelimit.NewInMemoryLimiter(ratelimit.Rate{Events: 1, Per: 2 * time.Second}, 1, clock))
+
+	router := gin.New()
+	router.Use(Authenticate(tokenService))
+	router.GET("/auction", RateLimit(policy), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request := func(remoteAddr, authorization string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		httpRequest := httptest.NewRequest(http.MethodGet, "/auction", nil)
+		httpRequest.RemoteAddr = remoteAddr
+		if authorization != "" {
+			httpRequest.Header.Set("Authorization", authorization)
+		}
+		router.ServeHTTP(recorder, httpRequest)
+		return recorder
+	}
+
+	assert.Equal(t, http.StatusOK, request("10.0.0.1:1234", "").Code)
+
+	response := request("10.0.0.1:1234", "")
+	assert.Equal(t, http.StatusTooManyRequests, response.Code)
+	assert.Equal(t, "2", response.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, request("10.0.0.2:1234", "").Code)
+	assert.Equal(t, http.StatusOK, request("10.0.0.1:1234", "Bearer "+tokens.AccessToken).Code)
+	assert.Equal(t, http.StatusTooManyRequests, request("10.0.0.3:1234", "Bearer "+tokens.AccessToken).Code)
+
+	clock.Advance(2 * time.Second)
+	assert.Equal(t, http.StatusOK, request("10.0.0.1:1234", "").Code)
+
+	assert.Equal(t, int64(4), policy.allowed.Value())
+	assert.Equal(t, int64(2), policy.rejected.Value())
+}
+
+func TestRateLimitWithoutLimiterLetsEverythingThrough(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/auction", RateLimit(nil), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for i := 0; i < 3; i++ {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/auction", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	RequestIdHeader = "X-Request-ID"
+
+	requestIdKey       = "requestId"
+	maxRequestIdLength = 128
+)
+
+// RequestId keeps the caller's X-Request-ID, or generates one, and echoes it
+// back on the response so a failure can be matched with its log lines.
+func RequestId() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := c.GetHeader(RequestIdHeader)
+		if !validRequestId(requestId) {
+			requestId = uuid.New().String()
+		}
+
+		c.Set(requestIdKey, requestId)
+		c.Request = c.Request.WithContext(logger.WithRequestId(c.Request.Context(), requestId))
+		c.Header(RequestIdHeader, requestId)
+
+		c.Next()
+	}
+}
+
+func RequestIdOf(c *gin.Context) string {
+	return c.GetString(requestIdKey)
+}
+
+// RequestContext is the context handed to use cases. Like the
+// context.Background() it replaces, it is not cancelled when the client goes
+// away, but it carries the request id into the logs.
+func RequestContext(c *gin.Context) context.Context {
+	return logger.WithRequestId(context.Background(), RequestIdOf(c))
+}
+
+// validRequestId rejects ids that would be unwieldy or unsafe to echo in a
+// header or log line.
+func validRequestId(requestId string) bool {
+	if requestId == "" || len(requestId) > maxRequestIdLength {
+		return false
+	}
+
+	for _, r := range requestId {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/configuration/rest_err"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestIdIsEchoedInHeadersContextAndErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var contextRequestId string
+
+	router := gin.New()
+	router.Use(RequestId())
+	router.GET("/fail", func(c *gin.Context) {
+		contextRequestId = logger.RequestId(RequestContext(c))
+		AbortWithError(c, rest_err.NewNotFoundError("Auction not found"))
+	})
+
+	request := func(requestId string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		httpRequest := httptest.NewRequest(http.MethodGet, "/fail", nil)
+		if requestId != "" {
+			httpRequest.Header.Set(RequestIdHeader, requestId)
+		}
+		router.ServeHTTP(recorder, httpRequest)
+		return recorder
+	}
+
+	response := request("ticket-42")
+	assert.Equal(t, "ticket-42", response.Header().Get(RequestIdHeader))
+	assert.Equal(t, "ticket-42", contextRequestId)
+
+	var restErr rest_err.RestErr
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &restErr))
+	assert.Equal(t, "ticket-42", restErr.RequestId)
+
+	for _, unusable := range []string{"", "has spaces", strings.Repeat("a", 129)} {
+		response = request(unusable)
+		assert.Nil(t, uuid.Validate(response.Header().Get(RequestIdHeader)), unusable)
+	}
+}
@@ -0,0 +1,210 @@
+package openapi
+
+import (
+	"fullcycle-auction_go/configuration/rest_err"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	bearerScheme = "bearerAuth"
+	apiV1Prefix  = "/api/v1"
+)
+
+type documentBuilder struct {
+	document *Document
+	schemas  *schemaRegistry
+
+	// pathPrefix is put before the path of every route added while it is set.
+	pathPrefix string
+}
+
+func newDocumentBuilder() *documentBuilder {
+	schemas := newSchemaRegistry()
+	schemas.schemaOf(rest_err.RestErr{})
+
+	return &documentBuilder{
+		document: &Document{
+			OpenAPI: "3.0.3",
+			Paths:   make(map[string]PathItem),
+			Components: Components{
+				Schemas: schemas.components,
+				SecuritySchemes: map[string]SecurityScheme{
+					bearerScheme: {
+						Type:         "http",
+						Scheme:       "bearer",
+						BearerFormat: "JWT",
+						Description:  "Access token from POST " + apiV1Prefix + "/auth/login",
+					},
+				},
+			},
+		},
+		schemas: schemas,
+	}
+}
+
+// rateLimited documents the 429 of the rate limits on every operation but
+// those under the paths given.
+func (b *documentBuilder) rateLimited(exceptPaths ...string) {
+	for path, item := range b.document.Paths {
+		if containsString(exceptPaths, path) {
+			continue
+		}
+		for _, operation := range item {
+			if _, ok := operation.Responses[strconv.Itoa(http.StatusTooManyRequests)]; !ok {
+				(&operationBuilder{operation: operation, schemas: b.schemas}).retryAfter(http.StatusTooManyRequests)
+			}
+		}
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+type operationBuilder struct {
+	operation *Operation
+	schemas   *schemaRegistry
+}
+
+func (b *documentBuilder) route(method, path, tag, operationId, summary string) *operationBuilder {
+	operation := &Operation{
+		Tags:        []string{tag},
+		Summary:     summary,
+		OperationId: operationId,
+		Responses:   make(map[string]*Response),
+	}
+	path = b.pathPrefix + path
+
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") {
+			name := strings.Trim(segment, "{}")
+			operation.Parameters = append(operation.Parameters, Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string", Format: "uuid"},
+			})
+		}
+	}
+
+	item, ok := b.document.Paths[path]
+	if !ok {
+		item = make(PathItem)
+		b.document.Paths[path] = item
+	}
+	item[strings.ToLower(method)] = operation
+
+	return &operationBuilder{operation: operation, schemas: b.schemas}
+}
+
+func (o *operationBuilder) describe(description string) *operationBuilder {
+	o.operation.Description = description
+	return o
+}
+
+func (o *operationBuilder) query(name, description string, schema *Schema) *operationBuilder {
+	return o.parameter(Parameter{Name: name, In: "query", Description: description, Schema: schema})
+}
+
+func (o *operationBuilder) requiredQuery(name, description string, schema *Schema) *operationBuilder {
+	return o.parameter(Parameter{Name: name, In: "query", Description: description, Required: true, Schema: schema})
+}
+
+func (o *operationBuilder) header(name, description string) *operationBuilder {
+	return o.parameter(Parameter{Name: name, In: "header", Description: description, Schema: &Schema{Type: "string"}})
+}
+
+func (o *operationBuilder) parameter(parameter Parameter) *operationBuilder {
+	o.operation.Parameters = append(o.operation.Parameters, parameter)
+	return o
+}
+
+func (o *operationBuilder) body(value interface{}) *operationBuilder {
+	o.operation.RequestBody = &RequestBody{
+		Required: true,
+		Content:  map[string]MediaType{"application/json": {Schema: o.schemas.schemaOf(value)}},
+	}
+	o.response(http.StatusBadRequest, "Malformed request body", errorSchema)
+	o.response(http.StatusUnprocessableEntity, "Invalid field values; details lists every violated constraint", errorSchema)
+	return o
+}
+
+func (o *operationBuilder) returns(code int, value interface{}) *operationBuilder {
+	return o.content(code, http.StatusText(code), "application/json", o.schemas.schemaOf(value))
+}
+
+func (o *operationBuilder) empty(code int, description string) *operationBuilder {
+	o.operation.Responses[strconv.Itoa(code)] = &Response{Description: description}
+	return o
+}
+
+func (o *operationBuilder) content(code int, description, contentType string, schema *Schema) *operationBuilder {
+	response := o.responseFor(code, description)
+	if response.Content == nil {
+		response.Content = make(map[string]MediaType)
+	}
+	response.Content[contentType] = MediaType{Schema: schema}
+	return o
+}
+
+func (o *operationBuilder) retryAfter(code int) *operationBuilder {
+	o.response(code, http.StatusText(code), errorSchema)
+	o.operation.Responses[strconv.Itoa(code)].Headers = map[string]Header{
+		"Retry-After": {Description: "Seconds to wait before retrying", Schema: &Schema{Type: "integer"}},
+	}
+	return o
+}
+
+// fails documents error responses, which all share the RestErr body.
+func (o *operationBuilder) fails(codes ...int) *operationBuilder {
+	for _, code := range codes {
+		o.response(code, http.StatusText(code), errorSchema)
+	}
+	return o
+}
+
+func (o *operationBuilder) authenticated() *operationBuilder {
+	o.operation.Security = []map[string][]string{{bearerScheme: {}}}
+	o.response(http.StatusUnauthorized, "Missing, invalid or expired access token", errorSchema)
+	return o
+}
+
+func (o *operationBuilder) adminOnly() *operationBuilder {
+	return o.authenticated().fails(http.StatusForbidden)
+}
+
+func (o *operationBuilder) response(code int, description string, schema *Schema) {
+	o.responseFor(code, description).Content = map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+func (o *operationBuilder) responseFor(code int, description string) *Response {
+	key := strconv.Itoa(code)
+	response, ok := o.operation.Responses[key]
+	if !ok {
+		response = &Response{Description: description}
+		o.operation.Responses[key] = response
+	}
+	return response
+}
+
+var errorSchema = &Schema{Ref: "#/components/schemas/RestErr"}
+
+// PathFromGin turns a gin route such as /auction/:auctionId into its OpenAPI
+// form, /auction/{auctionId}.
+func PathFromGin(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,41 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+//go:embed index.html
+var indexPage []byte
+
+// Handler serves the document at doc.json and a browser for it at
+// index.html. It expects to be registered under a wildcard named any, such
+// as /swagger/*any.
+func Handler(document *Document) gin.HandlerFunc {
+	documentJSON, err := json.Marshal(document)
+	if err != nil {
+		logger.Error("Error trying to encode the OpenAPI document", err)
+	}
+
+	return func(c *gin.Context) {
+		switch c.Param("any") {
+		case "/doc.json":
+			if documentJSON == nil {
+				errRest := rest_err.NewInternalServerError("The API document is not available")
+				middleware.AbortWithError(c, errRest)
+				return
+			}
+			c.Data(http.StatusOK, "application/json; charset=utf-8", documentJSON)
+		case "/", "/index.html":
+			c.Data(http.StatusOK, "text/html; charset=utf-8", indexPage)
+		default:
+			errRest := rest_err.NewNotFoundError("Not found")
+			middleware.AbortWithError(c, errRest)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package openapi
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuctionInputSchemaFollowsBindingRules(t *testing.T) {
+	schemas := NewDocument().Components.Schemas
+
+	auctionInput := schemas["AuctionInputDTO"]
+	assert.NotNil(t, auctionInput)
+	assert.ElementsMatch(t, []string{"product_name", "category", "description"}, auctionInput.Required)
+	assert.Equal(t, []interface{}{0, 1, 2, 3}, auctionInput.Properties["condition"].Enum)
+	assert.Equal(t, []interface{}{"open", "sealed", "dutch"}, auctionInput.Properties["auction_type"].Enum)
+	assert.Equal(t, int64(200), *auctionInput.Properties["description"].MaxLength)
+	assert.Equal(t, "string", auctionInput.Properties["reserve_price"].Type)
+	assert.NotContains(t, auctionInput.Properties, "IdempotencyKey")
+
+	auctionOutput := schemas["AuctionOutputDTO"]
+	assert.Equal(t, []interface{}{0, 1, 2, 3, 4}, auctionOutput.Properties["status"].Enum)
+	assert.Equal(t, "date-time", auctionOutput.Properties["end_time"].Format)
+	assert.True(t, auctionOutput.Properties["current_highest_amount"].Nullable)
+
+	assert.Contains(t, schemas, "RestErr")
+	assert.Equal(t, "#/components/schemas/Causes", schemas["RestErr"].Properties["details"].Items.Ref)
+}
+
+func TestHandlerServesDocumentAndUI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/swagger/*any", Handler(NewDocument()))
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/swagger/doc.json", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var document map[string]interface{}
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &document))
+	assert.Equal(t, "3.0.3", document["openapi"])
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/swagger/index.html", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "doc.json")
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/swagger/missing", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,202 @@
+package openapi
+
+import (
+	"fullcycle-auction_go/internal/money"
+	"fullcycle-auction_go/internal/usecase/auction_usecase"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// enumSchemas describes the numeric enums, which the DTOs only know as ints.
+var enumSchemas = map[reflect.Type]Schema{
+	reflect.TypeOf(auction_usecase.AuctionStatus(0)): {
+		Type:        "integer",
+		Description: "0 = active, 1 = completed, 2 = scheduled, 3 = cancelled, 4 = paused",
+		Enum:        []interface{}{0, 1, 2, 3, 4},
+	},
+	reflect.TypeOf(auction_usecase.ProductCondition(0)): {
+		Type:        "integer",
+		Description: "1 = new, 2 = used, 3 = refurbished; 0 leaves it unspecified",
+		Enum:        []interface{}{0, 1, 2, 3},
+	},
+}
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	amountType = reflect.TypeOf(money.Amount(0))
+)
+
+// schemaRegistry turns DTO types into schemas, keeping every struct as a
+// named component so the document stays readable.
+type schemaRegistry struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+func (r *schemaRegistry) schemaOf(value interface{}) *Schema {
+	return r.schemaFor(reflect.TypeOf(value))
+}
+
+func (r *schemaRegistry) schemaFor(t reflect.Type) *Schema {
+	if enum, ok := enumSchemas[t]; ok {
+		return &enum
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case amountType:
+		return &Schema{
+			Type:        "string",
+			Description: "Amount with two decimal places; requests may also send a JSON number",
+			Pattern:     `^-?\d+\.\d{2}$`,
+			Example:     "75.00",
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := r.schemaFor(t.Elem())
+		if schema.Ref != "" {
+			return schema
+		}
+		schema.Nullable = true
+		return schema
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: integerFormat(t)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: r.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaFor(t.Elem())}
+	case reflect.Struct:
+		return &Schema{Ref: "#/components/schemas/" + r.component(t)}
+	}
+
+	return &Schema{}
+}
+
+func (r *schemaRegistry) component(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := r.components[name]; taken {
+		name = t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:] + "." + name
+	}
+	r.names[t] = name
+
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	r.components[name] = schema
+	r.addFields(schema, t)
+
+	return name
+}
+
+func (r *schemaRegistry) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			r.addFields(schema, field.Type)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := r.schemaFor(field.Type)
+		if applyBinding(property, field.Tag.Get("binding")) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+}
+
+// applyBinding copies the validator rules that matter to clients onto the
+// schema and reports whether the field is required.
+func applyBinding(schema *Schema, binding string) bool {
+	if binding == "" || schema.Ref != "" {
+		return strings.Contains(binding, "required")
+	}
+
+	var required bool
+	for _, rule := range strings.Split(binding, ",") {
+		name, value, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "dive":
+			return required
+		case "oneof":
+			schema.Enum = nil
+			for _, option := range strings.Fields(value) {
+				if schema.Type == "integer" {
+					number, _ := strconv.Atoi(option)
+					schema.Enum = append(schema.Enum, number)
+					continue
+				}
+				schema.Enum = append(schema.Enum, option)
+			}
+		case "min", "max":
+			limit, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			applyLimit(schema, name, limit)
+		case "email":
+			schema.Format = "email"
+		case "uuid":
+			schema.Format = "uuid"
+		}
+	}
+
+	return required
+}
+
+func applyLimit(schema *Schema, name string, limit int64) {
+	number := float64(limit)
+	switch {
+	case schema.Type == "string" && schema.Pattern == "" && name == "min":
+		schema.MinLength = &limit
+	case schema.Type == "string" && schema.Pattern == "":
+		schema.MaxLength = &limit
+	case schema.Type == "array" && name == "min":
+		schema.MinItems = &limit
+	case schema.Type == "integer" && name == "min":
+		schema.Minimum = &number
+	case schema.Type == "integer":
+		schema.Maximum = &number
+	}
+}
+
+func integerFormat(t reflect.Type) string {
+	if t.Bits() == 32 {
+		return "int32"
+	}
+
+	return "int64"
+}
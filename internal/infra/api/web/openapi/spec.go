@@ -0,0 +1,318 @@
+package openapi
+
+import (
+	"fullcycle-auction_go/internal/infra/api/web/controller/auth_controller"
+	"fullcycle-auction_go/internal/infra/api/web/controller/health_controller"
+	"fullcycle-auction_go/internal/usecase/auction_usecase"
+	"fullcycle-auction_go/internal/usecase/bid_usecase"
+	"fullcycle-auction_go/internal/usecase/user_usecase"
+	"net/http"
+)
+
+const (
+	auctionsTag = "auctions"
+	bidsTag     = "bids"
+	usersTag    = "users"
+	adminTag    = "admin"
+	liveTag     = "live"
+	authTag     = "auth"
+	healthTag   = "health"
+)
+
+// NewDocument describes every route registered by cmd/auction but the
+// deprecated aliases. A test there compares both, so a route cannot be added
+// without documenting it.
+func NewDocument() *Document {
+	b := newDocumentBuilder()
+	b.document.Info = Info{
+		Title: "Auction API",
+		Description: "Auctions, bids and users. Amounts are decimal strings with two places. " +
+			"The unprefixed paths of earlier releases still answer, with a Deprecation header.",
+		Version: "1.0.0",
+	}
+	b.document.Tags = []Tag{
+		{Name: authTag, Description: "Log in and refresh access tokens"},
+		{Name: auctionsTag, Description: "Create, search and manage auctions"},
+		{Name: bidsTag, Description: "Place and list bids"},
+		{Name: usersTag, Description: "Accounts, wallets and watchlists"},
+		{Name: adminTag, Description: "Operations restricted to admins"},
+		{Name: liveTag, Description: "Live auction feeds"},
+		{Name: healthTag, Description: "Liveness and readiness probes"},
+	}
+
+	b.pathPrefix = apiV1Prefix
+	addAuthRoutes(b)
+	addAuctionRoutes(b)
+	addBidRoutes(b)
+	addUserRoutes(b)
+	addAdminRoutes(b)
+	addLiveRoutes(b)
+	b.pathPrefix = ""
+	addOperationalRoutes(b)
+	b.rateLimited("/metrics", "/healthz", "/readyz")
+
+	return b.document
+}
+
+func addAuthRoutes(b *documentBuilder) {
+	b.route(http.MethodPost, "/auth/login", authTag, "login", "Log in with email and password").
+		body(user_usecase.LoginInputDTO{}).
+		returns(http.StatusOK, auth_controller.TokenOutputDTO{}).
+		fails(http.StatusUnauthorized, http.StatusForbidden)
+	b.route(http.MethodPost, "/auth/refresh", authTag, "refreshToken", "Exchange a refresh token for new tokens").
+		body(auth_controller.RefreshInputDTO{}).
+		returns(http.StatusOK, auth_controller.TokenOutputDTO{}).
+		fails(http.StatusUnauthorized, http.StatusForbidden)
+}
+
+func addAuctionRoutes(b *documentBuilder) {
+	status := b.schemas.schemaOf(auction_usecase.AuctionStatus(0))
+	text := &Schema{Type: "string"}
+	integer := &Schema{Type: "integer"}
+
+	b.route(http.MethodGet, "/auction", auctionsTag, "findAuctions", "Search auctions").
+		describe("Offset pagination uses page and page_size; cursor pagination starts with an empty cursor "+
+			"and follows next_cursor. Both cannot be combined.").
+		requiredQuery("status", "Auction status", status).
+		query("category", "Exact category", text).
+		query("productName", "Product name", text).
+		query("q", "Full text search, at least 3 characters", &Schema{Type: "string", MinLength: int64Pointer(3)}).
+		query("sort", "Sort order; relevance requires q and cursors only support created_desc", &Schema{
+			Type: "string",
+			Enum: []interface{}{"created_desc", "created_asc", "ending_asc", "name_asc", "name_desc", "relevance"},
+		}).
+		query("page", "Page number, from 1 to 1000", integer).
+		query("page_size", "Items per page, from 1 to 100", integer).
+		query("cursor", "Cursor from a previous next_cursor", text).
+		query("created_from", "Only auctions created at or after this time", &Schema{Type: "string", Format: "date-time"}).
+		query("created_to", "Only auctions created before this time", &Schema{Type: "string", Format: "date-time"}).
+		query("include_deleted", "Include soft deleted auctions (admins only)", &Schema{Type: "boolean"}).
+		query("summary", "Return the lighter summary items", &Schema{Type: "boolean"}).
+		content(http.StatusOK, "A page of auctions, with summary items when summary=true", "application/json", &Schema{
+			OneOf: []*Schema{
+				b.schemas.schemaOf(auction_usecase.AuctionPageOutputDTO{}),
+				b.schemas.schemaOf(auction_usecase.AuctionSummaryPageOutputDTO{}),
+			},
+		}).
+		fails(http.StatusBadRequest)
+	b.route(http.MethodGet, "/auction/ending-soon", auctionsTag, "findAuctionsExpiringSoon", "List auctions about to end").
+		query("within", "Duration such as 30m", &Schema{Type: "string", Default: "30m"}).
+		query("limit", "Maximum number of auctions", &Schema{Type: "integer", Default: 20}).
+		returns(http.StatusOK, []auction_usecase.AuctionOutputDTO{}).
+		fails(http.StatusBadRequest)
+	b.route(http.MethodGet, "/auction/{auctionId}", auctionsTag, "findAuctionById", "Find an auction").
+		header("If-None-Match", "ETag of a previous answer; unchanged auctions answer 304").
+		returns(http.StatusOK, auction_usecase.AuctionOutputDTO{}).
+		empty(http.StatusNotModified, "Auction unchanged since the given ETag").
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	b.route(http.MethodPost, "/auction", auctionsTag, "createAuction", "Create an auction").
+		header("Idempotency-Key", "Retrying with the same key does not create a second auction").
+		body(auction_usecase.AuctionInputDTO{}).
+		empty(http.StatusCreated, "Auction created").
+		authenticated().
+		fails(http.StatusConflict)
+	b.route(http.MethodPost, "/auction/bulk", auctionsTag, "createAuctions", "Create several auctions").
+		body([]auction_usecase.AuctionInputDTO{}).
+		returns(http.StatusMultiStatus, []auction_usecase.BulkAuctionResultDTO{}).
+		authenticated()
+	b.route(http.MethodGet, "/auction/winner/{auctionId}", auctionsTag, "findWinningBid", "Find the winning bid").
+		describe("Deprecated, use /auction/{auctionId}/winner.").
+		returns(http.StatusOK, auction_usecase.WinningInfoOutputDTO{}).
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	b.route(http.MethodGet, "/auction/{auctionId}/winner", auctionsTag, "findAuctionWinner", "Find the winner").
+		returns(http.StatusOK, auction_usecase.AuctionWinnerOutputDTO{}).
+		fails(http.StatusBadRequest, http.StatusNotFound, http.StatusConflict)
+	b.route(http.MethodGet, "/auction/{auctionId}/bids/export", auctionsTag, "exportBids", "Export the bids").
+		query("format", "Export format", &Schema{Type: "string", Enum: []interface{}{"csv", "json"}, Default: "csv"}).
+		content(http.StatusOK, "Every bid of the auction", "text/csv", &Schema{Type: "string"}).
+		content(http.StatusOK, "", "application/json", b.schemas.schemaOf([]bid_usecase.BidOutputDTO{})).
+		authenticated().
+		fails(http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound)
+	b.route(http.MethodPost, "/auction/{auctionId}/close", auctionsTag, "closeAuction", "Close an auction now").
+		empty(http.StatusNoContent, "Auction closed").
+		authenticated().
+		fails(http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict)
+	b.route(http.MethodPatch, "/auction/{auctionId}", auctionsTag, "updateAuction", "Update an auction").
+		body(auction_usecase.UpdateAuctionInputDTO{}).
+		returns(http.StatusOK, auction_usecase.AuctionOutputDTO{}).
+		authenticated().
+		fails(http.StatusForbidden, http.StatusNotFound, http.StatusConflict)
+	b.route(http.MethodDelete, "/auction/{auctionId}", auctionsTag, "cancelAuction", "Cancel an auction").
+		body(auction_usecase.CancelAuctionInputDTO{}).
+		empty(http.StatusNoContent, "Auction cancelled").
+		authenticated().
+		fails(http.StatusForbidden, http.StatusNotFound, http.StatusConflict)
+	b.route(http.MethodPatch, "/auction/{auctionId}/extend", auctionsTag, "extendAuction", "Extend an auction").
+		body(auction_usecase.ExtendAuctionInputDTO{}).
+		returns(http.StatusOK, auction_usecase.AuctionOutputDTO{}).
+		authenticated().
+		fails(http.StatusForbidden, http.StatusNotFound, http.StatusConflict)
+	b.route(http.MethodPost, "/auction/{auctionId}/delete", auctionsTag, "deleteAuction", "Soft delete an auction").
+		empty(http.StatusNoContent, "Auction deleted").
+		adminOnly().
+		fails(http.StatusBadRequest, http.StatusNotFound, http.StatusConflict)
+	b.route(http.MethodPost, "/auction/{auctionId}/pause", auctionsTag, "pauseAuction", "Pause an auction").
+		empty(http.StatusNoContent, "Auction paused").
+		adminOnly().
+		fails(http.StatusBadRequest, http.StatusNotFound, http.StatusConflict)
+	b.route(http.MethodPost, "/auction/{auctionId}/resume", auctionsTag, "resumeAuction", "Resume a paused auction").
+		returns(http.StatusOK, auction_usecase.AuctionOutputDTO{}).
+		adminOnly().
+		fails(http.StatusBadRequest, http.StatusNotFound, http.StatusConflict)
+	b.route(http.MethodPost, "/auction/{auctionId}/rating", auctionsTag, "rateSeller", "Rate the seller").
+		describe("Only the winner of a completed auction can rate its seller.").
+		body(auction_usecase.RatingInputDTO{}).
+		returns(http.StatusCreated, auction_usecase.RatingOutputDTO{}).
+		authenticated().
+		fails(http.StatusForbidden, http.StatusNotFound, http.StatusConflict)
+}
+
+func addBidRoutes(b *documentBuilder) {
+	order := &Schema{Type: "string", Enum: []interface{}{"amount", "chronological"}, Default: "amount"}
+	integer := &Schema{Type: "integer"}
+
+	b.route(http.MethodPost, "/bid", bidsTag, "createBid", "Place a bid").
+		describe("The bidder is the authenticated user. Bids are stored in batches; "+
+			"sealed auctions answer without a body.").
+		header("Idempotency-Key", "Retrying with the same key does not place a second bid").
+		body(bid_usecase.BidInputDTO{}).
+		returns(http.StatusCreated, bid_usecase.BidOutputDTO{}).
+		authenticated().
+		fails(http.StatusForbidden, http.StatusNotFound, http.StatusConflict).
+		retryAfter(http.StatusTooManyRequests)
+	b.route(http.MethodPost, "/bid/bulk", bidsTag, "createBids", "Place bids on behalf of several users").
+		describe("Restricted to partners and admins. Every bid is validated before any is placed, then they are "+
+			"placed in the order given. Each result tells whether the bid was accepted, rejected or errored; "+
+			"bids not started before the deadline of the request are errored.").
+		body([]bid_usecase.BulkBidInputDTO{}).
+		returns(http.StatusMultiStatus, []bid_usecase.BulkBidResultDTO{}).
+		authenticated().
+		fails(http.StatusForbidden)
+	b.route(http.MethodGet, "/bid/{auctionId}", bidsTag, "findBidsByAuctionId", "List the bids of an auction").
+		query("order", "Sort order", order).
+		query("limit", "Items per page", integer).
+		query("offset", "Items to skip", integer).
+		returns(http.StatusOK, bid_usecase.BidPageOutputDTO{}).
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	b.route(http.MethodDelete, "/bid/{bidId}", bidsTag, "retractBid", "Retract a bid").
+		empty(http.StatusNoContent, "Bid retracted").
+		authenticated().
+		fails(http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict)
+	b.route(http.MethodGet, "/user/me/bids", bidsTag, "findMyBids", "List the caller's bids").
+		query("order", "Sort order", order).
+		query("limit", "Items per page", integer).
+		query("offset", "Items to skip", integer).
+		returns(http.StatusOK, bid_usecase.UserBidPageOutputDTO{}).
+		authenticated().
+		fails(http.StatusBadRequest)
+}
+
+func addUserRoutes(b *documentBuilder) {
+	integer := &Schema{Type: "integer"}
+
+	b.route(http.MethodPost, "/user", usersTag, "createUser", "Create a user").
+		body(user_usecase.UserInputDTO{}).
+		returns(http.StatusCreated, user_usecase.UserOutputDTO{}).
+		fails(http.StatusConflict)
+	b.route(http.MethodGet, "/user/{userId}", usersTag, "findUserById", "Find a user").
+		returns(http.StatusOK, user_usecase.UserOutputDTO{}).
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	b.route(http.MethodPatch, "/user/me", usersTag, "updateMe", "Update the caller's profile").
+		body(user_usecase.UpdateUserInputDTO{}).
+		returns(http.StatusOK, user_usecase.UserProfileOutputDTO{}).
+		authenticated().
+		fails(http.StatusNotFound, http.StatusConflict)
+	b.route(http.MethodGet, "/user/me/auctions", usersTag, "findMyAuctions", "List the caller's auctions").
+		query("status", "Only auctions with this status", b.schemas.schemaOf(auction_usecase.AuctionStatus(0))).
+		returns(http.StatusOK, []auction_usecase.AuctionOutputDTO{}).
+		authenticated().
+		fails(http.StatusBadRequest)
+	b.route(http.MethodGet, "/user/me/wallet/transactions", usersTag, "findMyWalletTransactions",
+		"List the caller's wallet transactions").
+		query("limit", "Items per page", integer).
+		query("offset", "Items to skip", integer).
+		returns(http.StatusOK, user_usecase.WalletTransactionPageOutputDTO{}).
+		authenticated().
+		fails(http.StatusBadRequest)
+	b.route(http.MethodGet, "/user/me/watchlist", usersTag, "findWatchedAuctions", "List watched auctions").
+		returns(http.StatusOK, []auction_usecase.AuctionSummaryOutputDTO{}).
+		authenticated()
+	b.route(http.MethodPost, "/user/me/watchlist/{auctionId}", usersTag, "addToWatchlist", "Watch an auction").
+		empty(http.StatusNoContent, "Auction watched").
+		authenticated().
+		fails(http.StatusBadRequest, http.StatusNotFound)
+	b.route(http.MethodDelete, "/user/me/watchlist/{auctionId}", usersTag, "removeFromWatchlist",
+		"Stop watching an auction").
+		empty(http.StatusNoContent, "Auction no longer watched").
+		authenticated().
+		fails(http.StatusBadRequest)
+}
+
+func addAdminRoutes(b *documentBuilder) {
+	b.route(http.MethodGet, "/auction/stats", adminTag, "getAuctionStats", "Auction statistics").
+		returns(http.StatusOK, auction_usecase.AuctionStatsOutputDTO{}).
+		adminOnly()
+	b.route(http.MethodPost, "/admin/auction/bid-count/reconcile", adminTag, "reconcileBidCounts",
+		"Recount the bids of every auction").
+		returns(http.StatusOK, auction_usecase.BidCountReconciliationOutputDTO{}).
+		adminOnly()
+	b.route(http.MethodPost, "/admin/auction/{auctionId}/force-close", adminTag, "forceCloseAuction",
+		"Force-close an auction").
+		describe("Completes an unfinished auction, resolves its winner and records the reason in the auction audit trail. "+
+			"Repeating the call returns the first result.").
+		body(auction_usecase.ForceCloseAuctionInputDTO{}).
+		returns(http.StatusOK, auction_usecase.ForceCloseOutputDTO{}).
+		adminOnly().
+		fails(http.StatusNotFound, http.StatusConflict)
+	b.route(http.MethodPost, "/admin/user/{userId}/wallet/credit", adminTag, "creditWallet", "Credit a wallet").
+		body(user_usecase.WalletAdjustmentInputDTO{}).
+		returns(http.StatusOK, user_usecase.WalletOutputDTO{}).
+		adminOnly().
+		fails(http.StatusNotFound)
+	b.route(http.MethodPost, "/admin/user/{userId}/wallet/debit", adminTag, "debitWallet", "Debit a wallet").
+		body(user_usecase.WalletAdjustmentInputDTO{}).
+		returns(http.StatusOK, user_usecase.WalletOutputDTO{}).
+		adminOnly().
+		fails(http.StatusNotFound, http.StatusConflict)
+	b.route(http.MethodPut, "/admin/user/{userId}/role", adminTag, "setUserRole", "Change a user's role").
+		body(user_usecase.RoleInputDTO{}).
+		returns(http.StatusOK, user_usecase.UserOutputDTO{}).
+		adminOnly().
+		fails(http.StatusNotFound)
+	b.route(http.MethodPost, "/user/{userId}/suspend", adminTag, "suspendUser", "Suspend or ban a user").
+		body(user_usecase.SuspendUserInputDTO{}).
+		returns(http.StatusOK, user_usecase.UserStatusOutputDTO{}).
+		adminOnly().
+		fails(http.StatusNotFound)
+}
+
+func addLiveRoutes(b *documentBuilder) {
+	b.route(http.MethodGet, "/auction/{auctionId}/events", liveTag, "streamAuctionEvents",
+		"Follow an auction with Server-Sent Events").
+		describe("Events are named bid, extended and closed, and their ids let a client resume.").
+		header("Last-Event-ID", "Resume after this event id").
+		query("last_event_id", "Same as Last-Event-ID, for clients that cannot set headers", &Schema{Type: "integer"}).
+		content(http.StatusOK, "Event stream", "text/event-stream", &Schema{Type: "string"}).
+		empty(http.StatusNoContent, "The auction is over and there is nothing to replay").
+		fails(http.StatusBadRequest, http.StatusNotFound).
+		retryAfter(http.StatusTooManyRequests)
+	b.route(http.MethodGet, "/ws/auction/{auctionId}", liveTag, "followAuction", "Follow an auction over WebSocket").
+		describe("Upgrades to a WebSocket that receives one JSON message per auction event.").
+		empty(http.StatusSwitchingProtocols, "WebSocket established").
+		fails(http.StatusBadRequest, http.StatusNotFound, http.StatusConflict)
+}
+
+func addOperationalRoutes(b *documentBuilder) {
+	b.route(http.MethodGet, "/healthz", healthTag, "healthz", "Tell whether the process is up").
+		content(http.StatusOK, "The process answers", "application/json", &Schema{Type: "object"})
+	b.route(http.MethodGet, "/readyz", healthTag, "readyz", "Tell whether the server can take traffic").
+		describe("Pings MongoDB and checks the auto-close scheduler. Fails while the server shuts down.").
+		returns(http.StatusOK, health_controller.ReadinessOutputDTO{}).
+		returns(http.StatusServiceUnavailable, health_controller.ReadinessOutputDTO{})
+	b.route(http.MethodGet, "/metrics", adminTag, "metrics", "Prometheus metrics").
+		content(http.StatusOK, "Metrics in the Prometheus text format", "text/plain", &Schema{Type: "string"})
+}
+
+func int64Pointer(value int64) *int64 {
+	return &value
+}
@@ -9,6 +9,8 @@ import (
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 	validator_en "github.com/go-playground/validator/v10/translations/en"
+	"reflect"
+	"strings"
 )
 
 var (
@@ -22,6 +24,7 @@ func init() {
 		enTransl := ut.New(en, en)
 		transl, _ = enTransl.GetTranslator("en")
 		validator_en.RegisterDefaultTranslations(value, transl)
+		value.RegisterTagNameFunc(jsonFieldName)
 	}
 }
 
@@ -30,19 +33,33 @@ func ValidateErr(validation_err error) *rest_err.RestErr {
 	var jsonValidation validator.ValidationErrors
 
 	if errors.As(validation_err, &jsonErr) {
-		return rest_err.NewNotFoundError("Invalid type error")
+		return rest_err.NewBadRequestError("Invalid type error", rest_err.Causes{
+			Field:   jsonErr.Field,
+			Message: "must be a " + jsonErr.Type.String(),
+		})
 	} else if errors.As(validation_err, &jsonValidation) {
 		errorCauses := []rest_err.Causes{}
 
 		for _, e := range validation_err.(validator.ValidationErrors) {
 			errorCauses = append(errorCauses, rest_err.Causes{
-				Field:   e.Field(),
-				Message: e.Translate(transl),
+				Field:      e.Field(),
+				Constraint: e.Tag(),
+				Message:    e.Translate(transl),
 			})
 		}
 
-		return rest_err.NewBadRequestError("Invalid field values", errorCauses...)
+		return rest_err.NewUnprocessableEntityError("Invalid field values", errorCauses...)
 	} else {
 		return rest_err.NewBadRequestError("Error trying to convert fields")
 	}
 }
+
+// jsonFieldName names fields in errors as clients send them.
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+
+	return name
+}
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"errors"
+	"fullcycle-auction_go/configuration/config"
+	"fullcycle-auction_go/internal/clock"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"time"
+)
+
+type TokenType string
+
+const (
+	AccessToken  TokenType = "access"
+	RefreshToken TokenType = "refresh"
+)
+
+var (
+	ErrInvalidToken = errors.New("token is invalid")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+type Claims struct {
+	jwt.RegisteredClaims
+	Type TokenType `json:"token_type"`
+}
+
+type TokenPair struct {
+	IssuedAt         time.Time
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// TokenService issues and verifies HS256 JWTs. Refresh tokens are only
+// distinguished by their token_type claim, so one cannot be used as an
+// access token.
+type TokenService struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	clock      clock.Clock
+	parser     *jwt.Parser
+}
+
+func NewTokenService(authConfig config.AuthConfig) (*TokenService, error) {
+	if authConfig.JWTSecret == "" {
+		return nil, errors.New("JWT_SECRET is required to sign tokens")
+	}
+
+	return NewTokenServiceWithClock(
+		[]byte(authConfig.JWTSecret), authConfig.AccessTokenTTL, authConfig.RefreshTokenTTL,
+		clock.NewRealClock()), nil
+}
+
+// NewTokenServiceWithClock only accepts tokens signed with HS256, so tokens
+// signed with another algorithm, or none, are rejected.
+func NewTokenServiceWithClock(
+	secret []byte, accessTTL, refreshTTL time.Duration, tokenClock clock.Clock) *TokenService {
+	return &TokenService{
+		secret:     secret,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		clock:      tokenClock,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+			jwt.WithExpirationRequired(),
+			jwt.WithTimeFunc(tokenClock.Now)),
+	}
+}
+
+func (s *TokenService) Issue(userId string) (*TokenPair, error) {
+	now := s.clock.Now()
+
+	accessToken, err := s.sign(userId, AccessToken, now, now.Add(s.accessTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := s.sign(userId, RefreshToken, now, now.Add(s.refreshTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		IssuedAt:         now,
+		AccessToken:      accessToken,
+		AccessExpiresAt:  now.Add(s.accessTTL),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: now.Add(s.refreshTTL),
+	}, nil
+}
+
+func (s *TokenService) VerifyAccessToken(token string) (*Claims, error) {
+	return s.verify(token, AccessToken)
+}
+
+func (s *TokenService) VerifyRefreshToken(token string) (*Claims, error) {
+	return s.verify(token, RefreshToken)
+}
+
+func (s *TokenService) sign(userId string, tokenType TokenType, issuedAt, expiresAt time.Time) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Subject:   userId,
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Type: tokenType,
+	}).SignedString(s.secret)
+}
+
+func (s *TokenService) verify(token string, tokenType TokenType) (*Claims, error) {
+	var claims Claims
+	_, err := s.parser.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	})
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, ErrExpiredToken
+	}
+	if err != nil || claims.Type != tokenType || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+
+	return &claims, nil
+}
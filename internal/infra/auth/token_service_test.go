@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"fullcycle-auction_go/configuration/config"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestTokenService(clock *fakeclock.FakeClock) *TokenService {
+	return NewTokenServiceWithClock([]byte("test-secret"), 15*time.Minute, time.Hour, clock)
+}
+
+func TestIssuedTokensVerify(t *testing.T) {
+	tokenService := newTestTokenService(fakeclock.New(time.Now()))
+
+	tokens, err := tokenService.Issue("user-id")
+	assert.Nil(t, err)
+
+	claims, err := tokenService.VerifyAccessToken(tokens.AccessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, "user-id", claims.Subject)
+
+	claims, err = tokenService.VerifyRefreshToken(tokens.RefreshToken)
+	assert.Nil(t, err)
+	assert.Equal(t, "user-id", claims.Subject)
+}
+
+func TestTokensExpire(t *testing.T) {
+	clock := fakeclock.New(time.Now())
+	tokenService := newTestTokenService(clock)
+	tokens, _ := tokenService.Issue("user-id")
+
+	clock.Advance(15 * time.Minute)
+
+	_, err := tokenService.VerifyAccessToken(tokens.AccessToken)
+	assert.ErrorIs(t, err, ErrExpiredToken)
+
+	_, err = tokenService.VerifyRefreshToken(tokens.RefreshToken)
+	assert.Nil(t, err)
+}
+
+func TestTokensAreRejectedWhenTamperedOrMisused(t *testing.T) {
+	tokenService := newTestTokenService(fakeclock.New(time.Now()))
+	tokens, _ := tokenService.Issue("user-id")
+
+	_, err := tokenService.VerifyAccessToken(tokens.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	parts := strings.Split(tokens.AccessToken, ".")
+	forged, _ := newTestTokenService(fakeclock.New(time.Now())).sign("admin-id", AccessToken, time.Now(), time.Now().Add(time.Hour))
+	forgedParts := strings.Split(forged, ".")
+
+	_, err = tokenService.VerifyAccessToken(parts[0] + "." + forgedParts[1] + "." + parts[2])
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	otherSecret := NewTokenServiceWithClock([]byte("other-secret"), time.Minute, time.Hour, fakeclock.New(time.Now()))
+	_, err = otherSecret.VerifyAccessToken(tokens.AccessToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = tokenService.VerifyAccessToken("eyJhbGciOiJub25lIn0." + parts[1] + ".")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestTokenServiceRequiresASecret(t *testing.T) {
+	_, err := NewTokenService(config.AuthConfig{AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour})
+	assert.Error(t, err)
+
+	tokenService, err := NewTokenService(config.AuthConfig{
+		JWTSecret: "jwt-signing-key", AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour})
+	assert.NoError(t, err)
+	assert.NotNil(t, tokenService)
+}
@@ -0,0 +1,36 @@
+package auction
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+type auctionCursor struct {
+	Timestamp int64  `json:"t"`
+	Id        string `json:"i"`
+}
+
+func encodeAuctionCursor(timestamp int64, auctionId string) string {
+	value, _ := json.Marshal(auctionCursor{Timestamp: timestamp, Id: auctionId})
+	return base64.RawURLEncoding.EncodeToString(value)
+}
+
+func decodeAuctionCursor(cursor string) (auctionCursor, error) {
+	var lastSeen auctionCursor
+
+	value, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return lastSeen, err
+	}
+
+	if err := json.Unmarshal(value, &lastSeen); err != nil {
+		return lastSeen, err
+	}
+
+	if lastSeen.Id == "" {
+		return lastSeen, errors.New("cursor is missing the auction id")
+	}
+
+	return lastSeen, nil
+}
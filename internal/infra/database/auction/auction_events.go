@@ -0,0 +1,108 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const (
+	AuctionEventCreated = event_entity.AuctionCreatedEventName
+	AuditActionCreated  = "created"
+)
+
+type AuctionEventMongo struct {
+	Id        string `bson:"_id"`
+	AuctionId string `bson:"auction_id"`
+	Type      string `bson:"type"`
+	Timestamp int64  `bson:"timestamp"`
+	Published bool   `bson:"published"`
+}
+
+type AuctionAuditMongo struct {
+	Id             string                       `bson:"_id"`
+	AuctionId      string                       `bson:"auction_id"`
+	Action         string                       `bson:"action"`
+	ActorId        string                       `bson:"actor_id,omitempty"`
+	Reason         string                       `bson:"reason,omitempty"`
+	PreviousStatus auction_entity.AuctionStatus `bson:"previous_status,omitempty"`
+	Timestamp      int64                        `bson:"timestamp"`
+}
+
+// auditWriter is the part of the audit collection creation records need.
+type auditWriter interface {
+	InsertOne(ctx context.Context, document interface{},
+		opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error)
+}
+
+func (ar *AuctionRepository) insertCreationRecords(
+	ctx context.Context, auctionEntity auction_entity.Auction) error {
+	now := ar.Clock.Now().Unix()
+
+	if _, err := ar.EventsCollection.InsertOne(ctx, AuctionEventMongo{
+		Id:        auctionEntity.Id + ":" + AuctionEventCreated,
+		AuctionId: auctionEntity.Id,
+		Type:      AuctionEventCreated,
+		Timestamp: now,
+	}); err != nil {
+		return err
+	}
+
+	_, err := ar.auditWriter.InsertOne(ctx, AuctionAuditMongo{
+		Id:        auctionEntity.Id + ":" + AuditActionCreated,
+		AuctionId: auctionEntity.Id,
+		Action:    AuditActionCreated,
+		ActorId:   auctionEntity.SellerId,
+		Timestamp: now,
+	})
+
+	return err
+}
+
+// FindUnpublishedEvents returns the oldest outbox events the relay has not
+// marked as published yet.
+func (ar *AuctionRepository) FindUnpublishedEvents(
+	ctx context.Context, limit int64) ([]auction_entity.OutboxEvent, *internal_error.InternalError) {
+	cursor, err := ar.EventsCollection.Find(ctx, bson.M{"published": false},
+		options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}}).SetLimit(limit))
+	if err != nil {
+		logger.ErrorContext(ctx, "Error trying to find unpublished auction events", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find auction events")
+	}
+	defer cursor.Close(ctx)
+
+	var auctionEventsMongo []AuctionEventMongo
+	if err := cursor.All(ctx, &auctionEventsMongo); err != nil {
+		logger.ErrorContext(ctx, "Error trying to decode unpublished auction events", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find auction events")
+	}
+
+	outboxEvents := make([]auction_entity.OutboxEvent, 0, len(auctionEventsMongo))
+	for _, auctionEventMongo := range auctionEventsMongo {
+		outboxEvents = append(outboxEvents, auction_entity.OutboxEvent{
+			Id:        auctionEventMongo.Id,
+			AuctionId: auctionEventMongo.AuctionId,
+			Type:      auctionEventMongo.Type,
+			Timestamp: time.Unix(auctionEventMongo.Timestamp, 0),
+		})
+	}
+
+	return outboxEvents, nil
+}
+
+func (ar *AuctionRepository) MarkEventPublished(ctx context.Context, eventId string) *internal_error.InternalError {
+	if _, err := ar.EventsCollection.UpdateOne(ctx,
+		bson.M{"_id": eventId}, bson.M{"$set": bson.M{"published": true}}); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to mark auction event %s as published", eventId), err)
+		return internal_error.NewInternalServerError("Error trying to mark auction event as published")
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package auction
+
+import (
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"sync/atomic"
+	"time"
+)
+
+const defaultAuctionInterval = 5 * time.Minute
+
+var auctionInterval atomic.Int64
+
+// getAuctionInterval is the duration of auctions created without one. It is
+// process wide, since documents read back without an end time need it too.
+func getAuctionInterval() time.Duration {
+	if interval := auctionInterval.Load(); interval > 0 {
+		return time.Duration(interval)
+	}
+
+	return defaultAuctionInterval
+}
+
+func SetAuctionInterval(interval time.Duration) {
+	previous := time.Duration(auctionInterval.Swap(int64(interval)))
+
+	if previous > 0 && previous != interval {
+		logger.Info(fmt.Sprintf("Auction interval changed from %s to %s", previous, interval))
+	}
+}
@@ -0,0 +1,278 @@
+package auction
+
+import (
+	"container/heap"
+	"context"
+	"fullcycle-auction_go/internal/clock"
+	"sync"
+	"time"
+)
+
+type scheduledAuction struct {
+	auctionId string
+	deadline  time.Time
+	index     int
+}
+
+type auctionHeap []*scheduledAuction
+
+func (h auctionHeap) Len() int { return len(h) }
+
+func (h auctionHeap) Less(i, j int) bool { return h[i].deadline.Before(h[j].deadline) }
+
+func (h auctionHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *auctionHeap) Push(x interface{}) {
+	item := x.(*scheduledAuction)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *auctionHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*h = old[:n-1]
+	return item
+}
+
+type AuctionScheduler struct {
+	clock    clock.Clock
+	mutex    *sync.Mutex
+	heap     auctionHeap
+	entries  map[string]*scheduledAuction
+	callback func(auctionId string)
+	closing  bool
+	running  bool
+
+	wakeUp   chan struct{}
+	settled  chan struct{}
+	stop     chan struct{}
+	stopOnce *sync.Once
+	done     chan struct{}
+}
+
+func NewAuctionScheduler(schedulerClock clock.Clock, callback func(auctionId string)) *AuctionScheduler {
+	scheduler := &AuctionScheduler{
+		clock:    schedulerClock,
+		mutex:    &sync.Mutex{},
+		entries:  make(map[string]*scheduledAuction),
+		callback: callback,
+		wakeUp:   make(chan struct{}, 1),
+		settled:  make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		stopOnce: &sync.Once{},
+		done:     make(chan struct{}),
+	}
+
+	go scheduler.run()
+
+	return scheduler
+}
+
+func (s *AuctionScheduler) Schedule(auctionId string, deadline time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closing {
+		return
+	}
+
+	if _, ok := s.entries[auctionId]; ok {
+		return
+	}
+
+	item := &scheduledAuction{auctionId: auctionId, deadline: deadline}
+	heap.Push(&s.heap, item)
+	s.entries[auctionId] = item
+
+	if item.index == 0 {
+		s.notify()
+	}
+}
+
+func (s *AuctionScheduler) Reschedule(auctionId string, deadline time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closing {
+		return
+	}
+
+	item, ok := s.entries[auctionId]
+	if !ok {
+		item = &scheduledAuction{auctionId: auctionId, deadline: deadline}
+		heap.Push(&s.heap, item)
+		s.entries[auctionId] = item
+		if item.index == 0 {
+			s.notify()
+		}
+		return
+	}
+
+	wasHead := item.index == 0
+	item.deadline = deadline
+	heap.Fix(&s.heap, item.index)
+
+	if wasHead || item.index == 0 {
+		s.notify()
+	}
+}
+
+func (s *AuctionScheduler) Remove(auctionId string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	item, ok := s.entries[auctionId]
+	if !ok {
+		return false
+	}
+
+	wasHead := item.index == 0
+	heap.Remove(&s.heap, item.index)
+	delete(s.entries, auctionId)
+
+	if wasHead {
+		s.notify()
+	}
+	s.notifySettled()
+
+	return true
+}
+
+func (s *AuctionScheduler) Has(auctionId string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, ok := s.entries[auctionId]
+	return ok
+}
+
+func (s *AuctionScheduler) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.heap)
+}
+
+// Alive reports whether the scheduler goroutine is still running.
+func (s *AuctionScheduler) Alive() bool {
+	select {
+	case <-s.done:
+		return false
+	default:
+		return true
+	}
+}
+
+func (s *AuctionScheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+func (s *AuctionScheduler) Shutdown(ctx context.Context) error {
+	s.mutex.Lock()
+	s.closing = true
+	s.mutex.Unlock()
+
+	// Waiting for settled rather than polling keeps the drain on the
+	// scheduler clock: it only makes progress when a callback finishes or an
+	// auction is removed.
+	shutdownDeadline, hasDeadline := ctx.Deadline()
+	for !s.drained(shutdownDeadline, hasDeadline) {
+		select {
+		case <-ctx.Done():
+			s.Stop()
+			return ctx.Err()
+		case <-s.settled:
+		}
+	}
+
+	s.Stop()
+	return nil
+}
+
+func (s *AuctionScheduler) drained(shutdownDeadline time.Time, hasDeadline bool) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.running {
+		return false
+	}
+
+	return len(s.heap) == 0 || !hasDeadline || s.heap[0].deadline.After(shutdownDeadline)
+}
+
+func (s *AuctionScheduler) notify() {
+	select {
+	case s.wakeUp <- struct{}{}:
+	default:
+	}
+}
+
+func (s *AuctionScheduler) notifySettled() {
+	select {
+	case s.settled <- struct{}{}:
+	default:
+	}
+}
+
+func (s *AuctionScheduler) run() {
+	defer close(s.done)
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+
+		s.mutex.Lock()
+		var timer clock.Timer
+		var deadline <-chan time.Time
+		if len(s.heap) > 0 {
+			head := s.heap[0]
+			untilDeadline := head.deadline.Sub(s.clock.Now())
+			if untilDeadline <= 0 {
+				heap.Pop(&s.heap)
+				delete(s.entries, head.auctionId)
+				s.running = true
+				s.mutex.Unlock()
+
+				s.callback(head.auctionId)
+
+				s.mutex.Lock()
+				s.running = false
+				s.mutex.Unlock()
+				s.notifySettled()
+				continue
+			}
+
+			timer = s.clock.NewTimer(untilDeadline)
+			deadline = timer.C()
+		}
+		s.mutex.Unlock()
+
+		select {
+		case <-deadline:
+		case <-s.wakeUp:
+		case <-s.stop:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
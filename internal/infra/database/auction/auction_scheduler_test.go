@@ -0,0 +1,245 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/clock"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAuctionSchedulerClosesInDeadlineOrder(t *testing.T) {
+	var mutex sync.Mutex
+	var closed []string
+	done := make(chan struct{}, 3)
+
+	scheduler := NewAuctionScheduler(clock.NewRealClock(), func(auctionId string) {
+		mutex.Lock()
+		closed = append(closed, auctionId)
+		mutex.Unlock()
+		done <- struct{}{}
+	})
+	defer scheduler.Stop()
+
+	now := time.Now()
+	scheduler.Schedule("late", now.Add(300*time.Millisecond))
+	scheduler.Schedule("middle", now.Add(200*time.Millisecond))
+	scheduler.Schedule("early", now.Add(100*time.Millisecond))
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for auctions to close")
+		}
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, []string{"early", "middle", "late"}, closed)
+	assert.Equal(t, 0, scheduler.Len())
+}
+
+func TestAuctionSchedulerWakesUpForEarlierDeadline(t *testing.T) {
+	done := make(chan string, 2)
+
+	scheduler := NewAuctionScheduler(clock.NewRealClock(), func(auctionId string) {
+		done <- auctionId
+	})
+	defer scheduler.Stop()
+
+	scheduler.Schedule("far", time.Now().Add(time.Hour))
+	time.Sleep(50 * time.Millisecond)
+	scheduler.Schedule("near", time.Now().Add(50*time.Millisecond))
+
+	select {
+	case auctionId := <-done:
+		assert.Equal(t, "near", auctionId)
+	case <-time.After(2 * time.Second):
+		t.Fatal("scheduler did not wake up for the earlier deadline")
+	}
+
+	assert.Equal(t, 1, scheduler.Len())
+}
+
+func TestAuctionSchedulerStop(t *testing.T) {
+	scheduler := NewAuctionScheduler(clock.NewRealClock(), func(auctionId string) {
+		t.Errorf("auction %s should not be closed after stop", auctionId)
+	})
+
+	scheduler.Schedule("pending", time.Now().Add(100*time.Millisecond))
+	assert.True(t, scheduler.Alive())
+	scheduler.Stop()
+	scheduler.Stop()
+	assert.False(t, scheduler.Alive())
+
+	time.Sleep(200 * time.Millisecond)
+}
+
+func TestAuctionSchedulerRemove(t *testing.T) {
+	done := make(chan string, 2)
+
+	scheduler := NewAuctionScheduler(clock.NewRealClock(), func(auctionId string) {
+		done <- auctionId
+	})
+	defer scheduler.Stop()
+
+	now := time.Now()
+	scheduler.Schedule("removed", now.Add(50*time.Millisecond))
+	scheduler.Schedule("kept", now.Add(150*time.Millisecond))
+
+	assert.True(t, scheduler.Remove("removed"))
+	assert.False(t, scheduler.Remove("removed"))
+
+	select {
+	case auctionId := <-done:
+		assert.Equal(t, "kept", auctionId)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for the remaining auction to close")
+	}
+}
+
+func TestAuctionSchedulerReschedule(t *testing.T) {
+	done := make(chan string, 2)
+
+	scheduler := NewAuctionScheduler(clock.NewRealClock(), func(auctionId string) {
+		done <- auctionId
+	})
+	defer scheduler.Stop()
+
+	now := time.Now()
+	scheduler.Schedule("extended", now.Add(50*time.Millisecond))
+	scheduler.Schedule("other", now.Add(150*time.Millisecond))
+	scheduler.Reschedule("extended", now.Add(300*time.Millisecond))
+
+	var closed []string
+	for i := 0; i < 2; i++ {
+		select {
+		case auctionId := <-done:
+			closed = append(closed, auctionId)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for auctions to close")
+		}
+	}
+
+	assert.Equal(t, []string{"other", "extended"}, closed)
+}
+
+func TestRescheduleCloseKeepsNoTimersInSweepMode(t *testing.T) {
+	for _, strategy := range []string{CloseStrategyTimer, CloseStrategySweep} {
+		auctionRepository := &AuctionRepository{
+			CloseStrategy: strategy,
+			Scheduler:     NewAuctionScheduler(clock.NewRealClock(), func(string) {}),
+		}
+
+		auctionRepository.rescheduleClose("extended", time.Now().Add(time.Hour))
+
+		assert.Equal(t, strategy == CloseStrategyTimer, auctionRepository.Scheduler.Has("extended"), strategy)
+		auctionRepository.Scheduler.Stop()
+	}
+}
+
+func TestAuctionSchedulerShutdownWaitsForImminentDeadlines(t *testing.T) {
+	var closed int32
+	scheduler := NewAuctionScheduler(clock.NewRealClock(), func(auctionId string) {
+		atomic.AddInt32(&closed, 1)
+	})
+
+	now := time.Now()
+	scheduler.Schedule("imminent", now.Add(100*time.Millisecond))
+	scheduler.Schedule("later", now.Add(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.Nil(t, scheduler.Shutdown(ctx))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&closed))
+
+	scheduler.Schedule("rejected", now)
+	assert.Equal(t, 1, scheduler.Len())
+}
+
+func TestAuctionSchedulerWithFakeClock(t *testing.T) {
+	done := make(chan string, 1)
+	fakeClock := fakeclock.New(time.Now())
+
+	scheduler := NewAuctionScheduler(fakeClock, func(auctionId string) {
+		done <- auctionId
+	})
+	defer scheduler.Stop()
+
+	scheduler.Schedule("auction", fakeClock.Now().Add(time.Hour))
+
+	assert.Eventually(t, func() bool {
+		return fakeClock.PendingTimers() == 1
+	}, time.Second, 5*time.Millisecond)
+
+	fakeClock.Advance(time.Hour)
+
+	select {
+	case auctionId := <-done:
+		assert.Equal(t, "auction", auctionId)
+	case <-time.After(2 * time.Second):
+		t.Fatal("scheduler did not fire after advancing the clock")
+	}
+}
+
+func TestAuctionSchedulerShutdownWaitsForRunningCloseWithFakeClock(t *testing.T) {
+	fakeClock := fakeclock.New(time.Now())
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	scheduler := NewAuctionScheduler(fakeClock, func(auctionId string) {
+		close(started)
+		<-release
+	})
+	scheduler.Schedule("running", fakeClock.Now())
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- scheduler.Shutdown(context.Background())
+	}()
+
+	select {
+	case <-shutdown:
+		t.Fatal("shutdown returned while a close was still running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	select {
+	case err := <-shutdown:
+		assert.Nil(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("shutdown did not return once the close finished")
+	}
+	assert.False(t, scheduler.Alive())
+}
+
+func TestAuctionSchedulerReturnsToEmpty(t *testing.T) {
+	scheduler := NewAuctionScheduler(clock.NewRealClock(), func(auctionId string) {})
+	defer scheduler.Stop()
+
+	for round := 0; round < 10; round++ {
+		now := time.Now()
+		for i := 0; i < 50; i++ {
+			auctionId := fmt.Sprintf("auction-%d-%d", round, i)
+			scheduler.Schedule(auctionId, now.Add(time.Duration(i%5)*10*time.Millisecond))
+			scheduler.Schedule(auctionId, now.Add(time.Hour))
+
+			if i%2 == 0 {
+				scheduler.Remove(auctionId)
+			}
+		}
+	}
+
+	assert.Eventually(t, func() bool {
+		return scheduler.Len() == 0
+	}, 2*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,96 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const defaultStatsTimeout = 10 * time.Second
+
+type auctionStatsMongo struct {
+	ByStatus []struct {
+		Status auction_entity.AuctionStatus `bson:"_id"`
+		Count  int64                        `bson:"count"`
+	} `bson:"by_status"`
+	ByCategory []struct {
+		Category string `bson:"_id"`
+		Count    int64  `bson:"count"`
+	} `bson:"by_category"`
+	Duration []struct {
+		AverageSeconds float64 `bson:"average_seconds"`
+	} `bson:"duration"`
+}
+
+func (ar *AuctionRepository) GetAuctionStats(
+	ctx context.Context) (*auction_entity.AuctionStats, *internal_error.InternalError) {
+	ctx, cancel := context.WithTimeout(ctx, ar.statsTimeout)
+	defer cancel()
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"deleted_at": bson.M{"$exists": false}}},
+		{"$facet": bson.M{
+			"by_status": bson.A{
+				bson.M{"$group": bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}},
+			},
+			"by_category": bson.A{
+				bson.M{"$group": bson.M{"_id": "$category", "count": bson.M{"$sum": 1}}},
+			},
+			"duration": bson.A{
+				bson.M{"$match": bson.M{
+					"status":    auction_entity.Completed,
+					"closed_at": bson.M{"$exists": true},
+				}},
+				bson.M{"$group": bson.M{
+					"_id":             nil,
+					"average_seconds": bson.M{"$avg": bson.M{"$subtract": bson.A{"$closed_at", "$timestamp"}}},
+				}},
+			},
+		}},
+	}
+
+	cursor, err := ar.queryCollection.Aggregate(ctx, pipeline, options.Aggregate().SetMaxTime(ar.statsTimeout))
+	if err != nil {
+		logger.ErrorContext(ctx, "Error trying to aggregate auction stats", err)
+		return nil, internal_error.NewInternalServerError("Error trying to get auction stats")
+	}
+	defer cursor.Close(ctx)
+
+	var results []auctionStatsMongo
+	if err := cursor.All(ctx, &results); err != nil {
+		logger.ErrorContext(ctx, "Error trying to decode auction stats", err)
+		return nil, internal_error.NewInternalServerError("Error trying to get auction stats")
+	}
+
+	auctionStats := &auction_entity.AuctionStats{
+		ByStatus:   make(map[auction_entity.AuctionStatus]int64),
+		ByCategory: make(map[string]int64),
+	}
+	if len(results) == 0 {
+		return auctionStats, nil
+	}
+
+	for _, statusCount := range results[0].ByStatus {
+		auctionStats.ByStatus[statusCount.Status] = statusCount.Count
+		auctionStats.Total += statusCount.Count
+	}
+	for _, categoryCount := range results[0].ByCategory {
+		auctionStats.ByCategory[categoryCount.Category] = categoryCount.Count
+	}
+	if len(results[0].Duration) > 0 {
+		auctionStats.AverageDuration = time.Duration(results[0].Duration[0].AverageSeconds * float64(time.Second))
+	}
+
+	return auctionStats, nil
+}
+
+// WithStatsTimeout bounds how long the stats aggregation may run.
+func WithStatsTimeout(timeout time.Duration) AuctionRepositoryOption {
+	return func(repository *AuctionRepository) {
+		repository.statsTimeout = timeout
+	}
+}
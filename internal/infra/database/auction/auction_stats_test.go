@@ -0,0 +1,31 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAverageDurationOnlyCountsCompletedAuctions(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+	database := conn.Client().Database(conn.Name() + "_stats_" + uuid.New().String()[:8])
+	defer database.Drop(ctx)
+
+	ar := NewAuctionRepository(database)
+	defer ar.Shutdown(ctx)
+
+	_, err := ar.Collection.InsertMany(ctx, []interface{}{
+		AuctionEntityMongo{Id: uuid.New().String(), Status: auction_entity.Completed, Timestamp: 0, ClosedAt: 600},
+		AuctionEntityMongo{Id: uuid.New().String(), Status: auction_entity.Cancelled, Timestamp: 0, ClosedAt: 60},
+	})
+	assert.Nil(t, err)
+
+	auctionStats, statsErr := ar.GetAuctionStats(ctx)
+	assert.Nil(t, statsErr)
+	assert.Equal(t, int64(2), auctionStats.Total)
+	assert.Equal(t, 10*time.Minute, auctionStats.AverageDuration)
+}
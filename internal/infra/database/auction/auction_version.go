@@ -0,0 +1,64 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Auctions written before the version field existed have no version, so
+// version 0 also matches documents where the field is missing.
+func versionFilter(expectedVersion int64) interface{} {
+	if expectedVersion == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+
+	return expectedVersion
+}
+
+// incrementVersion must be part of every update of an auction document: the
+// version guards concurrent writes and is the ETag of the auction detail.
+func incrementVersion() bson.M {
+	return bson.M{"version": 1}
+}
+
+func (ar *AuctionRepository) explainUpdateMiss(
+	ctx context.Context,
+	auctionId string,
+	expectedVersion int64,
+	allowedStatuses []auction_entity.AuctionStatus,
+	conflictMessage string,
+	action string) *internal_error.InternalError {
+	var auctionEntityMongo AuctionEntityMongo
+	err := ar.Collection.FindOne(ctx, bson.M{"_id": auctionId}).Decode(&auctionEntityMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction not found with this id = %s", auctionId))
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to find auction by id = %s", auctionId), err)
+		return internal_error.NewInternalServerError(fmt.Sprintf("Error trying to %s auction", action))
+	}
+
+	if auctionEntityMongo.Version != expectedVersion && statusIn(auctionEntityMongo.Status, allowedStatuses) {
+		return internal_error.NewVersionConflictError(
+			fmt.Sprintf("Auction %s was modified concurrently, reload it and try again", auctionId))
+	}
+
+	return internal_error.NewConflictError(conflictMessage)
+}
+
+func statusIn(status auction_entity.AuctionStatus, statuses []auction_entity.AuctionStatus) bool {
+	for _, value := range statuses {
+		if value == status {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,85 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type auctionBidCountMongo struct {
+	AuctionId string `bson:"_id"`
+	Count     int64  `bson:"count"`
+}
+
+func (ar *AuctionRepository) DecrementBidCount(
+	ctx context.Context, auctionId string) *internal_error.InternalError {
+	filter := bson.M{"_id": auctionId, "bid_count": bson.M{"$gt": 0}}
+	update := bson.M{"$inc": bson.M{"bid_count": -1, "version": 1}}
+
+	if _, err := ar.Collection.UpdateOne(ctx, filter, update); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to decrement bid count of auction %s", auctionId), err)
+		return internal_error.NewInternalServerError("Error trying to update bid count")
+	}
+
+	return nil
+}
+
+// ReconcileBidCounts recomputes bid_count from the bids collection and
+// returns how many auctions were corrected. Bids still waiting in the insert
+// batch are not counted yet, so open auctions may be corrected downwards
+// until the next flush.
+func (ar *AuctionRepository) ReconcileBidCounts(ctx context.Context) (int64, *internal_error.InternalError) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"retracted": bson.M{"$ne": true}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$auction_id", "count": bson.M{"$sum": 1}}}},
+	}
+
+	cursor, err := ar.Collection.Database().Collection("bids").Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.ErrorContext(ctx, "Error trying to count bids per auction", err)
+		return 0, internal_error.NewInternalServerError("Error trying to reconcile bid counts")
+	}
+	defer cursor.Close(ctx)
+
+	var bidCounts []auctionBidCountMongo
+	if err := cursor.All(ctx, &bidCounts); err != nil {
+		logger.ErrorContext(ctx, "Error decoding bid counts per auction", err)
+		return 0, internal_error.NewInternalServerError("Error trying to reconcile bid counts")
+	}
+
+	var reconciled int64
+	auctionIds := make([]string, 0, len(bidCounts))
+	models := make([]mongo.WriteModel, 0, len(bidCounts))
+	for _, bidCount := range bidCounts {
+		auctionIds = append(auctionIds, bidCount.AuctionId)
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": bidCount.AuctionId, "bid_count": bson.M{"$ne": bidCount.Count}}).
+			SetUpdate(bson.M{"$set": bson.M{"bid_count": bidCount.Count}, "$inc": incrementVersion()}))
+	}
+
+	if len(models) > 0 {
+		result, err := ar.Collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		if err != nil {
+			logger.ErrorContext(ctx, "Error trying to reconcile bid counts", err)
+			return 0, internal_error.NewInternalServerError("Error trying to reconcile bid counts")
+		}
+		reconciled += result.ModifiedCount
+	}
+
+	result, err := ar.Collection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$nin": auctionIds}, "bid_count": bson.M{"$gt": 0}},
+		bson.M{"$unset": bson.M{"bid_count": ""}, "$inc": incrementVersion()})
+	if err != nil {
+		logger.ErrorContext(ctx, "Error trying to reset bid counts of auctions without bids", err)
+		return reconciled, internal_error.NewInternalServerError("Error trying to reconcile bid counts")
+	}
+	reconciled += result.ModifiedCount
+
+	logger.InfoContext(ctx, fmt.Sprintf("%d auction bid counts reconciled", reconciled))
+
+	return reconciled, nil
+}
@@ -0,0 +1,112 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BuyNow closes the auction for the bidder and stores the bid in one
+// transaction. The update only matches while the auction is still Active,
+// so concurrent buy-now attempts produce exactly one winner, and only while
+// no bid already leads at or above the amount.
+func (ar *AuctionRepository) BuyNow(
+	ctx context.Context,
+	auctionId, bidId, userId string,
+	amount money.Amount) (*auction_entity.Auction, int64, *internal_error.InternalError) {
+	return ar.closeForBidder(ctx, auctionId, bidId, userId, amount, auction_entity.CloseReasonBuyNow, bson.M{
+		"buy_now_price": bson.M{"$gt": 0, "$lte": mongodb.DecimalFromAmount(amount)},
+		"$or": bson.A{
+			bson.M{"current_highest_amount": bson.M{"$exists": false}},
+			bson.M{"current_highest_amount": bson.M{"$lt": mongodb.DecimalFromAmount(amount)}},
+		},
+	})
+}
+
+// AcceptDutchPrice closes a dutch auction for the first bidder to accept its
+// current price. The caller checks the amount against the price schedule.
+func (ar *AuctionRepository) AcceptDutchPrice(
+	ctx context.Context,
+	auctionId, bidId, userId string,
+	amount money.Amount) (*auction_entity.Auction, int64, *internal_error.InternalError) {
+	return ar.closeForBidder(ctx, auctionId, bidId, userId, amount, auction_entity.CloseReasonAccepted, bson.M{
+		"auction_type": auction_entity.DutchAuction,
+	})
+}
+
+func (ar *AuctionRepository) closeForBidder(
+	ctx context.Context,
+	auctionId, bidId, userId string,
+	amount money.Amount,
+	closeReason string,
+	condition bson.M) (*auction_entity.Auction, int64, *internal_error.InternalError) {
+	now := ar.Clock.Now()
+	filter := bson.M{
+		"_id":        auctionId,
+		"status":     auction_entity.Active,
+		"deleted_at": bson.M{"$exists": false},
+		"end_time":   bson.M{"$gt": now.Unix()},
+	}
+	for key, value := range condition {
+		filter[key] = value
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":                  auction_entity.Completed,
+			"closed_at":               now.Unix(),
+			"close_reason":            closeReason,
+			"current_highest_amount":  mongodb.DecimalFromAmount(amount),
+			"current_highest_user_id": userId,
+			"winner_user_id":          userId,
+			"winning_amount":          mongodb.DecimalFromAmount(amount),
+		},
+		"$inc": incrementForAcceptedBid(),
+	}
+
+	var closedAuctionMongo AuctionEntityMongo
+	err := mongodb.WithTransaction(ctx, ar.Collection.Database(), func(txCtx context.Context) error {
+		if err := ar.Collection.FindOneAndUpdate(
+			txCtx, filter, update,
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&closedAuctionMongo); err != nil {
+			return err
+		}
+
+		_, err := ar.Collection.Database().Collection("bids").InsertOne(txCtx, bson.M{
+			"_id":        bidId,
+			"user_id":    userId,
+			"auction_id": auctionId,
+			"amount":     mongodb.DecimalFromAmount(amount),
+			"timestamp":  now.UnixMilli(),
+			"sequence":   closedAuctionMongo.BidSequence,
+		})
+		return err
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, 0, internal_error.NewConflictError("Auction is no longer available")
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to buy auction %s", auctionId), err)
+		return nil, 0, internal_error.NewInternalServerError("Error trying to buy auction")
+	}
+
+	ar.OpenScheduler.Remove(auctionId)
+	ar.Scheduler.Remove(auctionId)
+	ar.PriceScheduler.Remove(auctionId)
+
+	logger.InfoContext(ctx, fmt.Sprintf("Auction %s closed (%s) by user %s for %s", auctionId, closeReason, userId, amount))
+
+	ar.recordCloseOutcome(ctx, &closedAuctionMongo)
+	ar.notifyAuctionClosed(closedAuctionMongo.toEntity())
+
+	closedAuction := closedAuctionMongo.toEntity()
+	return &closedAuction, closedAuctionMongo.BidSequence, nil
+}
@@ -0,0 +1,89 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBuyNowHasExactlyOneWinner(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	ca := NewAuctionRepositoryWithClock(conn, fakeclock.New(time.Now()))
+	defer ca.Shutdown(ctx)
+
+	auction, _ := auction_entity.CreateAuction(
+		"bicycle",
+		"sports",
+		"road bicycle in carbon",
+		auction_entity.Used,
+		auction_entity.WithBuyNowPrice(1000))
+	assert.Nil(t, ca.CreateAuction(ctx, auction))
+	assert.True(t, ca.Scheduler.Has(auction.Id))
+
+	var winners int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, _, err := ca.BuyNow(ctx, auction.Id, uuid.New().String(), uuid.New().String(), 1000)
+			if err == nil {
+				atomic.AddInt32(&winners, 1)
+				return
+			}
+
+			assert.Equal(t, "conflict", err.Err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), winners)
+	assert.False(t, ca.Scheduler.Has(auction.Id))
+
+	auctionDb, err := ca.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Completed, auctionDb.Status)
+	assert.Equal(t, auction_entity.CloseReasonBuyNow, auctionDb.CloseReason)
+	assert.NotEmpty(t, auctionDb.WinnerUserId)
+
+	bids, countErr := conn.Collection("bids").CountDocuments(ctx, bson.M{"auction_id": auction.Id})
+	assert.Nil(t, countErr)
+	assert.Equal(t, int64(1), bids)
+}
+
+func TestBuyNowIsRefusedOnceTheLeadingBidReachesThePrice(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	ca := NewAuctionRepositoryWithClock(conn, fakeclock.New(time.Now()))
+	defer ca.Shutdown(ctx)
+
+	auction, _ := auction_entity.CreateAuction(
+		"camera",
+		"photography",
+		"mirrorless camera body",
+		auction_entity.Used,
+		auction_entity.WithBuyNowPrice(1000))
+	assert.Nil(t, ca.CreateAuction(ctx, auction))
+
+	_, err := ca.PlaceHighestBid(ctx, auction.Id, uuid.New().String(), 1200, 0, auction_entity.SnipeExtension{})
+	assert.Nil(t, err)
+
+	_, _, err = ca.BuyNow(ctx, auction.Id, uuid.New().String(), uuid.New().String(), 1100)
+	assert.Equal(t, "conflict", err.Err)
+
+	auctionDb, err := ca.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Active, auctionDb.Status)
+	assert.Empty(t, auctionDb.WinnerUserId)
+}
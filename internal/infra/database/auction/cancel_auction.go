@@ -0,0 +1,55 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var cancellableStatuses = []auction_entity.AuctionStatus{
+	auction_entity.Active, auction_entity.Scheduled, auction_entity.Paused}
+
+func (ar *AuctionRepository) CancelAuction(
+	ctx context.Context,
+	auctionId, reason string,
+	expectedVersion int64) *internal_error.InternalError {
+	// bid_count moves in the same update that accepts a bid, so it also
+	// covers bids still waiting in the batch.
+	filter := bson.M{
+		"_id":       auctionId,
+		"status":    bson.M{"$in": cancellableStatuses},
+		"version":   versionFilter(expectedVersion),
+		"bid_count": bson.M{"$not": bson.M{"$gt": 0}},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":        auction_entity.Cancelled,
+			"cancel_reason": reason,
+			"closed_at":     ar.Clock.Now().Unix(),
+			"close_reason":  auction_entity.CloseReasonCancelled,
+		},
+		"$inc": incrementVersion(),
+	}
+
+	result, err := ar.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to cancel auction %s", auctionId), err)
+		return internal_error.NewInternalServerError("Error trying to cancel auction")
+	}
+
+	if result.MatchedCount == 0 {
+		return ar.explainUpdateMiss(ctx, auctionId, expectedVersion, cancellableStatuses,
+			"Auction is already finished or has bids and cannot be cancelled", "cancel")
+	}
+
+	ar.OpenScheduler.Remove(auctionId)
+	ar.Scheduler.Remove(auctionId)
+	ar.PriceScheduler.Remove(auctionId)
+
+	logger.InfoContext(ctx, fmt.Sprintf("Auction %s cancelled: %s", auctionId, reason))
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AuctionClosedListener func(ctx context.Context, auction auction_entity.Auction)
+
+func (ar *AuctionRepository) RegisterCloseListener(listener AuctionClosedListener) {
+	ar.listenersMutex.Lock()
+	defer ar.listenersMutex.Unlock()
+
+	ar.closeListeners = append(ar.closeListeners, listener)
+}
+
+func (ar *AuctionRepository) notifyAuctionClosed(auctionEntity auction_entity.Auction) {
+	ar.listenersMutex.RLock()
+	defer ar.listenersMutex.RUnlock()
+
+	for _, listener := range ar.closeListeners {
+		go func(listener AuctionClosedListener) {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Error(
+						fmt.Sprintf("Auction closed listener panicked for auction %s", auctionEntity.Id),
+						fmt.Errorf("%v", r))
+				}
+			}()
+
+			listener(ar.ctx, auctionEntity)
+		}(listener)
+	}
+}
+
+func (ar *AuctionRepository) closeAuction(ctx context.Context, auctionId string) error {
+	now := ar.Clock.Now()
+
+	var currentAuction AuctionEntityMongo
+	err := ar.Collection.FindOne(ctx, bson.M{"_id": auctionId}).Decode(&currentAuction)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		logger.InfoContext(ctx, fmt.Sprintf("Auction %s not found, skipping automatic close", auctionId))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	currentEntity := currentAuction.toEntity()
+	if currentEntity.Status != auction_entity.Active ||
+		!currentEntity.DeletedAt.IsZero() || currentEntity.EndTime.After(now) {
+		logger.InfoContext(ctx, fmt.Sprintf("Auction %s is not active, skipping automatic close", auctionId))
+		return nil
+	}
+
+	filter := bson.M{
+		"_id":        auctionId,
+		"status":     auction_entity.Active,
+		"deleted_at": bson.M{"$exists": false},
+		"version":    versionFilter(currentAuction.Version),
+		"$or": bson.A{
+			bson.M{"end_time": bson.M{"$lte": now.Unix()}},
+			bson.M{"end_time": bson.M{"$exists": false}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":       auction_entity.Completed,
+			"closed_at":    now.Unix(),
+			"close_reason": auction_entity.CloseReasonExpired,
+		},
+		"$inc": incrementVersion(),
+	}
+
+	var auctionEntityMongo AuctionEntityMongo
+	err = ar.Collection.FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&auctionEntityMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return internal_error.NewVersionConflictError(
+			fmt.Sprintf("Auction %s was modified while closing", auctionId))
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.InfoContext(ctx, fmt.Sprintf("Auction %s closed automatically", auctionId))
+
+	ar.recordCloseOutcome(ctx, &auctionEntityMongo)
+	ar.notifyAuctionClosed(auctionEntityMongo.toEntity())
+
+	return nil
+}
+
+func (ar *AuctionRepository) CloseAuctionById(
+	ctx context.Context,
+	auctionId string,
+	expectedVersion int64) *internal_error.InternalError {
+	filter := bson.M{
+		"_id":     auctionId,
+		"status":  auction_entity.Active,
+		"version": versionFilter(expectedVersion),
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":       auction_entity.Completed,
+			"closed_at":    ar.Clock.Now().Unix(),
+			"close_reason": auction_entity.CloseReasonManual,
+		},
+		"$inc": incrementVersion(),
+	}
+
+	var closedAuctionMongo AuctionEntityMongo
+	err := ar.Collection.FindOneAndUpdate(
+		ctx,
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&closedAuctionMongo)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to close auction %s", auctionId), err)
+		return internal_error.NewInternalServerError("Error trying to close auction")
+	}
+
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ar.explainUpdateMiss(ctx, auctionId, expectedVersion,
+			[]auction_entity.AuctionStatus{auction_entity.Active},
+			"Auction is not active and cannot be closed", "close")
+	}
+
+	ar.OpenScheduler.Remove(auctionId)
+	ar.Scheduler.Remove(auctionId)
+	ar.PriceScheduler.Remove(auctionId)
+
+	logger.InfoContext(ctx, fmt.Sprintf("Auction %s closed manually", auctionId))
+
+	ar.recordCloseOutcome(ctx, &closedAuctionMongo)
+	ar.notifyAuctionClosed(closedAuctionMongo.toEntity())
+
+	return nil
+}
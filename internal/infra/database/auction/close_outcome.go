@@ -0,0 +1,50 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/configuration/logger"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// recordCloseOutcome runs after an auction is completed and writes who won
+// it. Bids can no longer claim the top position at that point, so
+// current_highest_amount is final, while the bids collection may still be
+// waiting for a batch flush. Auctions without a winner store an explicit
+// null winner_user_id.
+func (ar *AuctionRepository) recordCloseOutcome(ctx context.Context, closedAuction *AuctionEntityMongo) {
+	highestAmount := mongodb.AmountFromDecimal(closedAuction.CurrentHighestAmount)
+	hasWinner := closedAuction.CurrentHighestUserId != ""
+
+	set := bson.M{"winner_resolved": true}
+	if reservePrice := mongodb.AmountFromDecimal(closedAuction.ReservePrice); reservePrice > 0 {
+		reserveMet := highestAmount >= reservePrice
+		closedAuction.ReserveMet = &reserveMet
+		set["reserve_met"] = reserveMet
+		hasWinner = hasWinner && reserveMet
+
+		if !reserveMet {
+			logger.InfoContext(ctx, fmt.Sprintf("Auction %s closed below its reserve price, no winner", closedAuction.Id))
+		}
+	}
+
+	if hasWinner {
+		closedAuction.WinnerUserId = closedAuction.CurrentHighestUserId
+		closedAuction.WinningAmount = closedAuction.CurrentHighestAmount
+		set["winner_user_id"] = closedAuction.WinnerUserId
+		set["winning_amount"] = closedAuction.WinningAmount
+	} else {
+		closedAuction.WinnerUserId = ""
+		set["winner_user_id"] = nil
+	}
+	closedAuction.WinnerResolved = true
+
+	if _, err := ar.Collection.UpdateOne(ctx,
+		bson.M{"_id": closedAuction.Id},
+		bson.M{"$set": set, "$inc": incrementVersion()}); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to record the winner of auction %s", closedAuction.Id), err)
+		return
+	}
+	closedAuction.Version++
+}
@@ -0,0 +1,73 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+)
+
+const (
+	defaultCloseWorkers   = 8
+	defaultCloseQueueSize = 1024
+)
+
+func (ar *AuctionRepository) startCloseWorkers(workers int) {
+	for i := 0; i < workers; i++ {
+		ar.closeWorkers.Add(1)
+		go func() {
+			defer ar.closeWorkers.Done()
+
+			for auctionId := range ar.closeQueue {
+				if err := ar.closeJob(ar.ctx, auctionId); err != nil {
+					logger.Error(fmt.Sprintf("Failed to close auction %s automatically", auctionId), err)
+					ar.markFailedClose(auctionId)
+				}
+			}
+		}()
+	}
+}
+
+// enqueueClose blocks while the queue is full rather than dropping the close,
+// which holds back the scheduler until a worker frees up.
+func (ar *AuctionRepository) enqueueClose(auctionId string) {
+	if queued := len(ar.closeQueue); queued >= cap(ar.closeQueue)*3/4 {
+		logger.Warn(fmt.Sprintf("Close queue is backing up: %d of %d jobs pending", queued, cap(ar.closeQueue)))
+	}
+
+	select {
+	case ar.closeQueue <- auctionId:
+	case <-ar.ctx.Done():
+	}
+}
+
+func (ar *AuctionRepository) stopCloseWorkers(ctx context.Context) error {
+	ar.closeQueueOnce.Do(func() {
+		close(ar.closeQueue)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		ar.closeWorkers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (ar *AuctionRepository) CloseQueueLength() int {
+	return len(ar.closeQueue)
+}
+
+// WithCloseWorkers sets how many workers close expired auctions and how many
+// closes can wait for them.
+func WithCloseWorkers(workers, queueSize int) AuctionRepositoryOption {
+	return func(repository *AuctionRepository) {
+		repository.closeWorkerCount = workers
+		repository.closeQueueSize = queueSize
+	}
+}
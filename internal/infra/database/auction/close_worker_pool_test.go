@@ -0,0 +1,95 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/clock"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newClosePoolRepository(
+	workers, queueSize int, closeJob func(ctx context.Context, auctionId string) error) *AuctionRepository {
+	ctx, cancel := context.WithCancel(context.Background())
+	auctionRepository := &AuctionRepository{
+		Clock:             clock.NewRealClock(),
+		Scheduler:         NewAuctionScheduler(clock.NewRealClock(), func(string) {}),
+		ctx:               ctx,
+		cancel:            cancel,
+		failedCloses:      make(map[string]struct{}),
+		failedClosesMutex: &sync.Mutex{},
+		closeQueue:        make(chan string, queueSize),
+		closeJob:          closeJob,
+		closeQueueOnce:    &sync.Once{},
+		closeWorkers:      &sync.WaitGroup{},
+	}
+	auctionRepository.startCloseWorkers(workers)
+
+	return auctionRepository
+}
+
+func TestCloseWorkerPoolQueuesClosesWhileWorkersAreBusyAndDrainsThem(t *testing.T) {
+	const workers, queueSize, auctions = 2, 3, 8
+
+	release := make(chan struct{})
+	var mutex sync.Mutex
+	var running, maxRunning int
+	closed := make(map[string]int)
+
+	auctionRepository := newClosePoolRepository(workers, queueSize, func(ctx context.Context, auctionId string) error {
+		mutex.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+
+		<-release
+
+		mutex.Lock()
+		running--
+		closed[auctionId]++
+		mutex.Unlock()
+		return nil
+	})
+	defer auctionRepository.Scheduler.Stop()
+
+	enqueued := make(chan struct{})
+	go func() {
+		defer close(enqueued)
+		for i := 0; i < auctions; i++ {
+			auctionRepository.enqueueClose(fmt.Sprintf("auction-%d", i))
+		}
+	}()
+
+	assert.Eventually(t, func() bool {
+		return auctionRepository.CloseQueueLength() == queueSize
+	}, 2*time.Second, 5*time.Millisecond)
+	assert.Equal(t, queueSize, auctionRepository.PendingCloseCount())
+
+	select {
+	case <-enqueued:
+		t.Fatal("enqueueing did not block on a full queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	select {
+	case <-enqueued:
+	case <-time.After(2 * time.Second):
+		t.Fatal("enqueueing did not resume once the workers freed up")
+	}
+	assert.Nil(t, auctionRepository.stopCloseWorkers(context.Background()))
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, workers, maxRunning)
+	assert.Len(t, closed, auctions)
+	for auctionId, count := range closed {
+		assert.Equal(t, 1, count, auctionId)
+	}
+	assert.Equal(t, 0, auctionRepository.PendingCloseCount())
+}
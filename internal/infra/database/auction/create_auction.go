@@ -2,13 +2,15 @@ package auction
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"fullcycle-auction_go/configuration/database/mongodb"
 	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/clock"
 	"fullcycle-auction_go/internal/entity/auction_entity"
 	"fullcycle-auction_go/internal/internal_error"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo/options"
-	"os"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"sync"
 	"time"
 
@@ -16,129 +18,407 @@ import (
 )
 
 type AuctionEntityMongo struct {
-	Id          string                          `bson:"_id"`
-	ProductName string                          `bson:"product_name"`
-	Category    string                          `bson:"category"`
-	Description string                          `bson:"description"`
-	Condition   auction_entity.ProductCondition `bson:"condition"`
-	Status      auction_entity.AuctionStatus    `bson:"status"`
-	Timestamp   int64                           `bson:"timestamp"`
+	Id             string                          `bson:"_id"`
+	SellerId       string                          `bson:"seller_id,omitempty"`
+	ProductName    string                          `bson:"product_name"`
+	Category       string                          `bson:"category"`
+	Description    string                          `bson:"description"`
+	Condition      auction_entity.ProductCondition `bson:"condition"`
+	Status         auction_entity.AuctionStatus    `bson:"status"`
+	Type           auction_entity.AuctionType      `bson:"auction_type,omitempty"`
+	Timestamp      int64                           `bson:"timestamp"`
+	StartTime      int64                           `bson:"start_time,omitempty"`
+	EndTime        int64                           `bson:"end_time,omitempty"`
+	Duration       int64                           `bson:"duration_seconds,omitempty"`
+	Extension      int64                           `bson:"extension_seconds,omitempty"`
+	SnipeExtension int64                           `bson:"snipe_extension_seconds,omitempty"`
+	Remaining      int64                           `bson:"remaining_seconds,omitempty"`
+	ClosedAt       int64                           `bson:"closed_at,omitempty"`
+	CloseReason    string                          `bson:"close_reason,omitempty"`
+	DeletedAt      int64                           `bson:"deleted_at,omitempty"`
+	Version        int64                           `bson:"version"`
+
+	MinIncrement         primitive.Decimal128 `bson:"min_increment,omitempty"`
+	StartingPrice        primitive.Decimal128 `bson:"starting_price,omitempty"`
+	CurrentHighestAmount primitive.Decimal128 `bson:"current_highest_amount,omitempty"`
+	CurrentHighestUserId string               `bson:"current_highest_user_id,omitempty"`
+	ReservePrice         primitive.Decimal128 `bson:"reserve_price,omitempty"`
+	ReserveMet           *bool                `bson:"reserve_met,omitempty"`
+	BuyNowPrice          primitive.Decimal128 `bson:"buy_now_price,omitempty"`
+	WinnerUserId         string               `bson:"winner_user_id,omitempty"`
+	WinningAmount        primitive.Decimal128 `bson:"winning_amount,omitempty"`
+	WinnerResolved       bool                 `bson:"winner_resolved,omitempty"`
+	BidSequence          int64                `bson:"bid_sequence,omitempty"`
+	BidCount             int64                `bson:"bid_count,omitempty"`
+
+	StartPrice        primitive.Decimal128 `bson:"start_price,omitempty"`
+	FloorPrice        primitive.Decimal128 `bson:"floor_price,omitempty"`
+	PriceDecrement    primitive.Decimal128 `bson:"price_decrement,omitempty"`
+	DecrementInterval int64                `bson:"decrement_interval_seconds,omitempty"`
+	CurrentPrice      primitive.Decimal128 `bson:"current_price,omitempty"`
+
+	IdempotencyKey string `bson:"idempotency_key,omitempty"`
 }
 
 type AuctionRepository struct {
-	Collection        *mongo.Collection
-	AuctionsAutoClose map[string]auction_entity.AuctionStatus
-	CloseMutex        *sync.Mutex
+	Collection          *mongo.Collection
+	EventsCollection    *mongo.Collection
+	AuditCollection     *mongo.Collection
+	WatchlistCollection *mongo.Collection
+	Clock               clock.Clock
+	Scheduler           *AuctionScheduler
+	OpenScheduler       *AuctionScheduler
+	PriceScheduler      *AuctionScheduler
+	Lease               *AuctionCloseLease
+	CloseStrategy       string
+	sweepInterval       time.Duration
+
+	queryCollection *mongo.Collection
+	auditWriter     auditWriter
+
+	ctx            context.Context
+	cancel         context.CancelFunc
+	closeListeners []AuctionClosedListener
+	listenersMutex *sync.RWMutex
+
+	failedCloses      map[string]struct{}
+	failedClosesMutex *sync.Mutex
+
+	closeQueue       chan string
+	closeJob         func(ctx context.Context, auctionId string) error
+	closeQueueOnce   *sync.Once
+	closeWorkers     *sync.WaitGroup
+	closeWorkerCount int
+	closeQueueSize   int
+	closeMaxAttempts int
+
+	leaderElection      bool
+	leaseTTL            time.Duration
+	changeStreamEnabled bool
+	statsTimeout        time.Duration
+	streamBatchSize     int32
+}
+
+func NewAuctionRepository(database *mongo.Database, opts ...AuctionRepositoryOption) *AuctionRepository {
+	return NewAuctionRepositoryWithClock(database, clock.NewRealClock(), opts...)
 }
 
-func NewAuctionRepository(database *mongo.Database) *AuctionRepository {
-	return &AuctionRepository{
-		Collection:        database.Collection("auctions"),
-		AuctionsAutoClose: make(map[string]auction_entity.AuctionStatus),
-		CloseMutex:        &sync.Mutex{},
+func NewAuctionRepositoryWithClock(
+	database *mongo.Database,
+	auctionClock clock.Clock,
+	opts ...AuctionRepositoryOption) *AuctionRepository {
+	ctx, cancel := context.WithCancel(context.Background())
+	collection := primaryCollection(database.Collection("auctions"))
+	auditCollection := database.Collection("auction_audit")
+
+	auctionRepository := &AuctionRepository{
+		Collection:          collection,
+		EventsCollection:    database.Collection("auction_events"),
+		AuditCollection:     auditCollection,
+		WatchlistCollection: database.Collection("watchlists"),
+		queryCollection:     collection,
+		auditWriter:         auditCollection,
+		Clock:               auctionClock,
+		CloseStrategy:       CloseStrategyTimer,
+		sweepInterval:       defaultSweepInterval,
+		ctx:                 ctx,
+		cancel:              cancel,
+		listenersMutex:      &sync.RWMutex{},
+
+		failedCloses:      make(map[string]struct{}),
+		failedClosesMutex: &sync.Mutex{},
+
+		closeQueueOnce:   &sync.Once{},
+		closeWorkers:     &sync.WaitGroup{},
+		closeWorkerCount: defaultCloseWorkers,
+		closeQueueSize:   defaultCloseQueueSize,
+		closeMaxAttempts: defaultCloseMaxAttempts,
+
+		leaseTTL:        defaultLeaseTTL,
+		statsTimeout:    defaultStatsTimeout,
+		streamBatchSize: defaultStreamBatchSize,
+	}
+	for _, opt := range opts {
+		opt(auctionRepository)
+	}
+	auctionRepository.closeQueue = make(chan string, auctionRepository.closeQueueSize)
+	auctionRepository.closeJob = auctionRepository.closeAuctionWithRetry
+
+	auctionRepository.Scheduler = NewAuctionScheduler(auctionClock, auctionRepository.autoCloseAuction)
+	auctionRepository.OpenScheduler = NewAuctionScheduler(auctionClock, auctionRepository.autoOpenAuction)
+	auctionRepository.PriceScheduler = NewAuctionScheduler(auctionClock, auctionRepository.autoDropAuctionPrice)
+	auctionRepository.startCloseWorkers(auctionRepository.closeWorkerCount)
+
+	if auctionRepository.leaderElection {
+		auctionRepository.Lease = NewAuctionCloseLease(database, auctionClock, auctionRepository.leaseTTL)
+		go auctionRepository.runLeaseHeartbeat()
 	}
+
+	go auctionRepository.runExpiredSweep()
+
+	return auctionRepository
+}
+
+func (ar *AuctionRepository) Shutdown(ctx context.Context) error {
+	defer ar.cancel()
+
+	ar.PriceScheduler.Stop()
+
+	if err := ar.OpenScheduler.Shutdown(ctx); err != nil {
+		ar.Scheduler.Stop()
+		ar.stopCloseWorkers(ctx)
+		return err
+	}
+
+	if err := ar.Scheduler.Shutdown(ctx); err != nil {
+		ar.stopCloseWorkers(ctx)
+		return err
+	}
+
+	return ar.stopCloseWorkers(ctx)
+}
+
+// AutoCloseRunning reports whether auctions still get closed on time, which
+// stops being true once the repository is shut down.
+func (ar *AuctionRepository) AutoCloseRunning() bool {
+	return ar.ctx.Err() == nil && ar.Scheduler.Alive()
+}
+
+// PendingCloseCount is how many auctions wait to be closed, either for their
+// timer or in the close queue for a free worker.
+func (ar *AuctionRepository) PendingCloseCount() int {
+	return ar.Scheduler.Len() + ar.CloseQueueLength()
 }
 
 func (ar *AuctionRepository) CreateAuction(
 	ctx context.Context,
 	auctionEntity *auction_entity.Auction) *internal_error.InternalError {
 
-	auctionEntityMongo := &AuctionEntityMongo{
+	if auctionEntity.EndTime.IsZero() {
+		auctionEntity.EndTime = calculateAuctionEndTime(*auctionEntity)
+	}
+
+	auctionEntityMongo := newAuctionEntityMongo(auctionEntity)
+	err := mongodb.WithTransaction(ctx, ar.Collection.Database(), func(txCtx context.Context) error {
+		if _, err := ar.Collection.InsertOne(txCtx, auctionEntityMongo); err != nil {
+			return err
+		}
+
+		return ar.insertCreationRecords(txCtx, *auctionEntity)
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		if auctionEntity.IdempotencyKey != "" {
+			return ar.findByIdempotencyKey(ctx, auctionEntity)
+		}
+
+		return internal_error.NewConflictError(
+			fmt.Sprintf("Auction with id %s already exists", auctionEntity.Id))
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, "Error trying to insert auction", err)
+		return internal_error.NewInternalServerError("Error trying to insert auction")
+	}
+
+	ar.scheduleAuction(*auctionEntity)
+
+	return nil
+}
+
+func newAuctionEntityMongo(auctionEntity *auction_entity.Auction) *AuctionEntityMongo {
+	return &AuctionEntityMongo{
 		Id:          auctionEntity.Id,
+		SellerId:    auctionEntity.SellerId,
 		ProductName: auctionEntity.ProductName,
 		Category:    auctionEntity.Category,
 		Description: auctionEntity.Description,
 		Condition:   auctionEntity.Condition,
 		Status:      auctionEntity.Status,
+		Type:        auctionEntity.Type,
 		Timestamp:   auctionEntity.Timestamp.Unix(),
+		StartTime:   unixOrZero(auctionEntity.StartTime),
+		EndTime:     auctionEntity.EndTime.Unix(),
+		Duration:    int64(auctionEntity.Duration / time.Second),
+
+		IdempotencyKey: auctionEntity.IdempotencyKey,
+		MinIncrement:   mongodb.DecimalFromAmount(auctionEntity.MinIncrement),
+		StartingPrice:  mongodb.DecimalFromAmount(auctionEntity.StartingPrice),
+		ReservePrice:   mongodb.DecimalFromAmount(auctionEntity.ReservePrice),
+		BuyNowPrice:    mongodb.DecimalFromAmount(auctionEntity.BuyNowPrice),
+
+		StartPrice:        mongodb.DecimalFromAmount(auctionEntity.StartPrice),
+		FloorPrice:        mongodb.DecimalFromAmount(auctionEntity.FloorPrice),
+		PriceDecrement:    mongodb.DecimalFromAmount(auctionEntity.PriceDecrement),
+		DecrementInterval: int64(auctionEntity.DecrementInterval / time.Second),
+		CurrentPrice:      mongodb.DecimalFromAmount(auctionEntity.CurrentPrice),
+	}
+}
+
+func (ar *AuctionRepository) findByIdempotencyKey(
+	ctx context.Context,
+	auctionEntity *auction_entity.Auction) *internal_error.InternalError {
+	var existingAuction AuctionEntityMongo
+	err := ar.Collection.FindOne(
+		ctx, bson.M{"idempotency_key": auctionEntity.IdempotencyKey}).Decode(&existingAuction)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return internal_error.NewConflictError(
+			fmt.Sprintf("Auction with id %s already exists", auctionEntity.Id))
 	}
-	_, err := ar.Collection.InsertOne(ctx, auctionEntityMongo)
 	if err != nil {
-		logger.Error("Error trying to insert auction", err)
+		logger.ErrorContext(ctx, "Error trying to find auction by idempotency key", err)
 		return internal_error.NewInternalServerError("Error trying to insert auction")
 	}
 
-	ar.CloseMutex.Lock()
-	err = ar.autoClose(ctx)
-	ar.CloseMutex.Unlock()
+	logger.InfoContext(ctx, fmt.Sprintf("Auction %s already created for this idempotency key", existingAuction.Id))
 
-	if err != nil {
-		return internal_error.NewInternalServerError("Failed to initiate auto-close process")
+	*auctionEntity = existingAuction.toEntity()
+	return nil
+}
+
+func (ar *AuctionRepository) scheduleAuction(auctionEntity auction_entity.Auction) {
+	ar.schedulePriceDrop(auctionEntity)
+
+	if ar.CloseStrategy == CloseStrategySweep {
+		return
 	}
 
-	return nil
+	if auctionEntity.Status == auction_entity.Scheduled {
+		ar.OpenScheduler.Schedule(auctionEntity.Id, auctionEntity.OpensAt())
+	}
+
+	ar.Scheduler.Schedule(auctionEntity.Id, calculateAuctionEndTime(auctionEntity))
 }
 
-func (ar *AuctionRepository) autoClose(ctx context.Context) error {
-	openAuctions, err := ar.FindOpenAuctions(ctx)
-	if err != nil {
-		return err
+// rescheduleClose moves the close timer of an auction whose deadline changed.
+// In sweep mode there are no timers to move.
+func (ar *AuctionRepository) rescheduleClose(auctionId string, deadline time.Time) {
+	if ar.CloseStrategy == CloseStrategySweep {
+		return
 	}
 
-	for _, auctionEntity := range openAuctions {
-		timeUntilClose := calculateAuctionEndTime(auctionEntity)
+	ar.Scheduler.Reschedule(auctionId, deadline)
+}
 
-		if timeUntilClose <= 0 {
-			err := ar.closeAuction(ctx, auctionEntity)
-			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to close auction %s immediately", auctionEntity.Id), err)
-			}
-			continue
-		}
+func (ar *AuctionRepository) autoOpenAuction(auctionId string) {
+	if !ar.IsCloseLeader() {
+		return
+	}
 
-		_, okStatus := ar.AuctionsAutoClose[auctionEntity.Id]
-		if okStatus {
-			continue
-		}
+	if err := ar.openAuction(ar.ctx, auctionId); err != nil {
+		logger.Error(fmt.Sprintf("Failed to open auction %s automatically", auctionId), err)
+	}
+}
 
-		ar.AuctionsAutoClose[auctionEntity.Id] = auction_entity.Active
+func (ar *AuctionRepository) autoCloseAuction(auctionId string) {
+	if !ar.IsCloseLeader() {
+		return
+	}
 
-		timer := time.NewTimer(timeUntilClose)
+	ar.enqueueClose(auctionId)
+}
 
-		go func(auction auction_entity.Auction) {
-			<-timer.C
-			err := ar.closeAuction(ctx, auction)
-			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to close auction %s automatically", auction.Id), err)
-			}
-			delete(ar.AuctionsAutoClose, auction.Id)
-		}(auctionEntity)
+func calculateAuctionEndTime(auctionEntity auction_entity.Auction) time.Time {
+	if !auctionEntity.EndTime.IsZero() {
+		return auctionEntity.EndTime
 	}
 
-	return nil
+	if auctionEntity.Duration > 0 {
+		return auctionEntity.OpensAt().Add(auctionEntity.Duration)
+	}
+
+	return auctionEntity.OpensAt().Add(getAuctionInterval())
 }
 
-func getAuctionInterval() time.Duration {
-	auctionInterval := os.Getenv("AUCTION_INTERVAL")
-	duration, err := time.ParseDuration(auctionInterval)
-	if err != nil {
-		return time.Minute * 5
+func unixOrZero(value time.Time) int64 {
+	if value.IsZero() {
+		return 0
 	}
 
-	return duration
+	return value.Unix()
 }
 
-func calculateAuctionEndTime(auctionEntity auction_entity.Auction) time.Duration {
-	auctionEndTime := auctionEntity.Timestamp.Add(getAuctionInterval())
-	return time.Until(auctionEndTime)
+// auctionTypeOrOpen treats auctions stored before auction types existed as
+// open ones.
+func auctionTypeOrOpen(auctionType auction_entity.AuctionType) auction_entity.AuctionType {
+	if auctionType == "" {
+		return auction_entity.OpenAuction
+	}
+
+	return auctionType
 }
 
-func (ar *AuctionRepository) closeAuction(ctx context.Context, auctionEntity auction_entity.Auction) error {
-	filter := bson.M{"_id": auctionEntity.Id}
-	update := bson.M{"$set": bson.M{"status": auction_entity.Completed}}
+func (am *AuctionEntityMongo) toEntity() auction_entity.Auction {
+	auctionEntity := auction_entity.Auction{
+		Id:             am.Id,
+		SellerId:       am.SellerId,
+		ProductName:    am.ProductName,
+		Category:       am.Category,
+		Description:    am.Description,
+		Condition:      am.Condition,
+		Status:         am.Status,
+		Type:           auctionTypeOrOpen(am.Type),
+		Timestamp:      time.Unix(am.Timestamp, 0),
+		Duration:       time.Duration(am.Duration) * time.Second,
+		Extension:      time.Duration(am.Extension) * time.Second,
+		SnipeExtension: time.Duration(am.SnipeExtension) * time.Second,
+		CloseReason:    am.CloseReason,
+		Version:        am.Version,
+
+		IdempotencyKey:       am.IdempotencyKey,
+		MinIncrement:         mongodb.AmountFromDecimal(am.MinIncrement),
+		StartingPrice:        mongodb.AmountFromDecimal(am.StartingPrice),
+		CurrentHighestAmount: mongodb.AmountFromDecimal(am.CurrentHighestAmount),
+		CurrentHighestUserId: am.CurrentHighestUserId,
+		ReservePrice:         mongodb.AmountFromDecimal(am.ReservePrice),
+		ReserveMet:           am.ReserveMet,
+		BuyNowPrice:          mongodb.AmountFromDecimal(am.BuyNowPrice),
+		WinnerUserId:         am.WinnerUserId,
+		WinningAmount:        mongodb.AmountFromDecimal(am.WinningAmount),
+		WinnerResolved:       am.WinnerResolved,
+		BidCount:             am.BidCount,
 
-	_, err := ar.Collection.UpdateOne(
-		ctx,
-		filter,
-		update,
-		options.Update().SetUpsert(false),
-	)
+		StartPrice:        mongodb.AmountFromDecimal(am.StartPrice),
+		FloorPrice:        mongodb.AmountFromDecimal(am.FloorPrice),
+		PriceDecrement:    mongodb.AmountFromDecimal(am.PriceDecrement),
+		DecrementInterval: time.Duration(am.DecrementInterval) * time.Second,
+		CurrentPrice:      mongodb.AmountFromDecimal(am.CurrentPrice),
+	}
 
+	if am.StartTime != 0 {
+		auctionEntity.StartTime = time.Unix(am.StartTime, 0)
+	}
+	if am.EndTime != 0 {
+		auctionEntity.EndTime = time.Unix(am.EndTime, 0)
+	}
+	if am.ClosedAt != 0 {
+		auctionEntity.ClosedAt = time.Unix(am.ClosedAt, 0)
+	}
+	if am.DeletedAt != 0 {
+		auctionEntity.DeletedAt = time.Unix(am.DeletedAt, 0)
+	}
+	auctionEntity.EndTime = calculateAuctionEndTime(auctionEntity)
+
+	return auctionEntity
+}
+
+func (ar *AuctionRepository) openAuction(ctx context.Context, auctionId string) error {
+	filter := bson.M{
+		"_id":        auctionId,
+		"status":     auction_entity.Scheduled,
+		"deleted_at": bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$set": bson.M{"status": auction_entity.Active},
+		"$inc": incrementVersion(),
+	}
+
+	result, err := ar.Collection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
 
-	logger.Info(fmt.Sprintf("Auction %s closed automatically", auctionEntity.Id))
+	if result.ModifiedCount > 0 {
+		logger.InfoContext(ctx, fmt.Sprintf("Auction %s opened automatically", auctionId))
+	}
 
 	return nil
 }
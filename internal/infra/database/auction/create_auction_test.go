@@ -2,10 +2,15 @@ package auction
 
 import (
 	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/config"
 	"fullcycle-auction_go/configuration/database/mongodb"
 	"fullcycle-auction_go/internal/entity/auction_entity"
-	"github.com/joho/godotenv"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 	"log"
 	"os"
 	"path"
@@ -13,40 +18,271 @@ import (
 	"time"
 )
 
-func TestAuctionAutoClose(t *testing.T) {
+func connectTestDatabase() *mongo.Database {
 	dir := "./"
 	envFilePath := path.Join(dir, "../../../../cmd/auction/.env")
 
-	if err := godotenv.Load(envFilePath); err != nil {
-		log.Fatal("Error trying to load env variables")
-		return
+	cfg, err := config.Load(envFilePath)
+	if err != nil {
+		log.Fatal(err.Error())
 	}
+	SetAuctionInterval(cfg.Auction.Interval)
 
-	ctx := context.Background()
-	conn, err := mongodb.NewMongoDBConnection(ctx)
+	conn, err := mongodb.NewMongoDBConnection(context.Background(), cfg.Mongo)
 	if err != nil {
 		log.Fatal("Error trying to connect mongodb")
-		return
 	}
 
+	return conn
+}
+
+func assertAuctionStatusEventually(
+	t *testing.T, ca *AuctionRepository, auctionId string, status auction_entity.AuctionStatus) {
+	assert.Eventually(t, func() bool {
+		auctionDb, err := ca.FindAuctionById(context.Background(), auctionId)
+		return err == nil && auctionDb.Status == status
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestAuctionAutoClose(t *testing.T) {
+	strategies := []struct {
+		name     string
+		strategy string
+	}{
+		{name: "timer", strategy: CloseStrategyTimer},
+		{name: "sweep", strategy: CloseStrategySweep},
+	}
+
+	for _, tt := range strategies {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			conn := connectTestDatabase()
+
+			auction, _ := auction_entity.CreateAuction(
+				"mouse",
+				"peripherals",
+				"mouse gamer rgb",
+				auction_entity.New)
+
+			fakeClock := fakeclock.New(time.Now())
+			ca := NewAuctionRepositoryWithClock(
+				conn, fakeClock, WithCloseStrategy(tt.strategy, 100*time.Millisecond))
+			defer ca.Shutdown(ctx)
+			ca.CreateAuction(ctx, auction)
+
+			auctionInterval := os.Getenv("AUCTION_INTERVAL")
+			durationAuction, err := time.ParseDuration(auctionInterval)
+			if err != nil {
+				log.Fatal("Error parsing durationAuction")
+			}
+
+			t.Log(auctionInterval)
+			auctionDb, _ := ca.FindAuctionById(ctx, auction.Id)
+			assert.Equal(t, auction_entity.Active, auctionDb.Status)
+
+			fakeClock.Advance(durationAuction + time.Second*3)
+
+			assertAuctionStatusEventually(t, ca, auction.Id, auction_entity.Completed)
+		})
+	}
+}
+
+func TestAuctionAutoCloseWithCustomDuration(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	customDuration := 5 * time.Second
 	auction, _ := auction_entity.CreateAuction(
-		"mouse",
+		"headset",
 		"peripherals",
-		"mouse gamer rgb",
-		auction_entity.New)
+		"wireless headset",
+		auction_entity.New,
+		auction_entity.WithDuration(customDuration))
 
-	ca := NewAuctionRepository(conn)
+	fakeClock := fakeclock.New(time.Now())
+	ca := NewAuctionRepositoryWithClock(conn, fakeClock)
+	defer ca.Shutdown(ctx)
 	ca.CreateAuction(ctx, auction)
 
-	auctionInterval := os.Getenv("AUCTION_INTERVAL")
-	durationAuction, err := time.ParseDuration(auctionInterval)
+	auctionDb, _ := ca.FindAuctionById(ctx, auction.Id)
+	assert.Equal(t, auction_entity.Active, auctionDb.Status)
+	assert.Equal(t, customDuration, auctionDb.Duration)
+
+	fakeClock.Advance(customDuration + time.Second*3)
+
+	assertAuctionStatusEventually(t, ca, auction.Id, auction_entity.Completed)
+}
+
+func TestAuctionAutoCloseAfterRequestContextIsDone(t *testing.T) {
+	conn := connectTestDatabase()
+
+	auction, _ := auction_entity.CreateAuction(
+		"keyboard",
+		"peripherals",
+		"mechanical keyboard",
+		auction_entity.New)
+
+	fakeClock := fakeclock.New(time.Now())
+	ca := NewAuctionRepositoryWithClock(conn, fakeClock)
+	defer ca.Shutdown(context.Background())
+
+	requestCtx, cancel := context.WithCancel(context.Background())
+	ca.CreateAuction(requestCtx, auction)
+	cancel()
+
+	durationAuction, err := time.ParseDuration(os.Getenv("AUCTION_INTERVAL"))
 	if err != nil {
 		log.Fatal("Error parsing durationAuction")
 	}
 
-	t.Log(auctionInterval)
-	time.Sleep(durationAuction + time.Second*3)
+	fakeClock.Advance(durationAuction + time.Second*3)
 
-	auctionDb, _ := ca.FindAuctionById(ctx, auction.Id)
-	assert.Equal(t, auction_entity.Completed, auctionDb.Status)
+	assertAuctionStatusEventually(t, ca, auction.Id, auction_entity.Completed)
+}
+
+func TestAuctionAutoCloseDoesNotOverwriteCancelledAuction(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	auction, _ := auction_entity.CreateAuction(
+		"webcam",
+		"peripherals",
+		"full hd webcam",
+		auction_entity.New,
+		auction_entity.WithDuration(5*time.Second))
+
+	fakeClock := fakeclock.New(time.Now())
+	ca := NewAuctionRepositoryWithClock(conn, fakeClock)
+	defer ca.Shutdown(ctx)
+	assert.Nil(t, ca.CreateAuction(ctx, auction))
+
+	// Another instance cancels the auction, so this one keeps its timer.
+	otherInstance := NewAuctionRepositoryWithClock(conn, fakeClock)
+	defer otherInstance.Shutdown(ctx)
+	assert.Nil(t, otherInstance.CancelAuction(ctx, auction.Id, "listing error", 0))
+	assert.True(t, ca.Scheduler.Has(auction.Id))
+
+	fakeClock.Advance(10 * time.Second)
+	assert.Eventually(t, func() bool { return !ca.Scheduler.Has(auction.Id) }, 5*time.Second, 10*time.Millisecond)
+
+	assert.Never(t, func() bool {
+		auctionDb, err := ca.FindAuctionByIdFromPrimary(ctx, auction.Id)
+		return err != nil || auctionDb.Status != auction_entity.Cancelled
+	}, 500*time.Millisecond, 20*time.Millisecond)
+}
+
+// BenchmarkCreateAuctionWithOpenAuctions compares insert latency with no open
+// auctions against 10k of them; the two should stay about the same. Each run
+// seeds its own database and drops it afterwards.
+func BenchmarkCreateAuctionWithOpenAuctions(b *testing.B) {
+	for _, openAuctions := range []int{0, 10000} {
+		b.Run(fmt.Sprintf("open=%d", openAuctions), func(b *testing.B) {
+			benchmarkCreateAuction(b, openAuctions)
+		})
+	}
+}
+
+func benchmarkCreateAuction(b *testing.B, openAuctions int) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+	database := conn.Client().Database(conn.Name() + "_bench_" + uuid.New().String()[:8])
+	b.Cleanup(func() {
+		if err := database.Drop(ctx); err != nil {
+			b.Error(err)
+		}
+	})
+
+	fakeClock := fakeclock.New(time.Now())
+	ca := NewAuctionRepositoryWithClock(database, fakeClock)
+	b.Cleanup(func() { ca.Shutdown(ctx) })
+
+	if openAuctions > 0 {
+		seeded := make([]interface{}, 0, openAuctions)
+		for i := 0; i < openAuctions; i++ {
+			auction, _ := auction_entity.CreateAuction(
+				"seeded product",
+				"benchmark",
+				"seeded open auction",
+				auction_entity.New)
+
+			seeded = append(seeded, AuctionEntityMongo{
+				Id:          auction.Id,
+				ProductName: auction.ProductName,
+				Category:    auction.Category,
+				Description: auction.Description,
+				Condition:   auction.Condition,
+				Status:      auction.Status,
+				Timestamp:   auction.Timestamp.Unix(),
+				EndTime:     auction.Timestamp.Add(time.Hour).Unix(),
+			})
+		}
+		if _, err := ca.Collection.InsertMany(ctx, seeded); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		auction, _ := auction_entity.CreateAuction(
+			"benchmark product",
+			"benchmark",
+			"benchmark auction",
+			auction_entity.New)
+
+		if err := ca.CreateAuction(ctx, auction); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCreateAuctionWithDuplicateIdReturnsConflict(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	auction, _ := auction_entity.CreateAuction(
+		"speaker",
+		"audio",
+		"bluetooth speaker",
+		auction_entity.New)
+
+	ca := NewAuctionRepositoryWithClock(conn, fakeclock.New(time.Now()))
+	defer ca.Shutdown(ctx)
+
+	assert.Nil(t, ca.CreateAuction(ctx, auction))
+
+	retry := *auction
+	err := ca.CreateAuction(ctx, &retry)
+	assert.NotNil(t, err)
+	assert.Equal(t, "conflict", err.Err)
+}
+
+func TestCreateAuctionWithIdempotencyKeyReturnsExistingAuction(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	ca := NewAuctionRepositoryWithClock(conn, fakeclock.New(time.Now()))
+	defer ca.Shutdown(ctx)
+	assert.Nil(t, ca.EnsureIndexes(ctx))
+
+	idempotencyKey := uuid.New().String()
+	first, _ := auction_entity.CreateAuction(
+		"turntable",
+		"audio",
+		"vintage turntable",
+		auction_entity.Used,
+		auction_entity.WithIdempotencyKey(idempotencyKey))
+	assert.Nil(t, ca.CreateAuction(ctx, first))
+
+	retry, _ := auction_entity.CreateAuction(
+		"turntable",
+		"audio",
+		"vintage turntable",
+		auction_entity.Used,
+		auction_entity.WithIdempotencyKey(idempotencyKey))
+	assert.Nil(t, ca.CreateAuction(ctx, retry))
+	assert.Equal(t, first.Id, retry.Id)
+
+	count, err := ca.Collection.CountDocuments(ctx, bson.M{"idempotency_key": idempotencyKey})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
 }
@@ -0,0 +1,85 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"testing"
+	"time"
+)
+
+func TestCreateAuctionWritesCreationRecords(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	ca := NewAuctionRepositoryWithClock(conn, fakeclock.New(time.Now()))
+	defer ca.Shutdown(ctx)
+
+	auction, _ := auction_entity.CreateAuction(
+		"camera",
+		"photography",
+		"mirrorless camera body",
+		auction_entity.Used)
+	assert.Nil(t, ca.CreateAuction(ctx, auction))
+
+	events, err := ca.EventsCollection.CountDocuments(ctx, bson.M{"auction_id": auction.Id})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), events)
+
+	audits, err := ca.AuditCollection.CountDocuments(ctx, bson.M{"auction_id": auction.Id})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), audits)
+
+	assert.Nil(t, ca.MarkEventPublished(ctx, auction.Id+":"+AuctionEventCreated))
+	unpublished, err := ca.EventsCollection.CountDocuments(
+		ctx, bson.M{"auction_id": auction.Id, "published": false})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), unpublished)
+}
+
+type failingAuditWriter struct{}
+
+func (failingAuditWriter) InsertOne(
+	ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	return nil, errors.New("audit store unavailable")
+}
+
+func TestCreateAuctionRollsBackWhenCreationRecordFails(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	if !mongodb.SupportsTransactions(ctx, conn) {
+		t.Skip("mongodb is not a replica set, transactions are not available")
+	}
+
+	ca := NewAuctionRepositoryWithClock(conn, fakeclock.New(time.Now()))
+	defer ca.Shutdown(ctx)
+
+	auction, _ := auction_entity.CreateAuction(
+		"lens",
+		"photography",
+		"50mm prime lens",
+		auction_entity.Used)
+
+	ca.auditWriter = failingAuditWriter{}
+
+	createErr := ca.CreateAuction(ctx, auction)
+	assert.NotNil(t, createErr)
+	assert.Equal(t, internal_error.ErrInternalServer, createErr.Err)
+
+	auctions, countErr := ca.Collection.CountDocuments(ctx, bson.M{"_id": auction.Id})
+	assert.Nil(t, countErr)
+	assert.Equal(t, int64(0), auctions)
+
+	events, countErr := ca.EventsCollection.CountDocuments(ctx, bson.M{"auction_id": auction.Id})
+	assert.Nil(t, countErr)
+	assert.Equal(t, int64(0), events)
+	assert.Equal(t, 0, ca.PendingCloseCount())
+}
@@ -0,0 +1,63 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (ar *AuctionRepository) CreateAuctions(
+	ctx context.Context,
+	auctionEntities []*auction_entity.Auction) *internal_error.InternalError {
+	documents := make([]interface{}, 0, len(auctionEntities))
+	for _, auctionEntity := range auctionEntities {
+		if auctionEntity.EndTime.IsZero() {
+			auctionEntity.EndTime = calculateAuctionEndTime(*auctionEntity)
+		}
+
+		documents = append(documents, newAuctionEntityMongo(auctionEntity))
+	}
+
+	_, err := ar.Collection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+
+	var bulkWriteException mongo.BulkWriteException
+	if err != nil && !errors.As(err, &bulkWriteException) {
+		logger.ErrorContext(ctx, "Error trying to insert auctions", err)
+		return internal_error.NewInternalServerError("Error trying to insert auctions")
+	}
+
+	failed := make(map[int]struct{}, len(bulkWriteException.WriteErrors))
+	var failures []internal_error.ItemFailure
+	for _, writeError := range bulkWriteException.WriteErrors {
+		failed[writeError.Index] = struct{}{}
+
+		failure := internal_error.ItemFailure{
+			Index:   writeError.Index,
+			Err:     "internal_server_error",
+			Message: "Error trying to insert auction",
+		}
+		if writeError.HasErrorCode(11000) {
+			failure.Err = "conflict"
+			failure.Message = fmt.Sprintf("Auction with id %s already exists", auctionEntities[writeError.Index].Id)
+		}
+		failures = append(failures, failure)
+	}
+
+	for index, auctionEntity := range auctionEntities {
+		if _, ok := failed[index]; !ok {
+			ar.scheduleAuction(*auctionEntity)
+		}
+	}
+
+	if len(failures) > 0 {
+		logger.InfoContext(ctx, fmt.Sprintf("%d of %d auctions failed to insert", len(failures), len(auctionEntities)))
+		return internal_error.NewBulkWriteError("Some auctions could not be created", failures)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"go.mongodb.org/mongo-driver/bson"
+	"time"
+)
+
+// schedulePriceDrop arms the next price drop of a dutch auction. A price left
+// behind while no instance was running is caught up right away.
+func (ar *AuctionRepository) schedulePriceDrop(auctionEntity auction_entity.Auction) {
+	if !auctionEntity.IsDutch() {
+		return
+	}
+
+	now := ar.Clock.Now()
+	if auctionEntity.Status == auction_entity.Active && auctionEntity.CurrentPrice > auctionEntity.PriceAt(now) {
+		ar.PriceScheduler.Schedule(auctionEntity.Id, now)
+		return
+	}
+
+	ar.scheduleNextPriceDrop(auctionEntity, now)
+}
+
+func (ar *AuctionRepository) scheduleNextPriceDrop(auctionEntity auction_entity.Auction, now time.Time) {
+	if next := auctionEntity.NextPriceDropAt(now); !next.IsZero() {
+		ar.PriceScheduler.Schedule(auctionEntity.Id, next)
+	}
+}
+
+func (ar *AuctionRepository) autoDropAuctionPrice(auctionId string) {
+	auctionEntity, err := ar.FindAuctionByIdFromPrimary(ar.ctx, auctionId)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to load auction %s to drop its price", auctionId), err)
+		return
+	}
+
+	now := ar.Clock.Now()
+	switch auctionEntity.Status {
+	case auction_entity.Scheduled:
+		ar.scheduleNextPriceDrop(*auctionEntity, now)
+	case auction_entity.Active:
+		if ar.IsCloseLeader() {
+			if err := ar.lowerCurrentPrice(ar.ctx, auctionId, auctionEntity.PriceAt(now)); err != nil {
+				logger.Error(fmt.Sprintf("Failed to drop the price of auction %s", auctionId), err)
+			}
+		}
+		ar.scheduleNextPriceDrop(*auctionEntity, now)
+	}
+}
+
+// lowerCurrentPrice only ever moves the stored price down, so a late or
+// repeated drop cannot raise it again.
+func (ar *AuctionRepository) lowerCurrentPrice(
+	ctx context.Context, auctionId string, price money.Amount) *internal_error.InternalError {
+	filter := bson.M{
+		"_id":           auctionId,
+		"status":        auction_entity.Active,
+		"auction_type":  auction_entity.DutchAuction,
+		"current_price": bson.M{"$gt": mongodb.DecimalFromAmount(price)},
+	}
+	update := bson.M{
+		"$set": bson.M{"current_price": mongodb.DecimalFromAmount(price)},
+		"$inc": incrementVersion(),
+	}
+
+	if _, err := ar.Collection.UpdateOne(ctx, filter, update); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to lower the price of auction %s", auctionId), err)
+		return internal_error.NewInternalServerError("Error trying to lower the auction price")
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/money"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDutchAuctionPriceDropsOnSchedule(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	fakeClock := fakeclock.New(time.Now())
+	ca := NewAuctionRepositoryWithClock(conn, fakeClock)
+	defer ca.Shutdown(ctx)
+
+	auction, _ := auction_entity.CreateAuction(
+		"guitar",
+		"music",
+		"acoustic guitar with case",
+		auction_entity.Used,
+		auction_entity.WithAuctionType(auction_entity.DutchAuction),
+		auction_entity.WithDuration(time.Hour),
+		auction_entity.WithDescendingPrice(10000, 6000, 1000, time.Minute))
+	assert.Nil(t, ca.CreateAuction(ctx, auction))
+
+	assertCurrentPriceEventually := func(price money.Amount) {
+		assert.Eventually(t, func() bool {
+			auctionDb, err := ca.FindAuctionByIdFromPrimary(ctx, auction.Id)
+			return err == nil && auctionDb.CurrentPrice == price
+		}, 5*time.Second, 20*time.Millisecond)
+	}
+
+	fakeClock.Advance(time.Minute)
+	assertCurrentPriceEventually(9000)
+
+	fakeClock.Advance(10 * time.Minute)
+	assertCurrentPriceEventually(6000)
+
+	restarted := NewAuctionRepositoryWithClock(conn, fakeClock)
+	defer restarted.Shutdown(ctx)
+	assert.Nil(t, restarted.StartAutoCloseRecovery(ctx))
+	assert.False(t, restarted.PriceScheduler.Has(auction.Id))
+}
@@ -0,0 +1,78 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (ar *AuctionRepository) EnsureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "timestamp", Value: 1}},
+			Options: options.Index().SetName("status_timestamp"),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "end_time", Value: 1}},
+			Options: options.Index().SetName("status_end_time"),
+		},
+		{
+			Keys:    bson.D{{Key: "timestamp", Value: 1}},
+			Options: options.Index().SetName("timestamp"),
+		},
+		{
+			Keys:    bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("timestamp_desc_id_desc"),
+		},
+		{
+			Keys:    bson.D{{Key: "product_name", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().SetName("product_name_description_text"),
+		},
+		{
+			Keys:    bson.D{{Key: "seller_id", Value: 1}, {Key: "timestamp", Value: -1}},
+			Options: options.Index().SetName("seller_id_timestamp_desc"),
+		},
+		{
+			Keys: bson.D{{Key: "idempotency_key", Value: 1}},
+			Options: options.Index().
+				SetName("idempotency_key_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"idempotency_key": bson.M{"$exists": true}}),
+		},
+	}
+
+	if _, err := ar.Collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		logger.ErrorContext(ctx, "Error trying to create auction indexes", err)
+		return err
+	}
+
+	watchlistIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "auction_id", Value: 1}},
+			Options: options.Index().SetName("user_id_auction_id_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "auction_id", Value: 1}, {Key: "ending_notified_at", Value: 1}},
+			Options: options.Index().SetName("auction_id_ending_notified_at"),
+		},
+	}
+
+	if _, err := ar.WatchlistCollection.Indexes().CreateMany(ctx, watchlistIndexes); err != nil {
+		logger.ErrorContext(ctx, "Error trying to create watchlist indexes", err)
+		return err
+	}
+
+	if _, err := ar.EventsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "timestamp", Value: 1}},
+		Options: options.Index().
+			SetName("unpublished_timestamp").
+			SetPartialFilterExpression(bson.M{"published": false}),
+	}); err != nil {
+		logger.ErrorContext(ctx, "Error trying to create auction event indexes", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+var extendableStatuses = []auction_entity.AuctionStatus{auction_entity.Active, auction_entity.Scheduled}
+
+func (ar *AuctionRepository) ExtendAuction(
+	ctx context.Context,
+	auctionEntity auction_entity.Auction,
+	extra time.Duration) (*auction_entity.Auction, *internal_error.InternalError) {
+	if !statusIn(auctionEntity.Status, extendableStatuses) {
+		return nil, internal_error.NewConflictError("Auction is already finished and cannot be extended")
+	}
+
+	newEndTime := auctionEntity.EndTime.Add(extra)
+	filter := bson.M{
+		"_id":     auctionEntity.Id,
+		"status":  auctionEntity.Status,
+		"version": versionFilter(auctionEntity.Version),
+	}
+	update := bson.M{
+		"$set": bson.M{"end_time": newEndTime.Unix()},
+		"$inc": bson.M{"extension_seconds": int64(extra / time.Second), "version": 1},
+	}
+
+	var auctionEntityMongo AuctionEntityMongo
+	if err := ar.Collection.FindOneAndUpdate(
+		ctx, filter, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&auctionEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ar.explainUpdateMiss(ctx, auctionEntity.Id, auctionEntity.Version, extendableStatuses,
+				"Auction changed state and cannot be extended", "extend")
+		}
+
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to extend auction %s", auctionEntity.Id), err)
+		return nil, internal_error.NewInternalServerError("Error trying to extend auction")
+	}
+
+	extendedAuction := auctionEntityMongo.toEntity()
+	ar.rescheduleClose(extendedAuction.Id, extendedAuction.EndTime)
+
+	logger.InfoContext(ctx, fmt.Sprintf("Auction %s extended until %s", extendedAuction.Id, extendedAuction.EndTime))
+
+	return &extendedAuction, nil
+}
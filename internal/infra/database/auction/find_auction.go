@@ -2,43 +2,104 @@ package auction
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"fullcycle-auction_go/configuration/logger"
 	"fullcycle-auction_go/internal/entity/auction_entity"
 	"fullcycle-auction_go/internal/internal_error"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"time"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func (ar *AuctionRepository) FindAuctionById(
 	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
-	filter := bson.M{"_id": id}
+	return ar.findAuctionById(ctx, ar.queryCollection, id)
+}
+
+func (ar *AuctionRepository) FindAuctionByIdFromPrimary(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	return ar.findAuctionById(ctx, ar.Collection, id)
+}
+
+func (ar *AuctionRepository) findAuctionById(
+	ctx context.Context,
+	collection *mongo.Collection,
+	id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	filter := bson.M{"_id": id, "deleted_at": bson.M{"$exists": false}}
 
 	var auctionEntityMongo AuctionEntityMongo
-	if err := ar.Collection.FindOne(ctx, filter).Decode(&auctionEntityMongo); err != nil {
-		logger.Error(fmt.Sprintf("Error trying to find auction by id = %s", id), err)
+	if err := collection.FindOne(ctx, filter).Decode(&auctionEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Auction not found with this id = %s", id))
+		}
+
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to find auction by id = %s", id), err)
 		return nil, internal_error.NewInternalServerError("Error trying to find auction by id")
 	}
 
-	return &auction_entity.Auction{
-		Id:          auctionEntityMongo.Id,
-		ProductName: auctionEntityMongo.ProductName,
-		Category:    auctionEntityMongo.Category,
-		Description: auctionEntityMongo.Description,
-		Condition:   auctionEntityMongo.Condition,
-		Status:      auctionEntityMongo.Status,
-		Timestamp:   time.Unix(auctionEntityMongo.Timestamp, 0),
-	}, nil
+	auctionEntity := auctionEntityMongo.toEntity()
+	return &auctionEntity, nil
 }
 
-func (repo *AuctionRepository) FindAuctions(
-	ctx context.Context,
+// GetAuctionSeller reads only the seller of an auction. The seller never
+// changes, so the query collection is good enough here.
+func (ar *AuctionRepository) GetAuctionSeller(
+	ctx context.Context, auctionId string) (string, *internal_error.InternalError) {
+	filter := bson.M{"_id": auctionId, "deleted_at": bson.M{"$exists": false}}
+	opts := options.FindOne().SetProjection(bson.M{"seller_id": 1})
+
+	var auctionEntityMongo AuctionEntityMongo
+	if err := ar.queryCollection.FindOne(ctx, filter, opts).Decode(&auctionEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return "", internal_error.NewNotFoundError(
+				fmt.Sprintf("Auction not found with this id = %s", auctionId))
+		}
+
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to find seller of auction %s", auctionId), err)
+		return "", internal_error.NewInternalServerError("Error trying to find auction seller")
+	}
+
+	return auctionEntityMongo.SellerId, nil
+}
+
+const (
+	SortCreatedDesc = "created_desc"
+	SortCreatedAsc  = "created_asc"
+	SortEndingAsc   = "ending_asc"
+	SortNameAsc     = "name_asc"
+	SortNameDesc    = "name_desc"
+	SortRelevance   = "relevance"
+)
+
+var auctionSortSpecs = map[string]bson.D{
+	SortCreatedDesc: {{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}},
+	SortCreatedAsc:  {{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}},
+	SortEndingAsc:   {{Key: "end_time", Value: 1}, {Key: "_id", Value: 1}},
+	SortNameAsc:     {{Key: "product_name", Value: 1}, {Key: "_id", Value: 1}},
+	SortNameDesc:    {{Key: "product_name", Value: -1}, {Key: "_id", Value: -1}},
+	SortRelevance:   {{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "_id", Value: 1}},
+}
+
+type findAuctionsQuery struct {
+	filter   bson.M
+	sortKey  string
+	sortSpec bson.D
+}
+
+func buildFindAuctionsQuery(
 	status auction_entity.AuctionStatus,
 	category string,
-	productName string) ([]auction_entity.Auction, *internal_error.InternalError) {
+	productName string,
+	findOptions auction_entity.FindAuctionsOptions) (*findAuctionsQuery, *internal_error.InternalError) {
 	filter := bson.M{}
 
+	if !findOptions.IncludeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+
 	if status != 0 {
 		filter["status"] = status
 	}
@@ -51,30 +112,190 @@ func (repo *AuctionRepository) FindAuctions(
 		filter["productName"] = primitive.Regex{Pattern: productName, Options: "i"}
 	}
 
-	cursor, err := repo.Collection.Find(ctx, filter)
+	if findOptions.Query != "" {
+		filter["$text"] = bson.M{"$search": findOptions.Query}
+	}
+
+	var conditions bson.A
+	if !findOptions.CreatedFrom.IsZero() {
+		conditions = append(conditions, bson.M{"timestamp": bson.M{"$gte": findOptions.CreatedFrom.Unix()}})
+	}
+	if !findOptions.CreatedTo.IsZero() {
+		conditions = append(conditions, bson.M{"timestamp": bson.M{"$lte": findOptions.CreatedTo.Unix()}})
+	}
+	if len(conditions) > 0 {
+		filter["$and"] = conditions
+	}
+
+	sortKey := findOptions.Sort
+	if sortKey == "" {
+		sortKey = SortCreatedDesc
+		if findOptions.Query != "" && !findOptions.UseCursor {
+			sortKey = SortRelevance
+		}
+	}
+
+	sortSpec, ok := auctionSortSpecs[sortKey]
+	if !ok {
+		return nil, internal_error.NewBadRequestError(fmt.Sprintf("sort %s is not supported", sortKey))
+	}
+
+	if sortKey == SortRelevance && findOptions.Query == "" {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("sort %s requires a search query", SortRelevance))
+	}
+
+	if findOptions.UseCursor && sortKey != SortCreatedDesc {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("cursor pagination only supports sort %s", SortCreatedDesc))
+	}
+
+	if sortKey == SortEndingAsc && status == 0 {
+		filter["status"] = bson.M{"$ne": auction_entity.Completed}
+	}
+
+	return &findAuctionsQuery{
+		filter:   filter,
+		sortKey:  sortKey,
+		sortSpec: sortSpec,
+	}, nil
+}
+
+func (query *findAuctionsQuery) applyCursor(cursor string) *internal_error.InternalError {
+	if cursor == "" {
+		return nil
+	}
+
+	lastSeen, err := decodeAuctionCursor(cursor)
 	if err != nil {
-		logger.Error("Error finding auctions", err)
+		return internal_error.NewBadRequestError("cursor is invalid")
+	}
+
+	cursorCondition := bson.M{"$or": bson.A{
+		bson.M{"timestamp": bson.M{"$lt": lastSeen.Timestamp}},
+		bson.M{"timestamp": lastSeen.Timestamp, "_id": bson.M{"$lt": lastSeen.Id}},
+	}}
+
+	conditions, _ := query.filter["$and"].(bson.A)
+	query.filter["$and"] = append(conditions, cursorCondition)
+
+	return nil
+}
+
+func (repo *AuctionRepository) FindAuctions(
+	ctx context.Context,
+	status auction_entity.AuctionStatus,
+	category string,
+	productName string,
+	findOptions auction_entity.FindAuctionsOptions) (*auction_entity.AuctionPage, *internal_error.InternalError) {
+	query, queryErr := buildFindAuctionsQuery(status, category, productName, findOptions)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+
+	total, err := repo.queryCollection.CountDocuments(ctx, query.filter)
+	if err != nil {
+		logger.ErrorContext(ctx, "Error counting auctions", err)
+		return nil, internal_error.NewInternalServerError("Error finding auctions")
+	}
+
+	if queryErr := query.applyCursor(findOptions.Cursor); queryErr != nil {
+		return nil, queryErr
+	}
+
+	opts := options.Find().SetLimit(int64(findOptions.PageSize)).SetSort(query.sortSpec)
+	if query.sortKey == SortRelevance {
+		opts.SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}})
+	}
+	if !findOptions.UseCursor {
+		opts.SetSkip(int64((findOptions.Page - 1) * findOptions.PageSize))
+	}
+
+	cursor, err := repo.queryCollection.Find(ctx, query.filter, opts)
+	if err != nil {
+		logger.ErrorContext(ctx, "Error finding auctions", err)
+		return nil, internal_error.NewInternalServerError("Error finding auctions")
+	}
+	defer cursor.Close(ctx)
+
+	var auctionsMongo []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctionsMongo); err != nil {
+		logger.ErrorContext(ctx, "Error decoding auctions", err)
+		return nil, internal_error.NewInternalServerError("Error decoding auctions")
+	}
+
+	var auctionsEntity []auction_entity.Auction
+	for _, auction := range auctionsMongo {
+		auctionsEntity = append(auctionsEntity, auction.toEntity())
+	}
+
+	auctionPage := &auction_entity.AuctionPage{
+		Auctions: auctionsEntity,
+		Total:    total,
+	}
+
+	if findOptions.UseCursor && len(auctionsMongo) == findOptions.PageSize {
+		last := auctionsMongo[len(auctionsMongo)-1]
+		auctionPage.NextCursor = encodeAuctionCursor(last.Timestamp, last.Id)
+	}
+
+	return auctionPage, nil
+}
+
+func (repo *AuctionRepository) FindAuctionsBySeller(
+	ctx context.Context,
+	sellerId string,
+	statusFilter []auction_entity.AuctionStatus) ([]auction_entity.Auction, *internal_error.InternalError) {
+	filter := bson.M{"seller_id": sellerId, "deleted_at": bson.M{"$exists": false}}
+	if len(statusFilter) > 0 {
+		filter["status"] = bson.M{"$in": statusFilter}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+	cursor, err := repo.queryCollection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error finding auctions of seller %s", sellerId), err)
 		return nil, internal_error.NewInternalServerError("Error finding auctions")
 	}
 	defer cursor.Close(ctx)
 
 	var auctionsMongo []AuctionEntityMongo
 	if err := cursor.All(ctx, &auctionsMongo); err != nil {
-		logger.Error("Error decoding auctions", err)
+		logger.ErrorContext(ctx, "Error decoding auctions", err)
 		return nil, internal_error.NewInternalServerError("Error decoding auctions")
 	}
 
 	var auctionsEntity []auction_entity.Auction
 	for _, auction := range auctionsMongo {
-		auctionsEntity = append(auctionsEntity, auction_entity.Auction{
-			Id:          auction.Id,
-			ProductName: auction.ProductName,
-			Category:    auction.Category,
-			Status:      auction.Status,
-			Description: auction.Description,
-			Condition:   auction.Condition,
-			Timestamp:   time.Unix(auction.Timestamp, 0),
-		})
+		auctionsEntity = append(auctionsEntity, auction.toEntity())
+	}
+
+	return auctionsEntity, nil
+}
+
+func (repo *AuctionRepository) FindAuctionsByIds(
+	ctx context.Context, auctionIds []string) ([]auction_entity.Auction, *internal_error.InternalError) {
+	if len(auctionIds) == 0 {
+		return nil, nil
+	}
+
+	filter := bson.M{"_id": bson.M{"$in": auctionIds}, "deleted_at": bson.M{"$exists": false}}
+	cursor, err := repo.queryCollection.Find(ctx, filter)
+	if err != nil {
+		logger.ErrorContext(ctx, "Error finding auctions by ids", err)
+		return nil, internal_error.NewInternalServerError("Error finding auctions")
+	}
+	defer cursor.Close(ctx)
+
+	var auctionsMongo []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctionsMongo); err != nil {
+		logger.ErrorContext(ctx, "Error decoding auctions", err)
+		return nil, internal_error.NewInternalServerError("Error decoding auctions")
+	}
+
+	var auctionsEntity []auction_entity.Auction
+	for _, auction := range auctionsMongo {
+		auctionsEntity = append(auctionsEntity, auction.toEntity())
 	}
 
 	return auctionsEntity, nil
@@ -82,11 +303,22 @@ func (repo *AuctionRepository) FindAuctions(
 
 func (repo *AuctionRepository) FindOpenAuctions(
 	ctx context.Context) ([]auction_entity.Auction, *internal_error.InternalError) {
-	filter := bson.M{"status": auction_entity.Active}
+	return repo.findAuctionsByStatus(ctx, auction_entity.Active)
+}
+
+func (repo *AuctionRepository) FindScheduledAuctions(
+	ctx context.Context) ([]auction_entity.Auction, *internal_error.InternalError) {
+	return repo.findAuctionsByStatus(ctx, auction_entity.Scheduled)
+}
+
+func (repo *AuctionRepository) findAuctionsByStatus(
+	ctx context.Context,
+	status auction_entity.AuctionStatus) ([]auction_entity.Auction, *internal_error.InternalError) {
+	filter := bson.M{"status": status, "deleted_at": bson.M{"$exists": false}}
 
 	cursor, err := repo.Collection.Find(ctx, filter)
 	if err != nil {
-		logger.Error("Error finding auctions", err)
+		logger.ErrorContext(ctx, "Error finding auctions", err)
 		return nil, internal_error.NewInternalServerError("Error finding auctions")
 	}
 	defer cursor.Close(ctx)
@@ -94,22 +326,14 @@ func (repo *AuctionRepository) FindOpenAuctions(
 	var auctionsMongo []AuctionEntityMongo
 
 	if err := cursor.All(ctx, &auctionsMongo); err != nil {
-		logger.Error("Error decoding auctions", err)
+		logger.ErrorContext(ctx, "Error decoding auctions", err)
 		return nil, internal_error.NewInternalServerError("Error decoding auctions")
 	}
 
 	var auctionsEntity []auction_entity.Auction
 
 	for _, auction := range auctionsMongo {
-		auctionsEntity = append(auctionsEntity, auction_entity.Auction{
-			Id:          auction.Id,
-			ProductName: auction.ProductName,
-			Category:    auction.Category,
-			Status:      auction.Status,
-			Description: auction.Description,
-			Condition:   auction.Condition,
-			Timestamp:   time.Unix(auction.Timestamp, 0),
-		})
+		auctionsEntity = append(auctionsEntity, auction.toEntity())
 	}
 
 	return auctionsEntity, nil
@@ -0,0 +1,151 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type AuctionSummaryMongo struct {
+	Id            string                       `bson:"_id"`
+	ProductName   string                       `bson:"product_name"`
+	Category      string                       `bson:"category"`
+	Status        auction_entity.AuctionStatus `bson:"status"`
+	Type          auction_entity.AuctionType   `bson:"auction_type,omitempty"`
+	Timestamp     int64                        `bson:"timestamp"`
+	StartTime     int64                        `bson:"start_time,omitempty"`
+	EndTime       int64                        `bson:"end_time,omitempty"`
+	Duration      int64                        `bson:"duration_seconds,omitempty"`
+	StartingPrice primitive.Decimal128         `bson:"starting_price,omitempty"`
+	HighestBid    *primitive.Decimal128        `bson:"highest_bid,omitempty"`
+	BidCount      int64                        `bson:"bid_count,omitempty"`
+}
+
+func (repo *AuctionRepository) FindAuctionSummaries(
+	ctx context.Context,
+	status auction_entity.AuctionStatus,
+	category string,
+	productName string,
+	findOptions auction_entity.FindAuctionsOptions) (*auction_entity.AuctionSummaryPage, *internal_error.InternalError) {
+	query, queryErr := buildFindAuctionsQuery(status, category, productName, findOptions)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+
+	total, err := repo.queryCollection.CountDocuments(ctx, query.filter)
+	if err != nil {
+		logger.ErrorContext(ctx, "Error counting auctions", err)
+		return nil, internal_error.NewInternalServerError("Error finding auctions")
+	}
+
+	if queryErr := query.applyCursor(findOptions.Cursor); queryErr != nil {
+		return nil, queryErr
+	}
+
+	pipeline := []bson.M{
+		{"$match": query.filter},
+		{"$sort": query.sortSpec},
+	}
+	if !findOptions.UseCursor {
+		pipeline = append(pipeline, bson.M{"$skip": (findOptions.Page - 1) * findOptions.PageSize})
+	}
+	pipeline = append(pipeline, bson.M{"$limit": findOptions.PageSize})
+	pipeline = append(pipeline, summaryStages()...)
+
+	summariesMongo, summaryErr := repo.aggregateSummaries(ctx, pipeline)
+	if summaryErr != nil {
+		return nil, summaryErr
+	}
+
+	var summaries []auction_entity.AuctionSummary
+	for _, summaryMongo := range summariesMongo {
+		summaries = append(summaries, summaryMongo.toEntity())
+	}
+
+	summaryPage := &auction_entity.AuctionSummaryPage{
+		Summaries: summaries,
+		Total:     total,
+	}
+
+	if findOptions.UseCursor && len(summariesMongo) == findOptions.PageSize {
+		last := summariesMongo[len(summariesMongo)-1]
+		summaryPage.NextCursor = encodeAuctionCursor(last.Timestamp, last.Id)
+	}
+
+	return summaryPage, nil
+}
+
+// summaryStages projects matched auctions down to the fields of a summary. The
+// highest bid is the one cached on the auction when the bid was accepted, so
+// bids still waiting in the batch are included.
+func summaryStages() []bson.M {
+	return []bson.M{
+		{"$project": bson.M{
+			"product_name":     1,
+			"category":         1,
+			"status":           1,
+			"auction_type":     1,
+			"timestamp":        1,
+			"start_time":       1,
+			"end_time":         1,
+			"duration_seconds": 1,
+			"bid_count":        1,
+			"starting_price":   1,
+			"highest_bid":      "$current_highest_amount",
+		}},
+	}
+}
+
+func (repo *AuctionRepository) aggregateSummaries(
+	ctx context.Context, pipeline []bson.M) ([]AuctionSummaryMongo, *internal_error.InternalError) {
+	cursor, err := repo.queryCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.ErrorContext(ctx, "Error finding auction summaries", err)
+		return nil, internal_error.NewInternalServerError("Error finding auctions")
+	}
+	defer cursor.Close(ctx)
+
+	var summariesMongo []AuctionSummaryMongo
+	if err := cursor.All(ctx, &summariesMongo); err != nil {
+		logger.ErrorContext(ctx, "Error decoding auction summaries", err)
+		return nil, internal_error.NewInternalServerError("Error decoding auctions")
+	}
+
+	return summariesMongo, nil
+}
+
+func (sm *AuctionSummaryMongo) toEntity() auction_entity.AuctionSummary {
+	auctionEntity := auction_entity.Auction{
+		Timestamp: time.Unix(sm.Timestamp, 0),
+		Duration:  time.Duration(sm.Duration) * time.Second,
+	}
+	if sm.StartTime != 0 {
+		auctionEntity.StartTime = time.Unix(sm.StartTime, 0)
+	}
+	if sm.EndTime != 0 {
+		auctionEntity.EndTime = time.Unix(sm.EndTime, 0)
+	}
+
+	summary := auction_entity.AuctionSummary{
+		Id:          sm.Id,
+		ProductName: sm.ProductName,
+		Category:    sm.Category,
+		Status:      sm.Status,
+		Type:        auctionTypeOrOpen(sm.Type),
+		EndTime:     calculateAuctionEndTime(auctionEntity),
+		BidCount:    sm.BidCount,
+
+		StartingPrice: mongodb.AmountFromDecimal(sm.StartingPrice),
+	}
+	if sm.HighestBid != nil {
+		highestBid := mongodb.AmountFromDecimal(*sm.HighestBid)
+		summary.HighestBid = &highestBid
+	}
+
+	return summary
+}
@@ -0,0 +1,88 @@
+package auction
+
+import (
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"testing"
+	"time"
+)
+
+func TestFindAuctionsQueryBuildsFilters(t *testing.T) {
+	createdFrom := time.Unix(1704067200, 0)
+	query, err := buildFindAuctionsQuery(auction_entity.Completed, "art", "", auction_entity.FindAuctionsOptions{
+		Query:       "oil painting",
+		CreatedFrom: createdFrom,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Completed, query.filter["status"])
+	assert.Equal(t, "art", query.filter["category"])
+	assert.Equal(t, bson.M{"$search": "oil painting"}, query.filter["$text"])
+	assert.Equal(t, bson.M{"$exists": false}, query.filter["deleted_at"])
+	assert.Equal(t, bson.A{bson.M{"timestamp": bson.M{"$gte": createdFrom.Unix()}}}, query.filter["$and"])
+	assert.Equal(t, SortRelevance, query.sortKey)
+
+	query, err = buildFindAuctionsQuery(0, "", "", auction_entity.FindAuctionsOptions{IncludeDeleted: true})
+	assert.Nil(t, err)
+	assert.NotContains(t, query.filter, "deleted_at")
+	assert.Equal(t, SortCreatedDesc, query.sortKey)
+	assert.Equal(t, auctionSortSpecs[SortCreatedDesc], query.sortSpec)
+
+	query, err = buildFindAuctionsQuery(0, "", "", auction_entity.FindAuctionsOptions{Sort: SortEndingAsc})
+	assert.Nil(t, err)
+	assert.Equal(t, bson.M{"$ne": auction_entity.Completed}, query.filter["status"])
+}
+
+func TestFindAuctionsQueryRejectsUnsupportedSorts(t *testing.T) {
+	tests := []struct {
+		name        string
+		findOptions auction_entity.FindAuctionsOptions
+	}{
+		{name: "unknown sort", findOptions: auction_entity.FindAuctionsOptions{Sort: "price_asc"}},
+		{name: "relevance without query", findOptions: auction_entity.FindAuctionsOptions{Sort: SortRelevance}},
+		{
+			name:        "cursor with another sort",
+			findOptions: auction_entity.FindAuctionsOptions{UseCursor: true, Sort: SortNameAsc},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildFindAuctionsQuery(0, "", "", tt.findOptions)
+			assert.NotNil(t, err)
+			assert.Equal(t, internal_error.ErrBadRequest, err.Err)
+		})
+	}
+}
+
+func TestAuctionCursorRoundTripsAndNarrowsTheFilter(t *testing.T) {
+	cursor := encodeAuctionCursor(1704067200, "auction")
+	lastSeen, err := decodeAuctionCursor(cursor)
+	assert.Nil(t, err)
+	assert.Equal(t, auctionCursor{Timestamp: 1704067200, Id: "auction"}, lastSeen)
+
+	query, queryErr := buildFindAuctionsQuery(0, "", "", auction_entity.FindAuctionsOptions{
+		UseCursor: true, CreatedTo: time.Unix(1704153600, 0),
+	})
+	assert.Nil(t, queryErr)
+	assert.Nil(t, query.applyCursor(cursor))
+	assert.Equal(t, bson.A{
+		bson.M{"timestamp": bson.M{"$lte": int64(1704153600)}},
+		bson.M{"$or": bson.A{
+			bson.M{"timestamp": bson.M{"$lt": int64(1704067200)}},
+			bson.M{"timestamp": int64(1704067200), "_id": bson.M{"$lt": "auction"}},
+		}},
+	}, query.filter["$and"])
+}
+
+func TestInvalidAuctionCursorsAreBadRequests(t *testing.T) {
+	for _, cursor := range []string{"not base64!", "bm90IGpzb24", encodeAuctionCursor(1704067200, "")} {
+		query, err := buildFindAuctionsQuery(0, "", "", auction_entity.FindAuctionsOptions{UseCursor: true})
+		assert.Nil(t, err)
+
+		cursorErr := query.applyCursor(cursor)
+		assert.NotNil(t, cursorErr, cursor)
+		assert.Equal(t, internal_error.ErrBadRequest, cursorErr.Err)
+	}
+}
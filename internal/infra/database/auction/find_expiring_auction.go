@@ -0,0 +1,50 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+func (repo *AuctionRepository) FindAuctionsExpiringSoon(
+	ctx context.Context,
+	within time.Duration,
+	limit int) ([]auction_entity.Auction, *internal_error.InternalError) {
+	now := repo.Clock.Now()
+	filter := bson.M{
+		"status": auction_entity.Active,
+		"end_time": bson.M{
+			"$gt":  now.Unix(),
+			"$lte": now.Add(within).Unix(),
+		},
+		"deleted_at": bson.M{"$exists": false},
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "end_time", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := repo.queryCollection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.ErrorContext(ctx, "Error finding auctions expiring soon", err)
+		return nil, internal_error.NewInternalServerError("Error finding auctions")
+	}
+	defer cursor.Close(ctx)
+
+	var auctionsMongo []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctionsMongo); err != nil {
+		logger.ErrorContext(ctx, "Error decoding auctions", err)
+		return nil, internal_error.NewInternalServerError("Error decoding auctions")
+	}
+
+	var auctionsEntity []auction_entity.Auction
+	for _, auction := range auctionsMongo {
+		auctionsEntity = append(auctionsEntity, auction.toEntity())
+	}
+
+	return auctionsEntity, nil
+}
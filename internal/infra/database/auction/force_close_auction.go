@@ -0,0 +1,110 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+var forceClosableStatuses = []auction_entity.AuctionStatus{
+	auction_entity.Active, auction_entity.Scheduled, auction_entity.Paused}
+
+// ForceCloseAuction completes the auction whatever its state, unless it is
+// already finished, and stores the audit entry in the same transaction.
+func (ar *AuctionRepository) ForceCloseAuction(
+	ctx context.Context,
+	auctionId string,
+	expectedVersion int64,
+	auditEntry auction_entity.AuditEntry) (*auction_entity.Auction, *internal_error.InternalError) {
+	filter := bson.M{
+		"_id":     auctionId,
+		"status":  bson.M{"$in": forceClosableStatuses},
+		"version": versionFilter(expectedVersion),
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":       auction_entity.Completed,
+			"closed_at":    ar.Clock.Now().Unix(),
+			"close_reason": auction_entity.CloseReasonForced,
+		},
+		"$inc": incrementVersion(),
+	}
+
+	var closedAuctionMongo AuctionEntityMongo
+	err := mongodb.WithTransaction(ctx, ar.Collection.Database(), func(txCtx context.Context) error {
+		if err := ar.Collection.FindOneAndUpdate(
+			txCtx, filter, update,
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&closedAuctionMongo); err != nil {
+			return err
+		}
+
+		_, err := ar.AuditCollection.InsertOne(txCtx, AuctionAuditMongo{
+			Id:             auditEntry.Id,
+			AuctionId:      auditEntry.AuctionId,
+			Action:         auditEntry.Action,
+			ActorId:        auditEntry.ActorId,
+			Reason:         auditEntry.Reason,
+			PreviousStatus: auditEntry.PreviousStatus,
+			Timestamp:      auditEntry.Timestamp.Unix(),
+		})
+		return err
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// A concurrent force close leaves the auction completed with a new
+		// version, which is reported as a version conflict so the caller
+		// reloads it and finds the existing result.
+		return nil, ar.explainUpdateMiss(ctx, auctionId, expectedVersion,
+			append(forceClosableStatuses, auction_entity.Completed),
+			"Auction is already finished and cannot be force-closed", "force-close")
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to force-close auction %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to force-close auction")
+	}
+
+	ar.OpenScheduler.Remove(auctionId)
+	ar.Scheduler.Remove(auctionId)
+	ar.PriceScheduler.Remove(auctionId)
+
+	logger.InfoContext(ctx, fmt.Sprintf(
+		"Auction %s force-closed by %s: %s", auctionId, auditEntry.ActorId, auditEntry.Reason))
+
+	ar.recordCloseOutcome(ctx, &closedAuctionMongo)
+	ar.notifyAuctionClosed(closedAuctionMongo.toEntity())
+
+	closedAuction := closedAuctionMongo.toEntity()
+	return &closedAuction, nil
+}
+
+func (ar *AuctionRepository) FindAuditEntry(
+	ctx context.Context, auctionId, action string) (*auction_entity.AuditEntry, *internal_error.InternalError) {
+	var auctionAuditMongo AuctionAuditMongo
+	err := ar.AuditCollection.FindOne(ctx, bson.M{"auction_id": auctionId, "action": action}).Decode(&auctionAuditMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, internal_error.NewNotFoundError(
+			fmt.Sprintf("No %s audit entry for auction %s", action, auctionId))
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to find the audit entry of auction %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find audit entry")
+	}
+
+	return &auction_entity.AuditEntry{
+		Id:             auctionAuditMongo.Id,
+		AuctionId:      auctionAuditMongo.AuctionId,
+		Action:         auctionAuditMongo.Action,
+		ActorId:        auctionAuditMongo.ActorId,
+		Reason:         auctionAuditMongo.Reason,
+		PreviousStatus: auctionAuditMongo.PreviousStatus,
+		Timestamp:      time.Unix(auctionAuditMongo.Timestamp, 0),
+	}, nil
+}
@@ -0,0 +1,110 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/metrics"
+	"fullcycle-auction_go/internal/money"
+	"time"
+)
+
+var auctionsCreated = metrics.Default.NewCounter("auctions_created_total", "Auctions created.")
+
+// InstrumentedAuctionRepository records the latency of the calls on the
+// request path and counts created auctions. Every other method goes straight
+// to the wrapped repository.
+type InstrumentedAuctionRepository struct {
+	auction_entity.AuctionRepositoryInterface
+}
+
+func NewInstrumentedAuctionRepository(
+	repository auction_entity.AuctionRepositoryInterface) *InstrumentedAuctionRepository {
+	return &InstrumentedAuctionRepository{AuctionRepositoryInterface: repository}
+}
+
+// InstrumentAuctionRepository also exposes how many auto-close timers are
+// pending and how deep the close queue is, which only the concrete
+// repository knows.
+func InstrumentAuctionRepository(repository *AuctionRepository) *InstrumentedAuctionRepository {
+	metrics.Default.NewGaugeFunc("auction_auto_close_timers_pending", "Auctions waiting for their auto-close timer.",
+		func() float64 { return float64(repository.Scheduler.Len()) })
+	metrics.Default.NewGaugeFunc("auction_close_queue_depth", "Expired auctions waiting for a free close worker.",
+		func() float64 { return float64(repository.CloseQueueLength()) })
+
+	return NewInstrumentedAuctionRepository(repository)
+}
+
+func (ir *InstrumentedAuctionRepository) CreateAuction(
+	ctx context.Context, auctionEntity *auction_entity.Auction) *internal_error.InternalError {
+	defer mongodb.ObserveCall("auction", "CreateAuction", time.Now())
+
+	err := ir.AuctionRepositoryInterface.CreateAuction(ctx, auctionEntity)
+	if err == nil {
+		auctionsCreated.Inc()
+	}
+	return err
+}
+
+func (ir *InstrumentedAuctionRepository) CreateAuctions(
+	ctx context.Context, auctionEntities []*auction_entity.Auction) *internal_error.InternalError {
+	defer mongodb.ObserveCall("auction", "CreateAuctions", time.Now())
+
+	err := ir.AuctionRepositoryInterface.CreateAuctions(ctx, auctionEntities)
+	if err == nil {
+		auctionsCreated.Add(int64(len(auctionEntities)))
+	}
+	return err
+}
+
+func (ir *InstrumentedAuctionRepository) FindAuctions(
+	ctx context.Context,
+	status auction_entity.AuctionStatus,
+	category, productName string,
+	options auction_entity.FindAuctionsOptions) (*auction_entity.AuctionPage, *internal_error.InternalError) {
+	defer mongodb.ObserveCall("auction", "FindAuctions", time.Now())
+	return ir.AuctionRepositoryInterface.FindAuctions(ctx, status, category, productName, options)
+}
+
+func (ir *InstrumentedAuctionRepository) FindAuctionById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	defer mongodb.ObserveCall("auction", "FindAuctionById", time.Now())
+	return ir.AuctionRepositoryInterface.FindAuctionById(ctx, id)
+}
+
+func (ir *InstrumentedAuctionRepository) FindAuctionByIdFromPrimary(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	defer mongodb.ObserveCall("auction", "FindAuctionByIdFromPrimary", time.Now())
+	return ir.AuctionRepositoryInterface.FindAuctionByIdFromPrimary(ctx, id)
+}
+
+func (ir *InstrumentedAuctionRepository) PlaceHighestBid(
+	ctx context.Context,
+	auctionId, userId string,
+	amount, minIncrement money.Amount,
+	snipeExtension auction_entity.SnipeExtension) (auction_entity.PlacedBid, *internal_error.InternalError) {
+	defer mongodb.ObserveCall("auction", "PlaceHighestBid", time.Now())
+	return ir.AuctionRepositoryInterface.PlaceHighestBid(ctx, auctionId, userId, amount, minIncrement, snipeExtension)
+}
+
+func (ir *InstrumentedAuctionRepository) CloseAuctionById(
+	ctx context.Context, auctionId string, expectedVersion int64) *internal_error.InternalError {
+	defer mongodb.ObserveCall("auction", "CloseAuctionById", time.Now())
+	return ir.AuctionRepositoryInterface.CloseAuctionById(ctx, auctionId, expectedVersion)
+}
+
+func (ir *InstrumentedAuctionRepository) CancelAuction(
+	ctx context.Context, auctionId, reason string, expectedVersion int64) *internal_error.InternalError {
+	defer mongodb.ObserveCall("auction", "CancelAuction", time.Now())
+	return ir.AuctionRepositoryInterface.CancelAuction(ctx, auctionId, reason, expectedVersion)
+}
+
+func (ir *InstrumentedAuctionRepository) ForceCloseAuction(
+	ctx context.Context,
+	auctionId string,
+	expectedVersion int64,
+	auditEntry auction_entity.AuditEntry) (*auction_entity.Auction, *internal_error.InternalError) {
+	defer mongodb.ObserveCall("auction", "ForceCloseAuction", time.Now())
+	return ir.AuctionRepositoryInterface.ForceCloseAuction(ctx, auctionId, expectedVersion, auditEntry)
+}
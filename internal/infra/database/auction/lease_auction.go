@@ -0,0 +1,160 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/clock"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	autoCloseLeaseId = "auction_auto_close"
+	defaultLeaseTTL  = 15 * time.Second
+)
+
+type AuctionCloseLease struct {
+	Collection *mongo.Collection
+	InstanceId string
+	clock      clock.Clock
+	ttl        time.Duration
+	leader     *atomic.Bool
+}
+
+type auctionLeaseMongo struct {
+	Id        string `bson:"_id"`
+	Holder    string `bson:"holder"`
+	ExpiresAt int64  `bson:"expires_at"`
+}
+
+func NewAuctionCloseLease(
+	database *mongo.Database, leaseClock clock.Clock, ttl time.Duration) *AuctionCloseLease {
+	return &AuctionCloseLease{
+		Collection: database.Collection("auction_locks"),
+		InstanceId: uuid.New().String(),
+		clock:      leaseClock,
+		ttl:        ttl,
+		leader:     &atomic.Bool{},
+	}
+}
+
+func (l *AuctionCloseLease) IsLeader() bool {
+	return l.leader.Load()
+}
+
+func (l *AuctionCloseLease) TryAcquire(ctx context.Context) bool {
+	now := l.clock.Now()
+	filter := bson.M{
+		"_id": autoCloseLeaseId,
+		"$or": bson.A{
+			bson.M{"holder": l.InstanceId},
+			bson.M{"expires_at": bson.M{"$lt": now.Unix()}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"holder":     l.InstanceId,
+		"expires_at": now.Add(l.ttl).Unix(),
+	}}
+
+	var lease auctionLeaseMongo
+	err := l.Collection.FindOneAndUpdate(
+		ctx, filter, update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&lease)
+
+	acquired := err == nil && lease.Holder == l.InstanceId
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		logger.ErrorContext(ctx, "Error trying to acquire auto-close lease", err)
+	}
+
+	l.leader.Store(acquired)
+	return acquired
+}
+
+func (l *AuctionCloseLease) Release(ctx context.Context) {
+	if !l.leader.Swap(false) {
+		return
+	}
+
+	if _, err := l.Collection.DeleteOne(
+		ctx, bson.M{"_id": autoCloseLeaseId, "holder": l.InstanceId}); err != nil {
+		logger.ErrorContext(ctx, "Error trying to release auto-close lease", err)
+	}
+}
+
+func (ar *AuctionRepository) IsCloseLeader() bool {
+	if ar.Lease == nil {
+		return true
+	}
+
+	return ar.Lease.IsLeader()
+}
+
+func (ar *AuctionRepository) runLeaseHeartbeat() {
+	ticker := ar.Clock.NewTicker(ar.Lease.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		wasLeader := ar.Lease.IsLeader()
+		isLeader := ar.Lease.TryAcquire(ar.ctx)
+
+		if isLeader && !wasLeader {
+			logger.Info(fmt.Sprintf("Instance %s acquired the auto-close lease", ar.Lease.InstanceId))
+			if err := ar.StartAutoCloseRecovery(ar.ctx); err != nil {
+				logger.Error("Error trying to recover auto-closes after acquiring the lease", err)
+			}
+		} else if isLeader {
+			if err := ar.scheduleUpcomingAuctions(ar.ctx); err != nil {
+				logger.Error("Error trying to schedule upcoming auctions after renewing the lease", err)
+			}
+		} else if wasLeader {
+			logger.Info(fmt.Sprintf("Instance %s lost the auto-close lease", ar.Lease.InstanceId))
+		}
+
+		select {
+		case <-ar.ctx.Done():
+			ar.Lease.Release(context.Background())
+			return
+		case <-ticker.C():
+		}
+	}
+}
+
+// scheduleUpcomingAuctions schedules the auctions that open or close before
+// the lease is renewed again. Followers ignore their own timers, so without it
+// the leader would only close auctions created on a follower in the sweep.
+func (ar *AuctionRepository) scheduleUpcomingAuctions(ctx context.Context) *internal_error.InternalError {
+	if ar.CloseStrategy == CloseStrategySweep {
+		return nil
+	}
+
+	horizon := ar.Clock.Now().Add(ar.Lease.ttl).Unix()
+	filter := bson.M{
+		"deleted_at": bson.M{"$exists": false},
+		"$or": bson.A{
+			bson.M{"status": auction_entity.Active, "end_time": bson.M{"$lte": horizon}},
+			bson.M{"status": auction_entity.Scheduled, "start_time": bson.M{"$lte": horizon}},
+		},
+	}
+
+	return ar.forEachAuctionMatching(ctx, filter, func(auctionEntity auction_entity.Auction) error {
+		ar.scheduleAuction(auctionEntity)
+		return nil
+	})
+}
+
+// WithLeaderElection lets only the instance holding the close lease close
+// auctions, renewing it for leaseTTL at a time.
+func WithLeaderElection(leaseTTL time.Duration) AuctionRepositoryOption {
+	return func(repository *AuctionRepository) {
+		repository.leaderElection = true
+		repository.leaseTTL = leaseTTL
+	}
+}
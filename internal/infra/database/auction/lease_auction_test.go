@@ -0,0 +1,64 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAuctionAutoCloseRunsOnceAcrossInstances(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+	fakeClock := fakeclock.New(time.Now())
+
+	conn.Collection("auction_locks").DeleteMany(ctx, bson.M{})
+
+	first := NewAuctionRepositoryWithClock(conn, fakeClock, WithLeaderElection(30*time.Second))
+	defer first.Shutdown(ctx)
+	second := NewAuctionRepositoryWithClock(conn, fakeClock, WithLeaderElection(30*time.Second))
+	defer second.Shutdown(ctx)
+
+	assert.Eventually(t, func() bool {
+		return first.IsCloseLeader() != second.IsCloseLeader()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	leader, follower := first, second
+	if second.IsCloseLeader() {
+		leader, follower = second, first
+	}
+
+	auction, _ := auction_entity.CreateAuction(
+		"monitor",
+		"peripherals",
+		"ultrawide monitor",
+		auction_entity.New,
+		auction_entity.WithDuration(20*time.Second))
+
+	var closes int32
+	listener := func(ctx context.Context, closed auction_entity.Auction) {
+		if closed.Id == auction.Id {
+			atomic.AddInt32(&closes, 1)
+		}
+	}
+	first.RegisterCloseListener(listener)
+	second.RegisterCloseListener(listener)
+
+	assert.Nil(t, follower.CreateAuction(ctx, auction))
+
+	fakeClock.Advance(10 * time.Second)
+	assert.Eventually(t, func() bool {
+		return leader.Scheduler.Has(auction.Id)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	fakeClock.Advance(11 * time.Second)
+	assertAuctionStatusEventually(t, leader, auction.Id, auction_entity.Completed)
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&closes) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&closes))
+}
@@ -0,0 +1,25 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/configuration/logger"
+)
+
+// MigrateAmountsToDecimal converts monetary fields written as doubles by
+// earlier versions into Decimal128.
+func (ar *AuctionRepository) MigrateAmountsToDecimal(ctx context.Context) error {
+	converted, err := mongodb.ConvertFieldsToDecimal(ctx, ar.Collection,
+		"min_increment", "current_highest_amount", "reserve_price", "buy_now_price", "winning_amount")
+	if err != nil {
+		logger.ErrorContext(ctx, "Error trying to migrate auction amounts to decimal", err)
+		return err
+	}
+
+	if converted > 0 {
+		logger.InfoContext(ctx, fmt.Sprintf("%d auction amounts migrated to decimal", converted))
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+// BackfillEndTimes stores the end time of auctions written before it was
+// persisted, so that bids, buy-now and closes all filter on the same field.
+// It derives it the way calculateAuctionEndTime does.
+func (ar *AuctionRepository) BackfillEndTimes(ctx context.Context) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"end_time": bson.M{"$add": bson.A{
+				bson.M{"$ifNull": bson.A{"$start_time", "$timestamp"}},
+				bson.M{"$ifNull": bson.A{"$duration_seconds", int64(getAuctionInterval() / time.Second)}},
+			}},
+		}}},
+	}
+
+	result, err := ar.Collection.UpdateMany(ctx, bson.M{"end_time": bson.M{"$exists": false}}, pipeline)
+	if err != nil {
+		logger.ErrorContext(ctx, "Error trying to backfill auction end times", err)
+		return err
+	}
+
+	if result.ModifiedCount > 0 {
+		logger.InfoContext(ctx, fmt.Sprintf("%d auction end times backfilled", result.ModifiedCount))
+	}
+
+	return nil
+}
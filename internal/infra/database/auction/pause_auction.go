@@ -0,0 +1,115 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+func (ar *AuctionRepository) PauseAuction(
+	ctx context.Context, auctionId string) *internal_error.InternalError {
+	auctionEntity, err := ar.FindAuctionByIdFromPrimary(ctx, auctionId)
+	if err != nil {
+		return err
+	}
+
+	if auctionEntity.Status != auction_entity.Active {
+		return internal_error.NewConflictError("Only active auctions can be paused")
+	}
+
+	remaining := auctionEntity.EndTime.Sub(ar.Clock.Now())
+	if remaining <= 0 {
+		return internal_error.NewConflictError("Auction is already closed")
+	}
+
+	filter := bson.M{
+		"_id":      auctionId,
+		"status":   auction_entity.Active,
+		"end_time": auctionEntity.EndTime.Unix(),
+		"version":  versionFilter(auctionEntity.Version),
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":            auction_entity.Paused,
+			"remaining_seconds": int64(remaining / time.Second),
+		},
+		"$inc": incrementVersion(),
+	}
+
+	result, updateErr := ar.Collection.UpdateOne(ctx, filter, update)
+	if updateErr != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to pause auction %s", auctionId), updateErr)
+		return internal_error.NewInternalServerError("Error trying to pause auction")
+	}
+
+	if result.ModifiedCount == 0 {
+		return internal_error.NewConflictError("Auction changed state and cannot be paused")
+	}
+
+	ar.Scheduler.Remove(auctionId)
+
+	ar.PriceScheduler.Remove(auctionId)
+
+	logger.InfoContext(ctx, fmt.Sprintf("Auction %s paused with %s remaining", auctionId, remaining))
+
+	return nil
+}
+
+func (ar *AuctionRepository) ResumeAuction(
+	ctx context.Context, auctionId string) (*auction_entity.Auction, *internal_error.InternalError) {
+	var pausedAuction AuctionEntityMongo
+	if err := ar.Collection.FindOne(ctx, bson.M{"_id": auctionId}).Decode(&pausedAuction); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Auction not found with this id = %s", auctionId))
+		}
+
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to find auction by id = %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to resume auction")
+	}
+
+	if pausedAuction.Status != auction_entity.Paused {
+		return nil, internal_error.NewConflictError("Only paused auctions can be resumed")
+	}
+
+	remaining := time.Duration(pausedAuction.Remaining) * time.Second
+	newEndTime := ar.Clock.Now().Add(remaining)
+
+	filter := bson.M{
+		"_id":     auctionId,
+		"status":  auction_entity.Paused,
+		"version": versionFilter(pausedAuction.Version),
+	}
+	update := bson.M{
+		"$set":   bson.M{"status": auction_entity.Active, "end_time": newEndTime.Unix()},
+		"$unset": bson.M{"remaining_seconds": ""},
+		"$inc":   incrementVersion(),
+	}
+
+	var auctionEntityMongo AuctionEntityMongo
+	if err := ar.Collection.FindOneAndUpdate(
+		ctx, filter, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&auctionEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewConflictError("Auction changed state and cannot be resumed")
+		}
+
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to resume auction %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to resume auction")
+	}
+
+	resumedAuction := auctionEntityMongo.toEntity()
+	ar.scheduleAuction(resumedAuction)
+
+	logger.InfoContext(ctx, fmt.Sprintf("Auction %s resumed until %s", auctionId, resumedAuction.EndTime))
+
+	return &resumedAuction, nil
+}
@@ -0,0 +1,41 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPauseAndResumeAuctionPreservesRemainingTime(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	auction, _ := auction_entity.CreateAuction(
+		"monitor",
+		"peripherals",
+		"ultrawide monitor",
+		auction_entity.New,
+		auction_entity.WithDuration(time.Minute))
+
+	fakeClock := fakeclock.New(time.Now())
+	ca := NewAuctionRepositoryWithClock(conn, fakeClock)
+	defer ca.Shutdown(ctx)
+	ca.CreateAuction(ctx, auction)
+
+	fakeClock.Advance(20 * time.Second)
+	assert.Nil(t, ca.PauseAuction(ctx, auction.Id))
+	assert.False(t, ca.Scheduler.Has(auction.Id))
+
+	fakeClock.Advance(time.Hour)
+	auctionDb, _ := ca.FindAuctionById(ctx, auction.Id)
+	assert.Equal(t, auction_entity.Paused, auctionDb.Status)
+
+	resumedAuction, err := ca.ResumeAuction(ctx, auction.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Active, resumedAuction.Status)
+	assert.WithinDuration(t, fakeClock.Now().Add(40*time.Second), resumedAuction.EndTime, time.Second)
+	assert.True(t, ca.Scheduler.Has(auction.Id))
+}
@@ -0,0 +1,214 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+// PlaceHighestBid claims the top position of an auction for amount, returning
+// the user who held it before and the bid's sequence within the auction. The
+// increment check, the sequence, the snipe extension and the write happen in
+// one conditional update, so two concurrent bids can never both become the
+// highest and a leader is never written without its extension.
+func (ar *AuctionRepository) PlaceHighestBid(
+	ctx context.Context,
+	auctionId, userId string,
+	amount, minIncrement money.Amount,
+	snipeExtension auction_entity.SnipeExtension) (auction_entity.PlacedBid, *internal_error.InternalError) {
+	highestFilter := bson.M{"$lt": mongodb.DecimalFromAmount(amount)}
+	if minIncrement > 0 {
+		highestFilter = bson.M{"$lte": mongodb.DecimalFromAmount(amount - minIncrement)}
+	}
+
+	now := ar.Clock.Now()
+	filter := bson.M{
+		"_id":        auctionId,
+		"status":     auction_entity.Active,
+		"deleted_at": bson.M{"$exists": false},
+		"end_time":   bson.M{"$gt": now.Unix()},
+		"$or": bson.A{
+			bson.M{"current_highest_amount": bson.M{"$exists": false}},
+			bson.M{"current_highest_amount": highestFilter},
+		},
+	}
+	update := acceptBidUpdate(bson.M{
+		"current_highest_amount":  bson.M{"$literal": mongodb.DecimalFromAmount(amount)},
+		"current_highest_user_id": bson.M{"$literal": userId},
+	}, now, snipeExtension)
+
+	var previousAuction AuctionEntityMongo
+	err := ar.Collection.FindOneAndUpdate(ctx, filter, update).Decode(&previousAuction)
+	if err == nil {
+		placedBid := ar.acceptedBid(ctx, previousAuction, now, snipeExtension)
+		placedBid.PreviousLeaderUserId = previousAuction.CurrentHighestUserId
+		placedBid.PreviousAmount = mongodb.AmountFromDecimal(previousAuction.CurrentHighestAmount)
+		return placedBid, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to place highest bid on auction %s", auctionId), err)
+		return auction_entity.PlacedBid{}, internal_error.NewInternalServerError("Error trying to place bid")
+	}
+
+	var auctionEntityMongo AuctionEntityMongo
+	err = ar.Collection.FindOne(ctx, bson.M{"_id": auctionId}).Decode(&auctionEntityMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return auction_entity.PlacedBid{}, internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction not found with this id = %s", auctionId))
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to find auction by id = %s", auctionId), err)
+		return auction_entity.PlacedBid{}, internal_error.NewInternalServerError("Error trying to place bid")
+	}
+
+	auctionEntity := auctionEntityMongo.toEntity()
+	if auctionEntity.Status != auction_entity.Active || !auctionEntity.DeletedAt.IsZero() ||
+		!auctionEntity.EndTime.After(ar.Clock.Now()) {
+		return auction_entity.PlacedBid{}, internal_error.NewConflictError("Auction is not active and cannot receive bids")
+	}
+
+	if minIncrement <= 0 {
+		return auction_entity.PlacedBid{}, internal_error.NewFieldBadRequestError(
+			fmt.Sprintf("Bid must be greater than %s", auctionEntity.CurrentHighestAmount),
+			"amount",
+			fmt.Sprintf("next bid must be greater than %s", auctionEntity.CurrentHighestAmount))
+	}
+
+	minimumBid := auctionEntity.CurrentHighestAmount + minIncrement
+	return auction_entity.PlacedBid{}, internal_error.NewFieldBadRequestError(
+		fmt.Sprintf("Bid must be at least %s", minimumBid),
+		"amount",
+		fmt.Sprintf("minimum acceptable next bid is %s", minimumBid))
+}
+
+// RecordSealedBid accepts a sealed bid that does not beat the current highest
+// one, counting it and returning its sequence without changing the leader.
+func (ar *AuctionRepository) RecordSealedBid(
+	ctx context.Context,
+	auctionId string,
+	snipeExtension auction_entity.SnipeExtension) (auction_entity.PlacedBid, *internal_error.InternalError) {
+	now := ar.Clock.Now()
+	filter := bson.M{
+		"_id":        auctionId,
+		"status":     auction_entity.Active,
+		"deleted_at": bson.M{"$exists": false},
+		"end_time":   bson.M{"$gt": now.Unix()},
+	}
+	update := acceptBidUpdate(bson.M{}, now, snipeExtension)
+
+	var previousAuction AuctionEntityMongo
+	err := ar.Collection.FindOneAndUpdate(ctx, filter, update).Decode(&previousAuction)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return auction_entity.PlacedBid{}, internal_error.NewConflictError("Auction is not active and cannot receive bids")
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to record sealed bid on auction %s", auctionId), err)
+		return auction_entity.PlacedBid{}, internal_error.NewInternalServerError("Error trying to place bid")
+	}
+
+	return ar.acceptedBid(ctx, previousAuction, now, snipeExtension), nil
+}
+
+// acceptBidUpdate sets fields, counts the bid, stamps its sequence and marks
+// bidding as started, which locks the auction against edits. When the auction
+// ends within the snipe window of now, the same update pushes its end back.
+func acceptBidUpdate(fields bson.M, now time.Time, snipeExtension auction_entity.SnipeExtension) mongo.Pipeline {
+	fields["bid_sequence"] = plusOne("$bid_sequence")
+	fields["bid_count"] = plusOne("$bid_count")
+	fields["version"] = plusOne("$version")
+	fields["bidding_started"] = true
+
+	if snipeExtension.Window > 0 {
+		sniped := bson.M{"$lt": bson.A{"$end_time", now.Add(snipeExtension.Window).Unix()}}
+		extension := int64(snipeExtension.Extension / time.Second)
+		fields["end_time"] = bson.M{"$cond": bson.A{
+			sniped, bson.M{"$add": bson.A{"$end_time", extension}}, "$end_time"}}
+		fields["snipe_extension_seconds"] = bson.M{"$cond": bson.A{
+			sniped,
+			bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$snipe_extension_seconds", 0}}, extension}},
+			"$snipe_extension_seconds",
+		}}
+	}
+
+	return mongo.Pipeline{{{Key: "$set", Value: fields}}}
+}
+
+func plusOne(field string) bson.M {
+	return bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{field, 0}}, 1}}
+}
+
+// acceptedBid describes the bid accepted on previousAuction, as it was before
+// the update, and moves the close timer when the bid extended the auction.
+func (ar *AuctionRepository) acceptedBid(
+	ctx context.Context,
+	previousAuction AuctionEntityMongo,
+	now time.Time,
+	snipeExtension auction_entity.SnipeExtension) auction_entity.PlacedBid {
+	placedBid := auction_entity.PlacedBid{
+		Sequence: previousAuction.BidSequence + 1,
+		EndTime:  time.Unix(previousAuction.EndTime, 0),
+	}
+
+	if snipeExtension.Window > 0 && previousAuction.EndTime < now.Add(snipeExtension.Window).Unix() {
+		placedBid.Extended = true
+		placedBid.EndTime = placedBid.EndTime.Add(snipeExtension.Extension / time.Second * time.Second)
+		ar.rescheduleClose(previousAuction.Id, placedBid.EndTime)
+
+		logger.InfoContext(ctx, fmt.Sprintf(
+			"Auction %s extended until %s against sniping", previousAuction.Id, placedBid.EndTime))
+	}
+
+	return placedBid
+}
+
+func incrementForAcceptedBid() bson.M {
+	inc := incrementVersion()
+	inc["bid_sequence"] = 1
+	inc["bid_count"] = 1
+	return inc
+}
+
+// ReplaceHighestBid hands the top position back to the next best bid after a
+// retraction. It only applies while the retracted bid is still on top, so a
+// newer bid placed meanwhile is kept.
+func (ar *AuctionRepository) ReplaceHighestBid(
+	ctx context.Context,
+	auctionId, fromUserId string,
+	fromAmount money.Amount,
+	toUserId string,
+	toAmount money.Amount) *internal_error.InternalError {
+	filter := bson.M{
+		"_id":                     auctionId,
+		"current_highest_user_id": fromUserId,
+		"current_highest_amount":  mongodb.DecimalFromAmount(fromAmount),
+	}
+
+	update := bson.M{
+		"$unset": bson.M{"current_highest_amount": "", "current_highest_user_id": ""},
+		"$inc":   incrementVersion(),
+	}
+	if toUserId != "" {
+		update = bson.M{
+			"$set": bson.M{
+				"current_highest_amount":  mongodb.DecimalFromAmount(toAmount),
+				"current_highest_user_id": toUserId,
+			},
+			"$inc": incrementVersion(),
+		}
+	}
+
+	if _, err := ar.Collection.UpdateOne(ctx, filter, update); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to replace highest bid on auction %s", auctionId), err)
+		return internal_error.NewInternalServerError("Error trying to replace highest bid")
+	}
+
+	return nil
+}
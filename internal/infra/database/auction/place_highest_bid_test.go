@@ -0,0 +1,232 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+var noSnipeExtension = auction_entity.SnipeExtension{}
+
+func TestPlaceHighestBidEnforcesIncrementUnderConcurrency(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	ca := NewAuctionRepositoryWithClock(conn, fakeclock.New(time.Now()))
+	defer ca.Shutdown(ctx)
+
+	auction, _ := auction_entity.CreateAuction(
+		"watch",
+		"accessories",
+		"automatic wrist watch",
+		auction_entity.Used,
+		auction_entity.WithMinIncrement(5))
+	assert.Nil(t, ca.CreateAuction(ctx, auction))
+
+	var mutex sync.Mutex
+	var accepted []money.Amount
+	var wg sync.WaitGroup
+
+	for i := 1; i <= 20; i++ {
+		wg.Add(1)
+		go func(amount money.Amount) {
+			defer wg.Done()
+
+			if _, err := ca.PlaceHighestBid(ctx, auction.Id, uuid.New().String(), amount, 5, noSnipeExtension); err == nil {
+				mutex.Lock()
+				accepted = append(accepted, amount)
+				mutex.Unlock()
+			} else {
+				assert.Equal(t, "bad_request", err.Err)
+			}
+		}(money.Amount(i * 3))
+	}
+	wg.Wait()
+
+	auctionDb, err := ca.FindAuctionById(ctx, auction.Id)
+	assert.Nil(t, err)
+
+	var highest money.Amount
+	for _, amount := range accepted {
+		if amount > highest {
+			highest = amount
+		}
+	}
+	assert.Equal(t, highest, auctionDb.CurrentHighestAmount)
+
+	_, lowErr := ca.PlaceHighestBid(ctx, auction.Id, uuid.New().String(), highest+1, 5, noSnipeExtension)
+	assert.NotNil(t, lowErr)
+	assert.Equal(t, "amount", lowErr.Failures[0].Field)
+}
+
+func TestPlaceHighestBidAssignsSequencesWithoutGapsOrDuplicates(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	ca := NewAuctionRepositoryWithClock(conn, fakeclock.New(time.Now()))
+	defer ca.Shutdown(ctx)
+
+	auction, _ := auction_entity.CreateAuction(
+		"bicycle",
+		"sports",
+		"carbon road bicycle",
+		auction_entity.Used)
+	assert.Nil(t, ca.CreateAuction(ctx, auction))
+
+	var mutex sync.Mutex
+	var sequences []int64
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+
+	for i := 1; i <= 100; i++ {
+		wg.Add(1)
+		go func(amount money.Amount) {
+			defer wg.Done()
+			<-start
+
+			placedBid, err := ca.PlaceHighestBid(ctx, auction.Id, uuid.New().String(), amount, 0, noSnipeExtension)
+			if err != nil {
+				assert.Equal(t, "bad_request", err.Err)
+				return
+			}
+
+			mutex.Lock()
+			sequences = append(sequences, placedBid.Sequence)
+			mutex.Unlock()
+		}(money.Amount(i))
+	}
+	close(start)
+	wg.Wait()
+
+	sort.Slice(sequences, func(i, j int) bool { return sequences[i] < sequences[j] })
+	for i, sequence := range sequences {
+		assert.Equal(t, int64(i+1), sequence)
+	}
+
+	var auctionMongo AuctionEntityMongo
+	assert.Nil(t, ca.Collection.FindOne(ctx, bson.M{"_id": auction.Id}).Decode(&auctionMongo))
+	assert.Equal(t, int64(len(sequences)), auctionMongo.BidSequence)
+}
+
+func TestPlaceHighestBidExtendsTheAuctionInTheSameUpdate(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	now := time.Now()
+	ca := NewAuctionRepositoryWithClock(conn, fakeclock.New(now))
+	defer ca.Shutdown(ctx)
+
+	auction, _ := auction_entity.CreateAuction(
+		"camera",
+		"electronics",
+		"mirrorless camera body",
+		auction_entity.Used)
+	assert.Nil(t, ca.CreateAuction(ctx, auction))
+	endTime := now.Add(10 * time.Second).Unix()
+	_, err := ca.Collection.UpdateOne(ctx, bson.M{"_id": auction.Id}, bson.M{"$set": bson.M{"end_time": endTime}})
+	assert.Nil(t, err)
+
+	snipeExtension := auction_entity.SnipeExtension{Window: 30 * time.Second, Extension: time.Minute}
+	placedBid, placeErr := ca.PlaceHighestBid(ctx, auction.Id, uuid.New().String(), 10, 0, snipeExtension)
+	assert.Nil(t, placeErr)
+	assert.True(t, placedBid.Extended)
+	assert.Equal(t, endTime+60, placedBid.EndTime.Unix())
+
+	placedBid, placeErr = ca.PlaceHighestBid(ctx, auction.Id, uuid.New().String(), 20, 0, snipeExtension)
+	assert.Nil(t, placeErr)
+	assert.False(t, placedBid.Extended)
+
+	var auctionMongo AuctionEntityMongo
+	assert.Nil(t, ca.Collection.FindOne(ctx, bson.M{"_id": auction.Id}).Decode(&auctionMongo))
+	assert.Equal(t, endTime+60, auctionMongo.EndTime)
+	assert.Equal(t, int64(60), auctionMongo.SnipeExtension)
+	assert.Zero(t, auctionMongo.Extension)
+	assert.Equal(t, int64(2), auctionMongo.BidSequence)
+}
+
+func TestAcceptedBidLocksTheAuctionAgainstEditsBeforeItIsFlushed(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	ca := NewAuctionRepository(conn)
+	defer ca.Shutdown(ctx)
+
+	auction, _ := auction_entity.CreateAuction(
+		"camera",
+		"electronics",
+		"mirrorless camera body",
+		auction_entity.Used)
+	assert.Nil(t, ca.CreateAuction(ctx, auction))
+
+	_, placeErr := ca.PlaceHighestBid(ctx, auction.Id, uuid.New().String(), 10, 0, noSnipeExtension)
+	assert.Nil(t, placeErr)
+
+	_, updateErr := ca.UpdateAuction(ctx, auction.Id, auction_entity.AuctionUpdate{ProductName: "lens"})
+	assert.Equal(t, internal_error.ErrConflict, updateErr.Err)
+}
+
+func TestAcceptedBidBlocksCancellationBeforeItIsFlushed(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	ca := NewAuctionRepository(conn)
+	defer ca.Shutdown(ctx)
+
+	auction, _ := auction_entity.CreateAuction(
+		"camera",
+		"electronics",
+		"mirrorless camera body",
+		auction_entity.Used)
+	assert.Nil(t, ca.CreateAuction(ctx, auction))
+
+	_, placeErr := ca.PlaceHighestBid(ctx, auction.Id, uuid.New().String(), 10, 0, noSnipeExtension)
+	assert.Nil(t, placeErr)
+	auctionDb, findErr := ca.FindAuctionByIdFromPrimary(ctx, auction.Id)
+	assert.Nil(t, findErr)
+
+	cancelErr := ca.CancelAuction(ctx, auction.Id, "listing error", auctionDb.Version)
+	assert.Equal(t, internal_error.ErrConflict, cancelErr.Err)
+}
+
+func TestPlaceHighestBidAcceptsLegacyAuctionAfterEndTimeBackfill(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	ca := NewAuctionRepositoryWithClock(conn, fakeclock.New(time.Now()))
+	defer ca.Shutdown(ctx)
+
+	auctionId := uuid.New().String()
+	_, insertErr := ca.Collection.InsertOne(ctx, bson.M{
+		"_id":          auctionId,
+		"product_name": "turntable",
+		"category":     "audio",
+		"description":  "belt drive turntable",
+		"condition":    auction_entity.Used,
+		"status":       auction_entity.Active,
+		"timestamp":    time.Now().Unix(),
+		"version":      0,
+	})
+	assert.Nil(t, insertErr)
+
+	assert.Nil(t, ca.BackfillEndTimes(ctx))
+
+	auctionDb, err := ca.FindAuctionById(ctx, auctionId)
+	assert.Nil(t, err)
+	assert.Equal(t, auctionDb.Timestamp.Add(getAuctionInterval()), auctionDb.EndTime)
+
+	_, bidErr := ca.PlaceHighestBid(ctx, auctionId, uuid.New().String(), 10, 0, noSnipeExtension)
+	assert.Nil(t, bidErr)
+
+	var stored AuctionEntityMongo
+	assert.Nil(t, ca.Collection.FindOne(ctx, bson.M{"_id": auctionId}).Decode(&stored))
+	assert.Equal(t, auctionDb.EndTime.Unix(), stored.EndTime)
+}
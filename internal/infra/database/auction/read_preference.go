@@ -0,0 +1,37 @@
+package auction
+
+import (
+	"fullcycle-auction_go/configuration/logger"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+type AuctionRepositoryOption func(repository *AuctionRepository)
+
+// WithQueryReadPreference routes listing and lookup queries through the given
+// read preference. Writes, the close path and the bid flow status checks keep
+// reading from the primary with majority read concern.
+func WithQueryReadPreference(readPreference *readpref.ReadPref) AuctionRepositoryOption {
+	return func(repository *AuctionRepository) {
+		repository.queryCollection = cloneCollection(
+			repository.Collection, options.Collection().SetReadPreference(readPreference))
+	}
+}
+
+func primaryCollection(collection *mongo.Collection) *mongo.Collection {
+	return cloneCollection(collection, options.Collection().
+		SetReadPreference(readpref.Primary()).
+		SetReadConcern(readconcern.Majority()))
+}
+
+func cloneCollection(collection *mongo.Collection, opts *options.CollectionOptions) *mongo.Collection {
+	cloned, err := collection.Clone(opts)
+	if err != nil {
+		logger.Error("Error trying to configure auctions collection, using defaults", err)
+		return collection
+	}
+
+	return cloned
+}
@@ -0,0 +1,170 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"time"
+)
+
+func (ar *AuctionRepository) StartAutoCloseRecovery(ctx context.Context) *internal_error.InternalError {
+	now := ar.Clock.Now()
+	var rescheduled int
+
+	if err := ar.forEachScheduledAuction(ctx, func(auctionEntity auction_entity.Auction) error {
+		if !auctionEntity.OpensAt().After(now) {
+			if err := ar.openAuction(ctx, auctionEntity.Id); err != nil {
+				logger.ErrorContext(ctx, fmt.Sprintf("Failed to open auction %s during recovery", auctionEntity.Id), err)
+			}
+			return nil
+		}
+
+		ar.scheduleAuction(auctionEntity)
+		rescheduled++
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	closed, err := ar.CloseExpiredAuctions(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := ar.ForEachOpenAuction(ctx, func(auctionEntity auction_entity.Auction) error {
+		ar.scheduleAuction(auctionEntity)
+		rescheduled++
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	logger.InfoContext(ctx, fmt.Sprintf(
+		"Auto-close recovery finished: %d expired auctions closed, %d auctions rescheduled",
+		closed, rescheduled))
+
+	return nil
+}
+
+func (ar *AuctionRepository) CloseExpiredAuctions(ctx context.Context) (int64, *internal_error.InternalError) {
+	now := ar.Clock.Now()
+	closeBatchId := uuid.New().String()
+	filter := bson.M{
+		"status":     auction_entity.Active,
+		"deleted_at": bson.M{"$exists": false},
+		"$or": bson.A{
+			bson.M{"end_time": bson.M{"$lte": now.Unix()}},
+			bson.M{
+				"end_time":  bson.M{"$exists": false},
+				"timestamp": bson.M{"$lte": now.Add(-getAuctionInterval()).Unix()},
+			},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":         auction_entity.Completed,
+			"close_batch_id": closeBatchId,
+			"closed_at":      now.Unix(),
+			"close_reason":   auction_entity.CloseReasonExpired,
+		},
+		"$inc": incrementVersion(),
+	}
+
+	result, err := ar.Collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		logger.ErrorContext(ctx, "Error trying to close expired auctions", err)
+		return 0, internal_error.NewInternalServerError("Error trying to close expired auctions")
+	}
+
+	if result.ModifiedCount == 0 {
+		return 0, nil
+	}
+
+	logger.InfoContext(ctx, fmt.Sprintf("%d expired auctions closed", result.ModifiedCount))
+
+	cursor, err := ar.Collection.Find(ctx, bson.M{"close_batch_id": closeBatchId})
+	if err != nil {
+		logger.ErrorContext(ctx, "Error trying to find auctions closed by the expired sweep", err)
+		return result.ModifiedCount, nil
+	}
+	defer cursor.Close(ctx)
+
+	var closedAuctions []AuctionEntityMongo
+	if err := cursor.All(ctx, &closedAuctions); err != nil {
+		logger.ErrorContext(ctx, "Error decoding auctions closed by the expired sweep", err)
+		return result.ModifiedCount, nil
+	}
+
+	for _, closedAuction := range closedAuctions {
+		ar.Scheduler.Remove(closedAuction.Id)
+		ar.PriceScheduler.Remove(closedAuction.Id)
+		ar.recordCloseOutcome(ctx, &closedAuction)
+		ar.notifyAuctionClosed(closedAuction.toEntity())
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (ar *AuctionRepository) OpenDueAuctions(ctx context.Context) (int64, *internal_error.InternalError) {
+	filter := bson.M{
+		"status":     auction_entity.Scheduled,
+		"start_time": bson.M{"$lte": ar.Clock.Now().Unix()},
+		"deleted_at": bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$set": bson.M{"status": auction_entity.Active},
+		"$inc": incrementVersion(),
+	}
+
+	result, err := ar.Collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		logger.ErrorContext(ctx, "Error trying to open scheduled auctions", err)
+		return 0, internal_error.NewInternalServerError("Error trying to open scheduled auctions")
+	}
+
+	if result.ModifiedCount > 0 {
+		logger.InfoContext(ctx, fmt.Sprintf("%d scheduled auctions opened", result.ModifiedCount))
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (ar *AuctionRepository) runExpiredSweep() {
+	ticker := ar.Clock.NewTicker(ar.sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ar.ctx.Done():
+			return
+		case <-ticker.C():
+			if ar.IsCloseLeader() {
+				ar.retryFailedCloses(ar.ctx)
+				if ar.CloseStrategy == CloseStrategySweep {
+					ar.OpenDueAuctions(ar.ctx)
+				}
+				ar.CloseExpiredAuctions(ar.ctx)
+			}
+		}
+	}
+}
+
+const (
+	CloseStrategyTimer = "timer"
+	CloseStrategySweep = "sweep"
+
+	defaultSweepInterval = time.Minute
+)
+
+// WithCloseStrategy picks between a timer per auction and a periodic sweep
+// for closing auctions, and sets how often the sweep runs.
+func WithCloseStrategy(strategy string, sweepInterval time.Duration) AuctionRepositoryOption {
+	return func(repository *AuctionRepository) {
+		repository.CloseStrategy = strategy
+		repository.sweepInterval = sweepInterval
+	}
+}
@@ -0,0 +1,87 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"math/rand"
+	"time"
+)
+
+const (
+	closeRetryBaseDelay = 100 * time.Millisecond
+	closeRetryMaxDelay  = 5 * time.Second
+
+	defaultCloseMaxAttempts = 5
+)
+
+func (ar *AuctionRepository) closeAuctionWithRetry(ctx context.Context, auctionId string) error {
+	maxAttempts := ar.closeMaxAttempts
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = ar.closeAuction(ctx, auctionId); err == nil {
+			return nil
+		}
+
+		if attempt == maxAttempts {
+			break
+		}
+
+		delay := closeRetryDelay(attempt)
+		logger.ErrorContext(ctx, fmt.Sprintf("Failed to close auction %s (attempt %d/%d), retrying in %s",
+			auctionId, attempt, maxAttempts, delay), err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ar.Clock.After(delay):
+		}
+	}
+
+	return err
+}
+
+func (ar *AuctionRepository) markFailedClose(auctionId string) {
+	ar.failedClosesMutex.Lock()
+	defer ar.failedClosesMutex.Unlock()
+
+	ar.failedCloses[auctionId] = struct{}{}
+}
+
+func (ar *AuctionRepository) retryFailedCloses(ctx context.Context) {
+	ar.failedClosesMutex.Lock()
+	auctionIds := make([]string, 0, len(ar.failedCloses))
+	for auctionId := range ar.failedCloses {
+		auctionIds = append(auctionIds, auctionId)
+	}
+	ar.failedClosesMutex.Unlock()
+
+	for _, auctionId := range auctionIds {
+		if err := ar.closeAuctionWithRetry(ctx, auctionId); err != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf("Failed to close auction %s during retry sweep", auctionId), err)
+			continue
+		}
+
+		ar.failedClosesMutex.Lock()
+		delete(ar.failedCloses, auctionId)
+		ar.failedClosesMutex.Unlock()
+	}
+}
+
+func closeRetryDelay(attempt int) time.Duration {
+	delay := closeRetryBaseDelay << (attempt - 1)
+	if delay <= 0 || delay > closeRetryMaxDelay {
+		delay = closeRetryMaxDelay
+	}
+
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// WithCloseMaxAttempts sets how many times closing an auction is tried before
+// it is left for the sweep.
+func WithCloseMaxAttempts(maxAttempts int) AuctionRepositoryOption {
+	return func(repository *AuctionRepository) {
+		repository.closeMaxAttempts = maxAttempts
+	}
+}
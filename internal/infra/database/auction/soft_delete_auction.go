@@ -0,0 +1,37 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (ar *AuctionRepository) SoftDeleteAuction(
+	ctx context.Context, auctionId string) *internal_error.InternalError {
+	filter := bson.M{"_id": auctionId, "deleted_at": bson.M{"$exists": false}}
+	update := bson.M{
+		"$set": bson.M{"deleted_at": ar.Clock.Now().Unix()},
+		"$inc": incrementVersion(),
+	}
+
+	result, err := ar.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to delete auction %s", auctionId), err)
+		return internal_error.NewInternalServerError("Error trying to delete auction")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction not found with this id = %s", auctionId))
+	}
+
+	ar.OpenScheduler.Remove(auctionId)
+	ar.Scheduler.Remove(auctionId)
+	ar.PriceScheduler.Remove(auctionId)
+
+	logger.InfoContext(ctx, fmt.Sprintf("Auction %s soft deleted", auctionId))
+
+	return nil
+}
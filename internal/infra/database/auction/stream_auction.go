@@ -0,0 +1,75 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const defaultStreamBatchSize = 500
+
+func (repo *AuctionRepository) ForEachOpenAuction(
+	ctx context.Context,
+	fn func(auction_entity.Auction) error) *internal_error.InternalError {
+	return repo.forEachAuctionWithStatus(ctx, auction_entity.Active, fn)
+}
+
+func (repo *AuctionRepository) forEachScheduledAuction(
+	ctx context.Context,
+	fn func(auction_entity.Auction) error) *internal_error.InternalError {
+	return repo.forEachAuctionWithStatus(ctx, auction_entity.Scheduled, fn)
+}
+
+func (repo *AuctionRepository) forEachAuctionWithStatus(
+	ctx context.Context,
+	status auction_entity.AuctionStatus,
+	fn func(auction_entity.Auction) error) *internal_error.InternalError {
+	return repo.forEachAuctionMatching(
+		ctx, bson.M{"status": status, "deleted_at": bson.M{"$exists": false}}, fn)
+}
+
+func (repo *AuctionRepository) forEachAuctionMatching(
+	ctx context.Context,
+	filter bson.M,
+	fn func(auction_entity.Auction) error) *internal_error.InternalError {
+	opts := options.Find().SetBatchSize(repo.streamBatchSize)
+
+	cursor, err := repo.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.ErrorContext(ctx, "Error finding auctions", err)
+		return internal_error.NewInternalServerError("Error finding auctions")
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var auctionEntityMongo AuctionEntityMongo
+		if err := cursor.Decode(&auctionEntityMongo); err != nil {
+			logger.ErrorContext(ctx, "Error decoding auction", err)
+			return internal_error.NewInternalServerError("Error decoding auctions")
+		}
+
+		if err := fn(auctionEntityMongo.toEntity()); err != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf("Error processing auction %s", auctionEntityMongo.Id), err)
+			return internal_error.NewInternalServerError("Error processing auctions")
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating auctions", err)
+		return internal_error.NewInternalServerError("Error iterating auctions")
+	}
+
+	return nil
+}
+
+// WithStreamBatchSize sets how many auctions each round trip fetches while
+// streaming them.
+func WithStreamBatchSize(batchSize int) AuctionRepositoryOption {
+	return func(repository *AuctionRepository) {
+		repository.streamBatchSize = int32(batchSize)
+	}
+}
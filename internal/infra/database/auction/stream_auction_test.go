@@ -0,0 +1,49 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestForEachOpenAuctionStreamsInBatches(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	ca := NewAuctionRepositoryWithClock(conn, fakeclock.New(time.Now()), WithStreamBatchSize(1))
+	defer ca.Shutdown(ctx)
+
+	created := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		auction, _ := auction_entity.CreateAuction(
+			"lamp",
+			"lighting",
+			"vintage desk lamp",
+			auction_entity.Used)
+		assert.Nil(t, ca.CreateAuction(ctx, auction))
+		created[auction.Id] = true
+	}
+
+	visited := 0
+	err := ca.ForEachOpenAuction(ctx, func(auctionEntity auction_entity.Auction) error {
+		assert.Equal(t, auction_entity.Active, auctionEntity.Status)
+		if created[auctionEntity.Id] {
+			visited++
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, visited)
+
+	calls := 0
+	err = ca.ForEachOpenAuction(ctx, func(auctionEntity auction_entity.Auction) error {
+		calls++
+		return errors.New("stop")
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,53 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (ar *AuctionRepository) UpdateAuction(
+	ctx context.Context,
+	auctionId string,
+	fields auction_entity.AuctionUpdate) (*auction_entity.Auction, *internal_error.InternalError) {
+	set := bson.M{}
+	if fields.ProductName != "" {
+		set["product_name"] = fields.ProductName
+	}
+	if fields.Category != "" {
+		set["category"] = fields.Category
+	}
+	if fields.Description != "" {
+		set["description"] = fields.Description
+	}
+
+	filter := bson.M{
+		"_id":             auctionId,
+		"status":          auction_entity.Active,
+		"bidding_started": bson.M{"$ne": true},
+		"deleted_at":      bson.M{"$exists": false},
+	}
+
+	var auctionEntityMongo AuctionEntityMongo
+	if err := ar.Collection.FindOneAndUpdate(
+		ctx, filter, bson.M{"$set": set, "$inc": incrementVersion()},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&auctionEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewConflictError(
+				"Auction is no longer active or already received bids and cannot be updated")
+		}
+
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to update auction %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to update auction")
+	}
+
+	updatedAuction := auctionEntityMongo.toEntity()
+	return &updatedAuction, nil
+}
@@ -0,0 +1,89 @@
+package auction
+
+import (
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+type auctionChangeEvent struct {
+	FullDocument *AuctionEntityMongo `bson:"fullDocument"`
+}
+
+func (ar *AuctionRepository) StartChangeStreamSync() {
+	if !ar.changeStreamEnabled {
+		return
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"operationType": bson.M{"$in": bson.A{"update", "replace"}},
+		}}},
+	}
+
+	stream, err := ar.Collection.Watch(
+		ar.ctx, pipeline, options.ChangeStream().SetFullDocument(options.UpdateLookup))
+	if err != nil {
+		logger.Warn("Change streams are not available, auto-close runs without cross-instance coordination",
+			zap.NamedError("error", err))
+		return
+	}
+
+	go ar.consumeChangeStream(stream)
+}
+
+func (ar *AuctionRepository) consumeChangeStream(stream *mongo.ChangeStream) {
+	defer stream.Close(ar.ctx)
+
+	for stream.Next(ar.ctx) {
+		var event auctionChangeEvent
+		if err := stream.Decode(&event); err != nil {
+			logger.Error("Error decoding auction change event", err)
+			continue
+		}
+
+		if event.FullDocument == nil {
+			continue
+		}
+
+		ar.applyAuctionChange(event.FullDocument.toEntity())
+	}
+
+	if err := stream.Err(); err != nil && ar.ctx.Err() == nil {
+		logger.Error("Auction change stream stopped", err)
+	}
+}
+
+func (ar *AuctionRepository) applyAuctionChange(auctionEntity auction_entity.Auction) {
+	status := auctionEntity.Status
+	if !auctionEntity.DeletedAt.IsZero() {
+		status = auction_entity.Cancelled
+	}
+
+	switch status {
+	case auction_entity.Active, auction_entity.Scheduled:
+		if ar.Scheduler.Has(auctionEntity.Id) {
+			ar.Scheduler.Reschedule(auctionEntity.Id, auctionEntity.EndTime)
+		}
+	default:
+		removedOpen := ar.OpenScheduler.Remove(auctionEntity.Id)
+		removedClose := ar.Scheduler.Remove(auctionEntity.Id)
+		ar.PriceScheduler.Remove(auctionEntity.Id)
+		if removedOpen || removedClose {
+			logger.Info(fmt.Sprintf(
+				"Auction %s finished on another instance, local timer cancelled", auctionEntity.Id))
+		}
+	}
+}
+
+// WithChangeStreamSync cancels the local close timer of auctions finished on
+// another instance, through a change stream on the auctions collection.
+func WithChangeStreamSync() AuctionRepositoryOption {
+	return func(repository *AuctionRepository) {
+		repository.changeStreamEnabled = true
+	}
+}
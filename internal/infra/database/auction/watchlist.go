@@ -0,0 +1,172 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type WatchlistEntryMongo struct {
+	Id               string `bson:"_id"`
+	UserId           string `bson:"user_id"`
+	AuctionId        string `bson:"auction_id"`
+	CreatedAt        int64  `bson:"created_at"`
+	EndingNotifiedAt int64  `bson:"ending_notified_at,omitempty"`
+}
+
+// AddToWatchlist is idempotent: watching an auction twice keeps the first entry.
+func (ar *AuctionRepository) AddToWatchlist(
+	ctx context.Context, userId, auctionId string) *internal_error.InternalError {
+	if _, err := ar.FindAuctionById(ctx, auctionId); err != nil {
+		return err
+	}
+
+	filter := bson.M{"user_id": userId, "auction_id": auctionId}
+	update := bson.M{"$setOnInsert": bson.M{
+		"_id":        uuid.New().String(),
+		"created_at": ar.Clock.Now().Unix(),
+	}}
+
+	_, err := ar.WatchlistCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to watch auction %s", auctionId), err)
+		return internal_error.NewInternalServerError("Error trying to add auction to watchlist")
+	}
+
+	return nil
+}
+
+func (ar *AuctionRepository) RemoveFromWatchlist(
+	ctx context.Context, userId, auctionId string) *internal_error.InternalError {
+	result, err := ar.WatchlistCollection.DeleteOne(ctx, bson.M{"user_id": userId, "auction_id": auctionId})
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to stop watching auction %s", auctionId), err)
+		return internal_error.NewInternalServerError("Error trying to remove auction from watchlist")
+	}
+	if result.DeletedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction %s is not in the watchlist", auctionId))
+	}
+
+	return nil
+}
+
+// FindWatchedAuctions returns the summaries of the auctions the user watches,
+// most recently watched first. Deleted auctions are left out.
+func (ar *AuctionRepository) FindWatchedAuctions(
+	ctx context.Context, userId string) ([]auction_entity.AuctionSummary, *internal_error.InternalError) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := ar.WatchlistCollection.Find(ctx, bson.M{"user_id": userId}, opts)
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to find the watchlist of user %s", userId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find watchlist")
+	}
+
+	var entries []WatchlistEntryMongo
+	if err := cursor.All(ctx, &entries); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to decode the watchlist of user %s", userId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find watchlist")
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	auctionIds := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		auctionIds = append(auctionIds, entry.AuctionId)
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"_id":        bson.M{"$in": auctionIds},
+			"deleted_at": bson.M{"$exists": false},
+		}},
+	}
+	pipeline = append(pipeline, summaryStages()...)
+
+	summariesMongo, summaryErr := ar.aggregateSummaries(ctx, pipeline)
+	if summaryErr != nil {
+		return nil, summaryErr
+	}
+
+	summariesById := make(map[string]auction_entity.AuctionSummary, len(summariesMongo))
+	for _, summaryMongo := range summariesMongo {
+		summariesById[summaryMongo.Id] = summaryMongo.toEntity()
+	}
+
+	var summaries []auction_entity.AuctionSummary
+	for _, auctionId := range auctionIds {
+		if summary, ok := summariesById[auctionId]; ok {
+			summaries = append(summaries, summary)
+		}
+	}
+
+	return summaries, nil
+}
+
+// ClaimEndingWatches marks the watchlist entries of active auctions ending
+// within the window as notified and returns them. Each entry is claimed on
+// its own, so instances sweeping at the same time never notify a user twice.
+func (ar *AuctionRepository) ClaimEndingWatches(
+	ctx context.Context, within time.Duration) ([]auction_entity.WatchedAuctionEnding, *internal_error.InternalError) {
+	now := ar.Clock.Now()
+	filter := bson.M{
+		"status": auction_entity.Active,
+		"end_time": bson.M{
+			"$gt":  now.Unix(),
+			"$lte": now.Add(within).Unix(),
+		},
+		"deleted_at": bson.M{"$exists": false},
+	}
+	opts := options.Find().SetProjection(bson.M{"_id": 1, "end_time": 1})
+
+	cursor, err := ar.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.ErrorContext(ctx, "Error trying to find watched auctions ending soon", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find auctions ending soon")
+	}
+
+	var endingAuctions []struct {
+		Id      string `bson:"_id"`
+		EndTime int64  `bson:"end_time"`
+	}
+	if err := cursor.All(ctx, &endingAuctions); err != nil {
+		logger.ErrorContext(ctx, "Error trying to decode watched auctions ending soon", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find auctions ending soon")
+	}
+
+	var claimed []auction_entity.WatchedAuctionEnding
+	for _, endingAuction := range endingAuctions {
+		for {
+			var entry WatchlistEntryMongo
+			err := ar.WatchlistCollection.FindOneAndUpdate(ctx,
+				bson.M{"auction_id": endingAuction.Id, "ending_notified_at": bson.M{"$exists": false}},
+				bson.M{"$set": bson.M{"ending_notified_at": now.Unix()}},
+			).Decode(&entry)
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				break
+			}
+			if err != nil {
+				logger.ErrorContext(ctx, fmt.Sprintf("Error trying to claim watchers of auction %s", endingAuction.Id), err)
+				return claimed, internal_error.NewInternalServerError("Error trying to claim watchlist entries")
+			}
+
+			claimed = append(claimed, auction_entity.WatchedAuctionEnding{
+				AuctionId: endingAuction.Id,
+				UserId:    entry.UserId,
+				EndTime:   time.Unix(endingAuction.EndTime, 0),
+			})
+		}
+	}
+
+	return claimed, nil
+}
@@ -0,0 +1,86 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/money"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWatchlistNotifiesEachWatcherOnce(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	fakeClock := fakeclock.New(time.Now())
+	ar := NewAuctionRepositoryWithClock(conn, fakeClock)
+	defer ar.Shutdown(ctx)
+	assert.Nil(t, ar.EnsureIndexes(ctx))
+
+	auction, _ := auction_entity.CreateAuction(
+		"camera",
+		"photography",
+		"mirrorless camera",
+		auction_entity.Used,
+		auction_entity.WithDuration(2*time.Hour))
+	assert.Nil(t, ar.CreateAuction(ctx, auction))
+
+	userId := uuid.New().String()
+	assert.Nil(t, ar.AddToWatchlist(ctx, userId, auction.Id))
+	assert.Nil(t, ar.AddToWatchlist(ctx, userId, auction.Id))
+
+	summaries, err := ar.FindWatchedAuctions(ctx, userId)
+	assert.Nil(t, err)
+	assert.Len(t, summaries, 1)
+	assert.Equal(t, auction.Id, summaries[0].Id)
+
+	claimed, err := ar.ClaimEndingWatches(ctx, time.Hour)
+	assert.Nil(t, err)
+	assert.Empty(t, claimed)
+
+	fakeClock.Advance(90 * time.Minute)
+
+	claimed, err = ar.ClaimEndingWatches(ctx, time.Hour)
+	assert.Nil(t, err)
+	assert.Equal(t, []auction_entity.WatchedAuctionEnding{
+		{AuctionId: auction.Id, UserId: userId, EndTime: time.Unix(auction.EndTime.Unix(), 0)},
+	}, claimed)
+
+	claimed, err = ar.ClaimEndingWatches(ctx, time.Hour)
+	assert.Nil(t, err)
+	assert.Empty(t, claimed)
+
+	assert.Nil(t, ar.RemoveFromWatchlist(ctx, userId, auction.Id))
+	assert.True(t, ar.RemoveFromWatchlist(ctx, userId, auction.Id).IsNotFound())
+}
+
+func TestWatchedAuctionSummaryShowsTheCachedHighestBid(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	ar := NewAuctionRepository(conn)
+	defer ar.Shutdown(ctx)
+
+	auction, _ := auction_entity.CreateAuction(
+		"tripod",
+		"photography",
+		"carbon fibre tripod",
+		auction_entity.Used)
+	assert.Nil(t, ar.CreateAuction(ctx, auction))
+
+	userId := uuid.New().String()
+	assert.Nil(t, ar.AddToWatchlist(ctx, userId, auction.Id))
+
+	_, placeErr := ar.PlaceHighestBid(ctx, auction.Id, uuid.New().String(), 1500, 0, noSnipeExtension)
+	assert.Nil(t, placeErr)
+
+	summaries, err := ar.FindWatchedAuctions(ctx, userId)
+	assert.Nil(t, err)
+	assert.Len(t, summaries, 1)
+	assert.NotNil(t, summaries[0].HighestBid)
+	assert.Equal(t, money.Amount(1500), *summaries[0].HighestBid)
+	assert.Equal(t, int64(1), summaries[0].BidCount)
+}
@@ -2,45 +2,82 @@ package bid
 
 import (
 	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/database/mongodb"
 	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/clock"
 	"fullcycle-auction_go/internal/entity/auction_entity"
 	"fullcycle-auction_go/internal/entity/bid_entity"
 	"fullcycle-auction_go/internal/infra/database/auction"
 	"fullcycle-auction_go/internal/internal_error"
-	"os"
 	"sync"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// BidEntityMongo stores Timestamp in milliseconds, so bids placed within the
+// same second keep their order.
 type BidEntityMongo struct {
-	Id        string  `bson:"_id"`
-	UserId    string  `bson:"user_id"`
-	AuctionId string  `bson:"auction_id"`
-	Amount    float64 `bson:"amount"`
-	Timestamp int64   `bson:"timestamp"`
+	Id        string               `bson:"_id"`
+	UserId    string               `bson:"user_id"`
+	AuctionId string               `bson:"auction_id"`
+	Amount    primitive.Decimal128 `bson:"amount"`
+	Timestamp int64                `bson:"timestamp"`
+	Sequence  int64                `bson:"sequence,omitempty"`
+	Auto      bool                 `bson:"auto,omitempty"`
+	Retracted bool                 `bson:"retracted,omitempty"`
+	Voided    bool                 `bson:"voided,omitempty"`
+
+	IdempotencyKey string `bson:"idempotency_key,omitempty"`
 }
 
 type BidRepository struct {
+	Clock                 clock.Clock
 	Collection            *mongo.Collection
+	MaxBidCollection      *mongo.Collection
+	IdempotencyCollection *mongo.Collection
 	AuctionRepository     *auction.AuctionRepository
-	auctionInterval       time.Duration
 	auctionStatusMap      map[string]auction_entity.AuctionStatus
 	auctionEndTimeMap     map[string]time.Time
 	auctionStatusMapMutex *sync.Mutex
 	auctionEndTimeMutex   *sync.Mutex
+	idempotencyKeyTTL     time.Duration
+	streamBatchSize       int32
 }
 
-func NewBidRepository(database *mongo.Database, auctionRepository *auction.AuctionRepository) *BidRepository {
-	return &BidRepository{
-		auctionInterval:       getAuctionInterval(),
+type BidRepositoryOption func(repository *BidRepository)
+
+func NewBidRepository(
+	database *mongo.Database,
+	auctionRepository *auction.AuctionRepository,
+	opts ...BidRepositoryOption) *BidRepository {
+	bidRepository := &BidRepository{
+		Clock:                 clock.NewRealClock(),
 		auctionStatusMap:      make(map[string]auction_entity.AuctionStatus),
 		auctionEndTimeMap:     make(map[string]time.Time),
 		auctionStatusMapMutex: &sync.Mutex{},
 		auctionEndTimeMutex:   &sync.Mutex{},
 		Collection:            database.Collection("bids"),
+		MaxBidCollection:      database.Collection("max_bids"),
+		IdempotencyCollection: database.Collection("bid_idempotency_keys"),
 		AuctionRepository:     auctionRepository,
+		idempotencyKeyTTL:     defaultIdempotencyKeyTTL,
+		streamBatchSize:       defaultStreamBatchSize,
+	}
+	for _, opt := range opts {
+		opt(bidRepository)
+	}
+
+	return bidRepository
+}
+
+// WithClock sets the clock retractions, voids and idempotency keys are
+// stamped with.
+func WithClock(bidClock clock.Clock) BidRepositoryOption {
+	return func(repository *BidRepository) {
+		repository.Clock = bidClock
 	}
 }
 
@@ -61,34 +98,23 @@ func (bd *BidRepository) CreateBid(
 			auctionEndTime, okEndTime := bd.auctionEndTimeMap[bidValue.AuctionId]
 			bd.auctionEndTimeMutex.Unlock()
 
-			bidEntityMongo := &BidEntityMongo{
-				Id:        bidValue.Id,
-				UserId:    bidValue.UserId,
-				AuctionId: bidValue.AuctionId,
-				Amount:    bidValue.Amount,
-				Timestamp: bidValue.Timestamp.Unix(),
-			}
-
-			if okEndTime && okStatus {
-				now := time.Now()
-				if auctionStatus == auction_entity.Completed || now.After(auctionEndTime) {
-					return
-				}
+			bidEntityMongo := newBidEntityMongo(bidValue)
 
-				if _, err := bd.Collection.InsertOne(ctx, bidEntityMongo); err != nil {
-					logger.Error("Error trying to insert bid", err)
-					return
-				}
+			if okEndTime && okStatus &&
+				auctionStatus == auction_entity.Active && !bidValue.Timestamp.After(auctionEndTime) {
+				bd.insertBid(ctx, bidEntityMongo)
 
 				return
 			}
 
-			auctionEntity, err := bd.AuctionRepository.FindAuctionById(ctx, bidValue.AuctionId)
+			auctionEntity, err := bd.AuctionRepository.FindAuctionByIdFromPrimary(ctx, bidValue.AuctionId)
 			if err != nil {
-				logger.Error("Error trying to find auction by id", err)
+				logger.ErrorContext(ctx, "Error trying to find auction by id", err)
 				return
 			}
-			if auctionEntity.Status == auction_entity.Completed {
+			if !acceptedBeforeClose(*auctionEntity, bidValue) {
+				logger.InfoContext(ctx, fmt.Sprintf(
+					"Discarding bid %s placed after auction %s closed", bidValue.Id, bidValue.AuctionId))
 				return
 			}
 
@@ -97,25 +123,71 @@ func (bd *BidRepository) CreateBid(
 			bd.auctionStatusMapMutex.Unlock()
 
 			bd.auctionEndTimeMutex.Lock()
-			bd.auctionEndTimeMap[bidValue.AuctionId] = auctionEntity.Timestamp.Add(bd.auctionInterval)
+			bd.auctionEndTimeMap[bidValue.AuctionId] = auctionEntity.EndTime
 			bd.auctionEndTimeMutex.Unlock()
 
-			if _, err := bd.Collection.InsertOne(ctx, bidEntityMongo); err != nil {
-				logger.Error("Error trying to insert bid", err)
-				return
-			}
+			bd.insertBid(ctx, bidEntityMongo)
 		}(bid)
 	}
 	wg.Wait()
 	return nil
 }
 
-func getAuctionInterval() time.Duration {
-	auctionInterval := os.Getenv("AUCTION_INTERVAL")
-	duration, err := time.ParseDuration(auctionInterval)
-	if err != nil {
-		return time.Minute * 5
+// acceptedBeforeClose reports whether a bid still counts for the auction,
+// judged by when the bid was accepted rather than when its batch is flushed.
+func acceptedBeforeClose(auctionEntity auction_entity.Auction, bidEntity bid_entity.Bid) bool {
+	if auctionEntity.Status == auction_entity.Active {
+		return !bidEntity.Timestamp.After(auctionEntity.EndTime)
+	}
+
+	return auctionEntity.Status == auction_entity.Completed && !auctionEntity.ClosedAt.IsZero() &&
+		bidEntity.Timestamp.Unix() <= auctionEntity.ClosedAt.Unix()
+}
+
+// insertBid ignores duplicates, which happen when a bid still waiting in the
+// batch was retracted and stored ahead of it.
+func (bd *BidRepository) insertBid(ctx context.Context, bidEntityMongo *BidEntityMongo) {
+	if _, err := bd.Collection.InsertOne(ctx, bidEntityMongo); err != nil && !mongo.IsDuplicateKeyError(err) {
+		logger.ErrorContext(ctx, "Error trying to insert bid", err)
+	}
+}
+
+func newBidEntityMongo(bidEntity bid_entity.Bid) *BidEntityMongo {
+	return &BidEntityMongo{
+		Id:             bidEntity.Id,
+		UserId:         bidEntity.UserId,
+		AuctionId:      bidEntity.AuctionId,
+		Amount:         mongodb.DecimalFromAmount(bidEntity.Amount),
+		Timestamp:      bidEntity.Timestamp.UnixMilli(),
+		Sequence:       bidEntity.Sequence,
+		Auto:           bidEntity.Auto,
+		IdempotencyKey: bidEntity.IdempotencyKey,
+	}
+}
+
+func (bm *BidEntityMongo) toEntity() bid_entity.Bid {
+	return bid_entity.Bid{
+		Id:        bm.Id,
+		UserId:    bm.UserId,
+		AuctionId: bm.AuctionId,
+		Amount:    mongodb.AmountFromDecimal(bm.Amount),
+		Timestamp: bidTime(bm.Timestamp),
+		Sequence:  bm.Sequence,
+		Auto:      bm.Auto,
+		Retracted: bm.Retracted,
+		Voided:    bm.Voided,
+
+		IdempotencyKey: bm.IdempotencyKey,
+	}
+}
+
+// bidTime decodes a bid timestamp. Bids written by earlier versions hold
+// seconds, which stay below legacySecondsLimit until the year 5138, while any
+// timestamp in milliseconds after 1973 is above it.
+func bidTime(timestamp int64) time.Time {
+	if timestamp < legacySecondsLimit {
+		return time.Unix(timestamp, 0)
 	}
 
-	return duration
+	return time.UnixMilli(timestamp)
 }
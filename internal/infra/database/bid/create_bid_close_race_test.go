@@ -0,0 +1,118 @@
+package bid
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/config"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/database/auction"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log"
+	"math/rand"
+	"path"
+	"sync"
+	"testing"
+	"time"
+)
+
+func connectTestDatabase() *mongo.Database {
+	cfg, err := config.Load(path.Join("./", "../../../../cmd/auction/.env"))
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	conn, err := mongodb.NewMongoDBConnection(context.Background(), cfg.Mongo)
+	if err != nil {
+		log.Fatal("Error trying to connect mongodb")
+	}
+
+	return conn
+}
+
+func TestBidsAroundTheCloseInstantNeverCountAfterClosedAt(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	auctionRepository := auction.NewAuctionRepository(conn)
+	defer auctionRepository.Shutdown(ctx)
+	bidRepository := NewBidRepository(conn, auctionRepository)
+
+	auctionEntity, _ := auction_entity.CreateAuction(
+		"camera",
+		"photography",
+		"mirrorless camera body",
+		auction_entity.Used,
+		auction_entity.WithDuration(2*time.Second))
+	assert.Nil(t, auctionRepository.CreateAuction(ctx, auctionEntity))
+
+	time.Sleep(time.Until(auctionEntity.EndTime.Add(-300 * time.Millisecond)))
+
+	var mutex sync.Mutex
+	var accepted []bid_entity.Bid
+	var rejected int
+
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func(amount money.Amount) {
+			defer wg.Done()
+			time.Sleep(time.Duration(rand.Intn(600)) * time.Millisecond)
+
+			bidEntity, _ := bid_entity.CreateBid(uuid.New().String(), auctionEntity.Id, amount, time.Now())
+			_, err := auctionRepository.PlaceHighestBid(
+				ctx, auctionEntity.Id, bidEntity.UserId, bidEntity.Amount, 0, auction_entity.SnipeExtension{})
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err == nil {
+				accepted = append(accepted, *bidEntity)
+			} else if err.Err == internal_error.ErrConflict {
+				rejected++
+			}
+		}(money.Amount(i + 1))
+	}
+	wg.Wait()
+
+	assert.Eventually(t, func() bool {
+		auctionDb, err := auctionRepository.FindAuctionByIdFromPrimary(ctx, auctionEntity.Id)
+		return err == nil && auctionDb.Status == auction_entity.Completed
+	}, 5*time.Second, 20*time.Millisecond)
+
+	auctionDb, _ := auctionRepository.FindAuctionByIdFromPrimary(ctx, auctionEntity.Id)
+	lateBid := bid_entity.Bid{
+		Id:        uuid.New().String(),
+		UserId:    uuid.New().String(),
+		AuctionId: auctionEntity.Id,
+		Amount:    10000,
+		Timestamp: auctionDb.ClosedAt.Add(2 * time.Second),
+	}
+	_, lateErr := auctionRepository.PlaceHighestBid(
+		ctx, auctionEntity.Id, lateBid.UserId, lateBid.Amount, 0, auction_entity.SnipeExtension{})
+	assert.Equal(t, internal_error.ErrConflict, lateErr.Err)
+
+	assert.Nil(t, bidRepository.CreateBid(ctx, append(accepted, lateBid)))
+	assert.Greater(t, rejected, 0)
+
+	for _, bidEntity := range accepted {
+		assert.LessOrEqual(t, bidEntity.Timestamp.Unix(), auctionDb.ClosedAt.Unix())
+	}
+
+	bidPage, err := bidRepository.FindBidByAuctionId(ctx, auctionEntity.Id, bid_entity.FindBidsOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(accepted)), bidPage.Total)
+
+	if len(accepted) == 0 {
+		return
+	}
+
+	winningBid, err := bidRepository.FindWinningBidByAuctionId(ctx, auctionEntity.Id)
+	assert.Nil(t, err)
+	assert.LessOrEqual(t, winningBid.Timestamp.Unix(), auctionDb.ClosedAt.Unix())
+	assert.Equal(t, auctionDb.CurrentHighestAmount, winningBid.Amount)
+	assert.Equal(t, auctionDb.CurrentHighestUserId, winningBid.UserId)
+}
@@ -0,0 +1,80 @@
+package bid
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (bd *BidRepository) EnsureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "auction_id", Value: 1},
+				{Key: "amount", Value: -1},
+				{Key: "sequence", Value: 1},
+				{Key: "timestamp", Value: 1},
+			},
+			Options: options.Index().SetName("auction_id_amount_desc_sequence_timestamp"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "auction_id", Value: 1},
+				{Key: "sequence", Value: 1},
+				{Key: "timestamp", Value: 1},
+			},
+			Options: options.Index().SetName("auction_id_sequence_timestamp"),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("user_id_timestamp_desc_id_desc"),
+		},
+	}
+
+	if _, err := bd.Collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		logger.ErrorContext(ctx, "Error trying to create bid indexes", err)
+		return err
+	}
+
+	maxBidIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "auction_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("auction_id_user_id_unique").SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "auction_id", Value: 1},
+				{Key: "max_amount", Value: -1},
+				{Key: "timestamp", Value: 1},
+			},
+			Options: options.Index().SetName("auction_id_max_amount_desc_timestamp"),
+		},
+	}
+
+	if _, err := bd.MaxBidCollection.Indexes().CreateMany(ctx, maxBidIndexes); err != nil {
+		logger.ErrorContext(ctx, "Error trying to create max bid indexes", err)
+		return err
+	}
+
+	idempotencyIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "idempotency_key", Value: 1}},
+			Options: options.Index().SetName("user_id_idempotency_key_unique").SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().
+				SetName("created_at_ttl").
+				SetExpireAfterSeconds(int32(bd.idempotencyKeyTTL.Seconds())),
+		},
+	}
+
+	if _, err := bd.IdempotencyCollection.Indexes().CreateMany(ctx, idempotencyIndexes); err != nil {
+		logger.ErrorContext(ctx, "Error trying to create bid idempotency key indexes", err)
+		return err
+	}
+
+	return nil
+}
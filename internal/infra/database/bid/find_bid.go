@@ -2,22 +2,45 @@ package bid
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"fullcycle-auction_go/configuration/database/mongodb"
 	"fullcycle-auction_go/configuration/logger"
 	"fullcycle-auction_go/internal/entity/bid_entity"
 	"fullcycle-auction_go/internal/internal_error"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"time"
 )
 
 func (bd *BidRepository) FindBidByAuctionId(
-	ctx context.Context, auctionId string) ([]bid_entity.Bid, *internal_error.InternalError) {
-	filter := bson.M{"auctionId": auctionId}
+	ctx context.Context,
+	auctionId string,
+	findOptions bid_entity.FindBidsOptions) (*bid_entity.BidPage, *internal_error.InternalError) {
+	filter := bson.M{"auction_id": auctionId}
+	if findOptions.UserId != "" {
+		filter["user_id"] = findOptions.UserId
+	}
 
-	cursor, err := bd.Collection.Find(ctx, filter)
+	total, err := bd.Collection.CountDocuments(ctx, filter)
 	if err != nil {
-		logger.Error(
+		logger.ErrorContext(ctx,
+			fmt.Sprintf("Error trying to count bids by auctionId %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError(
+			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId))
+	}
+
+	opts := options.Find().SetSort(bidSort(findOptions.Order))
+	if findOptions.Limit > 0 {
+		opts.SetLimit(findOptions.Limit)
+	}
+	if findOptions.Offset > 0 {
+		opts.SetSkip(findOptions.Offset)
+	}
+
+	cursor, err := bd.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.ErrorContext(ctx,
 			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId), err)
 		return nil, internal_error.NewInternalServerError(
 			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId))
@@ -25,7 +48,7 @@ func (bd *BidRepository) FindBidByAuctionId(
 
 	var bidEntitiesMongo []BidEntityMongo
 	if err := cursor.All(ctx, &bidEntitiesMongo); err != nil {
-		logger.Error(
+		logger.ErrorContext(ctx,
 			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId), err)
 		return nil, internal_error.NewInternalServerError(
 			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId))
@@ -33,34 +56,153 @@ func (bd *BidRepository) FindBidByAuctionId(
 
 	var bidEntities []bid_entity.Bid
 	for _, bidEntityMongo := range bidEntitiesMongo {
-		bidEntities = append(bidEntities, bid_entity.Bid{
-			Id:        bidEntityMongo.Id,
-			UserId:    bidEntityMongo.UserId,
-			AuctionId: bidEntityMongo.AuctionId,
-			Amount:    bidEntityMongo.Amount,
-			Timestamp: time.Unix(bidEntityMongo.Timestamp, 0),
-		})
+		bidEntities = append(bidEntities, bidEntityMongo.toEntity())
 	}
 
-	return bidEntities, nil
+	return &bid_entity.BidPage{Bids: bidEntities, Total: total}, nil
+}
+
+// FindBidsByUserId pages through the user's bids on every auction, ordered by
+// when they were placed.
+func (bd *BidRepository) FindBidsByUserId(
+	ctx context.Context,
+	userId string,
+	findOptions bid_entity.FindBidsOptions) (*bid_entity.BidPage, *internal_error.InternalError) {
+	filter := bson.M{"user_id": userId}
+
+	total, err := bd.Collection.CountDocuments(ctx, filter)
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to count bids of user %s", userId), err)
+		return nil, internal_error.NewInternalServerError(
+			fmt.Sprintf("Error trying to find bids of user %s", userId))
+	}
+
+	direction := -1
+	if findOptions.Order == bid_entity.BidOrderChronological {
+		direction = 1
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: direction}, {Key: "_id", Value: direction}})
+	if findOptions.Limit > 0 {
+		opts.SetLimit(findOptions.Limit)
+	}
+	if findOptions.Offset > 0 {
+		opts.SetSkip(findOptions.Offset)
+	}
+
+	cursor, err := bd.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to find bids of user %s", userId), err)
+		return nil, internal_error.NewInternalServerError(
+			fmt.Sprintf("Error trying to find bids of user %s", userId))
+	}
+
+	var bidEntitiesMongo []BidEntityMongo
+	if err := cursor.All(ctx, &bidEntitiesMongo); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to find bids of user %s", userId), err)
+		return nil, internal_error.NewInternalServerError(
+			fmt.Sprintf("Error trying to find bids of user %s", userId))
+	}
+
+	var bidEntities []bid_entity.Bid
+	for _, bidEntityMongo := range bidEntitiesMongo {
+		bidEntities = append(bidEntities, bidEntityMongo.toEntity())
+	}
+
+	return &bid_entity.BidPage{Bids: bidEntities, Total: total}, nil
+}
+
+func countingBidsFilter(auctionId string) bson.M {
+	return bson.M{"auction_id": auctionId, "retracted": bson.M{"$ne": true}}
+}
+
+// winningBidsFilter leaves out bids voided when their bidder was banned, so
+// they can no longer lead or win the auction.
+func winningBidsFilter(auctionId string) bson.M {
+	filter := countingBidsFilter(auctionId)
+	filter["voided"] = bson.M{"$ne": true}
+	return filter
+}
+
+func bidSort(order string) bson.D {
+	if order == bid_entity.BidOrderChronological {
+		return bson.D{{Key: "sequence", Value: 1}, {Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}
+	}
+
+	return bson.D{
+		{Key: "amount", Value: -1},
+		{Key: "sequence", Value: 1},
+		{Key: "timestamp", Value: 1},
+		{Key: "_id", Value: 1},
+	}
+}
+
+func (bd *BidRepository) FindBidById(
+	ctx context.Context, bidId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	var bidEntityMongo BidEntityMongo
+	if err := bd.Collection.FindOne(ctx, bson.M{"_id": bidId}).Decode(&bidEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Bid not found with this id = %s", bidId))
+		}
+
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to find bid by id = %s", bidId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find bid by id")
+	}
+
+	bidEntity := bidEntityMongo.toEntity()
+	return &bidEntity, nil
+}
+
+func (bd *BidRepository) FindHighestBidByAuctionId(
+	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	var bidEntityMongo BidEntityMongo
+	opts := options.FindOne().SetSort(bidSort(bid_entity.BidOrderAmount))
+	if err := bd.Collection.FindOne(ctx, winningBidsFilter(auctionId), opts).Decode(&bidEntityMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("No bids found for auction with this id = %s", auctionId))
+		}
+
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to find the highest bid of auction %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find the highest bid")
+	}
+
+	highestBid := bidEntityMongo.toEntity()
+	return &highestBid, nil
 }
 
 func (bd *BidRepository) FindWinningBidByAuctionId(
 	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
-	filter := bson.M{"auction_id": auctionId}
+	filter := winningBidsFilter(auctionId)
+
+	auctionEntity, err := bd.AuctionRepository.FindAuctionByIdFromPrimary(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+	if !auctionEntity.ClosedAt.IsZero() {
+		// closed_at is stored in seconds, so bids accepted within its second
+		// still count, as in acceptedBeforeClose.
+		filter["timestamp"] = bson.M{"$lt": (auctionEntity.ClosedAt.Unix() + 1) * 1000}
+	}
 
 	var bidEntityMongo BidEntityMongo
-	opts := options.FindOne().SetSort(bson.D{{"amount", -1}})
+	opts := options.FindOne().SetSort(bidSort(bid_entity.BidOrderAmount))
 	if err := bd.Collection.FindOne(ctx, filter, opts).Decode(&bidEntityMongo); err != nil {
-		logger.Error("Error trying to find the auction winner", err)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("No bids found for auction with this id = %s", auctionId))
+		}
+
+		logger.ErrorContext(ctx, "Error trying to find the auction winner", err)
 		return nil, internal_error.NewInternalServerError("Error trying to find the auction winner")
 	}
 
-	return &bid_entity.Bid{
-		Id:        bidEntityMongo.Id,
-		UserId:    bidEntityMongo.UserId,
-		AuctionId: bidEntityMongo.AuctionId,
-		Amount:    bidEntityMongo.Amount,
-		Timestamp: time.Unix(bidEntityMongo.Timestamp, 0),
-	}, nil
+	if auctionEntity.HasReserve() && mongodb.AmountFromDecimal(bidEntityMongo.Amount) < auctionEntity.ReservePrice {
+		return nil, internal_error.NewNotFoundError(
+			fmt.Sprintf("Reserve price of auction %s was not met", auctionId))
+	}
+
+	winningBid := bidEntityMongo.toEntity()
+	return &winningBid, nil
 }
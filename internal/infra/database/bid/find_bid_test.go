@@ -0,0 +1,117 @@
+package bid
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/database/auction"
+	"fullcycle-auction_go/internal/money"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"testing"
+	"time"
+)
+
+const benchmarkBidCount = 50000
+
+func seedBenchmarkAuction(b *testing.B) (*BidRepository, string) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	auctionRepository := auction.NewAuctionRepository(conn)
+	b.Cleanup(func() { auctionRepository.Shutdown(ctx) })
+	bidRepository := NewBidRepository(conn, auctionRepository)
+	if err := bidRepository.EnsureIndexes(ctx); err != nil {
+		b.Fatal(err)
+	}
+
+	auctionEntity, _ := auction_entity.CreateAuction(
+		"guitar",
+		"instruments",
+		"electric guitar with case",
+		auction_entity.Used)
+	if err := auctionRepository.CreateAuction(ctx, auctionEntity); err != nil {
+		b.Fatal(err)
+	}
+
+	now := time.Now()
+	seeded := make([]interface{}, 0, benchmarkBidCount)
+	for i := 0; i < benchmarkBidCount; i++ {
+		seeded = append(seeded, BidEntityMongo{
+			Id:        uuid.New().String(),
+			UserId:    uuid.New().String(),
+			AuctionId: auctionEntity.Id,
+			Amount:    mongodb.DecimalFromAmount(money.Amount(i%10000 + 1)),
+			Timestamp: now.Add(time.Duration(i) * time.Millisecond).UnixMilli(),
+		})
+	}
+	if _, err := bidRepository.Collection.InsertMany(ctx, seeded); err != nil {
+		b.Fatal(err)
+	}
+
+	return bidRepository, auctionEntity.Id
+}
+
+// findWinningBidByScan is the previous lookup, kept to compare against.
+func findWinningBidByScan(ctx context.Context, bd *BidRepository, auctionId string) (*BidEntityMongo, error) {
+	cursor, err := bd.Collection.Find(ctx, bson.M{"auction_id": auctionId})
+	if err != nil {
+		return nil, err
+	}
+
+	var bidEntitiesMongo []BidEntityMongo
+	if err := cursor.All(ctx, &bidEntitiesMongo); err != nil {
+		return nil, err
+	}
+
+	var winningBid *BidEntityMongo
+	for i := range bidEntitiesMongo {
+		if winningBid == nil || mongodb.AmountFromDecimal(bidEntitiesMongo[i].Amount) > mongodb.AmountFromDecimal(winningBid.Amount) {
+			winningBid = &bidEntitiesMongo[i]
+		}
+	}
+
+	return winningBid, nil
+}
+
+func BenchmarkFindWinningBid(b *testing.B) {
+	ctx := context.Background()
+	bidRepository, auctionId := seedBenchmarkAuction(b)
+
+	b.Run("scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := findWinningBidByScan(ctx, bidRepository, auctionId); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("sort_and_limit", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := bidRepository.FindWinningBidByAuctionId(ctx, auctionId); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestFindWinningBidWithoutBidsReturnsNotFound(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	auctionRepository := auction.NewAuctionRepository(conn)
+	defer auctionRepository.Shutdown(ctx)
+	bidRepository := NewBidRepository(conn, auctionRepository)
+
+	auctionEntity, _ := auction_entity.CreateAuction(
+		"amplifier",
+		"instruments",
+		"tube guitar amplifier",
+		auction_entity.Used)
+	assert.Nil(t, auctionRepository.CreateAuction(ctx, auctionEntity))
+
+	winningBid, err := bidRepository.FindWinningBidByAuctionId(ctx, auctionEntity.Id)
+	assert.Nil(t, winningBid)
+	assert.True(t, err.IsNotFound())
+}
@@ -0,0 +1,125 @@
+package bid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+// Keys stay pending while their request runs. A pending key older than this
+// belongs to a request that never finished, so a retry may take it over.
+const abandonedIdempotencyKeyAfter = time.Minute
+
+const defaultIdempotencyKeyTTL = 24 * time.Hour
+
+type BidIdempotencyKeyMongo struct {
+	UserId         string          `bson:"user_id"`
+	IdempotencyKey string          `bson:"idempotency_key"`
+	CreatedAt      time.Time       `bson:"created_at"`
+	Completed      bool            `bson:"completed"`
+	Bid            *BidEntityMongo `bson:"bid,omitempty"`
+}
+
+// ReserveIdempotencyKey returns nil once the key is reserved for the caller,
+// or the record left by the earlier request that used it. Keys live in their
+// own collection because the TTL index would otherwise delete the bids.
+func (bd *BidRepository) ReserveIdempotencyKey(
+	ctx context.Context,
+	userId, idempotencyKey string) (*bid_entity.BidIdempotencyRecord, *internal_error.InternalError) {
+	now := bd.Clock.Now()
+	_, err := bd.IdempotencyCollection.InsertOne(ctx, BidIdempotencyKeyMongo{
+		UserId:         userId,
+		IdempotencyKey: idempotencyKey,
+		CreatedAt:      now,
+	})
+	if err == nil {
+		return nil, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		logger.ErrorContext(ctx, "Error trying to reserve bid idempotency key", err)
+		return nil, internal_error.NewInternalServerError("Error trying to reserve idempotency key")
+	}
+
+	filter := bson.M{"user_id": userId, "idempotency_key": idempotencyKey}
+	takeOver := bson.M{
+		"user_id":         userId,
+		"idempotency_key": idempotencyKey,
+		"completed":       false,
+		"created_at":      bson.M{"$lt": now.Add(-abandonedIdempotencyKeyAfter)},
+	}
+	result, err := bd.IdempotencyCollection.UpdateOne(ctx, takeOver, bson.M{"$set": bson.M{"created_at": now}})
+	if err != nil {
+		logger.ErrorContext(ctx, "Error trying to reserve bid idempotency key", err)
+		return nil, internal_error.NewInternalServerError("Error trying to reserve idempotency key")
+	}
+	if result.ModifiedCount > 0 {
+		return nil, nil
+	}
+
+	var keyMongo BidIdempotencyKeyMongo
+	if err := bd.IdempotencyCollection.FindOne(ctx, filter).Decode(&keyMongo); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return bd.ReserveIdempotencyKey(ctx, userId, idempotencyKey)
+		}
+
+		logger.ErrorContext(ctx, "Error trying to find bid idempotency key", err)
+		return nil, internal_error.NewInternalServerError("Error trying to reserve idempotency key")
+	}
+
+	record := &bid_entity.BidIdempotencyRecord{Completed: keyMongo.Completed}
+	if keyMongo.Bid != nil {
+		bidEntity := keyMongo.Bid.toEntity()
+		record.Bid = &bidEntity
+	}
+
+	return record, nil
+}
+
+func (bd *BidRepository) CompleteIdempotencyKey(
+	ctx context.Context,
+	userId, idempotencyKey string,
+	bidEntity *bid_entity.Bid) *internal_error.InternalError {
+	set := bson.M{"completed": true}
+	if bidEntity != nil {
+		set["bid"] = newBidEntityMongo(*bidEntity)
+	}
+
+	if _, err := bd.IdempotencyCollection.UpdateOne(ctx,
+		bson.M{"user_id": userId, "idempotency_key": idempotencyKey},
+		bson.M{"$set": set}); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to complete bid idempotency key of user %s", userId), err)
+		return internal_error.NewInternalServerError("Error trying to complete idempotency key")
+	}
+
+	return nil
+}
+
+// ReleaseIdempotencyKey drops a pending key after its request failed, so the
+// client can retry with the same key.
+func (bd *BidRepository) ReleaseIdempotencyKey(
+	ctx context.Context, userId, idempotencyKey string) *internal_error.InternalError {
+	if _, err := bd.IdempotencyCollection.DeleteOne(ctx, bson.M{
+		"user_id":         userId,
+		"idempotency_key": idempotencyKey,
+		"completed":       false,
+	}); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to release bid idempotency key of user %s", userId), err)
+		return internal_error.NewInternalServerError("Error trying to release idempotency key")
+	}
+
+	return nil
+}
+
+// WithIdempotencyKeyTTL sets how long an idempotency key is kept before a
+// retry with it counts as a new bid.
+func WithIdempotencyKeyTTL(ttl time.Duration) BidRepositoryOption {
+	return func(repository *BidRepository) {
+		repository.idempotencyKeyTTL = ttl
+	}
+}
@@ -0,0 +1,52 @@
+package bid
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"time"
+)
+
+// InstrumentedBidRepository records the latency of the calls on the bid
+// path. Every other method goes straight to the wrapped repository.
+type InstrumentedBidRepository struct {
+	bid_entity.BidEntityRepository
+}
+
+func NewInstrumentedBidRepository(repository bid_entity.BidEntityRepository) *InstrumentedBidRepository {
+	return &InstrumentedBidRepository{BidEntityRepository: repository}
+}
+
+func (ir *InstrumentedBidRepository) CreateBid(
+	ctx context.Context, bidEntities []bid_entity.Bid) *internal_error.InternalError {
+	defer mongodb.ObserveCall("bid", "CreateBid", time.Now())
+	return ir.BidEntityRepository.CreateBid(ctx, bidEntities)
+}
+
+func (ir *InstrumentedBidRepository) FindBidByAuctionId(
+	ctx context.Context,
+	auctionId string,
+	options bid_entity.FindBidsOptions) (*bid_entity.BidPage, *internal_error.InternalError) {
+	defer mongodb.ObserveCall("bid", "FindBidByAuctionId", time.Now())
+	return ir.BidEntityRepository.FindBidByAuctionId(ctx, auctionId, options)
+}
+
+func (ir *InstrumentedBidRepository) FindWinningBidByAuctionId(
+	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	defer mongodb.ObserveCall("bid", "FindWinningBidByAuctionId", time.Now())
+	return ir.BidEntityRepository.FindWinningBidByAuctionId(ctx, auctionId)
+}
+
+func (ir *InstrumentedBidRepository) FindHighestBidByAuctionId(
+	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	defer mongodb.ObserveCall("bid", "FindHighestBidByAuctionId", time.Now())
+	return ir.BidEntityRepository.FindHighestBidByAuctionId(ctx, auctionId)
+}
+
+func (ir *InstrumentedBidRepository) ReserveIdempotencyKey(
+	ctx context.Context,
+	userId, idempotencyKey string) (*bid_entity.BidIdempotencyRecord, *internal_error.InternalError) {
+	defer mongodb.ObserveCall("bid", "ReserveIdempotencyKey", time.Now())
+	return ir.BidEntityRepository.ReserveIdempotencyKey(ctx, userId, idempotencyKey)
+}
@@ -0,0 +1,76 @@
+package bid
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type MaxBidEntityMongo struct {
+	UserId    string               `bson:"user_id"`
+	AuctionId string               `bson:"auction_id"`
+	MaxAmount primitive.Decimal128 `bson:"max_amount"`
+	Timestamp int64                `bson:"timestamp"`
+}
+
+func (bd *BidRepository) SaveMaxBid(
+	ctx context.Context, maxBid bid_entity.MaxBid) *internal_error.InternalError {
+	filter := bson.M{"auction_id": maxBid.AuctionId, "user_id": maxBid.UserId}
+	update := bson.M{"$set": bson.M{
+		"max_amount": mongodb.DecimalFromAmount(maxBid.MaxAmount),
+		"timestamp":  maxBid.Timestamp.UnixNano(),
+	}}
+
+	if _, err := bd.MaxBidCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to save max bid for auction %s", maxBid.AuctionId), err)
+		return internal_error.NewInternalServerError("Error trying to save max bid")
+	}
+
+	return nil
+}
+
+func (bd *BidRepository) FindMaxBidsByAuctionId(
+	ctx context.Context, auctionId string) ([]bid_entity.MaxBid, *internal_error.InternalError) {
+	opts := options.Find().SetSort(bson.D{{Key: "max_amount", Value: -1}, {Key: "timestamp", Value: 1}})
+
+	cursor, err := bd.MaxBidCollection.Find(ctx, bson.M{"auction_id": auctionId}, opts)
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to find max bids by auctionId %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find max bids")
+	}
+
+	var maxBidsMongo []MaxBidEntityMongo
+	if err := cursor.All(ctx, &maxBidsMongo); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to decode max bids by auctionId %s", auctionId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find max bids")
+	}
+
+	var maxBids []bid_entity.MaxBid
+	for _, maxBidMongo := range maxBidsMongo {
+		maxBids = append(maxBids, bid_entity.MaxBid{
+			UserId:    maxBidMongo.UserId,
+			AuctionId: maxBidMongo.AuctionId,
+			MaxAmount: mongodb.AmountFromDecimal(maxBidMongo.MaxAmount),
+			Timestamp: time.Unix(0, maxBidMongo.Timestamp),
+		})
+	}
+
+	return maxBids, nil
+}
+
+func (bd *BidRepository) DeleteMaxBid(
+	ctx context.Context, auctionId, userId string) *internal_error.InternalError {
+	if _, err := bd.MaxBidCollection.DeleteOne(ctx, bson.M{"auction_id": auctionId, "user_id": userId}); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to delete max bid for auction %s", auctionId), err)
+		return internal_error.NewInternalServerError("Error trying to delete max bid")
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package bid
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/configuration/logger"
+)
+
+// MigrateAmountsToDecimal converts bid and max bid amounts written as doubles
+// by earlier versions into Decimal128.
+func (bd *BidRepository) MigrateAmountsToDecimal(ctx context.Context) error {
+	converted, err := mongodb.ConvertFieldsToDecimal(ctx, bd.Collection, "amount")
+	if err != nil {
+		logger.ErrorContext(ctx, "Error trying to migrate bid amounts to decimal", err)
+		return err
+	}
+
+	convertedMaxBids, err := mongodb.ConvertFieldsToDecimal(ctx, bd.MaxBidCollection, "max_amount")
+	if err != nil {
+		logger.ErrorContext(ctx, "Error trying to migrate max bid amounts to decimal", err)
+		return err
+	}
+
+	if converted+convertedMaxBids > 0 {
+		logger.InfoContext(ctx, fmt.Sprintf("%d bid amounts migrated to decimal", converted+convertedMaxBids))
+	}
+
+	return nil
+}
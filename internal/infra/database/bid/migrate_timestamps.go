@@ -0,0 +1,34 @@
+package bid
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// legacySecondsLimit separates bid timestamps in seconds from those in
+// milliseconds.
+const legacySecondsLimit = int64(100_000_000_000)
+
+// MigrateTimestampsToMillis converts bid timestamps written in seconds by
+// earlier versions into milliseconds, so they sort together with new bids.
+func (bd *BidRepository) MigrateTimestampsToMillis(ctx context.Context) error {
+	filter := bson.M{"timestamp": bson.M{"$lt": legacySecondsLimit}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"timestamp": bson.M{"$multiply": bson.A{"$timestamp", int64(1000)}}}}},
+	}
+
+	result, err := bd.Collection.UpdateMany(ctx, filter, pipeline)
+	if err != nil {
+		logger.ErrorContext(ctx, "Error trying to migrate bid timestamps to milliseconds", err)
+		return err
+	}
+
+	if result.ModifiedCount > 0 {
+		logger.InfoContext(ctx, fmt.Sprintf("%d bid timestamps migrated to milliseconds", result.ModifiedCount))
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package bid
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/database/auction"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"testing"
+	"time"
+)
+
+func TestBidTimestampsKeepTheirMilliseconds(t *testing.T) {
+	placedAt := time.UnixMilli(1709296200123)
+
+	bidEntityMongo := newBidEntityMongo(bid_entity.Bid{Id: "bid", Timestamp: placedAt})
+	assert.Equal(t, int64(1709296200123), bidEntityMongo.Timestamp)
+	assert.True(t, placedAt.Equal(bidEntityMongo.toEntity().Timestamp))
+
+	legacy := BidEntityMongo{Id: "legacy", Timestamp: 1709296200}
+	assert.True(t, time.Unix(1709296200, 0).Equal(legacy.toEntity().Timestamp))
+}
+
+func TestMigrateTimestampsToMillisConvertsOnlyLegacyBids(t *testing.T) {
+	ctx := context.Background()
+	conn := connectTestDatabase()
+
+	auctionRepository := auction.NewAuctionRepository(conn)
+	defer auctionRepository.Shutdown(ctx)
+	bidRepository := NewBidRepository(conn, auctionRepository)
+
+	legacyId, currentId := uuid.New().String(), uuid.New().String()
+	_, err := bidRepository.Collection.InsertMany(ctx, []interface{}{
+		BidEntityMongo{Id: legacyId, AuctionId: uuid.New().String(), Amount: mongodb.DecimalFromAmount(100),
+			Timestamp: 1709296200},
+		BidEntityMongo{Id: currentId, AuctionId: uuid.New().String(), Amount: mongodb.DecimalFromAmount(100),
+			Timestamp: 1709296200123},
+	})
+	assert.Nil(t, err)
+
+	assert.Nil(t, bidRepository.MigrateTimestampsToMillis(ctx))
+	assert.Nil(t, bidRepository.MigrateTimestampsToMillis(ctx))
+
+	for id, expected := range map[string]int64{legacyId: 1709296200000, currentId: 1709296200123} {
+		var bidEntityMongo BidEntityMongo
+		assert.Nil(t, bidRepository.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&bidEntityMongo))
+		assert.Equal(t, expected, bidEntityMongo.Timestamp)
+	}
+}
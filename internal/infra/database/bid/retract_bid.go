@@ -0,0 +1,45 @@
+package bid
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RetractBid upserts so that a bid still waiting in the insert batch can be
+// retracted; the batch insert then skips it as a duplicate.
+func (bd *BidRepository) RetractBid(
+	ctx context.Context, bidEntity bid_entity.Bid) *internal_error.InternalError {
+	filter := bson.M{"_id": bidEntity.Id, "retracted": bson.M{"$ne": true}}
+	update := bson.M{
+		"$set": bson.M{
+			"retracted":    true,
+			"retracted_at": bd.Clock.Now().Unix(),
+		},
+		"$setOnInsert": bson.M{
+			"user_id":    bidEntity.UserId,
+			"auction_id": bidEntity.AuctionId,
+			"amount":     mongodb.DecimalFromAmount(bidEntity.Amount),
+			"timestamp":  bidEntity.Timestamp.UnixMilli(),
+			"sequence":   bidEntity.Sequence,
+			"auto":       bidEntity.Auto,
+		},
+	}
+
+	_, err := bd.Collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return internal_error.NewConflictError("Bid was already retracted")
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to retract bid %s", bidEntity.Id), err)
+		return internal_error.NewInternalServerError("Error trying to retract bid")
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package bid
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const defaultStreamBatchSize = 500
+
+// ForEachBidByAuctionId walks every bid of an auction in the order they were
+// accepted, retracted ones included, reading them from the cursor in batches.
+func (bd *BidRepository) ForEachBidByAuctionId(
+	ctx context.Context,
+	auctionId string,
+	fn func(bid_entity.Bid) error) *internal_error.InternalError {
+	opts := options.Find().
+		SetSort(bidSort(bid_entity.BidOrderChronological)).
+		SetBatchSize(bd.streamBatchSize)
+
+	cursor, err := bd.Collection.Find(ctx, bson.M{"auction_id": auctionId}, opts)
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId), err)
+		return internal_error.NewInternalServerError(
+			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId))
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var bidEntityMongo BidEntityMongo
+		if err := cursor.Decode(&bidEntityMongo); err != nil {
+			logger.ErrorContext(ctx, "Error decoding bid", err)
+			return internal_error.NewInternalServerError("Error decoding bids")
+		}
+
+		if err := fn(bidEntityMongo.toEntity()); err != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf("Error processing bid %s", bidEntityMongo.Id), err)
+			return internal_error.NewInternalServerError("Error processing bids")
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating bids", err)
+		return internal_error.NewInternalServerError("Error iterating bids")
+	}
+
+	return nil
+}
+
+// WithStreamBatchSize sets how many bids each round trip fetches while
+// streaming them.
+func WithStreamBatchSize(batchSize int) BidRepositoryOption {
+	return func(repository *BidRepository) {
+		repository.streamBatchSize = int32(batchSize)
+	}
+}
@@ -0,0 +1,50 @@
+package bid
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (bd *BidRepository) FindAuctionIdsByUserId(
+	ctx context.Context, userId string) ([]string, *internal_error.InternalError) {
+	values, err := bd.Collection.Distinct(ctx, "auction_id", bson.M{"user_id": userId})
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to find the auctions user %s bid on", userId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find the auctions of the user bids")
+	}
+
+	auctionIds := make([]string, 0, len(values))
+	for _, value := range values {
+		if auctionId, ok := value.(string); ok {
+			auctionIds = append(auctionIds, auctionId)
+		}
+	}
+
+	return auctionIds, nil
+}
+
+// VoidBidsByUserId keeps the bids of the user on auctionIds in the history
+// but stops them from counting towards the winner.
+func (bd *BidRepository) VoidBidsByUserId(
+	ctx context.Context, userId string, auctionIds []string) *internal_error.InternalError {
+	if len(auctionIds) == 0 {
+		return nil
+	}
+
+	filter := bson.M{
+		"user_id":    userId,
+		"auction_id": bson.M{"$in": auctionIds},
+		"voided":     bson.M{"$ne": true},
+	}
+	update := bson.M{"$set": bson.M{"voided": true, "voided_at": bd.Clock.Now().Unix()}}
+
+	if _, err := bd.Collection.UpdateMany(ctx, filter, update); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to void the bids of user %s", userId), err)
+		return internal_error.NewInternalServerError("Error trying to void the user bids")
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package user
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/config"
+	"fullcycle-auction_go/internal/cache"
+	"fullcycle-auction_go/internal/clock"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/metrics"
+	"fullcycle-auction_go/internal/money"
+	"time"
+)
+
+var (
+	userCacheHits   = metrics.Default.NewCounter("user_cache_hits_total", "User lookups answered by the cache.")
+	userCacheMisses = metrics.Default.NewCounter("user_cache_misses_total", "User lookups that went to MongoDB.")
+)
+
+// CachedUserRepository answers FindUserById from userCache and forgets a user
+// whenever their profile, role, status, balance or rating is changed through
+// it.
+// Holds are left out since every bid moves them, so the held amount of a
+// cached user may lag behind. Changes made by other instances show up once the
+// cached copy expires.
+type CachedUserRepository struct {
+	user_entity.UserRepositoryInterface
+	userCache cache.Cache
+}
+
+func NewCachedUserRepository(
+	repository user_entity.UserRepositoryInterface, userCache cache.Cache) *CachedUserRepository {
+	return &CachedUserRepository{UserRepositoryInterface: repository, userCache: userCache}
+}
+
+// CacheUserLookups wraps repository with an in-memory cache of cfg.Size users
+// kept for cfg.TTL when the cache is enabled, and returns it unchanged
+// otherwise.
+func CacheUserLookups(
+	repository user_entity.UserRepositoryInterface, cfg config.UserCacheConfig) user_entity.UserRepositoryInterface {
+	if !cfg.Enabled {
+		return repository
+	}
+
+	return NewCachedUserRepository(repository, cache.NewLRUCache(cfg.Size, cfg.TTL, clock.NewRealClock()))
+}
+
+func (cr *CachedUserRepository) FindUserById(
+	ctx context.Context, userId string) (*user_entity.User, *internal_error.InternalError) {
+	if cached, ok := cr.userCache.Get(ctx, userId); ok {
+		userCacheHits.Inc()
+		return cloneUser(cached.(*user_entity.User)), nil
+	}
+	userCacheMisses.Inc()
+
+	userEntity, err := cr.UserRepositoryInterface.FindUserById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	cr.userCache.Set(ctx, userId, cloneUser(userEntity))
+	return userEntity, nil
+}
+
+func (cr *CachedUserRepository) UpdateUser(
+	ctx context.Context,
+	userId string,
+	fields map[string]interface{}) (*user_entity.User, *internal_error.InternalError) {
+	defer cr.userCache.Delete(ctx, userId)
+	return cr.UserRepositoryInterface.UpdateUser(ctx, userId, fields)
+}
+
+func (cr *CachedUserRepository) SetRole(
+	ctx context.Context, userId string, role user_entity.Role) *internal_error.InternalError {
+	defer cr.userCache.Delete(ctx, userId)
+	return cr.UserRepositoryInterface.SetRole(ctx, userId, role)
+}
+
+func (cr *CachedUserRepository) SetStatus(
+	ctx context.Context,
+	userId string,
+	status user_entity.UserStatus,
+	reason string,
+	until time.Time) *internal_error.InternalError {
+	defer cr.userCache.Delete(ctx, userId)
+	return cr.UserRepositoryInterface.SetStatus(ctx, userId, status, reason, until)
+}
+
+func (cr *CachedUserRepository) Credit(
+	ctx context.Context, userId string, amount money.Amount) *internal_error.InternalError {
+	defer cr.userCache.Delete(ctx, userId)
+	return cr.UserRepositoryInterface.Credit(ctx, userId, amount)
+}
+
+func (cr *CachedUserRepository) Debit(
+	ctx context.Context, userId string, amount money.Amount) *internal_error.InternalError {
+	defer cr.userCache.Delete(ctx, userId)
+	return cr.UserRepositoryInterface.Debit(ctx, userId, amount)
+}
+
+func (cr *CachedUserRepository) RateSeller(
+	ctx context.Context, rating user_entity.Rating) *internal_error.InternalError {
+	defer cr.userCache.Delete(ctx, rating.SellerId)
+	return cr.UserRepositoryInterface.RateSeller(ctx, rating)
+}
+
+// cloneUser keeps callers from changing the cached copy through the balance
+// pointer.
+func cloneUser(userEntity *user_entity.User) *user_entity.User {
+	clone := *userEntity
+	if userEntity.Balance != nil {
+		balance := *userEntity.Balance
+		clone.Balance = &balance
+	}
+
+	return &clone
+}
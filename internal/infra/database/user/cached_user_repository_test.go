@@ -0,0 +1,58 @@
+package user
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/cache"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type countingUserRepository struct {
+	user_entity.UserRepositoryInterface
+	user    user_entity.User
+	lookups int
+}
+
+func (f *countingUserRepository) FindUserById(
+	ctx context.Context, userId string) (*user_entity.User, *internal_error.InternalError) {
+	f.lookups++
+	userEntity := f.user
+	return &userEntity, nil
+}
+
+func (f *countingUserRepository) SetStatus(
+	ctx context.Context,
+	userId string,
+	status user_entity.UserStatus,
+	reason string,
+	until time.Time) *internal_error.InternalError {
+	f.user.Status = status
+	return nil
+}
+
+func TestCachedUserRepositoryForgetsUsersWhenTheyChange(t *testing.T) {
+	ctx := context.Background()
+	repository := &countingUserRepository{user: user_entity.User{Id: "user", Status: user_entity.ActiveStatus}}
+	cachedRepository := NewCachedUserRepository(
+		repository, cache.NewLRUCache(10, time.Minute, fakeclock.New(time.Now())))
+	hits, misses := userCacheHits.Value(), userCacheMisses.Value()
+
+	first, _ := cachedRepository.FindUserById(ctx, "user")
+	first.Status = user_entity.BannedStatus
+	second, _ := cachedRepository.FindUserById(ctx, "user")
+
+	assert.Equal(t, 1, repository.lookups)
+	assert.Equal(t, user_entity.ActiveStatus, second.Status)
+	assert.Equal(t, hits+1, userCacheHits.Value())
+	assert.Equal(t, misses+1, userCacheMisses.Value())
+
+	assert.Nil(t, cachedRepository.SetStatus(ctx, "user", user_entity.SuspendedStatus, "chargeback", time.Time{}))
+	third, _ := cachedRepository.FindUserById(ctx, "user")
+
+	assert.Equal(t, 2, repository.lookups)
+	assert.Equal(t, user_entity.SuspendedStatus, third.Status)
+}
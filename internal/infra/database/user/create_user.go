@@ -0,0 +1,32 @@
+package user
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (ur *UserRepository) CreateUser(
+	ctx context.Context, userEntity *user_entity.User) *internal_error.InternalError {
+	userEntityMongo := &UserEntityMongo{
+		Id:           userEntity.Id,
+		Name:         userEntity.Name,
+		Email:        userEntity.Email,
+		PasswordHash: userEntity.PasswordHash,
+		Role:         string(userEntity.Role),
+		Status:       string(userEntity.Status),
+	}
+
+	_, err := ur.Collection.InsertOne(ctx, userEntityMongo)
+	if mongo.IsDuplicateKeyError(err) {
+		return internal_error.NewConflictError("A user with this email already exists")
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, "Error trying to insert user", err)
+		return internal_error.NewInternalServerError("Error trying to insert user")
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Credit adds amount to the user's balance. Crediting a user without a
+// balance gives them one, so from then on their bids are limited by it.
+func (ur *UserRepository) Credit(
+	ctx context.Context, userId string, amount money.Amount) *internal_error.InternalError {
+	if amount <= 0 {
+		return internal_error.NewFieldBadRequestError(
+			"Invalid wallet amount", "amount", "amount must be greater than zero")
+	}
+
+	update := bson.M{"$inc": bson.M{"balance": mongodb.DecimalFromAmount(amount)}}
+
+	credited, err := ur.updateWallet(ctx, bson.M{"_id": userId}, update,
+		user_entity.NewWalletTransaction(userId, "", user_entity.WalletCredit, amount))
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to credit user %s", userId), err)
+		return internal_error.NewInternalServerError("Error trying to credit user")
+	}
+	if !credited {
+		return internal_error.NewNotFoundError(fmt.Sprintf("User not found with this id = %s", userId))
+	}
+
+	return nil
+}
+
+// Debit takes amount from the user's balance. Held funds cannot be debited,
+// so the balance never drops below what the user's leading bids hold.
+func (ur *UserRepository) Debit(
+	ctx context.Context, userId string, amount money.Amount) *internal_error.InternalError {
+	if amount <= 0 {
+		return internal_error.NewFieldBadRequestError(
+			"Invalid wallet amount", "amount", "amount must be greater than zero")
+	}
+
+	filter := bson.M{
+		"_id":     userId,
+		"balance": bson.M{"$exists": true},
+		"$expr": bson.M{"$lte": bson.A{
+			bson.M{"$add": bson.A{heldAmountOrZero(), mongodb.DecimalFromAmount(amount)}},
+			"$balance",
+		}},
+	}
+	update := bson.M{"$inc": bson.M{"balance": mongodb.DecimalFromAmount(-amount)}}
+
+	debited, err := ur.updateWallet(ctx, filter, update,
+		user_entity.NewWalletTransaction(userId, "", user_entity.WalletDebit, amount))
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to debit user %s", userId), err)
+		return internal_error.NewInternalServerError("Error trying to debit user")
+	}
+	if debited {
+		return nil
+	}
+
+	return ur.insufficientBalance(ctx, userId, "Insufficient balance to debit")
+}
@@ -0,0 +1,56 @@
+package user
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (ur *UserRepository) EnsureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().
+				SetName("email_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"email": bson.M{"$exists": true}}),
+		},
+	}
+
+	if _, err := ur.Collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		logger.ErrorContext(ctx, "Error trying to create user indexes", err)
+		return err
+	}
+
+	transactionIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("user_id_timestamp_desc_id_desc"),
+		},
+	}
+
+	if _, err := ur.TransactionCollection.Indexes().CreateMany(ctx, transactionIndexes); err != nil {
+		logger.ErrorContext(ctx, "Error trying to create wallet transaction indexes", err)
+		return err
+	}
+
+	ratingIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "auction_id", Value: 1}},
+			Options: options.Index().SetName("auction_id_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "seller_id", Value: 1}, {Key: "timestamp", Value: -1}},
+			Options: options.Index().SetName("seller_id_timestamp_desc"),
+		},
+	}
+
+	if _, err := ur.RatingCollection.Indexes().CreateMany(ctx, ratingIndexes); err != nil {
+		logger.ErrorContext(ctx, "Error trying to create rating indexes", err)
+		return err
+	}
+
+	return nil
+}
@@ -4,25 +4,55 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"fullcycle-auction_go/configuration/database/mongodb"
 	"fullcycle-auction_go/configuration/logger"
 	"fullcycle-auction_go/internal/entity/user_entity"
 	"fullcycle-auction_go/internal/internal_error"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"time"
 )
 
 type UserEntityMongo struct {
-	Id   string `bson:"_id"`
-	Name string `bson:"name"`
+	Id           string                `bson:"_id"`
+	Name         string                `bson:"name"`
+	Email        string                `bson:"email,omitempty"`
+	PasswordHash string                `bson:"password_hash,omitempty"`
+	Role         string                `bson:"role,omitempty"`
+	Balance      *primitive.Decimal128 `bson:"balance,omitempty"`
+	HeldAmount   primitive.Decimal128  `bson:"held_amount,omitempty"`
+
+	Status         string `bson:"status,omitempty"`
+	StatusReason   string `bson:"status_reason,omitempty"`
+	SuspendedUntil int64  `bson:"suspended_until,omitempty"`
+
+	NotificationPreferences *NotificationPreferencesMongo `bson:"notification_preferences,omitempty"`
+	UpdatedAt               int64                         `bson:"updated_at,omitempty"`
+
+	AverageRating float64 `bson:"average_rating,omitempty"`
+	RatingCount   int64   `bson:"rating_count,omitempty"`
+}
+
+// NotificationPreferencesMongo leaves out the preferences the user never
+// changed, so they keep the default.
+type NotificationPreferencesMongo struct {
+	Outbid               *bool `bson:"outbid,omitempty"`
+	WatchedAuctionEnding *bool `bson:"watched_auction_ending,omitempty"`
+	AuctionWon           *bool `bson:"auction_won,omitempty"`
 }
 
 type UserRepository struct {
-	Collection *mongo.Collection
+	Collection            *mongo.Collection
+	TransactionCollection *mongo.Collection
+	RatingCollection      *mongo.Collection
 }
 
 func NewUserRepository(database *mongo.Database) *UserRepository {
 	return &UserRepository{
-		Collection: database.Collection("users"),
+		Collection:            database.Collection("users"),
+		TransactionCollection: database.Collection("wallet_transactions"),
+		RatingCollection:      database.Collection("ratings"),
 	}
 }
 
@@ -34,19 +64,132 @@ func (ur *UserRepository) FindUserById(
 	err := ur.Collection.FindOne(ctx, filter).Decode(&userEntityMongo)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			logger.Error(fmt.Sprintf("User not found with this id = %s", userId), err)
+			logger.ErrorContext(ctx, fmt.Sprintf("User not found with this id = %s", userId), err)
 			return nil, internal_error.NewNotFoundError(
 				fmt.Sprintf("User not found with this id = %s", userId))
 		}
 
-		logger.Error("Error trying to find user by userId", err)
+		logger.ErrorContext(ctx, "Error trying to find user by userId", err)
 		return nil, internal_error.NewInternalServerError("Error trying to find user by userId")
 	}
 
+	return userEntityMongo.toEntity(), nil
+}
+
+func (ur *UserRepository) FindUserByEmail(
+	ctx context.Context, email string) (*user_entity.User, *internal_error.InternalError) {
+	email = user_entity.NormalizeEmail(email)
+
+	var userEntityMongo UserEntityMongo
+	err := ur.Collection.FindOne(ctx, bson.M{"email": email}).Decode(&userEntityMongo)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("User not found with this email = %s", email))
+		}
+
+		logger.ErrorContext(ctx, "Error trying to find user by email", err)
+		return nil, internal_error.NewInternalServerError("Error trying to find user by email")
+	}
+
+	return userEntityMongo.toEntity(), nil
+}
+
+func (ur *UserRepository) SetRole(
+	ctx context.Context, userId string, role user_entity.Role) *internal_error.InternalError {
+	result, err := ur.Collection.UpdateOne(ctx, bson.M{"_id": userId}, bson.M{"$set": bson.M{"role": role}})
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to set the role of user %s", userId), err)
+		return internal_error.NewInternalServerError("Error trying to set user role")
+	}
+	if result.MatchedCount == 0 {
+		return internal_error.NewNotFoundError(fmt.Sprintf("User not found with this id = %s", userId))
+	}
+
+	return nil
+}
+
+// SetStatus replaces the status of the user, clearing the reason and expiry
+// of the previous one.
+func (ur *UserRepository) SetStatus(
+	ctx context.Context,
+	userId string,
+	status user_entity.UserStatus,
+	reason string,
+	until time.Time) *internal_error.InternalError {
+	set := bson.M{"status": status}
+	unset := bson.M{}
+	if reason != "" {
+		set["status_reason"] = reason
+	} else {
+		unset["status_reason"] = ""
+	}
+	if !until.IsZero() {
+		set["suspended_until"] = until.Unix()
+	} else {
+		unset["suspended_until"] = ""
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	result, err := ur.Collection.UpdateOne(ctx, bson.M{"_id": userId}, update)
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to set the status of user %s", userId), err)
+		return internal_error.NewInternalServerError("Error trying to set user status")
+	}
+	if result.MatchedCount == 0 {
+		return internal_error.NewNotFoundError(fmt.Sprintf("User not found with this id = %s", userId))
+	}
+
+	return nil
+}
+
+func (um *UserEntityMongo) toEntity() *user_entity.User {
 	userEntity := &user_entity.User{
-		Id:   userEntityMongo.Id,
-		Name: userEntityMongo.Name,
+		Id:           um.Id,
+		Name:         um.Name,
+		Email:        um.Email,
+		PasswordHash: um.PasswordHash,
+		Role:         user_entity.BuyerRole,
+		HeldAmount:   mongodb.AmountFromDecimal(um.HeldAmount),
+		Status:       user_entity.ActiveStatus,
+		StatusReason: um.StatusReason,
+
+		AverageRating: um.AverageRating,
+		RatingCount:   um.RatingCount,
+	}
+	if um.Role != "" {
+		userEntity.Role = user_entity.Role(um.Role)
+	}
+	if um.Status != "" {
+		userEntity.Status = user_entity.UserStatus(um.Status)
+	}
+	if um.SuspendedUntil != 0 {
+		userEntity.SuspendedUntil = time.Unix(um.SuspendedUntil, 0)
+	}
+	if um.UpdatedAt != 0 {
+		userEntity.UpdatedAt = time.Unix(um.UpdatedAt, 0)
+	}
+
+	userEntity.NotificationPreferences = user_entity.DefaultNotificationPreferences()
+	if preferences := um.NotificationPreferences; preferences != nil {
+		if preferences.Outbid != nil {
+			userEntity.NotificationPreferences.Outbid = *preferences.Outbid
+		}
+		if preferences.WatchedAuctionEnding != nil {
+			userEntity.NotificationPreferences.WatchedAuctionEnding = *preferences.WatchedAuctionEnding
+		}
+		if preferences.AuctionWon != nil {
+			userEntity.NotificationPreferences.AuctionWon = *preferences.AuctionWon
+		}
+	}
+	if um.Balance != nil {
+		balance := mongodb.AmountFromDecimal(*um.Balance)
+		userEntity.Balance = &balance
 	}
 
-	return userEntity, nil
+	return userEntity
 }
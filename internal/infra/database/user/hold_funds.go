@@ -0,0 +1,134 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// HoldFunds reserves amount of the user's balance for a leading bid. The
+// balance check and the increment happen in one update, so concurrent bids
+// can never hold more than the balance. Users without a balance have no limit.
+func (ur *UserRepository) HoldFunds(
+	ctx context.Context, userId, auctionId string, amount money.Amount) *internal_error.InternalError {
+	if amount <= 0 {
+		return nil
+	}
+
+	filter := bson.M{
+		"_id": userId,
+		"$or": bson.A{
+			bson.M{"balance": bson.M{"$exists": false}},
+			bson.M{"$expr": bson.M{"$lte": bson.A{
+				bson.M{"$add": bson.A{heldAmountOrZero(), mongodb.DecimalFromAmount(amount)}},
+				"$balance",
+			}}},
+		},
+	}
+	update := bson.M{"$inc": bson.M{"held_amount": mongodb.DecimalFromAmount(amount)}}
+
+	held, err := ur.updateWallet(ctx, filter, update,
+		user_entity.NewWalletTransaction(userId, auctionId, user_entity.WalletHold, amount))
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to hold funds for user %s", userId), err)
+		return internal_error.NewInternalServerError("Error trying to hold funds")
+	}
+	if held {
+		return nil
+	}
+
+	return ur.insufficientBalance(ctx, userId, "Insufficient balance to place this bid")
+}
+
+// ReleaseFunds gives back a hold once the user no longer leads the auction it
+// was taken for. The held amount never goes below zero.
+func (ur *UserRepository) ReleaseFunds(
+	ctx context.Context, userId, auctionId string, amount money.Amount) *internal_error.InternalError {
+	if amount <= 0 {
+		return nil
+	}
+
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"held_amount": heldAmountMinus(amount)}}},
+	}
+
+	if _, err := ur.updateWallet(ctx, bson.M{"_id": userId}, update,
+		user_entity.NewWalletTransaction(userId, auctionId, user_entity.WalletRelease, amount)); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to release funds for user %s", userId), err)
+		return internal_error.NewInternalServerError("Error trying to release funds")
+	}
+
+	return nil
+}
+
+// CaptureFunds turns the hold the user took on an auction they won into a
+// debit of their balance. Users without a balance only lose the hold.
+func (ur *UserRepository) CaptureFunds(
+	ctx context.Context, userId, auctionId string, amount money.Amount) *internal_error.InternalError {
+	if amount <= 0 {
+		return nil
+	}
+
+	filter := bson.M{
+		"_id":         userId,
+		"held_amount": bson.M{"$gte": mongodb.DecimalFromAmount(amount)},
+	}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"held_amount": heldAmountMinus(amount),
+			"balance": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{bson.M{"$type": "$balance"}, "missing"}},
+				"$$REMOVE",
+				bson.M{"$subtract": bson.A{"$balance", mongodb.DecimalFromAmount(amount)}},
+			}},
+		}}},
+	}
+
+	captured, err := ur.updateWallet(ctx, filter, update,
+		user_entity.NewWalletTransaction(userId, auctionId, user_entity.WalletCapture, amount))
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to capture funds of user %s", userId), err)
+		return internal_error.NewInternalServerError("Error trying to capture funds")
+	}
+	if !captured {
+		return internal_error.NewConflictError(
+			fmt.Sprintf("User %s does not hold %s for auction %s", userId, amount, auctionId))
+	}
+
+	return nil
+}
+
+func (ur *UserRepository) insufficientBalance(
+	ctx context.Context, userId, message string) *internal_error.InternalError {
+	userEntity, findErr := ur.FindUserById(ctx, userId)
+	if findErr != nil {
+		return findErr
+	}
+
+	var available money.Amount
+	if userAvailable := userEntity.Available(); userAvailable != nil {
+		available = *userAvailable
+	}
+
+	return internal_error.NewFieldBadRequestError(
+		message,
+		"amount",
+		fmt.Sprintf("available balance is %s", available))
+}
+
+func heldAmountOrZero() bson.M {
+	return bson.M{"$ifNull": bson.A{"$held_amount", mongodb.DecimalFromAmount(0)}}
+}
+
+func heldAmountMinus(amount money.Amount) bson.M {
+	return bson.M{"$max": bson.A{
+		mongodb.DecimalFromAmount(0),
+		bson.M{"$subtract": bson.A{heldAmountOrZero(), mongodb.DecimalFromAmount(amount)}},
+	}}
+}
@@ -0,0 +1,75 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type RatingEntityMongo struct {
+	Id        string `bson:"_id"`
+	AuctionId string `bson:"auction_id"`
+	RaterId   string `bson:"rater_id"`
+	SellerId  string `bson:"seller_id"`
+	Stars     int    `bson:"stars"`
+	Comment   string `bson:"comment,omitempty"`
+	Timestamp int64  `bson:"timestamp"`
+}
+
+// RateSeller stores the rating and folds it into the seller's average in
+// the same transaction. The average is recomputed from the running total by
+// the update itself, so concurrent ratings cannot overwrite each other.
+func (ur *UserRepository) RateSeller(ctx context.Context, rating user_entity.Rating) *internal_error.InternalError {
+	ratingEntityMongo := &RatingEntityMongo{
+		Id:        rating.Id,
+		AuctionId: rating.AuctionId,
+		RaterId:   rating.RaterId,
+		SellerId:  rating.SellerId,
+		Stars:     rating.Stars,
+		Comment:   rating.Comment,
+		Timestamp: rating.Timestamp.Unix(),
+	}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"rating_count": bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$rating_count", 0}}, 1}},
+			"rating_total": bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$rating_total", 0}}, rating.Stars}},
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"average_rating": bson.M{"$divide": bson.A{"$rating_total", "$rating_count"}},
+		}}},
+	}
+
+	var sellerFound bool
+	err := mongodb.WithTransaction(ctx, ur.Collection.Database(), func(txCtx context.Context) error {
+		if _, err := ur.RatingCollection.InsertOne(txCtx, ratingEntityMongo); err != nil {
+			return err
+		}
+
+		result, err := ur.Collection.UpdateOne(txCtx, bson.M{"_id": rating.SellerId}, update)
+		if err != nil {
+			return err
+		}
+		sellerFound = result.MatchedCount > 0
+		return nil
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return internal_error.NewConflictError("The seller of this auction was already rated")
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to rate the seller of auction %s", rating.AuctionId), err)
+		return internal_error.NewInternalServerError("Error trying to rate the seller")
+	}
+
+	if !sellerFound {
+		logger.WarnContext(ctx, fmt.Sprintf(
+			"Seller %s of auction %s is not registered, the rating is kept without an average",
+			rating.SellerId, rating.AuctionId))
+	}
+
+	return nil
+}
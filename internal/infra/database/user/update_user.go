@@ -0,0 +1,55 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+var updatableUserFields = map[string]struct{}{
+	user_entity.NameField:                              {},
+	user_entity.EmailField:                             {},
+	user_entity.OutbidNotificationsField:               {},
+	user_entity.WatchedAuctionEndingNotificationsField: {},
+	user_entity.AuctionWonNotificationsField:           {},
+}
+
+// UpdateUser sets only fields, which must all be updatable, and leaves the
+// rest of the user untouched.
+func (ur *UserRepository) UpdateUser(
+	ctx context.Context,
+	userId string,
+	fields map[string]interface{}) (*user_entity.User, *internal_error.InternalError) {
+	set := bson.M{"updated_at": time.Now().Unix()}
+	for field, value := range fields {
+		if _, ok := updatableUserFields[field]; !ok {
+			return nil, internal_error.NewBadRequestError(fmt.Sprintf("Field %s cannot be updated", field))
+		}
+		set[field] = value
+	}
+
+	var userEntityMongo UserEntityMongo
+	err := ur.Collection.FindOneAndUpdate(ctx, bson.M{"_id": userId}, bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&userEntityMongo)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, internal_error.NewConflictError("A user with this email already exists")
+	}
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, internal_error.NewNotFoundError(fmt.Sprintf("User not found with this id = %s", userId))
+		}
+
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to update user %s", userId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to update user")
+	}
+
+	return userEntityMongo.toEntity(), nil
+}
@@ -0,0 +1,45 @@
+package user
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestUpdateUserLeavesUnspecifiedFieldsUntouched(t *testing.T) {
+	ctx := context.Background()
+	ur := NewUserRepository(connectTestDatabase())
+	assert.Nil(t, ur.EnsureIndexes(ctx))
+
+	email := uuid.New().String() + "@example.com"
+	userEntity, _ := user_entity.CreateUser("profile user", email, "s3cret-pass", user_entity.SellerRole)
+	assert.Nil(t, ur.CreateUser(ctx, userEntity))
+
+	updatedUser, err := ur.UpdateUser(ctx, userEntity.Id, map[string]interface{}{
+		user_entity.AuctionWonNotificationsField: false,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "profile user", updatedUser.Name)
+	assert.Equal(t, email, updatedUser.Email)
+	assert.Equal(t, user_entity.SellerRole, updatedUser.Role)
+	assert.True(t, updatedUser.PasswordMatches("s3cret-pass"))
+	assert.False(t, updatedUser.NotificationPreferences.AuctionWon)
+	assert.True(t, updatedUser.NotificationPreferences.Outbid)
+	assert.False(t, updatedUser.UpdatedAt.IsZero())
+
+	updatedUser, err = ur.UpdateUser(ctx, userEntity.Id, map[string]interface{}{user_entity.NameField: "renamed"})
+	assert.Nil(t, err)
+	assert.Equal(t, "renamed", updatedUser.Name)
+	assert.False(t, updatedUser.NotificationPreferences.AuctionWon)
+
+	_, err = ur.UpdateUser(ctx, userEntity.Id, map[string]interface{}{"role": "admin"})
+	assert.Equal(t, internal_error.ErrBadRequest, err.Err)
+
+	other, _ := user_entity.CreateUser("other user", uuid.New().String()+"@example.com", "s3cret-pass", "")
+	assert.Nil(t, ur.CreateUser(ctx, other))
+	_, err = ur.UpdateUser(ctx, other.Id, map[string]interface{}{user_entity.EmailField: email})
+	assert.Equal(t, internal_error.ErrConflict, err.Err)
+}
@@ -0,0 +1,65 @@
+package user
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/config"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	"log"
+	"path"
+	"testing"
+)
+
+func connectTestDatabase() *mongo.Database {
+	envFilePath := path.Join("./", "../../../../cmd/auction/.env")
+
+	cfg, err := config.Load(envFilePath)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	conn, err := mongodb.NewMongoDBConnection(context.Background(), cfg.Mongo)
+	if err != nil {
+		log.Fatal("Error trying to connect mongodb")
+	}
+
+	return conn
+}
+
+func TestWalletMutationsAreGuardedAndAudited(t *testing.T) {
+	ctx := context.Background()
+	ur := NewUserRepository(connectTestDatabase())
+
+	userEntity, _ := user_entity.CreateUser("wallet user", uuid.New().String()+"@example.com", "s3cret-pass", "")
+	assert.Nil(t, ur.CreateUser(ctx, userEntity))
+	auctionId := uuid.New().String()
+
+	assert.Nil(t, ur.Credit(ctx, userEntity.Id, 10000))
+	assert.Nil(t, ur.HoldFunds(ctx, userEntity.Id, auctionId, 6000))
+
+	err := ur.Debit(ctx, userEntity.Id, 5000)
+	assert.Equal(t, internal_error.ErrBadRequest, err.Err)
+
+	assert.Nil(t, ur.CaptureFunds(ctx, userEntity.Id, auctionId, 6000))
+	assert.Nil(t, ur.Debit(ctx, userEntity.Id, 4000))
+
+	userDb, _ := ur.FindUserById(ctx, userEntity.Id)
+	assert.Equal(t, int64(0), int64(*userDb.Balance))
+	assert.Equal(t, int64(0), int64(userDb.HeldAmount))
+
+	page, err := ur.FindWalletTransactions(ctx, userEntity.Id, 10, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), page.Total)
+
+	var types []user_entity.WalletTransactionType
+	for _, transaction := range page.Transactions {
+		types = append(types, transaction.Type)
+	}
+	assert.ElementsMatch(t, []user_entity.WalletTransactionType{
+		user_entity.WalletCredit, user_entity.WalletHold, user_entity.WalletCapture, user_entity.WalletDebit,
+	}, types)
+}
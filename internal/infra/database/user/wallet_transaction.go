@@ -0,0 +1,115 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type WalletTransactionEntityMongo struct {
+	Id        string               `bson:"_id"`
+	UserId    string               `bson:"user_id"`
+	Type      string               `bson:"type"`
+	Amount    primitive.Decimal128 `bson:"amount"`
+	AuctionId string               `bson:"auction_id,omitempty"`
+	Timestamp int64                `bson:"timestamp"`
+}
+
+var errWalletNotUpdated = errors.New("wallet update did not match the user")
+
+// updateWallet applies update to the user matched by filter and records the
+// transaction along with it. It reports false when filter matched nothing, in
+// which case no transaction is recorded.
+func (ur *UserRepository) updateWallet(
+	ctx context.Context,
+	filter bson.M,
+	update interface{},
+	transaction user_entity.WalletTransaction) (bool, error) {
+	err := mongodb.WithTransaction(ctx, ur.Collection.Database(), func(txCtx context.Context) error {
+		result, err := ur.Collection.UpdateOne(txCtx, filter, update)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errWalletNotUpdated
+		}
+
+		_, err = ur.TransactionCollection.InsertOne(txCtx, newWalletTransactionEntityMongo(transaction))
+		return err
+	})
+	if errors.Is(err, errWalletNotUpdated) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (ur *UserRepository) FindWalletTransactions(
+	ctx context.Context,
+	userId string,
+	limit, offset int64) (*user_entity.WalletTransactionPage, *internal_error.InternalError) {
+	filter := bson.M{"user_id": userId}
+
+	total, err := ur.TransactionCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to count wallet transactions of user %s", userId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find wallet transactions")
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+	if offset > 0 {
+		opts.SetSkip(offset)
+	}
+
+	cursor, err := ur.TransactionCollection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to find wallet transactions of user %s", userId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find wallet transactions")
+	}
+
+	var transactionsMongo []WalletTransactionEntityMongo
+	if err := cursor.All(ctx, &transactionsMongo); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to decode wallet transactions of user %s", userId), err)
+		return nil, internal_error.NewInternalServerError("Error trying to find wallet transactions")
+	}
+
+	var transactions []user_entity.WalletTransaction
+	for _, transactionMongo := range transactionsMongo {
+		transactions = append(transactions, transactionMongo.toEntity())
+	}
+
+	return &user_entity.WalletTransactionPage{Transactions: transactions, Total: total}, nil
+}
+
+func newWalletTransactionEntityMongo(transaction user_entity.WalletTransaction) *WalletTransactionEntityMongo {
+	return &WalletTransactionEntityMongo{
+		Id:        transaction.Id,
+		UserId:    transaction.UserId,
+		Type:      string(transaction.Type),
+		Amount:    mongodb.DecimalFromAmount(transaction.Amount),
+		AuctionId: transaction.AuctionId,
+		Timestamp: transaction.Timestamp.Unix(),
+	}
+}
+
+func (wm *WalletTransactionEntityMongo) toEntity() user_entity.WalletTransaction {
+	return user_entity.WalletTransaction{
+		Id:        wm.Id,
+		UserId:    wm.UserId,
+		Type:      user_entity.WalletTransactionType(wm.Type),
+		Amount:    mongodb.AmountFromDecimal(wm.Amount),
+		AuctionId: wm.AuctionId,
+		Timestamp: time.Unix(wm.Timestamp, 0),
+	}
+}
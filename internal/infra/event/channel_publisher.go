@@ -0,0 +1,54 @@
+package event
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"go.uber.org/zap"
+)
+
+const defaultBufferSize = 1000
+
+// ChannelPublisher logs every event and hands it to in-process consumers.
+// Publishing never blocks: when the buffer is full the event is dropped.
+type ChannelPublisher struct {
+	events chan event_entity.Event
+}
+
+type ChannelPublisherOption func(*channelPublisherOptions)
+
+type channelPublisherOptions struct {
+	bufferSize int
+}
+
+// WithBufferSize sets how many events may wait for the consumers.
+func WithBufferSize(bufferSize int) ChannelPublisherOption {
+	return func(options *channelPublisherOptions) {
+		options.bufferSize = bufferSize
+	}
+}
+
+func NewChannelPublisher(options ...ChannelPublisherOption) *ChannelPublisher {
+	publisherOptions := channelPublisherOptions{bufferSize: defaultBufferSize}
+	for _, option := range options {
+		option(&publisherOptions)
+	}
+
+	return &ChannelPublisher{
+		events: make(chan event_entity.Event, publisherOptions.bufferSize),
+	}
+}
+
+func (cp *ChannelPublisher) Publish(ctx context.Context, event event_entity.Event) {
+	logger.InfoContext(ctx, "Event published", zap.String("event", event.Name()), zap.Any("payload", event))
+
+	select {
+	case cp.events <- event:
+	default:
+		logger.WarnContext(ctx, "Event buffer is full, dropping event", zap.String("event", event.Name()))
+	}
+}
+
+func (cp *ChannelPublisher) Events() <-chan event_entity.Event {
+	return cp.events
+}
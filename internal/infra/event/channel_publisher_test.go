@@ -0,0 +1,19 @@
+package event
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestChannelPublisherDropsEventsWhenTheBufferIsFull(t *testing.T) {
+	publisher := NewChannelPublisher(WithBufferSize(1))
+
+	first := event_entity.OutbidEvent{AuctionId: "auction", PreviousLeaderUserId: "alice", NewAmount: 10}
+	publisher.Publish(context.Background(), first)
+	publisher.Publish(context.Background(), event_entity.OutbidEvent{AuctionId: "auction", NewAmount: 20})
+
+	assert.Equal(t, first, <-publisher.Events())
+	assert.Empty(t, publisher.Events())
+}
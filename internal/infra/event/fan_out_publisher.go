@@ -0,0 +1,21 @@
+package event
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/event_entity"
+)
+
+// FanOutPublisher hands every event to each of its publishers, in order.
+type FanOutPublisher struct {
+	publishers []event_entity.EventPublisher
+}
+
+func NewFanOutPublisher(publishers ...event_entity.EventPublisher) *FanOutPublisher {
+	return &FanOutPublisher{publishers: publishers}
+}
+
+func (fp *FanOutPublisher) Publish(ctx context.Context, event event_entity.Event) {
+	for _, publisher := range fp.publishers {
+		publisher.Publish(ctx, event)
+	}
+}
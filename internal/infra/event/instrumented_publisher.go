@@ -0,0 +1,28 @@
+package event
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"fullcycle-auction_go/internal/metrics"
+)
+
+var auctionsClosed = metrics.Default.NewCounterVec(
+	"auctions_closed_total", "Auctions closed, by close reason.", "close_reason")
+
+// InstrumentedPublisher counts closed auctions from the events that announce
+// them, since automatic closes happen inside the auction repository.
+type InstrumentedPublisher struct {
+	event_entity.EventPublisher
+}
+
+func NewInstrumentedPublisher(publisher event_entity.EventPublisher) *InstrumentedPublisher {
+	return &InstrumentedPublisher{EventPublisher: publisher}
+}
+
+func (ip *InstrumentedPublisher) Publish(ctx context.Context, event event_entity.Event) {
+	if closedEvent, ok := event.(event_entity.AuctionClosedEvent); ok {
+		auctionsClosed.WithLabelValues(closedEvent.CloseReason).Inc()
+	}
+
+	ip.EventPublisher.Publish(ctx, event)
+}
@@ -0,0 +1,32 @@
+package notification
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/user_entity"
+)
+
+// EmailSender is implemented by the email provider.
+type EmailSender interface {
+	SendEmail(ctx context.Context, to, subject, body string) error
+}
+
+type EmailChannel struct {
+	sender EmailSender
+}
+
+func NewEmailChannel(sender EmailSender) *EmailChannel {
+	return &EmailChannel{sender: sender}
+}
+
+func (EmailChannel) Name() string {
+	return "email"
+}
+
+// Send skips users registered without an email address.
+func (ec *EmailChannel) Send(ctx context.Context, user user_entity.User, notification Notification) error {
+	if user.Email == "" {
+		return nil
+	}
+
+	return ec.sender.SendEmail(ctx, user.Email, notification.Subject, notification.Message)
+}
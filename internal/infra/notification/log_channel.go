@@ -0,0 +1,30 @@
+package notification
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"go.uber.org/zap"
+)
+
+// LogChannel writes notifications to the application log, which is enough
+// to follow deliveries until a real provider is plugged in.
+type LogChannel struct{}
+
+func NewLogChannel() *LogChannel {
+	return &LogChannel{}
+}
+
+func (LogChannel) Name() string {
+	return "log"
+}
+
+func (LogChannel) Send(ctx context.Context, user user_entity.User, notification Notification) error {
+	logger.InfoContext(ctx, "Notification delivered",
+		zap.String("event", notification.Event),
+		zap.String("user_id", user.Id),
+		zap.String("subject", notification.Subject),
+		zap.String("message", notification.Message))
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package notification
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/user_entity"
+)
+
+// Notification is a message for a single user, already rendered.
+type Notification struct {
+	Event   string
+	UserId  string
+	Subject string
+	Message string
+}
+
+// Channel delivers notifications to users through one medium.
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, user user_entity.User, notification Notification) error
+}
@@ -0,0 +1,196 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/config"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/metrics"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+var (
+	notificationsSent = metrics.Default.NewCounter(
+		"notifications_sent_total", "Notifications handed to a delivery channel.")
+	notificationsDropped = metrics.Default.NewCounter(
+		"notifications_dropped_total", "Notifications dropped because the delivery queue was full.")
+	notificationsFailed = metrics.Default.NewCounter(
+		"notifications_failed_total", "Notifications a delivery channel failed to send.")
+)
+
+// NotificationDispatcher turns events into notifications and delivers them
+// on a pool of workers. The queue in front of the workers is bounded and
+// never blocks the producer, so a slow channel delays notifications, not bids.
+type NotificationDispatcher struct {
+	userRepository user_entity.UserRepositoryInterface
+	channels       []Channel
+	sendTimeout    time.Duration
+
+	queue      chan Notification
+	closeMutex sync.Mutex
+	closed     bool
+	workers    sync.WaitGroup
+}
+
+func NewNotificationDispatcher(
+	userRepository user_entity.UserRepositoryInterface,
+	cfg config.NotificationConfig,
+	channels ...Channel) *NotificationDispatcher {
+	nd := &NotificationDispatcher{
+		userRepository: userRepository,
+		channels:       channels,
+		sendTimeout:    cfg.SendTimeout,
+		queue:          make(chan Notification, cfg.QueueSize),
+	}
+
+	for i := 0; i < cfg.Workers; i++ {
+		nd.workers.Add(1)
+		go nd.deliver()
+	}
+
+	return nd
+}
+
+// Run consumes events until ctx is done or the stream is closed.
+func (nd *NotificationDispatcher) Run(ctx context.Context, events <-chan event_entity.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			nd.Dispatch(event)
+		}
+	}
+}
+
+func (nd *NotificationDispatcher) Dispatch(event event_entity.Event) {
+	notification, ok := toNotification(event)
+	if !ok {
+		return
+	}
+
+	nd.closeMutex.Lock()
+	defer nd.closeMutex.Unlock()
+
+	if nd.closed {
+		return
+	}
+
+	select {
+	case nd.queue <- notification:
+	default:
+		notificationsDropped.Inc()
+		logger.Warn("Notification queue is full, dropping notification",
+			zap.String("event", notification.Event), zap.String("user_id", notification.UserId))
+	}
+}
+
+// Close stops accepting notifications and waits for the queued ones to be
+// delivered.
+func (nd *NotificationDispatcher) Close(ctx context.Context) error {
+	nd.closeMutex.Lock()
+	if !nd.closed {
+		nd.closed = true
+		close(nd.queue)
+	}
+	nd.closeMutex.Unlock()
+
+	delivered := make(chan struct{})
+	go func() {
+		nd.workers.Wait()
+		close(delivered)
+	}()
+
+	select {
+	case <-delivered:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (nd *NotificationDispatcher) deliver() {
+	defer nd.workers.Done()
+
+	for notification := range nd.queue {
+		nd.send(notification)
+	}
+}
+
+func (nd *NotificationDispatcher) send(notification Notification) {
+	ctx, cancel := context.WithTimeout(context.Background(), nd.sendTimeout)
+	defer cancel()
+
+	user, err := nd.userRepository.FindUserById(ctx, notification.UserId)
+	if err != nil {
+		logger.Warn(fmt.Sprintf(
+			"Skipping %s notification for user %s that could not be loaded", notification.Event, notification.UserId),
+			zap.String("error", err.Message))
+		return
+	}
+
+	if !wantsNotification(user.NotificationPreferences, notification.Event) {
+		return
+	}
+
+	for _, channel := range nd.channels {
+		if err := channel.Send(ctx, *user, notification); err != nil {
+			notificationsFailed.Inc()
+			logger.Error(fmt.Sprintf(
+				"Error trying to send %s notification through %s", notification.Event, channel.Name()), err)
+			continue
+		}
+		notificationsSent.Inc()
+	}
+}
+
+func wantsNotification(preferences user_entity.NotificationPreferences, eventName string) bool {
+	switch eventName {
+	case event_entity.OutbidEventName:
+		return preferences.Outbid
+	case event_entity.AuctionWonEventName:
+		return preferences.AuctionWon
+	case event_entity.WatchedAuctionEndingEventName:
+		return preferences.WatchedAuctionEnding
+	}
+
+	return false
+}
+
+func toNotification(event event_entity.Event) (Notification, bool) {
+	switch e := event.(type) {
+	case event_entity.OutbidEvent:
+		if e.PreviousLeaderUserId == "" {
+			return Notification{}, false
+		}
+		return Notification{
+			Event:   e.Name(),
+			UserId:  e.PreviousLeaderUserId,
+			Subject: "You have been outbid",
+			Message: fmt.Sprintf("Someone bid %s on auction %s.", e.NewAmount, e.AuctionId),
+		}, true
+	case event_entity.AuctionWonEvent:
+		return Notification{
+			Event:   e.Name(),
+			UserId:  e.WinnerUserId,
+			Subject: "You won an auction",
+			Message: fmt.Sprintf("Your bid of %s won auction %s.", e.Amount, e.AuctionId),
+		}, true
+	case event_entity.WatchedAuctionEndingEvent:
+		return Notification{
+			Event:   e.Name(),
+			UserId:  e.UserId,
+			Subject: "An auction you watch is ending soon",
+			Message: fmt.Sprintf("Auction %s ends at %s.", e.AuctionId, e.EndTime.Format(time.RFC3339)),
+		}, true
+	}
+
+	return Notification{}, false
+}
@@ -0,0 +1,113 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fullcycle-auction_go/configuration/config"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeUserRepository struct {
+	user_entity.UserRepositoryInterface
+	users map[string]user_entity.User
+}
+
+func (f *fakeUserRepository) FindUserById(
+	ctx context.Context, userId string) (*user_entity.User, *internal_error.InternalError) {
+	user, ok := f.users[userId]
+	if !ok {
+		return nil, internal_error.NewNotFoundError("user not found")
+	}
+	return &user, nil
+}
+
+type fakeChannel struct {
+	mutex   sync.Mutex
+	sent    []Notification
+	release chan struct{}
+	err     error
+}
+
+func (f *fakeChannel) Name() string {
+	return "fake"
+}
+
+func (f *fakeChannel) Send(ctx context.Context, user user_entity.User, notification Notification) error {
+	if f.release != nil {
+		<-f.release
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.sent = append(f.sent, notification)
+	return f.err
+}
+
+func (f *fakeChannel) Sent() []Notification {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]Notification(nil), f.sent...)
+}
+
+func TestDispatcherRespectsNotificationPreferences(t *testing.T) {
+	optedOut := user_entity.DefaultNotificationPreferences()
+	optedOut.Outbid = false
+	userRepository := &fakeUserRepository{users: map[string]user_entity.User{
+		"alice": {Id: "alice", NotificationPreferences: user_entity.DefaultNotificationPreferences()},
+		"bob":   {Id: "bob", NotificationPreferences: optedOut},
+	}}
+	channel := &fakeChannel{}
+	dispatcher := NewNotificationDispatcher(userRepository, config.NotificationConfig{
+		QueueSize:   1000,
+		Workers:     4,
+		SendTimeout: 10 * time.Second,
+	}, channel)
+
+	dispatcher.Dispatch(event_entity.OutbidEvent{AuctionId: "auction", PreviousLeaderUserId: "alice", NewAmount: 10})
+	dispatcher.Dispatch(event_entity.OutbidEvent{AuctionId: "auction", PreviousLeaderUserId: "bob", NewAmount: 20})
+	dispatcher.Dispatch(event_entity.AuctionWonEvent{AuctionId: "auction", WinnerUserId: "bob", Amount: 20})
+	dispatcher.Dispatch(event_entity.OutbidEvent{AuctionId: "auction", PreviousLeaderUserId: "unknown"})
+	assert.Nil(t, dispatcher.Close(context.Background()))
+
+	sent := channel.Sent()
+	assert.Len(t, sent, 2)
+	assert.ElementsMatch(t,
+		[]string{"alice/" + event_entity.OutbidEventName, "bob/" + event_entity.AuctionWonEventName},
+		[]string{sent[0].UserId + "/" + sent[0].Event, sent[1].UserId + "/" + sent[1].Event})
+}
+
+func TestDispatcherDropsNotificationsInsteadOfBlockingWhenTheQueueIsFull(t *testing.T) {
+	userRepository := &fakeUserRepository{users: map[string]user_entity.User{
+		"alice": {Id: "alice", NotificationPreferences: user_entity.DefaultNotificationPreferences()},
+	}}
+	channel := &fakeChannel{release: make(chan struct{}), err: errors.New("provider unavailable")}
+	dispatcher := NewNotificationDispatcher(userRepository, config.NotificationConfig{
+		QueueSize:   1,
+		Workers:     1,
+		SendTimeout: 10 * time.Second,
+	}, channel)
+
+	dispatched := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			dispatcher.Dispatch(event_entity.OutbidEvent{AuctionId: "auction", PreviousLeaderUserId: "alice"})
+		}
+		close(dispatched)
+	}()
+
+	select {
+	case <-dispatched:
+	case <-time.After(time.Second):
+		t.Fatal("dispatching blocked on a slow channel")
+	}
+
+	close(channel.release)
+	assert.Nil(t, dispatcher.Close(context.Background()))
+	assert.LessOrEqual(t, len(channel.Sent()), 2)
+}
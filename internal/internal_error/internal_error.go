@@ -1,31 +1,154 @@
 package internal_error
 
+import "time"
+
+const (
+	ErrNotFound        = "not_found"
+	ErrInternalServer  = "internal_server_error"
+	ErrBadRequest      = "bad_request"
+	ErrConflict        = "conflict"
+	ErrVersionConflict = "version_conflict"
+	ErrForbidden       = "forbidden"
+	ErrBulkWrite       = "bulk_write"
+	ErrTooManyRequests = "too_many_requests"
+	ErrUnauthorized    = "unauthorized"
+	ErrValidation      = "validation_error"
+)
+
+// InternalError is the error returned across layers. Err is its kind, one of
+// the constants above, which decides the status it is answered with.
 type InternalError struct {
-	Message string
+	Message    string
+	Err        string
+	Failures   []ItemFailure
+	RetryAfter time.Duration
+	Violations []FieldViolation
+	Cause      error
+}
+
+type ItemFailure struct {
+	Index   int
+	Field   string
 	Err     string
+	Message string
+}
+
+// FieldViolation is one rule an input breaks. Constraint names the rule, such
+// as required or min, for clients that word the message themselves.
+type FieldViolation struct {
+	Field      string
+	Constraint string
+	Message    string
 }
 
 func (ie *InternalError) Error() string {
 	return ie.Message
 }
 
+func (ie *InternalError) Unwrap() error {
+	return ie.Cause
+}
+
+// WithCause keeps the error that led to ie, for errors.Is and errors.As and
+// for the logs, without exposing it in the message shown to clients.
+func (ie *InternalError) WithCause(cause error) *InternalError {
+	ie.Cause = cause
+	return ie
+}
+
 func NewNotFoundError(message string) *InternalError {
 	return &InternalError{
 		Message: message,
-		Err:     "not_found",
+		Err:     ErrNotFound,
 	}
 }
 
 func NewInternalServerError(message string) *InternalError {
 	return &InternalError{
 		Message: message,
-		Err:     "internal_server_error",
+		Err:     ErrInternalServer,
 	}
 }
 
 func NewBadRequestError(message string) *InternalError {
 	return &InternalError{
 		Message: message,
-		Err:     "bad_request",
+		Err:     ErrBadRequest,
+	}
+}
+
+func NewFieldBadRequestError(message, field, fieldMessage string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     ErrBadRequest,
+		Failures: []ItemFailure{
+			{Field: field, Err: ErrBadRequest, Message: fieldMessage},
+		},
+	}
+}
+
+// NewValidationError reports every violation at once rather than the first
+// one found.
+func NewValidationError(message string, violations []FieldViolation) *InternalError {
+	return &InternalError{
+		Message:    message,
+		Err:        ErrValidation,
+		Violations: violations,
+	}
+}
+
+func (ie *InternalError) IsValidation() bool {
+	return ie != nil && ie.Err == ErrValidation
+}
+
+func NewConflictError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     ErrConflict,
+	}
+}
+
+func NewVersionConflictError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     ErrVersionConflict,
+	}
+}
+
+func (ie *InternalError) IsNotFound() bool {
+	return ie != nil && ie.Err == ErrNotFound
+}
+
+func (ie *InternalError) IsVersionConflict() bool {
+	return ie != nil && ie.Err == ErrVersionConflict
+}
+
+func NewForbiddenError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     ErrForbidden,
+	}
+}
+
+func NewUnauthorizedError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     ErrUnauthorized,
+	}
+}
+
+func NewBulkWriteError(message string, failures []ItemFailure) *InternalError {
+	return &InternalError{
+		Message:  message,
+		Err:      ErrBulkWrite,
+		Failures: failures,
+	}
+}
+
+func NewTooManyRequestsError(message string, retryAfter time.Duration) *InternalError {
+	return &InternalError{
+		Message:    message,
+		Err:        ErrTooManyRequests,
+		RetryAfter: retryAfter,
 	}
 }
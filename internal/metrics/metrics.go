@@ -0,0 +1,330 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultBuckets suits latencies of calls to MongoDB, in seconds.
+var DefaultBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5}
+
+type collector interface {
+	writeTo(w io.Writer) (int, error)
+}
+
+// Counter is a value that only goes up, safe for concurrent use.
+type Counter struct {
+	name  string
+	help  string
+	value atomic.Int64
+}
+
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+func (c *Counter) Add(delta int64) {
+	if delta > 0 {
+		c.value.Add(delta)
+	}
+}
+
+func (c *Counter) Value() int64 {
+	return c.value.Load()
+}
+
+func (c *Counter) writeTo(w io.Writer) (int, error) {
+	return fmt.Fprintf(w, "%s%s %d\n", header(c.name, c.help, "counter"), c.name, c.Value())
+}
+
+// CounterVec is a family of counters told apart by the values of its labels.
+type CounterVec struct {
+	name       string
+	help       string
+	labelNames []string
+
+	mutex    sync.Mutex
+	counters map[string]*Counter
+}
+
+// WithLabelValues returns the counter of the given label values, in the
+// order of the label names.
+func (v *CounterVec) WithLabelValues(labelValues ...string) *Counter {
+	key := labelPairs(v.labelNames, labelValues)
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	counter, ok := v.counters[key]
+	if !ok {
+		counter = &Counter{name: v.name}
+		v.counters[key] = counter
+	}
+
+	return counter
+}
+
+func (v *CounterVec) writeTo(w io.Writer) (int, error) {
+	v.mutex.Lock()
+	keys := sortedKeys(v.counters)
+	lines := make([]string, len(keys))
+	for index, key := range keys {
+		lines[index] = fmt.Sprintf("%s{%s} %d\n", v.name, key, v.counters[key].Value())
+	}
+	v.mutex.Unlock()
+
+	return io.WriteString(w, header(v.name, v.help, "counter")+strings.Join(lines, ""))
+}
+
+// Gauge is a value that goes up and down, safe for concurrent use.
+type Gauge struct {
+	name  string
+	help  string
+	value atomic.Int64
+}
+
+func (g *Gauge) Inc() {
+	g.value.Add(1)
+}
+
+func (g *Gauge) Dec() {
+	g.value.Add(-1)
+}
+
+func (g *Gauge) Set(value int64) {
+	g.value.Store(value)
+}
+
+func (g *Gauge) Value() int64 {
+	return g.value.Load()
+}
+
+func (g *Gauge) writeTo(w io.Writer) (int, error) {
+	return fmt.Fprintf(w, "%s%s %d\n", header(g.name, g.help, "gauge"), g.name, g.Value())
+}
+
+// GaugeFunc reads its value when the metrics are scraped.
+type GaugeFunc struct {
+	name     string
+	help     string
+	function func() float64
+}
+
+func (g *GaugeFunc) writeTo(w io.Writer) (int, error) {
+	return fmt.Fprintf(w, "%s%s %s\n", header(g.name, g.help, "gauge"), g.name, formatFloat(g.function()))
+}
+
+// HistogramVec counts observations into cumulative buckets, per label values.
+type HistogramVec struct {
+	name       string
+	help       string
+	labelNames []string
+	buckets    []float64
+
+	mutex  sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	counts []int64
+	count  int64
+	sum    float64
+}
+
+func (v *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := labelPairs(v.labelNames, labelValues)
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	series, ok := v.series[key]
+	if !ok {
+		series = &histogramSeries{counts: make([]int64, len(v.buckets))}
+		v.series[key] = series
+	}
+
+	for index, upperBound := range v.buckets {
+		if value <= upperBound {
+			series.counts[index]++
+		}
+	}
+	series.count++
+	series.sum += value
+}
+
+// ObserveSince records the seconds elapsed since start.
+func (v *HistogramVec) ObserveSince(start time.Time, labelValues ...string) {
+	v.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+func (v *HistogramVec) writeTo(w io.Writer) (int, error) {
+	var out strings.Builder
+	out.WriteString(header(v.name, v.help, "histogram"))
+
+	v.mutex.Lock()
+	for _, key := range sortedKeys(v.series) {
+		series := v.series[key]
+		for index, upperBound := range v.buckets {
+			fmt.Fprintf(&out, "%s_bucket{%s} %d\n",
+				v.name, joinLabels(key, `le="`+formatFloat(upperBound)+`"`), series.counts[index])
+		}
+		fmt.Fprintf(&out, "%s_bucket{%s} %d\n", v.name, joinLabels(key, `le="+Inf"`), series.count)
+		fmt.Fprintf(&out, "%s_sum%s %s\n", v.name, braces(key), formatFloat(series.sum))
+		fmt.Fprintf(&out, "%s_count%s %d\n", v.name, braces(key), series.count)
+	}
+	v.mutex.Unlock()
+
+	return io.WriteString(w, out.String())
+}
+
+// Registry holds the metrics of the process and serves them in the
+// Prometheus text format.
+type Registry struct {
+	mutex      sync.Mutex
+	collectors []collector
+}
+
+// Default is the registry served on /metrics.
+var Default = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(c collector) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.collectors = append(r.collectors, c)
+}
+
+func (r *Registry) NewCounter(name, help string) *Counter {
+	counter := &Counter{name: name, help: help}
+	r.register(counter)
+	return counter
+}
+
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	counterVec := &CounterVec{
+		name: name, help: help, labelNames: labelNames, counters: make(map[string]*Counter)}
+	r.register(counterVec)
+	return counterVec
+}
+
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	gauge := &Gauge{name: name, help: help}
+	r.register(gauge)
+	return gauge
+}
+
+func (r *Registry) NewGaugeFunc(name, help string, function func() float64) *GaugeFunc {
+	gaugeFunc := &GaugeFunc{name: name, help: help, function: function}
+	r.register(gaugeFunc)
+	return gaugeFunc
+}
+
+// NewHistogramVec uses DefaultBuckets when no buckets are given.
+func (r *Registry) NewHistogramVec(
+	name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	histogramVec := &HistogramVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		buckets:    append([]float64(nil), buckets...),
+		series:     make(map[string]*histogramSeries),
+	}
+	sort.Float64s(histogramVec.buckets)
+	r.register(histogramVec)
+	return histogramVec
+}
+
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mutex.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mutex.Unlock()
+
+	var written int64
+	for _, c := range collectors {
+		n, err := c.writeTo(w)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteTo(w)
+}
+
+func header(name, help, metricType string) string {
+	return fmt.Sprintf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// labelPairs renders the labels as name="value" pairs. Missing values are
+// empty so a wrong call still produces a valid series.
+func labelPairs(labelNames, labelValues []string) string {
+	pairs := make([]string, len(labelNames))
+	for index, labelName := range labelNames {
+		var value string
+		if index < len(labelValues) {
+			value = labelValues[index]
+		}
+		pairs[index] = labelName + `="` + escapeLabelValue(value) + `"`
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func joinLabels(labels, extra string) string {
+	if labels == "" {
+		return extra
+	}
+
+	return labels + "," + extra
+}
+
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+
+	return "{" + labels + "}"
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRegistryWritesCountersInThePrometheusTextFormat(t *testing.T) {
+	registry := NewRegistry()
+	hits := registry.NewCounter("cache_hits_total", "Cache hits.")
+	registry.NewCounter("cache_misses_total", "Cache misses.")
+
+	hits.Inc()
+	hits.Inc()
+
+	var out bytes.Buffer
+	_, err := registry.WriteTo(&out)
+	assert.Nil(t, err)
+	assert.Equal(t,
+		"# HELP cache_hits_total Cache hits.\n# TYPE cache_hits_total counter\ncache_hits_total 2\n"+
+			"# HELP cache_misses_total Cache misses.\n# TYPE cache_misses_total counter\ncache_misses_total 0\n",
+		out.String())
+}
+
+func TestRegistryWritesLabelledCountersGaugesAndHistograms(t *testing.T) {
+	registry := NewRegistry()
+	closed := registry.NewCounterVec("auctions_closed_total", "Auctions closed.", "close_reason")
+	registry.NewGaugeFunc("timers_pending", "Pending timers.", func() float64 { return 3 })
+	latency := registry.NewHistogramVec("call_seconds", "Call latency.", []float64{1, 0.1}, "method")
+
+	closed.WithLabelValues("manual").Inc()
+	closed.WithLabelValues("expired").Add(2)
+	latency.Observe(0.05, "Find")
+	latency.Observe(0.5, "Find")
+	latency.Observe(2, "Find")
+
+	var out bytes.Buffer
+	_, err := registry.WriteTo(&out)
+	assert.Nil(t, err)
+	assert.Equal(t,
+		"# HELP auctions_closed_total Auctions closed.\n# TYPE auctions_closed_total counter\n"+
+			"auctions_closed_total{close_reason=\"expired\"} 2\n"+
+			"auctions_closed_total{close_reason=\"manual\"} 1\n"+
+			"# HELP timers_pending Pending timers.\n# TYPE timers_pending gauge\ntimers_pending 3\n"+
+			"# HELP call_seconds Call latency.\n# TYPE call_seconds histogram\n"+
+			"call_seconds_bucket{method=\"Find\",le=\"0.1\"} 1\n"+
+			"call_seconds_bucket{method=\"Find\",le=\"1\"} 2\n"+
+			"call_seconds_bucket{method=\"Find\",le=\"+Inf\"} 3\n"+
+			"call_seconds_sum{method=\"Find\"} 2.55\n"+
+			"call_seconds_count{method=\"Find\"} 3\n",
+		out.String())
+}
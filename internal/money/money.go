@@ -0,0 +1,69 @@
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Amount is a monetary value in cents. It is serialized to JSON as a numeric
+// string with two decimals, such as "19.99".
+type Amount int64
+
+const Cent Amount = 1
+
+func FromFloat(value float64) Amount {
+	return Amount(math.Round(value * 100))
+}
+
+func Parse(value string) (Amount, error) {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+
+	return FromFloat(parsed), nil
+}
+
+func (a Amount) Float64() float64 {
+	return float64(a) / 100
+}
+
+func (a Amount) String() string {
+	sign := ""
+	cents := int64(a)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON accepts both JSON numbers and numeric strings.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	value := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := Parse(value)
+	if err != nil {
+		return err
+	}
+
+	*a = parsed
+	return nil
+}
@@ -0,0 +1,44 @@
+package money
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAmountJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Amount
+		output   string
+	}{
+		{input: `19.99`, expected: 1999, output: `"19.99"`},
+		{input: `"19.99"`, expected: 1999, output: `"19.99"`},
+		{input: `100`, expected: 10000, output: `"100.00"`},
+		{input: `0.1`, expected: 10, output: `"0.10"`},
+		{input: `"-2.5"`, expected: -250, output: `"-2.50"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var amount Amount
+			assert.Nil(t, json.Unmarshal([]byte(tt.input), &amount))
+			assert.Equal(t, tt.expected, amount)
+
+			output, err := json.Marshal(amount)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.output, string(output))
+		})
+	}
+}
+
+func TestAmountRejectsInvalidValues(t *testing.T) {
+	var amount Amount
+	assert.NotNil(t, json.Unmarshal([]byte(`"abc"`), &amount))
+	assert.NotNil(t, json.Unmarshal([]byte(`true`), &amount))
+}
+
+func TestFromFloatRoundsToTheNearestCent(t *testing.T) {
+	assert.Equal(t, Amount(1999), FromFloat(19.989999999))
+	assert.Equal(t, Amount(30), FromFloat(0.1+0.2))
+}
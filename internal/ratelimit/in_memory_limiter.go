@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/clock"
+	"math"
+	"sync"
+	"time"
+)
+
+const pruneInterval = time.Minute
+
+// InMemoryLimiter is a token bucket per key. Buckets live in the process, so
+// every instance enforces the limit on its own.
+type InMemoryLimiter struct {
+	clock     clock.Clock
+	perSecond float64
+	burst     float64
+
+	mutex      sync.Mutex
+	buckets    map[string]*bucket
+	lastPruned time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func NewInMemoryLimiter(rate Rate, burst int, limiterClock clock.Clock) *InMemoryLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &InMemoryLimiter{
+		clock:      limiterClock,
+		perSecond:  rate.perSecond(),
+		burst:      float64(burst),
+		buckets:    make(map[string]*bucket),
+		lastPruned: limiterClock.Now(),
+	}
+}
+
+func (l *InMemoryLimiter) Allow(ctx context.Context, key string) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.clock.Now()
+	l.pruneFullBuckets(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, updated: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = l.refill(b, now)
+	b.updated = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	missing := (1 - b.tokens) / l.perSecond
+	return false, time.Duration(math.Ceil(missing * float64(time.Second)))
+}
+
+func (l *InMemoryLimiter) refill(b *bucket, now time.Time) float64 {
+	return math.Min(l.burst, b.tokens+now.Sub(b.updated).Seconds()*l.perSecond)
+}
+
+// pruneFullBuckets forgets keys whose bucket has refilled, since a new bucket
+// for them would start out exactly the same.
+func (l *InMemoryLimiter) pruneFullBuckets(now time.Time) {
+	if now.Sub(l.lastPruned) < pruneInterval {
+		return
+	}
+	l.lastPruned = now
+
+	for key, b := range l.buckets {
+		if l.refill(b, now) >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
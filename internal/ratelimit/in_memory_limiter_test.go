@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseRate(t *testing.T) {
+	rate, err := ParseRate("5/s")
+	assert.Nil(t, err)
+	assert.Equal(t, Rate{Events: 5, Per: time.Second}, rate)
+
+	rate, err = ParseRate("120/m")
+	assert.Nil(t, err)
+	assert.Equal(t, 2.0, rate.perSecond())
+
+	for _, invalid := range []string{"5", "0/s", "five/s", "5/d"} {
+		_, err := ParseRate(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestInMemoryLimiterRefillsEachKeyOnItsOwn(t *testing.T) {
+	ctx := context.Background()
+	fakeClock := fakeclock.New(time.Now())
+	limiter := NewInMemoryLimiter(Rate{Events: 2, Per: time.Second}, 3, fakeClock)
+
+	for i := 0; i < 3; i++ {
+		allowed, _ := limiter.Allow(ctx, "bot")
+		assert.True(t, allowed)
+	}
+
+	allowed, retryAfter := limiter.Allow(ctx, "bot")
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	allowed, _ = limiter.Allow(ctx, "someone-else")
+	assert.True(t, allowed)
+
+	fakeClock.Advance(500 * time.Millisecond)
+	allowed, _ = limiter.Allow(ctx, "bot")
+	assert.True(t, allowed)
+
+	allowed, _ = limiter.Allow(ctx, "bot")
+	assert.False(t, allowed)
+}
+
+func TestInMemoryLimiterForgetsRefilledBuckets(t *testing.T) {
+	ctx := context.Background()
+	fakeClock := fakeclock.New(time.Now())
+	limiter := NewInMemoryLimiter(Rate{Events: 5, Per: time.Second}, 5, fakeClock)
+
+	limiter.Allow(ctx, "first")
+	limiter.Allow(ctx, "second")
+	assert.Len(t, limiter.buckets, 2)
+
+	fakeClock.Advance(2 * time.Minute)
+	limiter.Allow(ctx, "third")
+	assert.Len(t, limiter.buckets, 1)
+}
+
+func TestPolicyNewLimiter(t *testing.T) {
+	assert.Nil(t, Policy{Rate: Rate{Events: 1, Per: time.Second}, Burst: 1, Disabled: true}.NewLimiter())
+
+	limiter, ok := Policy{Rate: Rate{Events: 10, Per: time.Minute}, Burst: 3}.NewLimiter().(*InMemoryLimiter)
+	assert.True(t, ok)
+	assert.Equal(t, 3.0, limiter.burst)
+	assert.InDelta(t, 10.0/60, limiter.perSecond, 1e-9)
+}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/clock"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limiter decides whether the caller identified by key may act now. When it
+// may not, Allow also returns how long until it may try again.
+type Limiter interface {
+	Allow(ctx context.Context, key string) (bool, time.Duration)
+}
+
+// Rate is a number of events allowed per period, written as "5/s", "100/m"
+// or "1000/h".
+type Rate struct {
+	Events float64
+	Per    time.Duration
+}
+
+func ParseRate(value string) (Rate, error) {
+	events, unit, found := strings.Cut(strings.TrimSpace(value), "/")
+	if !found {
+		return Rate{}, fmt.Errorf("rate %q must look like 5/s", value)
+	}
+
+	count, err := strconv.ParseFloat(events, 64)
+	if err != nil || count <= 0 {
+		return Rate{}, fmt.Errorf("rate %q must allow a positive number of events", value)
+	}
+
+	per, ok := map[string]time.Duration{"s": time.Second, "m": time.Minute, "h": time.Hour}[unit]
+	if !ok {
+		return Rate{}, fmt.Errorf("rate %q must be per s, m or h", value)
+	}
+
+	return Rate{Events: count, Per: per}, nil
+}
+
+func (r Rate) perSecond() float64 {
+	return r.Events / r.Per.Seconds()
+}
+
+// Policy is a rate and its burst, as configured. A disabled policy limits
+// nothing.
+type Policy struct {
+	Rate     Rate
+	Burst    int
+	Disabled bool
+}
+
+// NewLimiter builds an in-memory limiter for the policy, or returns nil when
+// the policy is disabled.
+func (p Policy) NewLimiter() Limiter {
+	if p.Disabled {
+		return nil
+	}
+
+	return NewInMemoryLimiter(p.Rate, p.Burst, clock.NewRealClock())
+}
@@ -0,0 +1,150 @@
+package fakeclock
+
+import (
+	"fullcycle-auction_go/internal/clock"
+	"sync"
+	"time"
+)
+
+type FakeClock struct {
+	mutex   *sync.Mutex
+	now     time.Time
+	timers  map[*fakeTimer]struct{}
+	tickers map[*fakeTicker]struct{}
+}
+
+func New(now time.Time) *FakeClock {
+	return &FakeClock{
+		mutex:   &sync.Mutex{},
+		now:     now,
+		timers:  make(map[*fakeTimer]struct{}),
+		tickers: make(map[*fakeTicker]struct{}),
+	}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) clock.Timer {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	timer := &fakeTimer{
+		clock:    c,
+		channel:  make(chan time.Time, 1),
+		deadline: c.now.Add(d),
+	}
+
+	if d <= 0 {
+		timer.channel <- c.now
+		return timer
+	}
+
+	c.timers[timer] = struct{}{}
+	return timer
+}
+
+// NewTicker ticks every d of fake time. Like time.Ticker, it drops ticks
+// nobody was ready to receive.
+func (c *FakeClock) NewTicker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("fakeclock: non-positive interval for NewTicker")
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	ticker := &fakeTicker{
+		clock:   c,
+		channel: make(chan time.Time, 1),
+		period:  d,
+		next:    c.now.Add(d),
+	}
+	c.tickers[ticker] = struct{}{}
+	return ticker
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+	for timer := range c.timers {
+		if !timer.deadline.After(c.now) {
+			delete(c.timers, timer)
+			timer.channel <- c.now
+		}
+	}
+	for ticker := range c.tickers {
+		if ticker.next.After(c.now) {
+			continue
+		}
+
+		select {
+		case ticker.channel <- c.now:
+		default:
+		}
+		for !ticker.next.After(c.now) {
+			ticker.next = ticker.next.Add(ticker.period)
+		}
+	}
+}
+
+func (c *FakeClock) PendingTimers() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.timers)
+}
+
+func (c *FakeClock) PendingTickers() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.tickers)
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	channel  chan time.Time
+	deadline time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.channel
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+
+	_, active := t.clock.timers[t]
+	delete(t.clock.timers, t)
+	return active
+}
+
+type fakeTicker struct {
+	clock   *FakeClock
+	channel chan time.Time
+	period  time.Duration
+	next    time.Time
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.channel
+}
+
+func (t *fakeTicker) Stop() {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+
+	delete(t.clock.tickers, t)
+}
@@ -0,0 +1,76 @@
+package auction_usecase
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/entity/event_entity"
+)
+
+// RegisterBidFlusher lets OnAuctionClosed write the bids still waiting in the
+// batch, the winning one among them, before it reconciles the winner.
+func (au *AuctionUseCase) RegisterBidFlusher(flush func(ctx context.Context) error) {
+	au.bidFlusher = flush
+}
+
+func (au *AuctionUseCase) OnAuctionClosed(ctx context.Context, auction auction_entity.Auction) {
+	au.eventPublisher.Publish(ctx, event_entity.AuctionClosedEvent{
+		AuctionId:     auction.Id,
+		CloseReason:   auction.CloseReason,
+		WinnerUserId:  auction.WinnerUserId,
+		WinningAmount: auction.WinningAmount,
+		Timestamp:     auction.ClosedAt,
+	})
+
+	if auction.WinnerUserId != "" {
+		au.eventPublisher.Publish(ctx, event_entity.AuctionWonEvent{
+			AuctionId:    auction.Id,
+			WinnerUserId: auction.WinnerUserId,
+			Amount:       auction.WinningAmount,
+			Timestamp:    auction.ClosedAt,
+		})
+	}
+
+	if au.bidFlusher != nil {
+		if err := au.bidFlusher(ctx); err != nil {
+			logger.WarnContext(ctx, fmt.Sprintf(
+				"Reconciling auction %s without writing the buffered bids first: %s", auction.Id, err))
+		}
+	}
+
+	bidWinning, err := au.bidRepositoryInterface.FindWinningBidByAuctionId(ctx, auction.Id)
+	if err != nil {
+		if err.IsNotFound() {
+			reconcileHighestBid(auction, nil)
+		}
+		logger.InfoContext(ctx, fmt.Sprintf("Auction %s closed without a winning bid", auction.Id))
+		return
+	}
+
+	reconcileHighestBid(auction, bidWinning)
+	logger.InfoContext(ctx, fmt.Sprintf("Auction %s closed, winner is user %s with amount %s",
+		auction.Id, bidWinning.UserId, bidWinning.Amount))
+}
+
+// reconcileHighestBid compares the highest bid cached on the auction document
+// with the winning bid found in the bids collection. It only reports
+// discrepancies, so they can be investigated instead of silently overwritten.
+func reconcileHighestBid(auction auction_entity.Auction, bidWinning *bid_entity.Bid) {
+	if bidWinning == nil {
+		if auction.CurrentHighestUserId != "" && auction.IsReserveMet() {
+			logger.Warn(fmt.Sprintf(
+				"Auction %s caches a highest bid of %s by user %s but no winning bid was found",
+				auction.Id, auction.CurrentHighestAmount, auction.CurrentHighestUserId))
+		}
+		return
+	}
+
+	if bidWinning.UserId != auction.CurrentHighestUserId || bidWinning.Amount != auction.CurrentHighestAmount {
+		logger.Warn(fmt.Sprintf(
+			"Auction %s caches a highest bid of %s by user %s but the winning bid is %s by user %s",
+			auction.Id, auction.CurrentHighestAmount, auction.CurrentHighestUserId,
+			bidWinning.Amount, bidWinning.UserId))
+	}
+}
@@ -0,0 +1,38 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/event"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type fakeFlushedBidRepository struct {
+	bid_entity.BidEntityRepository
+	flushed           bool
+	foundAfterFlushed bool
+}
+
+func (f *fakeFlushedBidRepository) FindWinningBidByAuctionId(
+	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	f.foundAfterFlushed = f.flushed
+	return &bid_entity.Bid{AuctionId: auctionId, UserId: "winner", Amount: 1000}, nil
+}
+
+func TestClosedAuctionFlushesBufferedBidsBeforeReconciling(t *testing.T) {
+	bidRepository := &fakeFlushedBidRepository{}
+	auctionUseCase := NewAuctionUseCase(nil, bidRepository, nil, event.NewChannelPublisher())
+	auctionUseCase.RegisterBidFlusher(func(ctx context.Context) error {
+		bidRepository.flushed = true
+		return nil
+	})
+
+	auctionUseCase.OnAuctionClosed(context.Background(), auction_entity.Auction{
+		Id: "auction", CurrentHighestUserId: "winner", CurrentHighestAmount: 1000,
+	})
+
+	assert.True(t, bidRepository.foundAfterFlushed)
+}
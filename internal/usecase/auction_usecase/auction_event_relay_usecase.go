@@ -0,0 +1,61 @@
+package auction_usecase
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"time"
+)
+
+const eventRelayBatchSize = 100
+
+// RunEventRelay publishes the outbox events stored with each auction until
+// ctx is done. An event is marked only after it was published, so a crash in
+// between publishes it again rather than losing it.
+func (au *AuctionUseCase) RunEventRelay(ctx context.Context) {
+	ticker := time.NewTicker(au.eventRelayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			au.RelayAuctionEvents(ctx)
+		}
+	}
+}
+
+func (au *AuctionUseCase) RelayAuctionEvents(ctx context.Context) {
+	outboxEvents, err := au.auctionRepositoryInterface.FindUnpublishedEvents(ctx, eventRelayBatchSize)
+	if err != nil {
+		return
+	}
+
+	for _, outboxEvent := range outboxEvents {
+		if relayed, ok := toRelayedEvent(outboxEvent); ok {
+			au.eventPublisher.Publish(ctx, relayed)
+		} else {
+			logger.WarnContext(ctx, fmt.Sprintf(
+				"Skipping auction event %s of unknown type %s", outboxEvent.Id, outboxEvent.Type))
+		}
+
+		if err := au.auctionRepositoryInterface.MarkEventPublished(ctx, outboxEvent.Id); err != nil {
+			return
+		}
+	}
+}
+
+func toRelayedEvent(outboxEvent auction_entity.OutboxEvent) (event_entity.Event, bool) {
+	switch outboxEvent.Type {
+	case event_entity.AuctionCreatedEventName:
+		return event_entity.AuctionCreatedEvent{
+			AuctionId: outboxEvent.AuctionId,
+			Timestamp: outboxEvent.Timestamp,
+		}, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,45 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"fullcycle-auction_go/internal/infra/event"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type fakeOutboxAuctionRepository struct {
+	auction_entity.AuctionRepositoryInterface
+	events    []auction_entity.OutboxEvent
+	published []string
+}
+
+func (f *fakeOutboxAuctionRepository) FindUnpublishedEvents(
+	ctx context.Context, limit int64) ([]auction_entity.OutboxEvent, *internal_error.InternalError) {
+	return f.events, nil
+}
+
+func (f *fakeOutboxAuctionRepository) MarkEventPublished(
+	ctx context.Context, eventId string) *internal_error.InternalError {
+	f.published = append(f.published, eventId)
+	return nil
+}
+
+func TestRelayPublishesOutboxEventsAndMarksThem(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	auctionRepository := &fakeOutboxAuctionRepository{events: []auction_entity.OutboxEvent{
+		{Id: "a:auction_created", AuctionId: "a", Type: event_entity.AuctionCreatedEventName, Timestamp: createdAt},
+		{Id: "b:unknown", AuctionId: "b", Type: "unknown"},
+	}}
+	publisher := event.NewChannelPublisher()
+	auctionUseCase := NewAuctionUseCase(auctionRepository, nil, nil, publisher)
+
+	auctionUseCase.RelayAuctionEvents(context.Background())
+
+	assert.Equal(t, []string{"a:auction_created", "b:unknown"}, auctionRepository.published)
+	assert.Len(t, publisher.Events(), 1)
+	assert.Equal(t, event_entity.AuctionCreatedEvent{AuctionId: "a", Timestamp: createdAt}, <-publisher.Events())
+}
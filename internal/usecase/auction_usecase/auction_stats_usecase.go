@@ -0,0 +1,47 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"strconv"
+)
+
+type AuctionStatsOutputDTO struct {
+	Total                  int64            `json:"total"`
+	ByStatus               map[string]int64 `json:"by_status"`
+	ByCategory             map[string]int64 `json:"by_category"`
+	AverageDurationSeconds float64          `json:"average_duration_seconds"`
+}
+
+var auctionStatusNames = map[auction_entity.AuctionStatus]string{
+	auction_entity.Active:    "active",
+	auction_entity.Completed: "completed",
+	auction_entity.Scheduled: "scheduled",
+	auction_entity.Cancelled: "cancelled",
+	auction_entity.Paused:    "paused",
+}
+
+func (au *AuctionUseCase) GetAuctionStats(
+	ctx context.Context) (*AuctionStatsOutputDTO, *internal_error.InternalError) {
+	auctionStats, err := au.auctionRepositoryInterface.GetAuctionStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byStatus := make(map[string]int64, len(auctionStats.ByStatus))
+	for status, count := range auctionStats.ByStatus {
+		name, ok := auctionStatusNames[status]
+		if !ok {
+			name = strconv.Itoa(int(status))
+		}
+		byStatus[name] = count
+	}
+
+	return &AuctionStatsOutputDTO{
+		Total:                  auctionStats.Total,
+		ByStatus:               byStatus,
+		ByCategory:             auctionStats.ByCategory,
+		AverageDurationSeconds: auctionStats.AverageDuration.Seconds(),
+	}, nil
+}
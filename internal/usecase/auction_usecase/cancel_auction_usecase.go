@@ -0,0 +1,46 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"fullcycle-auction_go/internal/internal_error"
+)
+
+type CancelAuctionInputDTO struct {
+	Reason string `json:"reason" binding:"required,min=3,max=200"`
+}
+
+func (au *AuctionUseCase) CancelAuction(
+	ctx context.Context,
+	auctionId, callerId string,
+	callerIsAdmin bool,
+	cancelInput CancelAuctionInputDTO) *internal_error.InternalError {
+	err := au.retryOnVersionConflict(auctionId, func() *internal_error.InternalError {
+		auctionEntity, err := au.auctionRepositoryInterface.FindAuctionByIdFromPrimary(ctx, auctionId)
+		if err != nil {
+			return err
+		}
+
+		if err := ensureAuctionOwner(auctionEntity, callerId, callerIsAdmin); err != nil {
+			return err
+		}
+
+		if auctionEntity.BidCount > 0 {
+			return internal_error.NewConflictError("Auction already has bids and cannot be cancelled")
+		}
+
+		return au.auctionRepositoryInterface.CancelAuction(
+			ctx, auctionId, cancelInput.Reason, auctionEntity.Version)
+	})
+	if err != nil {
+		return err
+	}
+
+	au.eventPublisher.Publish(ctx, event_entity.AuctionClosedEvent{
+		AuctionId:   auctionId,
+		CloseReason: auction_entity.CloseReasonCancelled,
+		Timestamp:   au.clock.Now(),
+	})
+	return nil
+}
@@ -0,0 +1,109 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/event"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type fakeCancelAuctionRepository struct {
+	auction_entity.AuctionRepositoryInterface
+	auction   *auction_entity.Auction
+	cancelled bool
+}
+
+func (f *fakeCancelAuctionRepository) FindAuctionByIdFromPrimary(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	auctionEntity := *f.auction
+	return &auctionEntity, nil
+}
+
+func (f *fakeCancelAuctionRepository) CancelAuction(
+	ctx context.Context, auctionId, reason string, version int64) *internal_error.InternalError {
+	f.cancelled = true
+	return nil
+}
+
+func TestOnlyTheSellerOrAnAdminCanCancelAnAuction(t *testing.T) {
+	cancelInput := CancelAuctionInputDTO{Reason: "listing error"}
+	tests := []struct {
+		name          string
+		callerId      string
+		callerIsAdmin bool
+		allowed       bool
+	}{
+		{name: "seller", callerId: "seller", allowed: true},
+		{name: "admin", callerId: "admin", callerIsAdmin: true, allowed: true},
+		{name: "another user", callerId: "buyer", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auctionRepository := &fakeCancelAuctionRepository{
+				auction: &auction_entity.Auction{Id: "auction", SellerId: "seller"},
+			}
+			auctionUseCase := NewAuctionUseCase(auctionRepository, nil, nil, event.NewChannelPublisher())
+
+			err := auctionUseCase.CancelAuction(
+				context.Background(), "auction", tt.callerId, tt.callerIsAdmin, cancelInput)
+
+			assert.Equal(t, tt.allowed, auctionRepository.cancelled)
+			if tt.allowed {
+				assert.Nil(t, err)
+			} else {
+				assert.NotNil(t, err)
+				assert.Equal(t, "forbidden", err.Err)
+			}
+		})
+	}
+}
+
+func TestOnlyAnAdminCanCancelAnAuctionWithoutSeller(t *testing.T) {
+	tests := []struct {
+		name          string
+		callerId      string
+		callerIsAdmin bool
+		allowed       bool
+	}{
+		{name: "admin", callerId: "admin", callerIsAdmin: true, allowed: true},
+		{name: "another user", callerId: "buyer", allowed: false},
+		{name: "empty caller", callerId: "", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auctionRepository := &fakeCancelAuctionRepository{
+				auction: &auction_entity.Auction{Id: "auction"},
+			}
+			auctionUseCase := NewAuctionUseCase(auctionRepository, nil, nil, event.NewChannelPublisher())
+
+			err := auctionUseCase.CancelAuction(
+				context.Background(), "auction", tt.callerId, tt.callerIsAdmin,
+				CancelAuctionInputDTO{Reason: "listing error"})
+
+			assert.Equal(t, tt.allowed, auctionRepository.cancelled)
+			if tt.allowed {
+				assert.Nil(t, err)
+			} else {
+				assert.NotNil(t, err)
+				assert.Equal(t, "forbidden", err.Err)
+			}
+		})
+	}
+}
+
+func TestAuctionWithCountedBidsCannotBeCancelled(t *testing.T) {
+	auctionRepository := &fakeCancelAuctionRepository{
+		auction: &auction_entity.Auction{Id: "auction", SellerId: "seller", BidCount: 1},
+	}
+	auctionUseCase := NewAuctionUseCase(auctionRepository, nil, nil, event.NewChannelPublisher())
+
+	err := auctionUseCase.CancelAuction(
+		context.Background(), "auction", "seller", false, CancelAuctionInputDTO{Reason: "listing error"})
+
+	assert.False(t, auctionRepository.cancelled)
+	assert.Equal(t, internal_error.ErrConflict, err.Err)
+}
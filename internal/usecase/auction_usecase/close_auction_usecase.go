@@ -0,0 +1,44 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+)
+
+func (au *AuctionUseCase) CloseAuction(
+	ctx context.Context, auctionId, callerId string, callerIsAdmin bool) *internal_error.InternalError {
+	return au.retryOnVersionConflict(auctionId, func() *internal_error.InternalError {
+		auctionEntity, err := au.auctionRepositoryInterface.FindAuctionByIdFromPrimary(ctx, auctionId)
+		if err != nil {
+			return err
+		}
+
+		if err := ensureAuctionOwner(auctionEntity, callerId, callerIsAdmin); err != nil {
+			return err
+		}
+
+		return au.auctionRepositoryInterface.CloseAuctionById(ctx, auctionId, auctionEntity.Version)
+	})
+}
+
+// ensureAuctionOwner lets the seller and administrators manage an auction.
+// Auctions created without a seller are left to administrators.
+func ensureAuctionOwner(
+	auctionEntity *auction_entity.Auction, callerId string, callerIsAdmin bool) *internal_error.InternalError {
+	if !callerIsAdmin && (auctionEntity.SellerId == "" || auctionEntity.SellerId != callerId) {
+		return internal_error.NewForbiddenError("Only the seller can manage this auction")
+	}
+
+	return nil
+}
+
+// ensureAdmin keeps moderation, such as hiding or freezing an auction, to
+// administrators.
+func ensureAdmin(callerIsAdmin bool) *internal_error.InternalError {
+	if !callerIsAdmin {
+		return internal_error.NewForbiddenError("Only administrators can perform this action")
+	}
+
+	return nil
+}
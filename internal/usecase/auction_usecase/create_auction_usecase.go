@@ -2,9 +2,14 @@ package auction_usecase
 
 import (
 	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/clock"
 	"fullcycle-auction_go/internal/entity/auction_entity"
 	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"fullcycle-auction_go/internal/entity/user_entity"
 	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
 	"fullcycle-auction_go/internal/usecase/bid_usecase"
 	"time"
 )
@@ -13,49 +18,323 @@ type AuctionInputDTO struct {
 	ProductName string           `json:"product_name" binding:"required,min=1"`
 	Category    string           `json:"category" binding:"required,min=2"`
 	Description string           `json:"description" binding:"required,min=10,max=200"`
-	Condition   ProductCondition `json:"condition" binding:"oneof=0 1 2"`
+	Condition   ProductCondition `json:"condition" binding:"oneof=0 1 2 3"`
+	Duration    int64            `json:"duration_seconds" binding:"omitempty,min=0"`
+	StartTime   time.Time        `json:"start_time"`
+	AuctionType string           `json:"auction_type" binding:"omitempty,oneof=open sealed dutch"`
+
+	MinIncrement  money.Amount `json:"min_increment" binding:"omitempty,min=0"`
+	StartingPrice money.Amount `json:"starting_price" binding:"omitempty,min=0"`
+	ReservePrice  money.Amount `json:"reserve_price" binding:"omitempty,min=0"`
+	BuyNowPrice   money.Amount `json:"buy_now_price" binding:"omitempty,min=0"`
+
+	StartPrice        money.Amount `json:"start_price" binding:"omitempty,min=0"`
+	FloorPrice        money.Amount `json:"floor_price" binding:"omitempty,min=0"`
+	PriceDecrement    money.Amount `json:"price_decrement" binding:"omitempty,min=0"`
+	DecrementInterval int64        `json:"decrement_interval_seconds" binding:"omitempty,min=0"`
+
+	IdempotencyKey string `json:"-"`
+	SellerId       string `json:"-"`
 }
 
 type AuctionOutputDTO struct {
 	Id          string           `json:"id"`
+	SellerId    string           `json:"seller_id,omitempty"`
 	ProductName string           `json:"product_name"`
 	Category    string           `json:"category"`
 	Description string           `json:"description"`
 	Condition   ProductCondition `json:"condition"`
 	Status      AuctionStatus    `json:"status"`
+	AuctionType string           `json:"auction_type"`
 	Timestamp   time.Time        `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	Duration    int64            `json:"duration_seconds,omitempty"`
+	StartTime   time.Time        `json:"start_time" time_format:"2006-01-02 15:04:05"`
+	EndTime     time.Time        `json:"end_time" time_format:"2006-01-02 15:04:05"`
+	ClosedAt    *time.Time       `json:"closed_at,omitempty" time_format:"2006-01-02 15:04:05"`
+	CloseReason string           `json:"close_reason,omitempty"`
+
+	MinIncrement  money.Amount  `json:"min_increment,omitempty"`
+	StartingPrice money.Amount  `json:"starting_price"`
+	ReservePrice  *money.Amount `json:"reserve_price,omitempty"`
+	ReserveMet    *bool         `json:"reserve_met,omitempty"`
+	BuyNowPrice   money.Amount  `json:"buy_now_price,omitempty"`
+
+	CurrentHighestAmount *money.Amount `json:"current_highest_amount,omitempty"`
+	CurrentHighestUserId string        `json:"current_highest_user_id,omitempty"`
+	BidCount             int64         `json:"bid_count"`
+
+	WinnerUserId  string        `json:"winner_user_id,omitempty"`
+	WinningAmount *money.Amount `json:"winning_amount,omitempty"`
+
+	StartPrice        money.Amount  `json:"start_price,omitempty"`
+	FloorPrice        money.Amount  `json:"floor_price,omitempty"`
+	PriceDecrement    money.Amount  `json:"price_decrement,omitempty"`
+	DecrementInterval int64         `json:"decrement_interval_seconds,omitempty"`
+	CurrentPrice      *money.Amount `json:"current_price,omitempty"`
+
+	Version int64 `json:"-"`
+}
+
+type FindAuctionsInputDTO struct {
+	Page           int
+	PageSize       int
+	UseCursor      bool
+	Cursor         string
+	Sort           string
+	Query          string
+	CreatedFrom    time.Time
+	CreatedTo      time.Time
+	IncludeDeleted bool
+}
+
+type AuctionPageOutputDTO struct {
+	Items      []AuctionOutputDTO `json:"items"`
+	Total      int64              `json:"total"`
+	Page       int                `json:"page,omitempty"`
+	PageSize   int                `json:"page_size"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+type AuctionSummaryOutputDTO struct {
+	Id          string        `json:"id"`
+	ProductName string        `json:"product_name"`
+	Category    string        `json:"category"`
+	Status      AuctionStatus `json:"status"`
+	AuctionType string        `json:"auction_type"`
+	EndTime     time.Time     `json:"end_time" time_format:"2006-01-02 15:04:05"`
+	HighestBid  *money.Amount `json:"highest_bid,omitempty"`
+	BidCount    int64         `json:"bid_count"`
+
+	StartingPrice money.Amount `json:"starting_price"`
+	CurrentPrice  money.Amount `json:"current_price"`
+}
+
+type AuctionSummaryPageOutputDTO struct {
+	Items      []AuctionSummaryOutputDTO `json:"items"`
+	Total      int64                     `json:"total"`
+	Page       int                       `json:"page,omitempty"`
+	PageSize   int                       `json:"page_size"`
+	NextCursor string                    `json:"next_cursor,omitempty"`
 }
 
 type WinningInfoOutputDTO struct {
 	Auction AuctionOutputDTO          `json:"auction"`
+	Winner  *WinnerOutputDTO          `json:"winner"`
 	Bid     *bid_usecase.BidOutputDTO `json:"bid,omitempty"`
 }
 
+type WinnerOutputDTO struct {
+	UserId string       `json:"user_id"`
+	Amount money.Amount `json:"amount"`
+}
+
+const (
+	defaultMinAuctionDuration        = 10 * time.Second
+	defaultMaxAuctionDuration        = 30 * 24 * time.Hour
+	defaultMaxAuctionExtension       = 24 * time.Hour
+	defaultMaxBulkAuctions           = 500
+	defaultVersionConflictMaxAttempt = 3
+	defaultWatchlistEndingWindow     = time.Hour
+	defaultWatchlistNotifyInterval   = time.Minute
+	defaultEventRelayInterval        = 5 * time.Second
+)
+
+type AuctionUseCaseOption func(auctionUseCase *AuctionUseCase)
+
+// WithDurationLimits rejects auctions that would last less than min or more
+// than max.
+func WithDurationLimits(min, max time.Duration) AuctionUseCaseOption {
+	return func(auctionUseCase *AuctionUseCase) {
+		auctionUseCase.minDuration = min
+		auctionUseCase.maxDuration = max
+	}
+}
+
+// WithMaxExtension caps how much a seller can extend an auction in total.
+func WithMaxExtension(maxExtension time.Duration) AuctionUseCaseOption {
+	return func(auctionUseCase *AuctionUseCase) {
+		auctionUseCase.maxExtension = maxExtension
+	}
+}
+
+func WithMaxBulkAuctions(maxItems int) AuctionUseCaseOption {
+	return func(auctionUseCase *AuctionUseCase) {
+		auctionUseCase.maxBulkAuctions = maxItems
+	}
+}
+
+// WithVersionConflictMaxAttempts sets how many times an update that lost a
+// race on the auction version is tried.
+func WithVersionConflictMaxAttempts(maxAttempts int) AuctionUseCaseOption {
+	return func(auctionUseCase *AuctionUseCase) {
+		auctionUseCase.versionConflictMaxAttempts = maxAttempts
+	}
+}
+
+// WithWatchlistTiming checks every notifyInterval for watched auctions that
+// end within endingWindow.
+func WithWatchlistTiming(endingWindow, notifyInterval time.Duration) AuctionUseCaseOption {
+	return func(auctionUseCase *AuctionUseCase) {
+		auctionUseCase.watchlistEndingWindow = endingWindow
+		auctionUseCase.watchlistNotifyInterval = notifyInterval
+	}
+}
+
+func WithEventRelayInterval(interval time.Duration) AuctionUseCaseOption {
+	return func(auctionUseCase *AuctionUseCase) {
+		auctionUseCase.eventRelayInterval = interval
+	}
+}
+
+// WithClock sets the clock auction events are stamped with.
+func WithClock(auctionClock clock.Clock) AuctionUseCaseOption {
+	return func(auctionUseCase *AuctionUseCase) {
+		auctionUseCase.clock = auctionClock
+	}
+}
+
 func NewAuctionUseCase(
 	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface,
-	bidRepositoryInterface bid_entity.BidEntityRepository) AuctionUseCaseInterface {
-	return &AuctionUseCase{
+	bidRepositoryInterface bid_entity.BidEntityRepository,
+	userRepositoryInterface user_entity.UserRepositoryInterface,
+	eventPublisher event_entity.EventPublisher,
+	opts ...AuctionUseCaseOption) AuctionUseCaseInterface {
+	auctionUseCase := &AuctionUseCase{
 		auctionRepositoryInterface: auctionRepositoryInterface,
 		bidRepositoryInterface:     bidRepositoryInterface,
+		userRepositoryInterface:    userRepositoryInterface,
+		eventPublisher:             eventPublisher,
+		clock:                      clock.NewRealClock(),
+
+		minDuration:                defaultMinAuctionDuration,
+		maxDuration:                defaultMaxAuctionDuration,
+		maxExtension:               defaultMaxAuctionExtension,
+		maxBulkAuctions:            defaultMaxBulkAuctions,
+		versionConflictMaxAttempts: defaultVersionConflictMaxAttempt,
+		watchlistEndingWindow:      defaultWatchlistEndingWindow,
+		watchlistNotifyInterval:    defaultWatchlistNotifyInterval,
+		eventRelayInterval:         defaultEventRelayInterval,
 	}
+	for _, opt := range opts {
+		opt(auctionUseCase)
+	}
+
+	return auctionUseCase
 }
 
 type AuctionUseCaseInterface interface {
 	CreateAuction(
 		ctx context.Context,
-		auctionInput AuctionInputDTO) *internal_error.InternalError
+		auctionInput AuctionInputDTO) (*AuctionOutputDTO, *internal_error.InternalError)
+
+	CreateAuctions(
+		ctx context.Context,
+		auctionInputs []AuctionInputDTO) ([]BulkAuctionResultDTO, *internal_error.InternalError)
 
 	FindAuctionById(
-		ctx context.Context, id string) (*AuctionOutputDTO, *internal_error.InternalError)
+		ctx context.Context, id, callerId string) (*AuctionOutputDTO, *internal_error.InternalError)
 
 	FindAuctions(
 		ctx context.Context,
 		status AuctionStatus,
-		category, productName string) ([]AuctionOutputDTO, *internal_error.InternalError)
+		category, productName string,
+		findInput FindAuctionsInputDTO) (*AuctionPageOutputDTO, *internal_error.InternalError)
+
+	FindAuctionSummaries(
+		ctx context.Context,
+		status AuctionStatus,
+		category, productName string,
+		findInput FindAuctionsInputDTO) (*AuctionSummaryPageOutputDTO, *internal_error.InternalError)
 
 	FindWinningBidByAuctionId(
 		ctx context.Context,
 		auctionId string) (*WinningInfoOutputDTO, *internal_error.InternalError)
+
+	ExportBids(
+		ctx context.Context,
+		auctionId, callerId string,
+		callerIsAdmin bool,
+		write func(bid_usecase.BidOutputDTO) error) *internal_error.InternalError
+
+	GetAuctionStats(
+		ctx context.Context) (*AuctionStatsOutputDTO, *internal_error.InternalError)
+
+	FindAuctionsBySeller(
+		ctx context.Context,
+		sellerId string,
+		statusFilter []AuctionStatus) ([]AuctionOutputDTO, *internal_error.InternalError)
+
+	FindAuctionsExpiringSoon(
+		ctx context.Context,
+		within time.Duration,
+		limit int) ([]AuctionOutputDTO, *internal_error.InternalError)
+
+	CloseAuction(
+		ctx context.Context, auctionId, callerId string, callerIsAdmin bool) *internal_error.InternalError
+
+	CancelAuction(
+		ctx context.Context,
+		auctionId, callerId string,
+		callerIsAdmin bool,
+		cancelInput CancelAuctionInputDTO) *internal_error.InternalError
+
+	ForceCloseAuction(
+		ctx context.Context,
+		auctionId, actorId string,
+		forceCloseInput ForceCloseAuctionInputDTO) (*ForceCloseOutputDTO, *internal_error.InternalError)
+
+	ExtendAuction(
+		ctx context.Context,
+		auctionId, callerId string,
+		callerIsAdmin bool,
+		extendInput ExtendAuctionInputDTO) (*AuctionOutputDTO, *internal_error.InternalError)
+
+	UpdateAuction(
+		ctx context.Context,
+		auctionId, callerId string,
+		updateInput UpdateAuctionInputDTO) (*AuctionOutputDTO, *internal_error.InternalError)
+
+	DeleteAuction(
+		ctx context.Context, auctionId string, callerIsAdmin bool) *internal_error.InternalError
+
+	PauseAuction(
+		ctx context.Context, auctionId string, callerIsAdmin bool) *internal_error.InternalError
+
+	ResumeAuction(
+		ctx context.Context, auctionId string, callerIsAdmin bool) (*AuctionOutputDTO, *internal_error.InternalError)
+
+	ReconcileBidCounts(
+		ctx context.Context) (*BidCountReconciliationOutputDTO, *internal_error.InternalError)
+
+	OnAuctionClosed(
+		ctx context.Context, auction auction_entity.Auction)
+
+	AddToWatchlist(
+		ctx context.Context, userId, auctionId string) *internal_error.InternalError
+
+	RemoveFromWatchlist(
+		ctx context.Context, userId, auctionId string) *internal_error.InternalError
+
+	FindAuctionWinner(
+		ctx context.Context, auctionId string) (*AuctionWinnerOutputDTO, *internal_error.InternalError)
+
+	RateSeller(
+		ctx context.Context,
+		auctionId, raterId string,
+		ratingInput RatingInputDTO) (*RatingOutputDTO, *internal_error.InternalError)
+
+	FindWatchedAuctions(
+		ctx context.Context, userId string) ([]AuctionSummaryOutputDTO, *internal_error.InternalError)
+
+	NotifyWatchersOfEndingAuctions(ctx context.Context)
+
+	RunWatchlistNotifier(ctx context.Context)
+
+	RelayAuctionEvents(ctx context.Context)
+
+	RegisterBidFlusher(flush func(ctx context.Context) error)
+
+	RunEventRelay(ctx context.Context)
 }
 
 type ProductCondition int64
@@ -64,24 +343,85 @@ type AuctionStatus int64
 type AuctionUseCase struct {
 	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface
 	bidRepositoryInterface     bid_entity.BidEntityRepository
+	userRepositoryInterface    user_entity.UserRepositoryInterface
+	eventPublisher             event_entity.EventPublisher
+	clock                      clock.Clock
+	bidFlusher                 func(ctx context.Context) error
+
+	minDuration                time.Duration
+	maxDuration                time.Duration
+	maxExtension               time.Duration
+	maxBulkAuctions            int
+	versionConflictMaxAttempts int
+	watchlistEndingWindow      time.Duration
+	watchlistNotifyInterval    time.Duration
+	eventRelayInterval         time.Duration
 }
 
+// CreateAuction returns the auction as stored, which is the original one when
+// the idempotency key was already used.
 func (au *AuctionUseCase) CreateAuction(
 	ctx context.Context,
-	auctionInput AuctionInputDTO) *internal_error.InternalError {
+	auctionInput AuctionInputDTO) (*AuctionOutputDTO, *internal_error.InternalError) {
+	auction, err := au.newAuctionFromInput(auctionInput)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := au.auctionRepositoryInterface.CreateAuction(
+		ctx, auction); err != nil {
+		return nil, err
+	}
+
+	auctionOutputDTO := toOwnerAuctionOutputDTO(*auction)
+	return &auctionOutputDTO, nil
+}
+
+// newAuctionFromInput reports the duration limits, which depend on the
+// configuration, together with every rule of the auction entity.
+func (au *AuctionUseCase) newAuctionFromInput(
+	auctionInput AuctionInputDTO) (*auction_entity.Auction, *internal_error.InternalError) {
+	var violations []internal_error.FieldViolation
+	duration := time.Duration(auctionInput.Duration) * time.Second
+	if duration != 0 {
+		minDuration, maxDuration := au.minDuration, au.maxDuration
+		if duration < minDuration || duration > maxDuration {
+			violations = append(violations, internal_error.FieldViolation{
+				Field:      "duration_seconds",
+				Constraint: "range",
+				Message:    fmt.Sprintf("Auction duration must be between %s and %s", minDuration, maxDuration),
+			})
+		}
+	}
+
 	auction, err := auction_entity.CreateAuction(
 		auctionInput.ProductName,
 		auctionInput.Category,
 		auctionInput.Description,
-		auction_entity.ProductCondition(auctionInput.Condition))
+		auction_entity.ProductCondition(auctionInput.Condition),
+		auction_entity.WithDuration(duration),
+		auction_entity.WithStartTime(auctionInput.StartTime),
+		auction_entity.WithIdempotencyKey(auctionInput.IdempotencyKey),
+		auction_entity.WithSellerId(auctionInput.SellerId),
+		auction_entity.WithMinIncrement(auctionInput.MinIncrement),
+		auction_entity.WithStartingPrice(auctionInput.StartingPrice),
+		auction_entity.WithReservePrice(auctionInput.ReservePrice),
+		auction_entity.WithBuyNowPrice(auctionInput.BuyNowPrice),
+		auction_entity.WithAuctionType(auction_entity.AuctionType(auctionInput.AuctionType)),
+		auction_entity.WithDescendingPrice(
+			auctionInput.StartPrice,
+			auctionInput.FloorPrice,
+			auctionInput.PriceDecrement,
+			time.Duration(auctionInput.DecrementInterval)*time.Second))
+	if err != nil && !err.IsValidation() {
+		return nil, err
+	}
 	if err != nil {
-		return err
+		violations = append(violations, err.Violations...)
 	}
-
-	if err := au.auctionRepositoryInterface.CreateAuction(
-		ctx, auction); err != nil {
-		return err
+	if len(violations) > 0 {
+		return nil, internal_error.NewValidationError("Invalid auction", violations)
 	}
 
-	return nil
+	return auction, nil
 }
@@ -0,0 +1,46 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCreateAuctionReportsEveryInvalidField(t *testing.T) {
+	auctionUseCase := NewAuctionUseCase(nil, nil, nil, nil)
+
+	_, err := auctionUseCase.CreateAuction(context.Background(), AuctionInputDTO{
+		Category:    "sports",
+		Description: "road bicycle in good shape",
+		Condition:   ProductCondition(9),
+		Duration:    1,
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, internal_error.ErrValidation, err.Err)
+	assert.Equal(t, []string{"duration_seconds", "product_name", "condition"}, violatedFields(err))
+}
+
+func TestCreateAuctionsPrefixesViolationsWithTheirIndex(t *testing.T) {
+	auctionUseCase := NewAuctionUseCase(nil, nil, nil, nil)
+	valid := AuctionInputDTO{
+		ProductName: "bicycle",
+		Category:    "sports",
+		Description: "road bicycle in good shape",
+	}
+	invalid := valid
+	invalid.ProductName = ""
+
+	_, err := auctionUseCase.CreateAuctions(context.Background(), []AuctionInputDTO{valid, invalid})
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"[1].product_name"}, violatedFields(err))
+}
+
+func violatedFields(err *internal_error.InternalError) []string {
+	var fields []string
+	for _, violation := range err.Violations {
+		fields = append(fields, violation.Field)
+	}
+
+	return fields
+}
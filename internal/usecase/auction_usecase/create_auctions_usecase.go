@@ -0,0 +1,74 @@
+package auction_usecase
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"net/http"
+)
+
+type BulkAuctionResultDTO struct {
+	Index  int    `json:"index"`
+	Id     string `json:"id,omitempty"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (au *AuctionUseCase) CreateAuctions(
+	ctx context.Context,
+	auctionInputs []AuctionInputDTO) ([]BulkAuctionResultDTO, *internal_error.InternalError) {
+	maxItems := au.maxBulkAuctions
+	if len(auctionInputs) == 0 || len(auctionInputs) > maxItems {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("Bulk requests must contain between 1 and %d auctions", maxItems))
+	}
+
+	auctionEntities := make([]*auction_entity.Auction, 0, len(auctionInputs))
+	var violations []internal_error.FieldViolation
+	for index, auctionInput := range auctionInputs {
+		auctionEntity, err := au.newAuctionFromInput(auctionInput)
+		if err != nil && !err.IsValidation() {
+			return nil, err
+		}
+		if err != nil {
+			for _, violation := range err.Violations {
+				violation.Field = fmt.Sprintf("[%d].%s", index, violation.Field)
+				violations = append(violations, violation)
+			}
+			continue
+		}
+
+		auctionEntities = append(auctionEntities, auctionEntity)
+	}
+
+	if len(violations) > 0 {
+		return nil, internal_error.NewValidationError("Some auctions are invalid", violations)
+	}
+
+	results := make([]BulkAuctionResultDTO, len(auctionEntities))
+	for index, auctionEntity := range auctionEntities {
+		results[index] = BulkAuctionResultDTO{
+			Index:  index,
+			Id:     auctionEntity.Id,
+			Status: http.StatusCreated,
+		}
+	}
+
+	if err := au.auctionRepositoryInterface.CreateAuctions(ctx, auctionEntities); err != nil {
+		if err.Err != "bulk_write" {
+			return nil, err
+		}
+
+		for _, failure := range err.Failures {
+			results[failure.Index].Id = ""
+			results[failure.Index].Status = http.StatusInternalServerError
+			if failure.Err == "conflict" {
+				results[failure.Index].Status = http.StatusConflict
+			}
+			results[failure.Index].Error = failure.Message
+		}
+	}
+
+	return results, nil
+}
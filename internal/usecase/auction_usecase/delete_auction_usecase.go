@@ -0,0 +1,41 @@
+package auction_usecase
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+)
+
+// DeleteAuction soft deletes the auction. An auction deleted before it closed
+// will never have a winner, so the hold of its leading bidder is released.
+func (au *AuctionUseCase) DeleteAuction(
+	ctx context.Context, auctionId string, callerIsAdmin bool) *internal_error.InternalError {
+	if err := ensureAdmin(callerIsAdmin); err != nil {
+		return err
+	}
+
+	auctionEntity, err := au.auctionRepositoryInterface.FindAuctionByIdFromPrimary(ctx, auctionId)
+	if err != nil {
+		return err
+	}
+
+	if err := au.auctionRepositoryInterface.SoftDeleteAuction(ctx, auctionId); err != nil {
+		return err
+	}
+
+	if auctionEntity.Status == auction_entity.Completed || auctionEntity.Status == auction_entity.Cancelled ||
+		auctionEntity.CurrentHighestUserId == "" {
+		return nil
+	}
+
+	if err := au.userRepositoryInterface.ReleaseFunds(
+		ctx, auctionEntity.CurrentHighestUserId, auctionId, auctionEntity.CurrentHighestAmount); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf(
+			"Error trying to release the hold of user %s on deleted auction %s",
+			auctionEntity.CurrentHighestUserId, auctionId), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/infra/event"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type fakeDeleteAuctionRepository struct {
+	auction_entity.AuctionRepositoryInterface
+	auction *auction_entity.Auction
+	deleted bool
+}
+
+func (f *fakeDeleteAuctionRepository) FindAuctionByIdFromPrimary(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	auctionEntity := *f.auction
+	return &auctionEntity, nil
+}
+
+func (f *fakeDeleteAuctionRepository) SoftDeleteAuction(
+	ctx context.Context, auctionId string) *internal_error.InternalError {
+	f.deleted = true
+	return nil
+}
+
+type fakeReleaseUserRepository struct {
+	user_entity.UserRepositoryInterface
+	released map[string]money.Amount
+}
+
+func (f *fakeReleaseUserRepository) ReleaseFunds(
+	ctx context.Context, userId, auctionId string, amount money.Amount) *internal_error.InternalError {
+	f.released[userId] += amount
+	return nil
+}
+
+func TestDeletingAnOpenAuctionReleasesTheHoldOfItsLeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   auction_entity.AuctionStatus
+		released money.Amount
+	}{
+		{name: "active", status: auction_entity.Active, released: 8000},
+		{name: "paused", status: auction_entity.Paused, released: 8000},
+		{name: "completed", status: auction_entity.Completed, released: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auctionRepository := &fakeDeleteAuctionRepository{auction: &auction_entity.Auction{
+				Id:                   "auction",
+				Status:               tt.status,
+				CurrentHighestUserId: "leader",
+				CurrentHighestAmount: 8000,
+			}}
+			userRepository := &fakeReleaseUserRepository{released: map[string]money.Amount{}}
+			auctionUseCase := NewAuctionUseCase(auctionRepository, nil, userRepository, event.NewChannelPublisher())
+
+			assert.Nil(t, auctionUseCase.DeleteAuction(context.Background(), "auction", true))
+
+			assert.True(t, auctionRepository.deleted)
+			assert.Equal(t, tt.released, userRepository.released["leader"])
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/usecase/bid_usecase"
+)
+
+// ExportBids hands every bid of a completed auction to write, one at a time,
+// once the caller is known to be its seller or an administrator. Nothing is
+// written when the export is refused.
+func (au *AuctionUseCase) ExportBids(
+	ctx context.Context,
+	auctionId, callerId string,
+	callerIsAdmin bool,
+	write func(bid_usecase.BidOutputDTO) error) *internal_error.InternalError {
+	auctionEntity, err := au.auctionRepositoryInterface.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return err
+	}
+
+	if !callerIsAdmin && (auctionEntity.SellerId == "" || auctionEntity.SellerId != callerId) {
+		return internal_error.NewForbiddenError("Only the seller can export the bids of this auction")
+	}
+	if auctionEntity.Status != auction_entity.Completed {
+		return internal_error.NewBadRequestError("Bids can only be exported once the auction is completed")
+	}
+
+	return au.bidRepositoryInterface.ForEachBidByAuctionId(ctx, auctionId, func(bidEntity bid_entity.Bid) error {
+		return write(bid_usecase.BidOutputDTO{
+			Id:        bidEntity.Id,
+			UserId:    bidEntity.UserId,
+			AuctionId: bidEntity.AuctionId,
+			Amount:    bidEntity.Amount,
+			Timestamp: bidEntity.Timestamp,
+			Sequence:  bidEntity.Sequence,
+			Auto:      bidEntity.Auto,
+			Retracted: bidEntity.Retracted,
+		})
+	})
+}
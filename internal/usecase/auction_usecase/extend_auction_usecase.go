@@ -0,0 +1,59 @@
+package auction_usecase
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"time"
+)
+
+type ExtendAuctionInputDTO struct {
+	ExtraSeconds int64 `json:"extra_seconds" binding:"required,min=1"`
+}
+
+func (au *AuctionUseCase) ExtendAuction(
+	ctx context.Context,
+	auctionId, callerId string,
+	callerIsAdmin bool,
+	extendInput ExtendAuctionInputDTO) (*AuctionOutputDTO, *internal_error.InternalError) {
+	extra := time.Duration(extendInput.ExtraSeconds) * time.Second
+	maxExtension := au.maxExtension
+
+	var extendedAuction *auction_entity.Auction
+	err := au.retryOnVersionConflict(auctionId, func() *internal_error.InternalError {
+		auctionEntity, err := au.auctionRepositoryInterface.FindAuctionByIdFromPrimary(ctx, auctionId)
+		if err != nil {
+			return err
+		}
+
+		if err := ensureAuctionOwner(auctionEntity, callerId, callerIsAdmin); err != nil {
+			return err
+		}
+
+		if auctionEntity.Status == auction_entity.Completed {
+			return internal_error.NewConflictError("Auction is already completed and cannot be extended")
+		}
+
+		if auctionEntity.Extension+extra > maxExtension {
+			return internal_error.NewBadRequestError(fmt.Sprintf(
+				"Auction cannot be extended more than %s in total", maxExtension))
+		}
+
+		extendedAuction, err = au.auctionRepositoryInterface.ExtendAuction(ctx, *auctionEntity, extra)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	au.eventPublisher.Publish(ctx, event_entity.AuctionExtendedEvent{
+		AuctionId: extendedAuction.Id,
+		EndTime:   extendedAuction.EndTime,
+		Timestamp: au.clock.Now(),
+	})
+
+	auctionOutputDTO := toAuctionOutputDTO(*extendedAuction)
+	return &auctionOutputDTO, nil
+}
@@ -0,0 +1,107 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"fullcycle-auction_go/internal/infra/event"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type fakeExtendAuctionRepository struct {
+	auction_entity.AuctionRepositoryInterface
+	auction  auction_entity.Auction
+	extended bool
+}
+
+func (f *fakeExtendAuctionRepository) FindAuctionByIdFromPrimary(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	auctionEntity := f.auction
+	return &auctionEntity, nil
+}
+
+func (f *fakeExtendAuctionRepository) ExtendAuction(
+	ctx context.Context,
+	auctionEntity auction_entity.Auction,
+	extra time.Duration) (*auction_entity.Auction, *internal_error.InternalError) {
+	f.extended = true
+	auctionEntity.EndTime = auctionEntity.EndTime.Add(extra)
+	return &auctionEntity, nil
+}
+
+func TestOnlyTheSellerOrAnAdminCanExtendAnAuction(t *testing.T) {
+	extendInput := ExtendAuctionInputDTO{ExtraSeconds: 60}
+	tests := []struct {
+		name          string
+		callerId      string
+		callerIsAdmin bool
+		allowed       bool
+	}{
+		{name: "seller", callerId: "seller", allowed: true},
+		{name: "admin", callerId: "admin", callerIsAdmin: true, allowed: true},
+		{name: "another user", callerId: "buyer", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auctionRepository := &fakeExtendAuctionRepository{auction: auction_entity.Auction{
+				Id: "auction", SellerId: "seller", Status: auction_entity.Active, EndTime: time.Now().Add(time.Hour),
+			}}
+			auctionUseCase := NewAuctionUseCase(auctionRepository, nil, nil, event.NewChannelPublisher())
+
+			_, err := auctionUseCase.ExtendAuction(
+				context.Background(), "auction", tt.callerId, tt.callerIsAdmin, extendInput)
+
+			assert.Equal(t, tt.allowed, auctionRepository.extended)
+			if tt.allowed {
+				assert.Nil(t, err)
+			} else {
+				assert.Equal(t, internal_error.ErrForbidden, err.Err)
+			}
+		})
+	}
+}
+
+func TestSnipeExtensionsDoNotUseTheManualExtensionBudget(t *testing.T) {
+	auctionRepository := &fakeExtendAuctionRepository{auction: auction_entity.Auction{
+		Id:             "auction",
+		SellerId:       "seller",
+		Status:         auction_entity.Active,
+		EndTime:        time.Now().Add(time.Hour),
+		SnipeExtension: 30 * time.Minute,
+	}}
+	auctionUseCase := NewAuctionUseCase(auctionRepository, nil, nil, event.NewChannelPublisher(),
+		WithMaxExtension(10*time.Minute))
+
+	_, err := auctionUseCase.ExtendAuction(
+		context.Background(), "auction", "seller", false, ExtendAuctionInputDTO{ExtraSeconds: 600})
+	assert.Nil(t, err)
+	assert.True(t, auctionRepository.extended)
+
+	auctionRepository.auction.Extension = 10 * time.Minute
+	_, err = auctionUseCase.ExtendAuction(
+		context.Background(), "auction", "seller", false, ExtendAuctionInputDTO{ExtraSeconds: 1})
+	assert.Equal(t, internal_error.ErrBadRequest, err.Err)
+}
+
+func TestExtendedEventIsStampedWithTheInjectedClock(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	auctionRepository := &fakeExtendAuctionRepository{auction: auction_entity.Auction{
+		Id: "auction", SellerId: "seller", Status: auction_entity.Active, EndTime: now.Add(time.Hour),
+	}}
+	publisher := event.NewChannelPublisher()
+	auctionUseCase := NewAuctionUseCase(auctionRepository, nil, nil, publisher,
+		WithClock(fakeclock.New(now)))
+
+	_, err := auctionUseCase.ExtendAuction(
+		context.Background(), "auction", "seller", false, ExtendAuctionInputDTO{ExtraSeconds: 60})
+	assert.Nil(t, err)
+
+	extendedEvent := (<-publisher.Events()).(event_entity.AuctionExtendedEvent)
+	assert.Equal(t, now, extendedEvent.Timestamp)
+	assert.Equal(t, now.Add(time.Hour+time.Minute), extendedEvent.EndTime)
+}
@@ -2,56 +2,153 @@ package auction_usecase
 
 import (
 	"context"
-	"fullcycle-auction_go/configuration/logger"
+	"fmt"
 	"fullcycle-auction_go/internal/entity/auction_entity"
 	"fullcycle-auction_go/internal/internal_error"
 	"fullcycle-auction_go/internal/usecase/bid_usecase"
+	"time"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+	// maxPage keeps the offset of page pagination small enough for Mongo to
+	// skip; deeper pages are reached with cursors.
+	maxPage = 1000
 )
 
 func (au *AuctionUseCase) FindAuctionById(
-	ctx context.Context, id string) (*AuctionOutputDTO, *internal_error.InternalError) {
+	ctx context.Context, id, callerId string) (*AuctionOutputDTO, *internal_error.InternalError) {
 	auctionEntity, err := au.auctionRepositoryInterface.FindAuctionById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return &AuctionOutputDTO{
-		Id:          auctionEntity.Id,
-		ProductName: auctionEntity.ProductName,
-		Category:    auctionEntity.Category,
-		Description: auctionEntity.Description,
-		Condition:   ProductCondition(auctionEntity.Condition),
-		Status:      AuctionStatus(auctionEntity.Status),
-		Timestamp:   auctionEntity.Timestamp,
-	}, nil
+	auctionOutputDTO := toAuctionOutputDTO(*auctionEntity)
+	if callerId != "" && callerId == auctionEntity.SellerId {
+		auctionOutputDTO = toOwnerAuctionOutputDTO(*auctionEntity)
+	}
+
+	return &auctionOutputDTO, nil
 }
 
 func (au *AuctionUseCase) FindAuctions(
 	ctx context.Context,
 	status AuctionStatus,
-	category, productName string) ([]AuctionOutputDTO, *internal_error.InternalError) {
-	auctionEntities, err := au.auctionRepositoryInterface.FindAuctions(
-		ctx, auction_entity.AuctionStatus(status), category, productName)
+	category, productName string,
+	findInput FindAuctionsInputDTO) (*AuctionPageOutputDTO, *internal_error.InternalError) {
+	findOptions, err := toFindAuctionsOptions(&findInput)
+	if err != nil {
+		return nil, err
+	}
+
+	auctionPage, err := au.auctionRepositoryInterface.FindAuctions(
+		ctx, auction_entity.AuctionStatus(status), category, productName, findOptions)
 	if err != nil {
 		return nil, err
 	}
 
-	var auctionOutputs []AuctionOutputDTO
+	auctionOutputs := []AuctionOutputDTO{}
+	for _, value := range auctionPage.Auctions {
+		auctionOutputs = append(auctionOutputs, toAuctionOutputDTO(value))
+	}
+
+	return &AuctionPageOutputDTO{
+		Items:      auctionOutputs,
+		Total:      auctionPage.Total,
+		Page:       findInput.Page,
+		PageSize:   findInput.PageSize,
+		NextCursor: auctionPage.NextCursor,
+	}, nil
+}
+
+func (au *AuctionUseCase) FindAuctionsBySeller(
+	ctx context.Context,
+	sellerId string,
+	statusFilter []AuctionStatus) ([]AuctionOutputDTO, *internal_error.InternalError) {
+	var statuses []auction_entity.AuctionStatus
+	for _, status := range statusFilter {
+		statuses = append(statuses, auction_entity.AuctionStatus(status))
+	}
+
+	auctionEntities, err := au.auctionRepositoryInterface.FindAuctionsBySeller(ctx, sellerId, statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	auctionOutputs := []AuctionOutputDTO{}
 	for _, value := range auctionEntities {
-		auctionOutputs = append(auctionOutputs, AuctionOutputDTO{
-			Id:          value.Id,
-			ProductName: value.ProductName,
-			Category:    value.Category,
-			Description: value.Description,
-			Condition:   ProductCondition(value.Condition),
-			Status:      AuctionStatus(value.Status),
-			Timestamp:   value.Timestamp,
-		})
+		auctionOutputs = append(auctionOutputs, toOwnerAuctionOutputDTO(value))
 	}
 
 	return auctionOutputs, nil
 }
 
+func (au *AuctionUseCase) FindAuctionSummaries(
+	ctx context.Context,
+	status AuctionStatus,
+	category, productName string,
+	findInput FindAuctionsInputDTO) (*AuctionSummaryPageOutputDTO, *internal_error.InternalError) {
+	findOptions, err := toFindAuctionsOptions(&findInput)
+	if err != nil {
+		return nil, err
+	}
+
+	summaryPage, err := au.auctionRepositoryInterface.FindAuctionSummaries(
+		ctx, auction_entity.AuctionStatus(status), category, productName, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	summaryOutputs := []AuctionSummaryOutputDTO{}
+	for _, summary := range summaryPage.Summaries {
+		summaryOutputs = append(summaryOutputs, toAuctionSummaryOutputDTO(summary))
+	}
+
+	return &AuctionSummaryPageOutputDTO{
+		Items:      summaryOutputs,
+		Total:      summaryPage.Total,
+		Page:       findInput.Page,
+		PageSize:   findInput.PageSize,
+		NextCursor: summaryPage.NextCursor,
+	}, nil
+}
+
+func toFindAuctionsOptions(
+	findInput *FindAuctionsInputDTO) (auction_entity.FindAuctionsOptions, *internal_error.InternalError) {
+	if findInput.Page == 0 && !findInput.UseCursor {
+		findInput.Page = 1
+	}
+	if findInput.PageSize == 0 {
+		findInput.PageSize = defaultPageSize
+	}
+
+	if findInput.UseCursor && findInput.Page != 0 {
+		return auction_entity.FindAuctionsOptions{}, internal_error.NewBadRequestError(
+			"page and cursor cannot be used together")
+	}
+	if !findInput.UseCursor && (findInput.Page < 1 || findInput.Page > maxPage) {
+		return auction_entity.FindAuctionsOptions{}, internal_error.NewBadRequestError(
+			fmt.Sprintf("page must be between 1 and %d, use a cursor to go further", maxPage))
+	}
+	if findInput.PageSize < 1 || findInput.PageSize > maxPageSize {
+		return auction_entity.FindAuctionsOptions{}, internal_error.NewBadRequestError(
+			fmt.Sprintf("page_size must be between 1 and %d", maxPageSize))
+	}
+
+	return auction_entity.FindAuctionsOptions{
+		Page:           findInput.Page,
+		PageSize:       findInput.PageSize,
+		UseCursor:      findInput.UseCursor,
+		Cursor:         findInput.Cursor,
+		Sort:           findInput.Sort,
+		Query:          findInput.Query,
+		CreatedFrom:    findInput.CreatedFrom,
+		CreatedTo:      findInput.CreatedTo,
+		IncludeDeleted: findInput.IncludeDeleted,
+	}, nil
+}
+
 func (au *AuctionUseCase) FindWinningBidByAuctionId(
 	ctx context.Context,
 	auctionId string) (*WinningInfoOutputDTO, *internal_error.InternalError) {
@@ -60,24 +157,31 @@ func (au *AuctionUseCase) FindWinningBidByAuctionId(
 		return nil, err
 	}
 
-	auctionOutputDTO := AuctionOutputDTO{
-		Id:          auction.Id,
-		ProductName: auction.ProductName,
-		Category:    auction.Category,
-		Description: auction.Description,
-		Condition:   ProductCondition(auction.Condition),
-		Status:      AuctionStatus(auction.Status),
-		Timestamp:   auction.Timestamp,
+	auctionOutputDTO := toAuctionOutputDTO(*auction)
+
+	if auction.HidesBids() {
+		return &WinningInfoOutputDTO{Auction: auctionOutputDTO}, nil
+	}
+
+	if auction.WinnerResolved {
+		winningInfo := &WinningInfoOutputDTO{Auction: auctionOutputDTO}
+		if auction.WinnerUserId != "" {
+			winningInfo.Winner = &WinnerOutputDTO{UserId: auction.WinnerUserId, Amount: auction.WinningAmount}
+		}
+
+		return winningInfo, nil
 	}
 
 	bidWinning, err := au.bidRepositoryInterface.FindWinningBidByAuctionId(ctx, auction.Id)
-	if err != nil {
-		logger.Error("", err)
+	if err.IsNotFound() {
 		return &WinningInfoOutputDTO{
 			Auction: auctionOutputDTO,
 			Bid:     nil,
 		}, nil
 	}
+	if err != nil {
+		return nil, err
+	}
 
 	bidOutputDTO := &bid_usecase.BidOutputDTO{
 		Id:        bidWinning.Id,
@@ -89,6 +193,113 @@ func (au *AuctionUseCase) FindWinningBidByAuctionId(
 
 	return &WinningInfoOutputDTO{
 		Auction: auctionOutputDTO,
+		Winner:  &WinnerOutputDTO{UserId: bidWinning.UserId, Amount: bidWinning.Amount},
 		Bid:     bidOutputDTO,
 	}, nil
 }
+
+func toAuctionOutputDTO(auctionEntity auction_entity.Auction) AuctionOutputDTO {
+	auctionOutputDTO := AuctionOutputDTO{
+		Id:          auctionEntity.Id,
+		SellerId:    auctionEntity.SellerId,
+		ProductName: auctionEntity.ProductName,
+		Category:    auctionEntity.Category,
+		Description: auctionEntity.Description,
+		Condition:   ProductCondition(auctionEntity.Condition),
+		Status:      AuctionStatus(auctionEntity.Status),
+		AuctionType: string(auctionEntity.Type),
+		Timestamp:   auctionEntity.Timestamp,
+		Duration:    int64(auctionEntity.Duration / time.Second),
+		StartTime:   auctionEntity.OpensAt(),
+		EndTime:     auctionEntity.EndTime,
+		CloseReason: auctionEntity.CloseReason,
+
+		MinIncrement:  auctionEntity.MinIncrement,
+		StartingPrice: auctionEntity.StartingPrice,
+		BuyNowPrice:   auctionEntity.BuyNowPrice,
+		BidCount:      auctionEntity.BidCount,
+		WinnerUserId:  auctionEntity.WinnerUserId,
+
+		Version: auctionEntity.Version,
+	}
+
+	if auctionEntity.WinnerUserId != "" {
+		winningAmount := auctionEntity.WinningAmount
+		auctionOutputDTO.WinningAmount = &winningAmount
+	}
+
+	if !auctionEntity.ClosedAt.IsZero() {
+		closedAt := auctionEntity.ClosedAt
+		auctionOutputDTO.ClosedAt = &closedAt
+	}
+
+	if auctionEntity.IsDutch() {
+		currentPrice := auctionEntity.CurrentPrice
+		auctionOutputDTO.StartPrice = auctionEntity.StartPrice
+		auctionOutputDTO.FloorPrice = auctionEntity.FloorPrice
+		auctionOutputDTO.PriceDecrement = auctionEntity.PriceDecrement
+		auctionOutputDTO.DecrementInterval = int64(auctionEntity.DecrementInterval / time.Second)
+		auctionOutputDTO.CurrentPrice = &currentPrice
+	}
+
+	if !auctionEntity.IsDutch() {
+		currentPrice := auctionEntity.StartingPrice
+		if auctionEntity.CurrentHighestUserId != "" && !auctionEntity.HidesBids() {
+			currentPrice = auctionEntity.CurrentHighestAmount
+		}
+		auctionOutputDTO.CurrentPrice = &currentPrice
+	}
+
+	if auctionEntity.HidesBids() {
+		return auctionOutputDTO
+	}
+
+	if auctionEntity.CurrentHighestUserId != "" {
+		currentHighestAmount := auctionEntity.CurrentHighestAmount
+		auctionOutputDTO.CurrentHighestAmount = &currentHighestAmount
+		auctionOutputDTO.CurrentHighestUserId = auctionEntity.CurrentHighestUserId
+	}
+
+	if auctionEntity.HasReserve() {
+		reserveMet := auctionEntity.IsReserveMet()
+		auctionOutputDTO.ReserveMet = &reserveMet
+	}
+
+	return auctionOutputDTO
+}
+
+// toOwnerAuctionOutputDTO also exposes the reserve price, which only the
+// seller may see.
+func toOwnerAuctionOutputDTO(auctionEntity auction_entity.Auction) AuctionOutputDTO {
+	auctionOutputDTO := toAuctionOutputDTO(auctionEntity)
+	if auctionEntity.HasReserve() {
+		reservePrice := auctionEntity.ReservePrice
+		auctionOutputDTO.ReservePrice = &reservePrice
+	}
+
+	return auctionOutputDTO
+}
+
+func toAuctionSummaryOutputDTO(summary auction_entity.AuctionSummary) AuctionSummaryOutputDTO {
+	summaryOutput := AuctionSummaryOutputDTO{
+		Id:          summary.Id,
+		ProductName: summary.ProductName,
+		Category:    summary.Category,
+		Status:      AuctionStatus(summary.Status),
+		AuctionType: string(summary.Type),
+		EndTime:     summary.EndTime,
+		HighestBid:  summary.HighestBid,
+		BidCount:    summary.BidCount,
+
+		StartingPrice: summary.StartingPrice,
+		CurrentPrice:  summary.StartingPrice,
+	}
+	if summary.Type == auction_entity.SealedAuction && summary.Status != auction_entity.Completed {
+		summaryOutput.HighestBid = nil
+	}
+	if summaryOutput.HighestBid != nil {
+		summaryOutput.CurrentPrice = *summaryOutput.HighestBid
+	}
+
+	return summaryOutput
+}
@@ -0,0 +1,311 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestReservePriceIsOnlyVisibleToTheSeller(t *testing.T) {
+	auctionEntity, _ := auction_entity.CreateAuction(
+		"painting",
+		"art",
+		"oil painting on canvas",
+		auction_entity.Used,
+		auction_entity.WithReservePrice(500))
+	auctionEntity.CurrentHighestAmount = 450
+
+	bidderView := toAuctionOutputDTO(*auctionEntity)
+	assert.Nil(t, bidderView.ReservePrice)
+	assert.NotNil(t, bidderView.ReserveMet)
+	assert.False(t, *bidderView.ReserveMet)
+
+	ownerView := toOwnerAuctionOutputDTO(*auctionEntity)
+	assert.Equal(t, money.Amount(500), *ownerView.ReservePrice)
+
+	auctionEntity.CurrentHighestAmount = 500
+	assert.True(t, *toAuctionOutputDTO(*auctionEntity).ReserveMet)
+}
+
+func TestAuctionWithoutReserveHasNoReserveFields(t *testing.T) {
+	auctionEntity, _ := auction_entity.CreateAuction(
+		"poster",
+		"art",
+		"framed movie poster",
+		auction_entity.Used)
+
+	output := toOwnerAuctionOutputDTO(*auctionEntity)
+	assert.Nil(t, output.ReservePrice)
+	assert.Nil(t, output.ReserveMet)
+}
+
+func TestAuctionOutputExposesTheCachedHighestBid(t *testing.T) {
+	auctionEntity, _ := auction_entity.CreateAuction(
+		"lamp",
+		"decor",
+		"vintage desk lamp",
+		auction_entity.Used)
+
+	output := toAuctionOutputDTO(*auctionEntity)
+	assert.Nil(t, output.CurrentHighestAmount)
+	assert.Empty(t, output.CurrentHighestUserId)
+
+	auctionEntity.CurrentHighestAmount = 2500
+	auctionEntity.CurrentHighestUserId = "bidder"
+
+	output = toAuctionOutputDTO(*auctionEntity)
+	assert.Equal(t, money.Amount(2500), *output.CurrentHighestAmount)
+	assert.Equal(t, "bidder", output.CurrentHighestUserId)
+}
+
+type fakeFindAuctionRepository struct {
+	auction_entity.AuctionRepositoryInterface
+	auction auction_entity.Auction
+}
+
+func (f *fakeFindAuctionRepository) FindAuctionById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	auctionEntity := f.auction
+	return &auctionEntity, nil
+}
+
+func TestFindWinningBidPrefersThePersistedWinner(t *testing.T) {
+	auctionEntity, _ := auction_entity.CreateAuction(
+		"clock",
+		"decor",
+		"antique wall clock",
+		auction_entity.Used)
+	auctionEntity.Status = auction_entity.Completed
+	auctionEntity.WinnerResolved = true
+	auctionEntity.WinnerUserId = "winner"
+	auctionEntity.WinningAmount = 7500
+
+	auctionUseCase := NewAuctionUseCase(&fakeFindAuctionRepository{auction: *auctionEntity}, nil, nil, nil)
+
+	winningInfo, err := auctionUseCase.FindWinningBidByAuctionId(context.Background(), auctionEntity.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, &WinnerOutputDTO{UserId: "winner", Amount: 7500}, winningInfo.Winner)
+	assert.Nil(t, winningInfo.Bid)
+
+	auctionEntity.WinnerUserId = ""
+	auctionUseCase = NewAuctionUseCase(&fakeFindAuctionRepository{auction: *auctionEntity}, nil, nil, nil)
+
+	winningInfo, err = auctionUseCase.FindWinningBidByAuctionId(context.Background(), auctionEntity.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, winningInfo.Winner)
+}
+
+type fakeWinningBidRepository struct {
+	bid_entity.BidEntityRepository
+	winningBid *bid_entity.Bid
+}
+
+func (f *fakeWinningBidRepository) FindWinningBidByAuctionId(
+	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	if f.winningBid == nil {
+		return nil, internal_error.NewNotFoundError("no bids")
+	}
+	return f.winningBid, nil
+}
+
+func TestFindAuctionWinner(t *testing.T) {
+	auctionEntity, _ := auction_entity.CreateAuction(
+		"guitar",
+		"music",
+		"acoustic guitar",
+		auction_entity.Used,
+		auction_entity.WithReservePrice(5000))
+	auctionUseCase := NewAuctionUseCase(&fakeFindAuctionRepository{auction: *auctionEntity}, nil, nil, nil)
+
+	_, err := auctionUseCase.FindAuctionWinner(context.Background(), auctionEntity.Id)
+	assert.Equal(t, internal_error.ErrConflict, err.Err)
+
+	auctionEntity.Status = auction_entity.Completed
+	bidRepository := &fakeWinningBidRepository{}
+	auctionUseCase = NewAuctionUseCase(&fakeFindAuctionRepository{auction: *auctionEntity}, bidRepository, nil, nil)
+
+	winner, err := auctionUseCase.FindAuctionWinner(context.Background(), auctionEntity.Id)
+	assert.Nil(t, err)
+	assert.False(t, winner.HasWinner)
+	assert.Equal(t, NoWinnerReasonNoBids, winner.NoWinnerReason)
+
+	bidRepository.winningBid = &bid_entity.Bid{UserId: "bidder", Amount: 4000}
+	winner, err = auctionUseCase.FindAuctionWinner(context.Background(), auctionEntity.Id)
+	assert.Nil(t, err)
+	assert.False(t, winner.HasWinner)
+	assert.Equal(t, NoWinnerReasonReserveNotMet, winner.NoWinnerReason)
+
+	bidRepository.winningBid = &bid_entity.Bid{UserId: "bidder", Amount: 6000}
+	winner, err = auctionUseCase.FindAuctionWinner(context.Background(), auctionEntity.Id)
+	assert.Nil(t, err)
+	assert.True(t, winner.HasWinner)
+	assert.Equal(t, "bidder", winner.Winner.Id)
+	assert.Equal(t, money.Amount(6000), *winner.Amount)
+
+	auctionEntity.WinnerResolved = true
+	auctionEntity.WinnerUserId = "winner"
+	auctionEntity.WinningAmount = 7000
+	auctionUseCase = NewAuctionUseCase(&fakeFindAuctionRepository{auction: *auctionEntity}, bidRepository, nil, nil)
+
+	winner, err = auctionUseCase.FindAuctionWinner(context.Background(), auctionEntity.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, "winner", winner.Winner.Id)
+	assert.Equal(t, money.Amount(7000), *winner.Amount)
+}
+
+func TestSealedAuctionHidesTheHighestBidUntilItCloses(t *testing.T) {
+	auctionEntity, _ := auction_entity.CreateAuction(
+		"painting",
+		"art",
+		"oil painting on canvas",
+		auction_entity.Used,
+		auction_entity.WithAuctionType(auction_entity.SealedAuction))
+	auctionEntity.CurrentHighestAmount = 9000
+	auctionEntity.CurrentHighestUserId = "bidder"
+
+	output := toAuctionOutputDTO(*auctionEntity)
+	assert.Equal(t, "sealed", output.AuctionType)
+	assert.Nil(t, output.CurrentHighestAmount)
+	assert.Empty(t, output.CurrentHighestUserId)
+
+	auctionUseCase := NewAuctionUseCase(&fakeFindAuctionRepository{auction: *auctionEntity}, nil, nil, nil)
+	winningInfo, err := auctionUseCase.FindWinningBidByAuctionId(context.Background(), auctionEntity.Id)
+	assert.Nil(t, err)
+	assert.Nil(t, winningInfo.Winner)
+	assert.Nil(t, winningInfo.Bid)
+
+	auctionEntity.Status = auction_entity.Completed
+	auctionEntity.WinnerResolved = true
+	auctionEntity.WinnerUserId = "bidder"
+	auctionEntity.WinningAmount = 9000
+
+	output = toAuctionOutputDTO(*auctionEntity)
+	assert.Equal(t, money.Amount(9000), *output.CurrentHighestAmount)
+	assert.Equal(t, "bidder", output.CurrentHighestUserId)
+
+	auctionUseCase = NewAuctionUseCase(&fakeFindAuctionRepository{auction: *auctionEntity}, nil, nil, nil)
+	winningInfo, err = auctionUseCase.FindWinningBidByAuctionId(context.Background(), auctionEntity.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, &WinnerOutputDTO{UserId: "bidder", Amount: 9000}, winningInfo.Winner)
+}
+
+func TestAuctionsAreOpenByDefault(t *testing.T) {
+	auctionEntity, err := auction_entity.CreateAuction(
+		"vase",
+		"decor",
+		"ceramic flower vase",
+		auction_entity.New,
+		auction_entity.WithAuctionType(""))
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.OpenAuction, auctionEntity.Type)
+
+	_, err = auction_entity.CreateAuction(
+		"vase",
+		"decor",
+		"ceramic flower vase",
+		auction_entity.New,
+		auction_entity.WithAuctionType("dutch"))
+	assert.NotNil(t, err)
+}
+
+func TestStartingPriceIsTheCurrentPriceUntilTheFirstBid(t *testing.T) {
+	auctionEntity, err := auction_entity.CreateAuction(
+		"camera",
+		"photo",
+		"film camera with lens",
+		auction_entity.Used,
+		auction_entity.WithStartingPrice(3000))
+	assert.Nil(t, err)
+
+	output := toAuctionOutputDTO(*auctionEntity)
+	assert.Equal(t, money.Amount(3000), output.StartingPrice)
+	assert.Equal(t, money.Amount(3000), *output.CurrentPrice)
+
+	auctionEntity.CurrentHighestAmount = 3500
+	auctionEntity.CurrentHighestUserId = "bidder"
+
+	output = toAuctionOutputDTO(*auctionEntity)
+	assert.Equal(t, money.Amount(3500), *output.CurrentPrice)
+
+	_, err = auction_entity.CreateAuction(
+		"camera",
+		"photo",
+		"film camera with lens",
+		auction_entity.Used,
+		auction_entity.WithStartingPrice(-1))
+	assert.NotNil(t, err)
+}
+
+func TestFindAuctionsOptionsDefaultAndValidatePagination(t *testing.T) {
+	findInput := FindAuctionsInputDTO{}
+	findOptions, err := toFindAuctionsOptions(&findInput)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, findOptions.Page)
+	assert.Equal(t, defaultPageSize, findOptions.PageSize)
+
+	findInput = FindAuctionsInputDTO{UseCursor: true, Cursor: "cursor"}
+	findOptions, err = toFindAuctionsOptions(&findInput)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, findOptions.Page)
+	assert.True(t, findOptions.UseCursor)
+
+	tests := []struct {
+		name      string
+		findInput FindAuctionsInputDTO
+	}{
+		{name: "negative page", findInput: FindAuctionsInputDTO{Page: -1}},
+		{name: "page past the cap", findInput: FindAuctionsInputDTO{Page: maxPage + 1}},
+		{name: "huge page", findInput: FindAuctionsInputDTO{Page: int(^uint(0) >> 1), PageSize: maxPageSize}},
+		{name: "negative page size", findInput: FindAuctionsInputDTO{PageSize: -5}},
+		{name: "page size past the cap", findInput: FindAuctionsInputDTO{PageSize: maxPageSize + 1}},
+		{name: "page with cursor", findInput: FindAuctionsInputDTO{Page: 2, UseCursor: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := toFindAuctionsOptions(&tt.findInput)
+			assert.NotNil(t, err)
+			assert.Equal(t, internal_error.ErrBadRequest, err.Err)
+		})
+	}
+}
+
+type fakeListAuctionRepository struct {
+	auction_entity.AuctionRepositoryInterface
+	findOptions auction_entity.FindAuctionsOptions
+}
+
+func (f *fakeListAuctionRepository) FindAuctions(
+	ctx context.Context,
+	status auction_entity.AuctionStatus,
+	category, productName string,
+	findOptions auction_entity.FindAuctionsOptions) (*auction_entity.AuctionPage, *internal_error.InternalError) {
+	f.findOptions = findOptions
+	return &auction_entity.AuctionPage{Total: 42}, nil
+}
+
+func TestFindAuctionsPassesFiltersToTheRepository(t *testing.T) {
+	auctionRepository := &fakeListAuctionRepository{}
+	auctionUseCase := NewAuctionUseCase(auctionRepository, nil, nil, nil)
+	createdFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	auctionPage, err := auctionUseCase.FindAuctions(context.Background(), 0, "art", "", FindAuctionsInputDTO{
+		Page: 3, PageSize: 10, Sort: "name_asc", Query: "oil painting", CreatedFrom: createdFrom,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), auctionPage.Total)
+	assert.Equal(t, 3, auctionPage.Page)
+	assert.Equal(t, 10, auctionPage.PageSize)
+	assert.Empty(t, auctionPage.Items)
+	assert.Equal(t, auction_entity.FindAuctionsOptions{
+		Page: 3, PageSize: 10, Sort: "name_asc", Query: "oil painting", CreatedFrom: createdFrom,
+	}, auctionRepository.findOptions)
+
+	_, err = auctionUseCase.FindAuctions(context.Background(), 0, "", "", FindAuctionsInputDTO{Page: maxPage + 1})
+	assert.Equal(t, internal_error.ErrBadRequest, err.Err)
+}
@@ -0,0 +1,104 @@
+package auction_usecase
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+)
+
+const (
+	NoWinnerReasonNoBids        = "no_bids"
+	NoWinnerReasonReserveNotMet = "reserve_not_met"
+)
+
+type AuctionWinnerOutputDTO struct {
+	AuctionId      string         `json:"auction_id"`
+	HasWinner      bool           `json:"has_winner"`
+	Winner         *WinnerUserDTO `json:"winner"`
+	Amount         *money.Amount  `json:"amount"`
+	NoWinnerReason string         `json:"no_winner_reason,omitempty"`
+}
+
+type WinnerUserDTO struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// FindAuctionWinner tells who won a completed auction. Auctions closed before
+// the winner was persisted fall back to the winning bid.
+func (au *AuctionUseCase) FindAuctionWinner(
+	ctx context.Context, auctionId string) (*AuctionWinnerOutputDTO, *internal_error.InternalError) {
+	auctionEntity, err := au.auctionRepositoryInterface.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if auctionEntity.Status != auction_entity.Completed {
+		return nil, internal_error.NewConflictError("Auction is still open, it has no winner yet")
+	}
+
+	winner, noWinnerReason, err := au.resolveWinner(ctx, *auctionEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	if winner == nil {
+		return &AuctionWinnerOutputDTO{AuctionId: auctionEntity.Id, NoWinnerReason: noWinnerReason}, nil
+	}
+
+	return &AuctionWinnerOutputDTO{
+		AuctionId: auctionEntity.Id,
+		HasWinner: true,
+		Winner:    &WinnerUserDTO{Id: winner.UserId, Name: au.findUserName(ctx, winner.UserId)},
+		Amount:    &winner.Amount,
+	}, nil
+}
+
+func (au *AuctionUseCase) resolveWinner(
+	ctx context.Context,
+	auctionEntity auction_entity.Auction) (*WinnerOutputDTO, string, *internal_error.InternalError) {
+	if auctionEntity.WinnerResolved {
+		if auctionEntity.WinnerUserId != "" {
+			return &WinnerOutputDTO{UserId: auctionEntity.WinnerUserId, Amount: auctionEntity.WinningAmount}, "", nil
+		}
+		if auctionEntity.ReserveMet != nil && !*auctionEntity.ReserveMet {
+			return nil, NoWinnerReasonReserveNotMet, nil
+		}
+		return nil, NoWinnerReasonNoBids, nil
+	}
+
+	winningBid, err := au.bidRepositoryInterface.FindWinningBidByAuctionId(ctx, auctionEntity.Id)
+	if err.IsNotFound() {
+		return nil, NoWinnerReasonNoBids, nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	if auctionEntity.HasReserve() && winningBid.Amount < auctionEntity.ReservePrice {
+		return nil, NoWinnerReasonReserveNotMet, nil
+	}
+
+	return &WinnerOutputDTO{UserId: winningBid.UserId, Amount: winningBid.Amount}, "", nil
+}
+
+// findUserName leaves the name empty for bidders without a registered
+// account instead of hiding who won.
+func (au *AuctionUseCase) findUserName(ctx context.Context, userId string) string {
+	if au.userRepositoryInterface == nil {
+		return ""
+	}
+
+	userEntity, err := au.userRepositoryInterface.FindUserById(ctx, userId)
+	if err != nil {
+		if !err.IsNotFound() {
+			logger.ErrorContext(ctx, fmt.Sprintf("Error trying to find the name of auction winner %s", userId), err)
+		}
+		return ""
+	}
+
+	return userEntity.Name
+}
@@ -0,0 +1,37 @@
+package auction_usecase
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/internal_error"
+	"time"
+)
+
+const maxExpiringWindow = 24 * time.Hour
+
+func (au *AuctionUseCase) FindAuctionsExpiringSoon(
+	ctx context.Context,
+	within time.Duration,
+	limit int) ([]AuctionOutputDTO, *internal_error.InternalError) {
+	if within <= 0 || within > maxExpiringWindow {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("within must be greater than 0 and at most %s", maxExpiringWindow))
+	}
+
+	if limit < 1 || limit > maxPageSize {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("limit must be between 1 and %d", maxPageSize))
+	}
+
+	auctionEntities, err := au.auctionRepositoryInterface.FindAuctionsExpiringSoon(ctx, within, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	auctionOutputs := []AuctionOutputDTO{}
+	for _, value := range auctionEntities {
+		auctionOutputs = append(auctionOutputs, toAuctionOutputDTO(value))
+	}
+
+	return auctionOutputs, nil
+}
@@ -0,0 +1,82 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"time"
+)
+
+type ForceCloseAuctionInputDTO struct {
+	Reason string `json:"reason" binding:"required,min=3,max=200"`
+}
+
+type AuditEntryOutputDTO struct {
+	Id             string        `json:"id"`
+	AuctionId      string        `json:"auction_id"`
+	Action         string        `json:"action"`
+	ActorId        string        `json:"actor_id"`
+	Reason         string        `json:"reason"`
+	PreviousStatus AuctionStatus `json:"previous_status"`
+	Timestamp      time.Time     `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+type ForceCloseOutputDTO struct {
+	Auction AuctionOutputDTO    `json:"auction"`
+	Audit   AuditEntryOutputDTO `json:"audit"`
+}
+
+// ForceCloseAuction lets an administrator complete an auction that is not
+// finished yet. Calling it again for a force-closed auction returns the
+// original result instead of failing.
+func (au *AuctionUseCase) ForceCloseAuction(
+	ctx context.Context,
+	auctionId, actorId string,
+	forceCloseInput ForceCloseAuctionInputDTO) (*ForceCloseOutputDTO, *internal_error.InternalError) {
+	var closedAuction *auction_entity.Auction
+	var auditEntry *auction_entity.AuditEntry
+	err := au.retryOnVersionConflict(auctionId, func() *internal_error.InternalError {
+		auctionEntity, err := au.auctionRepositoryInterface.FindAuctionByIdFromPrimary(ctx, auctionId)
+		if err != nil {
+			return err
+		}
+
+		if auctionEntity.CloseReason == auction_entity.CloseReasonForced {
+			closedAuction = auctionEntity
+			auditEntry, err = au.auctionRepositoryInterface.FindAuditEntry(
+				ctx, auctionId, auction_entity.AuditActionForceClose)
+			return err
+		}
+
+		if auctionEntity.Status == auction_entity.Completed || auctionEntity.Status == auction_entity.Cancelled {
+			return internal_error.NewConflictError("Auction is already finished and cannot be force-closed")
+		}
+
+		entry := auction_entity.NewAuditEntry(
+			*auctionEntity, auction_entity.AuditActionForceClose, actorId, forceCloseInput.Reason)
+		closedAuction, err = au.auctionRepositoryInterface.ForceCloseAuction(
+			ctx, auctionId, auctionEntity.Version, entry)
+		auditEntry = &entry
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ForceCloseOutputDTO{
+		Auction: toOwnerAuctionOutputDTO(*closedAuction),
+		Audit:   toAuditEntryOutputDTO(*auditEntry),
+	}, nil
+}
+
+func toAuditEntryOutputDTO(auditEntry auction_entity.AuditEntry) AuditEntryOutputDTO {
+	return AuditEntryOutputDTO{
+		Id:             auditEntry.Id,
+		AuctionId:      auditEntry.AuctionId,
+		Action:         auditEntry.Action,
+		ActorId:        auditEntry.ActorId,
+		Reason:         auditEntry.Reason,
+		PreviousStatus: AuctionStatus(auditEntry.PreviousStatus),
+		Timestamp:      auditEntry.Timestamp,
+	}
+}
@@ -0,0 +1,120 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/event"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type fakeForceCloseAuctionRepository struct {
+	auction_entity.AuctionRepositoryInterface
+	auction     auction_entity.Auction
+	auditLog    []auction_entity.AuditEntry
+	forceCloses int
+}
+
+func (f *fakeForceCloseAuctionRepository) FindAuctionByIdFromPrimary(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	auctionEntity := f.auction
+	return &auctionEntity, nil
+}
+
+func (f *fakeForceCloseAuctionRepository) ForceCloseAuction(
+	ctx context.Context,
+	auctionId string,
+	expectedVersion int64,
+	auditEntry auction_entity.AuditEntry) (*auction_entity.Auction, *internal_error.InternalError) {
+	f.forceCloses++
+	f.auction.Status = auction_entity.Completed
+	f.auction.CloseReason = auction_entity.CloseReasonForced
+	f.auction.WinnerUserId = f.auction.CurrentHighestUserId
+	f.auction.Version++
+	f.auditLog = append(f.auditLog, auditEntry)
+
+	closedAuction := f.auction
+	return &closedAuction, nil
+}
+
+func (f *fakeForceCloseAuctionRepository) FindAuditEntry(
+	ctx context.Context, auctionId, action string) (*auction_entity.AuditEntry, *internal_error.InternalError) {
+	for _, entry := range f.auditLog {
+		if entry.AuctionId == auctionId && entry.Action == action {
+			return &entry, nil
+		}
+	}
+
+	return nil, internal_error.NewNotFoundError("audit entry not found")
+}
+
+func TestForceCloseAuctionRecordsWhoClosedItAndWhy(t *testing.T) {
+	auctionRepository := &fakeForceCloseAuctionRepository{auction: auction_entity.Auction{
+		Id:                   "auction",
+		SellerId:             "seller",
+		Status:               auction_entity.Paused,
+		CurrentHighestUserId: "bidder",
+		Version:              4,
+	}}
+	auctionUseCase := NewAuctionUseCase(auctionRepository, nil, nil, event.NewChannelPublisher())
+	before := time.Now()
+
+	result, err := auctionUseCase.ForceCloseAuction(context.Background(), "auction", "admin",
+		ForceCloseAuctionInputDTO{Reason: "seller reported fraud"})
+	assert.Nil(t, err)
+
+	assert.Len(t, auctionRepository.auditLog, 1)
+	entry := auctionRepository.auditLog[0]
+	assert.Equal(t, "auction:force_close", entry.Id)
+	assert.Equal(t, "auction", entry.AuctionId)
+	assert.Equal(t, auction_entity.AuditActionForceClose, entry.Action)
+	assert.Equal(t, "admin", entry.ActorId)
+	assert.Equal(t, "seller reported fraud", entry.Reason)
+	assert.Equal(t, auction_entity.Paused, entry.PreviousStatus)
+	assert.False(t, entry.Timestamp.Before(before))
+
+	assert.Equal(t, AuctionStatus(auction_entity.Completed), result.Auction.Status)
+	assert.Equal(t, auction_entity.CloseReasonForced, result.Auction.CloseReason)
+	assert.Equal(t, "bidder", result.Auction.WinnerUserId)
+	assert.Equal(t, toAuditEntryOutputDTO(entry), result.Audit)
+}
+
+func TestForceCloseAuctionTwiceReturnsTheFirstResult(t *testing.T) {
+	auctionRepository := &fakeForceCloseAuctionRepository{auction: auction_entity.Auction{
+		Id:     "auction",
+		Status: auction_entity.Active,
+	}}
+	auctionUseCase := NewAuctionUseCase(auctionRepository, nil, nil, event.NewChannelPublisher())
+
+	first, err := auctionUseCase.ForceCloseAuction(context.Background(), "auction", "admin",
+		ForceCloseAuctionInputDTO{Reason: "broken listing"})
+	assert.Nil(t, err)
+
+	second, err := auctionUseCase.ForceCloseAuction(context.Background(), "auction", "other-admin",
+		ForceCloseAuctionInputDTO{Reason: "duplicate request"})
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, auctionRepository.forceCloses)
+	assert.Len(t, auctionRepository.auditLog, 1)
+	assert.Equal(t, first, second)
+	assert.Equal(t, "admin", second.Audit.ActorId)
+	assert.Equal(t, "broken listing", second.Audit.Reason)
+}
+
+func TestFinishedAuctionsCannotBeForceClosed(t *testing.T) {
+	for _, auction := range []auction_entity.Auction{
+		{Id: "auction", Status: auction_entity.Completed, CloseReason: auction_entity.CloseReasonExpired},
+		{Id: "auction", Status: auction_entity.Cancelled, CloseReason: auction_entity.CloseReasonCancelled},
+	} {
+		auctionRepository := &fakeForceCloseAuctionRepository{auction: auction}
+		auctionUseCase := NewAuctionUseCase(auctionRepository, nil, nil, event.NewChannelPublisher())
+
+		_, err := auctionUseCase.ForceCloseAuction(context.Background(), "auction", "admin",
+			ForceCloseAuctionInputDTO{Reason: "too late"})
+		assert.NotNil(t, err)
+		assert.Equal(t, internal_error.ErrConflict, err.Err)
+		assert.Empty(t, auctionRepository.auditLog)
+	}
+}
@@ -0,0 +1,30 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/internal_error"
+)
+
+func (au *AuctionUseCase) PauseAuction(
+	ctx context.Context, auctionId string, callerIsAdmin bool) *internal_error.InternalError {
+	if err := ensureAdmin(callerIsAdmin); err != nil {
+		return err
+	}
+
+	return au.auctionRepositoryInterface.PauseAuction(ctx, auctionId)
+}
+
+func (au *AuctionUseCase) ResumeAuction(
+	ctx context.Context, auctionId string, callerIsAdmin bool) (*AuctionOutputDTO, *internal_error.InternalError) {
+	if err := ensureAdmin(callerIsAdmin); err != nil {
+		return nil, err
+	}
+
+	resumedAuction, err := au.auctionRepositoryInterface.ResumeAuction(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	auctionOutputDTO := toAuctionOutputDTO(*resumedAuction)
+	return &auctionOutputDTO, nil
+}
@@ -0,0 +1,67 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/event"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type fakeModerateAuctionRepository struct {
+	auction_entity.AuctionRepositoryInterface
+	calls []string
+}
+
+func (f *fakeModerateAuctionRepository) FindAuctionByIdFromPrimary(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	return &auction_entity.Auction{Id: id, Status: auction_entity.Active}, nil
+}
+
+func (f *fakeModerateAuctionRepository) SoftDeleteAuction(
+	ctx context.Context, auctionId string) *internal_error.InternalError {
+	f.calls = append(f.calls, "delete")
+	return nil
+}
+
+func (f *fakeModerateAuctionRepository) PauseAuction(
+	ctx context.Context, auctionId string) *internal_error.InternalError {
+	f.calls = append(f.calls, "pause")
+	return nil
+}
+
+func (f *fakeModerateAuctionRepository) ResumeAuction(
+	ctx context.Context, auctionId string) (*auction_entity.Auction, *internal_error.InternalError) {
+	f.calls = append(f.calls, "resume")
+	return &auction_entity.Auction{Id: auctionId, Status: auction_entity.Active}, nil
+}
+
+func TestOnlyAnAdminCanDeletePauseOrResumeAnAuction(t *testing.T) {
+	for _, callerIsAdmin := range []bool{false, true} {
+		auctionRepository := &fakeModerateAuctionRepository{}
+		auctionUseCase := NewAuctionUseCase(auctionRepository, nil, nil, event.NewChannelPublisher())
+		ctx := context.Background()
+
+		errs := []*internal_error.InternalError{
+			auctionUseCase.DeleteAuction(ctx, "auction", callerIsAdmin),
+			auctionUseCase.PauseAuction(ctx, "auction", callerIsAdmin),
+		}
+		_, err := auctionUseCase.ResumeAuction(ctx, "auction", callerIsAdmin)
+		errs = append(errs, err)
+
+		for _, err := range errs {
+			if callerIsAdmin {
+				assert.Nil(t, err)
+			} else {
+				assert.Equal(t, internal_error.ErrForbidden, err.Err)
+			}
+		}
+
+		if callerIsAdmin {
+			assert.Equal(t, []string{"delete", "pause", "resume"}, auctionRepository.calls)
+		} else {
+			assert.Empty(t, auctionRepository.calls)
+		}
+	}
+}
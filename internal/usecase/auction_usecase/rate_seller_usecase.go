@@ -0,0 +1,65 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"time"
+)
+
+type RatingInputDTO struct {
+	Stars   int    `json:"stars" binding:"required,min=1,max=5"`
+	Comment string `json:"comment" binding:"omitempty,max=500"`
+}
+
+type RatingOutputDTO struct {
+	Id        string    `json:"id"`
+	AuctionId string    `json:"auction_id"`
+	RaterId   string    `json:"rater_id"`
+	SellerId  string    `json:"seller_id"`
+	Stars     int       `json:"stars"`
+	Comment   string    `json:"comment,omitempty"`
+	Timestamp time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+// RateSeller lets the recorded winner of a completed auction rate its seller.
+func (au *AuctionUseCase) RateSeller(
+	ctx context.Context,
+	auctionId, raterId string,
+	ratingInput RatingInputDTO) (*RatingOutputDTO, *internal_error.InternalError) {
+	auctionEntity, err := au.auctionRepositoryInterface.FindAuctionByIdFromPrimary(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if auctionEntity.Status != auction_entity.Completed {
+		return nil, internal_error.NewBadRequestError("Only completed auctions can be rated")
+	}
+	if auctionEntity.WinnerUserId == "" || auctionEntity.WinnerUserId != raterId {
+		return nil, internal_error.NewForbiddenError("Only the winner of the auction can rate its seller")
+	}
+	if auctionEntity.SellerId == "" {
+		return nil, internal_error.NewBadRequestError("Auction has no seller to rate")
+	}
+
+	rating, err := user_entity.NewRating(
+		auctionId, raterId, auctionEntity.SellerId, ratingInput.Stars, ratingInput.Comment)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := au.userRepositoryInterface.RateSeller(ctx, *rating); err != nil {
+		return nil, err
+	}
+
+	return &RatingOutputDTO{
+		Id:        rating.Id,
+		AuctionId: rating.AuctionId,
+		RaterId:   rating.RaterId,
+		SellerId:  rating.SellerId,
+		Stars:     rating.Stars,
+		Comment:   rating.Comment,
+		Timestamp: rating.Timestamp,
+	}, nil
+}
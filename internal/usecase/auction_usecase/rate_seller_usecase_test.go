@@ -0,0 +1,63 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type fakeRatingUserRepository struct {
+	user_entity.UserRepositoryInterface
+	ratings []user_entity.Rating
+}
+
+func (f *fakeRatingUserRepository) RateSeller(
+	ctx context.Context, rating user_entity.Rating) *internal_error.InternalError {
+	f.ratings = append(f.ratings, rating)
+	return nil
+}
+
+func TestOnlyTheWinnerOfACompletedAuctionCanRateTheSeller(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   auction_entity.AuctionStatus
+		raterId  string
+		expected string
+	}{
+		{name: "winner", status: auction_entity.Completed, raterId: "winner"},
+		{name: "another user", status: auction_entity.Completed, raterId: "buyer", expected: "forbidden"},
+		{name: "auction still active", status: auction_entity.Active, raterId: "winner", expected: "bad_request"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auctionRepository := &fakeCancelAuctionRepository{
+				auction: &auction_entity.Auction{
+					Id:           "auction",
+					SellerId:     "seller",
+					WinnerUserId: "winner",
+					Status:       tt.status,
+				},
+			}
+			userRepository := &fakeRatingUserRepository{}
+			auctionUseCase := NewAuctionUseCase(auctionRepository, nil, userRepository, nil)
+
+			rating, err := auctionUseCase.RateSeller(
+				context.Background(), "auction", tt.raterId, RatingInputDTO{Stars: 4, Comment: "fast shipping"})
+
+			if tt.expected == "" {
+				assert.Nil(t, err)
+				assert.Equal(t, "seller", rating.SellerId)
+				assert.Len(t, userRepository.ratings, 1)
+				assert.Equal(t, 4, userRepository.ratings[0].Stars)
+			} else {
+				assert.NotNil(t, err)
+				assert.Equal(t, tt.expected, err.Err)
+				assert.Empty(t, userRepository.ratings)
+			}
+		})
+	}
+}
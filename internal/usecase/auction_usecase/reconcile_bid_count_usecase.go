@@ -0,0 +1,20 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/internal_error"
+)
+
+type BidCountReconciliationOutputDTO struct {
+	Reconciled int64 `json:"reconciled"`
+}
+
+func (au *AuctionUseCase) ReconcileBidCounts(
+	ctx context.Context) (*BidCountReconciliationOutputDTO, *internal_error.InternalError) {
+	reconciled, err := au.auctionRepositoryInterface.ReconcileBidCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BidCountReconciliationOutputDTO{Reconciled: reconciled}, nil
+}
@@ -0,0 +1,48 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+)
+
+type UpdateAuctionInputDTO struct {
+	ProductName string `json:"product_name" binding:"omitempty,min=1"`
+	Category    string `json:"category" binding:"omitempty,min=2"`
+	Description string `json:"description" binding:"omitempty,min=10,max=200"`
+}
+
+func (au *AuctionUseCase) UpdateAuction(
+	ctx context.Context,
+	auctionId, callerId string,
+	updateInput UpdateAuctionInputDTO) (*AuctionOutputDTO, *internal_error.InternalError) {
+	if updateInput.ProductName == "" && updateInput.Category == "" && updateInput.Description == "" {
+		return nil, internal_error.NewBadRequestError("At least one field must be provided")
+	}
+
+	auctionEntity, err := au.auctionRepositoryInterface.FindAuctionByIdFromPrimary(ctx, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureAuctionOwner(auctionEntity, callerId, false); err != nil {
+		return nil, err
+	}
+
+	if auctionEntity.Status != auction_entity.Active {
+		return nil, internal_error.NewConflictError("Only active auctions can be updated")
+	}
+
+	updatedAuction, err := au.auctionRepositoryInterface.UpdateAuction(ctx, auctionId,
+		auction_entity.AuctionUpdate{
+			ProductName: updateInput.ProductName,
+			Category:    updateInput.Category,
+			Description: updateInput.Description,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	auctionOutputDTO := toAuctionOutputDTO(*updatedAuction)
+	return &auctionOutputDTO, nil
+}
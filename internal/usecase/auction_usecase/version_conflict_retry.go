@@ -0,0 +1,23 @@
+package auction_usecase
+
+import (
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/internal_error"
+)
+
+func (au *AuctionUseCase) retryOnVersionConflict(
+	auctionId string, operation func() *internal_error.InternalError) *internal_error.InternalError {
+	maxAttempts := au.versionConflictMaxAttempts
+
+	var err *internal_error.InternalError
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = operation(); !err.IsVersionConflict() {
+			return err
+		}
+
+		logger.Info(fmt.Sprintf("Version conflict on auction %s (attempt %d/%d)", auctionId, attempt, maxAttempts))
+	}
+
+	return err
+}
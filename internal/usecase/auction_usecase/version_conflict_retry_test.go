@@ -0,0 +1,83 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/config"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/database/auction"
+	"fullcycle-auction_go/internal/infra/event"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"path"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConcurrentCloseAndExtendDoNotLoseUpdates(t *testing.T) {
+	cfg, err := config.Load(path.Join("./", "../../../cmd/auction/.env"))
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	ctx := context.Background()
+	conn, err := mongodb.NewMongoDBConnection(ctx, cfg.Mongo)
+	if err != nil {
+		log.Fatal("Error trying to connect mongodb")
+	}
+
+	auctionRepository := auction.NewAuctionRepository(conn)
+	defer auctionRepository.Shutdown(ctx)
+
+	auctionEntity, _ := auction_entity.CreateAuction(
+		"guitar",
+		"instruments",
+		"electric guitar with case",
+		auction_entity.Used,
+		auction_entity.WithDuration(time.Hour))
+	assert.Nil(t, auctionRepository.CreateAuction(ctx, auctionEntity))
+
+	auctionUseCase := NewAuctionUseCase(auctionRepository, nil, nil, event.NewChannelPublisher(),
+		WithVersionConflictMaxAttempts(50))
+
+	const extenders = 10
+	var extended int64
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+
+	for i := 0; i < extenders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			_, err := auctionUseCase.ExtendAuction(
+				ctx, auctionEntity.Id, "", true, ExtendAuctionInputDTO{ExtraSeconds: 60})
+			if err == nil {
+				atomic.AddInt64(&extended, 1)
+				return
+			}
+
+			assert.Equal(t, "conflict", err.Err)
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-start
+
+		assert.Nil(t, auctionUseCase.CloseAuction(ctx, auctionEntity.Id, "", true))
+	}()
+
+	close(start)
+	wg.Wait()
+
+	auctionDb, findErr := auctionRepository.FindAuctionById(ctx, auctionEntity.Id)
+	assert.Nil(t, findErr)
+	assert.Equal(t, auction_entity.Completed, auctionDb.Status)
+	assert.Equal(t, time.Duration(extended)*time.Minute, auctionDb.Extension)
+	assert.Equal(t, extended+1, auctionDb.Version)
+}
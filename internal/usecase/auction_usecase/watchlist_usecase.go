@@ -0,0 +1,66 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"time"
+)
+
+func (au *AuctionUseCase) AddToWatchlist(
+	ctx context.Context, userId, auctionId string) *internal_error.InternalError {
+	return au.auctionRepositoryInterface.AddToWatchlist(ctx, userId, auctionId)
+}
+
+func (au *AuctionUseCase) RemoveFromWatchlist(
+	ctx context.Context, userId, auctionId string) *internal_error.InternalError {
+	return au.auctionRepositoryInterface.RemoveFromWatchlist(ctx, userId, auctionId)
+}
+
+func (au *AuctionUseCase) FindWatchedAuctions(
+	ctx context.Context, userId string) ([]AuctionSummaryOutputDTO, *internal_error.InternalError) {
+	summaries, err := au.auctionRepositoryInterface.FindWatchedAuctions(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	summaryOutputs := []AuctionSummaryOutputDTO{}
+	for _, summary := range summaries {
+		summaryOutputs = append(summaryOutputs, toAuctionSummaryOutputDTO(summary))
+	}
+
+	return summaryOutputs, nil
+}
+
+// NotifyWatchersOfEndingAuctions tells every watcher of an auction entering
+// its final stretch, once per watchlist entry.
+func (au *AuctionUseCase) NotifyWatchersOfEndingAuctions(ctx context.Context) {
+	endingWatches, err := au.auctionRepositoryInterface.ClaimEndingWatches(ctx, au.watchlistEndingWindow)
+	if err != nil {
+		logger.ErrorContext(ctx, "Error trying to notify watchers of auctions ending soon", err)
+	}
+
+	for _, endingWatch := range endingWatches {
+		au.eventPublisher.Publish(ctx, event_entity.WatchedAuctionEndingEvent{
+			AuctionId: endingWatch.AuctionId,
+			UserId:    endingWatch.UserId,
+			EndTime:   endingWatch.EndTime,
+			Timestamp: time.Now(),
+		})
+	}
+}
+
+func (au *AuctionUseCase) RunWatchlistNotifier(ctx context.Context) {
+	ticker := time.NewTicker(au.watchlistNotifyInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			au.NotifyWatchersOfEndingAuctions(ctx)
+		}
+	}
+}
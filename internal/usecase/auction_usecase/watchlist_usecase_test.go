@@ -0,0 +1,53 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"fullcycle-auction_go/internal/infra/event"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type fakeWatchlistAuctionRepository struct {
+	auction_entity.AuctionRepositoryInterface
+	endingWatches []auction_entity.WatchedAuctionEnding
+	window        time.Duration
+}
+
+func (f *fakeWatchlistAuctionRepository) ClaimEndingWatches(
+	ctx context.Context, within time.Duration) ([]auction_entity.WatchedAuctionEnding, *internal_error.InternalError) {
+	f.window = within
+	endingWatches := f.endingWatches
+	f.endingWatches = nil
+	return endingWatches, nil
+}
+
+func TestWatchersAreNotifiedOnceWhenAnAuctionEntersItsFinalHour(t *testing.T) {
+	endTime := time.Now().Add(30 * time.Minute)
+	auctionRepository := &fakeWatchlistAuctionRepository{
+		endingWatches: []auction_entity.WatchedAuctionEnding{
+			{AuctionId: "auction", UserId: "first-watcher", EndTime: endTime},
+			{AuctionId: "auction", UserId: "second-watcher", EndTime: endTime},
+		},
+	}
+	publisher := event.NewChannelPublisher()
+	auctionUseCase := NewAuctionUseCase(auctionRepository, nil, nil, publisher)
+
+	auctionUseCase.NotifyWatchersOfEndingAuctions(context.Background())
+	auctionUseCase.NotifyWatchersOfEndingAuctions(context.Background())
+
+	assert.Equal(t, time.Hour, auctionRepository.window)
+	assert.Len(t, publisher.Events(), 2)
+
+	var notified []string
+	for len(publisher.Events()) > 0 {
+		endingEvent := (<-publisher.Events()).(event_entity.WatchedAuctionEndingEvent)
+		assert.Equal(t, "auction", endingEvent.AuctionId)
+		assert.Equal(t, endTime, endingEvent.EndTime)
+		notified = append(notified, endingEvent.UserId)
+	}
+	assert.Equal(t, []string{"first-watcher", "second-watcher"}, notified)
+}
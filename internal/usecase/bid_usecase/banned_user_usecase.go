@@ -0,0 +1,85 @@
+package bid_usecase
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+)
+
+// ensureUserCanBid rejects bids from suspended and banned users. Bidders
+// without a registered account are not restricted.
+func (bu *BidUseCase) ensureUserCanBid(ctx context.Context, userId string) *internal_error.InternalError {
+	userEntity, err := bu.UserRepository.FindUserById(ctx, userId)
+	if err != nil {
+		if err.IsNotFound() {
+			return nil
+		}
+
+		return err
+	}
+
+	return userEntity.EnsureCanBid(bu.Clock.Now())
+}
+
+// OnUserBanned voids the bids a banned user has on auctions that have not
+// ended, drops their proxies there and gives the lead of the auctions they
+// were winning to the runner-up.
+func (bu *BidUseCase) OnUserBanned(ctx context.Context, userId string) {
+	if err := bu.Flush(ctx); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to flush bids before voiding the bids of user %s", userId), err)
+	}
+
+	auctionIds, err := bu.BidRepository.FindAuctionIdsByUserId(ctx, userId)
+	if err != nil {
+		return
+	}
+
+	auctions, err := bu.AuctionRepository.FindAuctionsByIds(ctx, auctionIds)
+	if err != nil {
+		return
+	}
+
+	var openAuctionIds []string
+	for _, auctionEntity := range auctions {
+		if auctionEntity.Status != auction_entity.Completed && auctionEntity.Status != auction_entity.Cancelled {
+			openAuctionIds = append(openAuctionIds, auctionEntity.Id)
+		}
+	}
+
+	if err := bu.BidRepository.VoidBidsByUserId(ctx, userId, openAuctionIds); err != nil {
+		return
+	}
+
+	for _, auctionId := range openAuctionIds {
+		if err := bu.BidRepository.DeleteMaxBid(ctx, auctionId, userId); err != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf("Error trying to drop the proxy of banned user %s", userId), err)
+		}
+
+		if err := bu.removeLeader(ctx, auctionId, userId); err != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf(
+				"Error trying to promote the runner-up of auction %s led by banned user %s", auctionId, userId), err)
+		}
+	}
+
+	logger.InfoContext(ctx, fmt.Sprintf("Voided the bids of banned user %s on %d open auctions", userId, len(openAuctionIds)))
+}
+
+func (bu *BidUseCase) removeLeader(ctx context.Context, auctionId, userId string) *internal_error.InternalError {
+	unlock, err := bu.lockAuction(ctx, auctionId)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	auctionEntity, err := bu.AuctionRepository.FindAuctionByIdFromPrimary(ctx, auctionId)
+	if err != nil {
+		return err
+	}
+	if auctionEntity.CurrentHighestUserId != userId {
+		return nil
+	}
+
+	return bu.promoteRunnerUp(ctx, auctionId, userId, auctionEntity.CurrentHighestAmount)
+}
@@ -0,0 +1,212 @@
+package bid_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/infra/event"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCreateBidRejectsSuspendedAndBannedUsers(t *testing.T) {
+	tests := []struct {
+		name        string
+		user        user_entity.User
+		wantMessage string
+	}{
+		{
+			name:        "suspended",
+			user:        user_entity.User{Status: user_entity.SuspendedStatus, StatusReason: "chargeback"},
+			wantMessage: "Your account is suspended: chargeback",
+		},
+		{
+			name: "suspension expired",
+			user: user_entity.User{
+				Status: user_entity.SuspendedStatus, StatusReason: "chargeback",
+				SuspendedUntil: time.Now().Add(-time.Minute),
+			},
+		},
+		{
+			name:        "banned",
+			user:        user_entity.User{Status: user_entity.BannedStatus, StatusReason: "shill bidding"},
+			wantMessage: "Your account is banned: shill bidding",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userId, auctionId := uuid.New().String(), uuid.New().String()
+			userEntity := tt.user
+			userEntity.Id = userId
+			userRepository := &fakeBalanceUserRepository{
+				balances: map[string]money.Amount{userId: 10000},
+				held:     map[string]money.Amount{},
+				users:    map[string]*user_entity.User{userId: &userEntity},
+			}
+			auctionRepository := &fakeBiddingAuctionRepository{auction: auction_entity.Auction{
+				Id:      auctionId,
+				Status:  auction_entity.Active,
+				EndTime: time.Now().Add(time.Hour),
+			}}
+			bidUseCase := NewBidUseCase(
+				&fakeBatchBidRepository{}, auctionRepository, userRepository, event.NewChannelPublisher())
+			defer bidUseCase.Close(context.Background())
+
+			_, err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+				UserId:    userId,
+				AuctionId: auctionId,
+				Amount:    5000,
+			})
+
+			if tt.wantMessage == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.Equal(t, internal_error.ErrForbidden, err.Err)
+			assert.Equal(t, tt.wantMessage, err.Message)
+			assert.Equal(t, money.Amount(0), userRepository.held[userId])
+		})
+	}
+}
+
+func TestCreateBidChecksTheSuspensionAgainstTheClock(t *testing.T) {
+	userId, auctionId := uuid.New().String(), uuid.New().String()
+	fakeClock := fakeclock.New(time.Now())
+	userRepository := &fakeBalanceUserRepository{
+		balances: map[string]money.Amount{userId: 10000},
+		held:     map[string]money.Amount{},
+		users: map[string]*user_entity.User{userId: {
+			Id: userId, Status: user_entity.SuspendedStatus, StatusReason: "chargeback",
+			SuspendedUntil: fakeClock.Now().Add(time.Hour),
+		}},
+	}
+	auctionRepository := &fakeBiddingAuctionRepository{auction: auction_entity.Auction{
+		Id:      auctionId,
+		Status:  auction_entity.Active,
+		EndTime: fakeClock.Now().Add(24 * time.Hour),
+	}}
+	bidUseCase := NewBidUseCase(&fakeBatchBidRepository{}, auctionRepository, userRepository,
+		event.NewChannelPublisher(), WithClock(fakeClock))
+	defer bidUseCase.Close(context.Background())
+
+	placeBid := func(amount money.Amount) *internal_error.InternalError {
+		_, err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+			UserId: userId, AuctionId: auctionId, Amount: amount,
+		})
+		return err
+	}
+
+	assert.Equal(t, internal_error.ErrForbidden, placeBid(5000).Err)
+
+	fakeClock.Advance(2 * time.Hour)
+	assert.Nil(t, placeBid(5000))
+}
+
+type fakeBannedAuctionRepository struct {
+	fakeBiddingAuctionRepository
+}
+
+func (f *fakeBannedAuctionRepository) FindAuctionsByIds(
+	ctx context.Context, auctionIds []string) ([]auction_entity.Auction, *internal_error.InternalError) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return []auction_entity.Auction{f.auction}, nil
+}
+
+func (f *fakeBannedAuctionRepository) ReplaceHighestBid(
+	ctx context.Context,
+	auctionId, fromUserId string,
+	fromAmount money.Amount,
+	toUserId string,
+	toAmount money.Amount) *internal_error.InternalError {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.auction.CurrentHighestUserId == fromUserId && f.auction.CurrentHighestAmount == fromAmount {
+		f.auction.CurrentHighestUserId, f.auction.CurrentHighestAmount = toUserId, toAmount
+	}
+	return nil
+}
+
+type fakeBannedBidRepository struct {
+	fakeBatchBidRepository
+	bids          []bid_entity.Bid
+	deletedMaxBid string
+}
+
+func (f *fakeBannedBidRepository) FindAuctionIdsByUserId(
+	ctx context.Context, userId string) ([]string, *internal_error.InternalError) {
+	return []string{f.bids[0].AuctionId}, nil
+}
+
+func (f *fakeBannedBidRepository) VoidBidsByUserId(
+	ctx context.Context, userId string, auctionIds []string) *internal_error.InternalError {
+	for i := range f.bids {
+		if f.bids[i].UserId == userId {
+			f.bids[i].Voided = true
+		}
+	}
+	return nil
+}
+
+func (f *fakeBannedBidRepository) DeleteMaxBid(
+	ctx context.Context, auctionId, userId string) *internal_error.InternalError {
+	f.deletedMaxBid = userId
+	return nil
+}
+
+func (f *fakeBannedBidRepository) FindHighestBidByAuctionId(
+	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	var highestBid *bid_entity.Bid
+	for i := range f.bids {
+		if !f.bids[i].Voided && (highestBid == nil || f.bids[i].Amount > highestBid.Amount) {
+			highestBid = &f.bids[i]
+		}
+	}
+	if highestBid == nil {
+		return nil, internal_error.NewNotFoundError("No bids found")
+	}
+
+	return highestBid, nil
+}
+
+func TestBanningTheLeaderPromotesTheRunnerUp(t *testing.T) {
+	banned, runnerUp := uuid.New().String(), uuid.New().String()
+	auctionId := uuid.New().String()
+	userRepository := &fakeBalanceUserRepository{
+		balances: map[string]money.Amount{banned: 10000, runnerUp: 10000},
+		held:     map[string]money.Amount{banned: 9000},
+	}
+	auctionRepository := &fakeBannedAuctionRepository{fakeBiddingAuctionRepository{auction: auction_entity.Auction{
+		Id:                   auctionId,
+		Status:               auction_entity.Active,
+		EndTime:              time.Now().Add(time.Hour),
+		CurrentHighestUserId: banned,
+		CurrentHighestAmount: 9000,
+	}}}
+	bidRepository := &fakeBannedBidRepository{bids: []bid_entity.Bid{
+		{Id: "first", UserId: banned, AuctionId: auctionId, Amount: 6000},
+		{Id: "second", UserId: runnerUp, AuctionId: auctionId, Amount: 7000},
+		{Id: "third", UserId: banned, AuctionId: auctionId, Amount: 9000},
+	}}
+	bidUseCase := NewBidUseCase(bidRepository, auctionRepository, userRepository, event.NewChannelPublisher())
+	defer bidUseCase.Close(context.Background())
+
+	bidUseCase.OnUserBanned(context.Background(), banned)
+
+	assert.Equal(t, runnerUp, auctionRepository.auction.CurrentHighestUserId)
+	assert.Equal(t, money.Amount(7000), auctionRepository.auction.CurrentHighestAmount)
+	assert.Equal(t, money.Amount(0), userRepository.held[banned])
+	assert.Equal(t, money.Amount(7000), userRepository.held[runnerUp])
+	assert.Equal(t, banned, bidRepository.deletedMaxBid)
+	assert.True(t, bidRepository.bids[0].Voided)
+	assert.False(t, bidRepository.bids[1].Voided)
+}
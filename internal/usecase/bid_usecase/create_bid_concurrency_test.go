@@ -0,0 +1,116 @@
+package bid_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/config"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/database/auction"
+	"fullcycle-auction_go/internal/infra/database/bid"
+	"fullcycle-auction_go/internal/infra/database/user"
+	"fullcycle-auction_go/internal/infra/event"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"math/rand"
+	"path"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Two use cases stand in for two instances of the service, so their
+// in-process auction locks cannot serialize the bids and only the
+// conditional update on the auction document decides which ones win.
+func TestConcurrentBidsKeepTheHighestAcceptedAmount(t *testing.T) {
+	cfg, err := config.Load(path.Join("./", "../../../cmd/auction/.env"))
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	ctx := context.Background()
+	conn, err := mongodb.NewMongoDBConnection(ctx, cfg.Mongo)
+	if err != nil {
+		log.Fatal("Error trying to connect mongodb")
+	}
+
+	auctionRepository := auction.NewAuctionRepository(conn)
+	defer auctionRepository.Shutdown(ctx)
+	auctionEntity, _ := auction_entity.CreateAuction(
+		"console",
+		"games",
+		"retro game console with controllers",
+		auction_entity.Used,
+		auction_entity.WithDuration(time.Hour))
+	assert.Nil(t, auctionRepository.CreateAuction(ctx, auctionEntity))
+
+	bidRepository := bid.NewBidRepository(conn, auctionRepository)
+	userRepository := user.NewUserRepository(conn)
+	instances := []BidUseCaseInterface{
+		NewBidUseCase(bidRepository, auctionRepository, userRepository, event.NewChannelPublisher(),
+			WithBatching(1000, time.Hour), WithRateLimiter(nil)),
+		NewBidUseCase(bidRepository, auctionRepository, userRepository, event.NewChannelPublisher(),
+			WithBatching(1000, time.Hour), WithRateLimiter(nil)),
+	}
+
+	const bidders = 200
+	userIds := make([]string, bidders)
+	for i := range userIds {
+		userIds[i] = uuid.New().String()
+		_, insertErr := userRepository.Collection.InsertOne(ctx, user.UserEntityMongo{Id: userIds[i], Name: "bidder"})
+		assert.Nil(t, insertErr)
+	}
+
+	var mutex sync.Mutex
+	var accepted []money.Amount
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+
+	for i, userId := range userIds {
+		wg.Add(1)
+		go func(bidUseCase BidUseCaseInterface, userId string, amount money.Amount) {
+			defer wg.Done()
+			<-start
+
+			_, err := bidUseCase.CreateBid(ctx, BidInputDTO{
+				UserId:    userId,
+				AuctionId: auctionEntity.Id,
+				Amount:    amount,
+			})
+			if err != nil {
+				assert.Equal(t, internal_error.ErrBadRequest, err.Err)
+				return
+			}
+
+			mutex.Lock()
+			accepted = append(accepted, amount)
+			mutex.Unlock()
+		}(instances[i%len(instances)], userId, money.Amount(rand.Intn(100000)+1))
+	}
+	close(start)
+	wg.Wait()
+
+	for _, bidUseCase := range instances {
+		assert.Nil(t, bidUseCase.Close(ctx))
+	}
+
+	var highest money.Amount
+	for _, amount := range accepted {
+		if amount > highest {
+			highest = amount
+		}
+	}
+
+	auctionDb, findErr := auctionRepository.FindAuctionByIdFromPrimary(ctx, auctionEntity.Id)
+	assert.Nil(t, findErr)
+	assert.Equal(t, highest, auctionDb.CurrentHighestAmount)
+	assert.Equal(t, int64(len(accepted)), auctionDb.BidCount)
+
+	stored, storeErr := bidRepository.FindBidByAuctionId(ctx, auctionEntity.Id, bid_entity.FindBidsOptions{Limit: 1})
+	assert.Nil(t, storeErr)
+	assert.Equal(t, int64(len(accepted)), stored.Total)
+	assert.Equal(t, highest, stored.Bids[0].Amount)
+}
@@ -2,47 +2,218 @@ package bid_usecase
 
 import (
 	"context"
+	"fmt"
 	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/clock"
+	"fullcycle-auction_go/internal/entity/auction_entity"
 	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"fullcycle-auction_go/internal/entity/user_entity"
 	"fullcycle-auction_go/internal/internal_error"
-	"os"
-	"strconv"
+	"fullcycle-auction_go/internal/money"
+	"fullcycle-auction_go/internal/ratelimit"
+	"sync"
 	"time"
 )
 
+const (
+	defaultMaxBatchSize        = 5
+	defaultBatchInsertInterval = 3 * time.Minute
+	defaultSnipeWindow         = 30 * time.Second
+	defaultSnipeExtension      = 30 * time.Second
+	defaultMaxBulkBids         = 100
+	defaultBulkBidTimeout      = 10 * time.Second
+	defaultRetractionWindow    = time.Minute
+)
+
+type bidUseCaseSettings struct {
+	maxBatchSize        int
+	batchInsertInterval time.Duration
+	clock               clock.Clock
+	minIncrement        money.Amount
+	snipeExtension      auction_entity.SnipeExtension
+	rateLimiter         ratelimit.Limiter
+	maxBulkBids         int
+	bulkBidTimeout      time.Duration
+	retractionWindow    time.Duration
+}
+
+type BidUseCaseOption func(settings *bidUseCaseSettings)
+
+// WithBatching stores accepted bids once maxSize of them are waiting, or
+// every interval.
+func WithBatching(maxSize int, interval time.Duration) BidUseCaseOption {
+	return func(settings *bidUseCaseSettings) {
+		settings.maxBatchSize = maxSize
+		settings.batchInsertInterval = interval
+	}
+}
+
+// WithClock sets the clock bids are timed against, such as the snipe window
+// and the price of dutch auctions.
+func WithClock(bidClock clock.Clock) BidUseCaseOption {
+	return func(settings *bidUseCaseSettings) {
+		settings.clock = bidClock
+	}
+}
+
+// WithMinIncrement sets the increment required over the highest bid of
+// auctions that set none of their own.
+func WithMinIncrement(minIncrement money.Amount) BidUseCaseOption {
+	return func(settings *bidUseCaseSettings) {
+		settings.minIncrement = minIncrement
+	}
+}
+
+// WithSnipeExtension extends an auction by extension when a bid arrives
+// within window of its end.
+func WithSnipeExtension(window, extension time.Duration) BidUseCaseOption {
+	return func(settings *bidUseCaseSettings) {
+		settings.snipeExtension = auction_entity.SnipeExtension{Window: window, Extension: extension}
+	}
+}
+
+// WithRateLimiter limits the bids of each user; a nil limiter disables the
+// limit.
+func WithRateLimiter(limiter ratelimit.Limiter) BidUseCaseOption {
+	return func(settings *bidUseCaseSettings) {
+		settings.rateLimiter = limiter
+	}
+}
+
+// WithBulkLimits caps bulk requests at maxItems bids, and reports the bids
+// not started after timeout as errored.
+func WithBulkLimits(maxItems int, timeout time.Duration) BidUseCaseOption {
+	return func(settings *bidUseCaseSettings) {
+		settings.maxBulkBids = maxItems
+		settings.bulkBidTimeout = timeout
+	}
+}
+
+// WithRetractionWindow sets how long after being placed a bid can be
+// retracted.
+func WithRetractionWindow(window time.Duration) BidUseCaseOption {
+	return func(settings *bidUseCaseSettings) {
+		settings.retractionWindow = window
+	}
+}
+
 type BidInputDTO struct {
-	UserId    string  `json:"user_id"`
-	AuctionId string  `json:"auction_id"`
-	Amount    float64 `json:"amount"`
+	AuctionId string       `json:"auction_id"`
+	Amount    money.Amount `json:"amount"`
+	MaxAmount money.Amount `json:"max_amount"`
+
+	UserId         string `json:"-"`
+	IdempotencyKey string `json:"-"`
+}
+
+// violations are the rules of the request itself; the rules of the bid come
+// from its entity.
+func (b BidInputDTO) violations() []internal_error.FieldViolation {
+	if b.MaxAmount < 0 {
+		return []internal_error.FieldViolation{{
+			Field: "max_amount", Constraint: "min", Message: "max_amount cannot be negative"}}
+	}
+
+	return nil
 }
 
 type BidOutputDTO struct {
-	Id        string    `json:"id"`
-	UserId    string    `json:"user_id"`
-	AuctionId string    `json:"auction_id"`
-	Amount    float64   `json:"amount"`
-	Timestamp time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	Id        string       `json:"id"`
+	UserId    string       `json:"user_id"`
+	AuctionId string       `json:"auction_id"`
+	Amount    money.Amount `json:"amount"`
+	Timestamp time.Time    `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	Sequence  int64        `json:"sequence,omitempty"`
+	Auto      bool         `json:"auto,omitempty"`
+	Retracted bool         `json:"retracted,omitempty"`
+	Voided    bool         `json:"voided,omitempty"`
+}
+
+type FindBidsInputDTO struct {
+	Limit    int
+	Offset   int
+	Order    string
+	CallerId string
+}
+
+type BidPageOutputDTO struct {
+	Items  []BidOutputDTO `json:"items"`
+	Total  int64          `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+	Order  string         `json:"order"`
 }
 
 type BidUseCase struct {
-	BidRepository bid_entity.BidEntityRepository
+	BidRepository     bid_entity.BidEntityRepository
+	AuctionRepository auction_entity.AuctionRepositoryInterface
+	UserRepository    user_entity.UserRepositoryInterface
+	EventPublisher    event_entity.EventPublisher
+	RateLimiter       ratelimit.Limiter
+	Clock             clock.Clock
+
+	minIncrement   money.Amount
+	snipeExtension auction_entity.SnipeExtension
+	maxBulkBids    int
+	bulkBidTimeout time.Duration
 
 	timer               *time.Timer
 	maxBatchSize        int
 	batchInsertInterval time.Duration
 	bidChannel          chan bid_entity.Bid
+	auctionLocks        *auctionLocker
+	recentBids          *recentBids
+
+	flushRequests chan chan struct{}
+	stop          chan struct{}
+	stopped       chan struct{}
+	closed        bool
+	closeMutex    *sync.RWMutex
 }
 
-func NewBidUseCase(bidRepository bid_entity.BidEntityRepository) BidUseCaseInterface {
-	maxSizeInterval := getMaxBatchSizeInterval()
-	maxBatchSize := getMaxBatchSize()
+func NewBidUseCase(
+	bidRepository bid_entity.BidEntityRepository,
+	auctionRepository auction_entity.AuctionRepositoryInterface,
+	userRepository user_entity.UserRepositoryInterface,
+	eventPublisher event_entity.EventPublisher,
+	opts ...BidUseCaseOption) BidUseCaseInterface {
+	settings := bidUseCaseSettings{
+		maxBatchSize:        defaultMaxBatchSize,
+		batchInsertInterval: defaultBatchInsertInterval,
+		clock:               clock.NewRealClock(),
+		snipeExtension:      auction_entity.SnipeExtension{Window: defaultSnipeWindow, Extension: defaultSnipeExtension},
+		rateLimiter: ratelimit.NewInMemoryLimiter(
+			ratelimit.Rate{Events: 5, Per: time.Second}, 10, clock.NewRealClock()),
+		maxBulkBids:      defaultMaxBulkBids,
+		bulkBidTimeout:   defaultBulkBidTimeout,
+		retractionWindow: defaultRetractionWindow,
+	}
+	for _, opt := range opts {
+		opt(&settings)
+	}
 
 	bidUseCase := &BidUseCase{
 		BidRepository:       bidRepository,
-		maxBatchSize:        maxBatchSize,
-		batchInsertInterval: maxSizeInterval,
-		timer:               time.NewTimer(maxSizeInterval),
-		bidChannel:          make(chan bid_entity.Bid, maxBatchSize),
+		AuctionRepository:   auctionRepository,
+		UserRepository:      userRepository,
+		EventPublisher:      eventPublisher,
+		RateLimiter:         settings.rateLimiter,
+		Clock:               settings.clock,
+		minIncrement:        settings.minIncrement,
+		snipeExtension:      settings.snipeExtension,
+		maxBulkBids:         settings.maxBulkBids,
+		bulkBidTimeout:      settings.bulkBidTimeout,
+		maxBatchSize:        settings.maxBatchSize,
+		batchInsertInterval: settings.batchInsertInterval,
+		timer:               time.NewTimer(settings.batchInsertInterval),
+		bidChannel:          make(chan bid_entity.Bid, settings.maxBatchSize),
+		auctionLocks:        newAuctionLocker(),
+		recentBids:          newRecentBids(settings.retractionWindow, settings.clock),
+		flushRequests:       make(chan chan struct{}),
+		stop:                make(chan struct{}),
+		stopped:             make(chan struct{}),
+		closeMutex:          &sync.RWMutex{},
 	}
 
 	bidUseCase.triggerCreateRoutine(context.Background())
@@ -50,86 +221,503 @@ func NewBidUseCase(bidRepository bid_entity.BidEntityRepository) BidUseCaseInter
 	return bidUseCase
 }
 
-var bidBatch []bid_entity.Bid
-
 type BidUseCaseInterface interface {
 	CreateBid(
 		ctx context.Context,
-		bidInputDTO BidInputDTO) *internal_error.InternalError
+		bidInputDTO BidInputDTO) (*BidOutputDTO, *internal_error.InternalError)
+
+	CreateBids(
+		ctx context.Context,
+		bidInputs []BulkBidInputDTO) ([]BulkBidResultDTO, *internal_error.InternalError)
+
+	RetractBid(
+		ctx context.Context, bidId, callerId string) *internal_error.InternalError
 
 	FindWinningBidByAuctionId(
 		ctx context.Context, auctionId string) (*BidOutputDTO, *internal_error.InternalError)
 
 	FindBidByAuctionId(
-		ctx context.Context, auctionId string) ([]BidOutputDTO, *internal_error.InternalError)
+		ctx context.Context,
+		auctionId string,
+		findInput FindBidsInputDTO) (*BidPageOutputDTO, *internal_error.InternalError)
+
+	FindBidsByUserId(
+		ctx context.Context,
+		userId string,
+		findInput FindBidsInputDTO) (*UserBidPageOutputDTO, *internal_error.InternalError)
+
+	OnUserBanned(ctx context.Context, userId string)
+
+	Flush(ctx context.Context) error
+
+	Close(ctx context.Context) error
 }
 
 func (bu *BidUseCase) triggerCreateRoutine(ctx context.Context) {
 	go func() {
-		defer close(bu.bidChannel)
+		defer close(bu.stopped)
 
-		for {
-			select {
-			case bidEntity, ok := <-bu.bidChannel:
-				if !ok {
-					if len(bidBatch) > 0 {
-						if err := bu.BidRepository.CreateBid(ctx, bidBatch); err != nil {
-							logger.Error("error trying to process bid batch list", err)
-						}
-					}
+		var bidBatch []bid_entity.Bid
+		flush := func() {
+			if len(bidBatch) > 0 {
+				if err := bu.BidRepository.CreateBid(ctx, bidBatch); err != nil {
+					logger.ErrorContext(ctx, "error trying to process bid batch list", err)
+				}
+			}
+
+			bidBatch = nil
+			bu.timer.Reset(bu.batchInsertInterval)
+		}
+		drain := func() {
+			for {
+				select {
+				case bidEntity := <-bu.bidChannel:
+					bidBatch = append(bidBatch, bidEntity)
+				default:
 					return
 				}
+			}
+		}
 
+		for {
+			select {
+			case bidEntity := <-bu.bidChannel:
 				bidBatch = append(bidBatch, bidEntity)
 
 				if len(bidBatch) >= bu.maxBatchSize {
-					if err := bu.BidRepository.CreateBid(ctx, bidBatch); err != nil {
-						logger.Error("error trying to process bid batch list", err)
-					}
-
-					bidBatch = nil
-					bu.timer.Reset(bu.batchInsertInterval)
+					flush()
 				}
 			case <-bu.timer.C:
-				if err := bu.BidRepository.CreateBid(ctx, bidBatch); err != nil {
-					logger.Error("error trying to process bid batch list", err)
-				}
-				bidBatch = nil
-				bu.timer.Reset(bu.batchInsertInterval)
+				flush()
+			case done := <-bu.flushRequests:
+				drain()
+				flush()
+				close(done)
+			case <-bu.stop:
+				drain()
+				flush()
+				return
 			}
 		}
 	}()
 }
 
+func (bu *BidUseCase) enqueueBid(bidEntity bid_entity.Bid) {
+	bu.recentBids.add(bidEntity)
+
+	bu.closeMutex.RLock()
+	defer bu.closeMutex.RUnlock()
+
+	if bu.closed {
+		if err := bu.BidRepository.CreateBid(context.Background(), []bid_entity.Bid{bidEntity}); err != nil {
+			logger.Error("error trying to insert bid after the batch was closed", err)
+		}
+		return
+	}
+
+	bu.bidChannel <- bidEntity
+}
+
+// Flush writes every buffered bid now instead of waiting for the batch timer.
+func (bu *BidUseCase) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+
+	select {
+	case bu.flushRequests <- done:
+	case <-bu.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops the batch routine after writing the bids still buffered. Bids
+// accepted afterwards are written one by one.
+func (bu *BidUseCase) Close(ctx context.Context) error {
+	bu.closeMutex.Lock()
+	if !bu.closed {
+		bu.closed = true
+		close(bu.stop)
+	}
+	bu.closeMutex.Unlock()
+
+	select {
+	case <-bu.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CreateBid answers a retried request carrying an idempotency key with the
+// outcome of the first one instead of placing the bid again.
 func (bu *BidUseCase) CreateBid(
 	ctx context.Context,
-	bidInputDTO BidInputDTO) *internal_error.InternalError {
+	bidInputDTO BidInputDTO) (*BidOutputDTO, *internal_error.InternalError) {
+	if bu.RateLimiter != nil {
+		if allowed, retryAfter := bu.RateLimiter.Allow(ctx, bidInputDTO.UserId); !allowed {
+			return nil, internal_error.NewTooManyRequestsError("Too many bids, slow down", retryAfter)
+		}
+	}
+
+	if bidInputDTO.IdempotencyKey == "" {
+		return toOptionalBidOutputDTO(bu.placeBid(ctx, bidInputDTO))
+	}
+
+	record, err := bu.BidRepository.ReserveIdempotencyKey(ctx, bidInputDTO.UserId, bidInputDTO.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if record != nil {
+		if !record.Completed {
+			return nil, internal_error.NewConflictError("A bid with this idempotency key is still being processed")
+		}
+
+		return toOptionalBidOutputDTO(record.Bid, nil)
+	}
+
+	bidEntity, err := bu.placeBid(ctx, bidInputDTO)
+	if err != nil {
+		if releaseErr := bu.BidRepository.ReleaseIdempotencyKey(
+			ctx, bidInputDTO.UserId, bidInputDTO.IdempotencyKey); releaseErr != nil {
+			logger.ErrorContext(ctx, "error trying to release bid idempotency key", releaseErr)
+		}
+		return nil, err
+	}
+
+	if err := bu.BidRepository.CompleteIdempotencyKey(
+		ctx, bidInputDTO.UserId, bidInputDTO.IdempotencyKey, bidEntity); err != nil {
+		logger.ErrorContext(ctx, "error trying to complete bid idempotency key", err)
+	}
+
+	return toOptionalBidOutputDTO(bidEntity, nil)
+}
+
+func toOptionalBidOutputDTO(
+	bidEntity *bid_entity.Bid, err *internal_error.InternalError) (*BidOutputDTO, *internal_error.InternalError) {
+	if err != nil || bidEntity == nil {
+		return nil, err
+	}
+
+	return toBidOutputDTO(*bidEntity), nil
+}
+
+// placeBid returns a nil bid when the request only raised the maximum of the
+// bidder's proxy.
+func (bu *BidUseCase) placeBid(
+	ctx context.Context,
+	bidInputDTO BidInputDTO) (*bid_entity.Bid, *internal_error.InternalError) {
+	violations := bidInputDTO.violations()
+	bidEntity, err := bid_entity.CreateBid(
+		bidInputDTO.UserId, bidInputDTO.AuctionId, bidAmount(bidInputDTO), bu.Clock.Now())
+	if err != nil && !err.IsValidation() {
+		return nil, err
+	}
+	if err != nil {
+		violations = append(violations, err.Violations...)
+	}
+	if len(violations) > 0 {
+		return nil, internal_error.NewValidationError("Invalid bid", violations)
+	}
+	bidEntity.IdempotencyKey = bidInputDTO.IdempotencyKey
+
+	sellerId, err := bu.AuctionRepository.GetAuctionSeller(ctx, bidEntity.AuctionId)
+	if err != nil {
+		return nil, err
+	}
+	if sellerId != "" && sellerId == bidEntity.UserId {
+		return nil, internal_error.NewFieldBadRequestError(
+			"cannot bid on your own auction", "user_id", "the seller cannot bid on their own auction")
+	}
 
-	bidEntity, err := bid_entity.CreateBid(bidInputDTO.UserId, bidInputDTO.AuctionId, bidInputDTO.Amount)
+	if err := bu.ensureUserCanBid(ctx, bidEntity.UserId); err != nil {
+		return nil, err
+	}
+
+	unlock, err := bu.lockAuction(ctx, bidEntity.AuctionId)
 	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	auctionEntity, err := bu.AuctionRepository.FindAuctionByIdFromPrimary(ctx, bidEntity.AuctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	switch auctionEntity.Status {
+	case auction_entity.Scheduled:
+		return nil, internal_error.NewBadRequestError("Auction is not open for bids yet")
+	case auction_entity.Cancelled:
+		return nil, internal_error.NewBadRequestError("Auction was cancelled")
+	case auction_entity.Paused:
+		return nil, internal_error.NewBadRequestError("Auction is paused")
+	case auction_entity.Completed:
+		return nil, internal_error.NewBadRequestError("Auction is already closed")
+	}
+
+	now := bu.Clock.Now()
+	if !auctionEntity.EndTime.After(now) {
+		return nil, internal_error.NewBadRequestError("Auction is already closed")
+	}
+
+	isProxy := bidInputDTO.MaxAmount > 0
+	if isProxy && auctionEntity.IsSealed() {
+		return nil, internal_error.NewFieldBadRequestError(
+			"proxy bids are not allowed on sealed auctions", "max_amount", "sealed auctions only accept plain bids")
+	}
+	if isProxy && auctionEntity.IsDutch() {
+		return nil, internal_error.NewFieldBadRequestError(
+			"proxy bids are not allowed on dutch auctions", "max_amount", "dutch auctions only accept plain bids")
+	}
+
+	if auctionEntity.IsDutch() {
+		price := auctionEntity.PriceAt(now)
+		if bidEntity.Amount < price {
+			return nil, internal_error.NewFieldBadRequestError(
+				fmt.Sprintf("Bid must be at least the current price of %s", price),
+				"amount",
+				fmt.Sprintf("current price is %s", price))
+		}
+
+		return bu.placeClosingBid(ctx, auctionEntity, bidEntity, bu.AuctionRepository.AcceptDutchPrice)
+	}
+
+	if bidEntity.Amount < auctionEntity.StartingPrice {
+		return nil, internal_error.NewFieldBadRequestError(
+			fmt.Sprintf("Bid must be at least the starting price of %s", auctionEntity.StartingPrice),
+			"amount",
+			fmt.Sprintf("minimum acceptable bid is %s", auctionEntity.StartingPrice))
+	}
+
+	if !isProxy && auctionEntity.IsBuyNow(bidEntity.Amount) {
+		return bu.placeClosingBid(ctx, auctionEntity, bidEntity, bu.AuctionRepository.BuyNow)
+	}
+
+	if isProxy {
+		leading := auctionEntity.CurrentHighestUserId == bidEntity.UserId
+		closed, err := bu.placeProxyBid(ctx, auctionEntity, bidEntity)
+		if err != nil {
+			if err.Err == internal_error.ErrConflict {
+				return nil, internal_error.NewBadRequestError("Auction is already closed")
+			}
+
+			return nil, err
+		}
+
+		if closed {
+			return bidEntity, nil
+		}
+		if leading {
+			return nil, nil
+		}
+	} else if auctionEntity.IsSealed() {
+		if err := bu.placeSealedBid(ctx, auctionEntity, bidEntity); err != nil {
+			if err.Err == internal_error.ErrConflict {
+				return nil, internal_error.NewBadRequestError("Auction is already closed")
+			}
+
+			return nil, err
+		}
+	} else if err := bu.placeHighestBid(ctx, auctionEntity, bidEntity, bu.minIncrementFor(*auctionEntity)); err != nil {
+		if err.Err == internal_error.ErrConflict {
+			return nil, internal_error.NewBadRequestError("Auction is already closed")
+		}
+
+		return nil, err
+	}
+
+	bu.enqueueBid(*bidEntity)
+	bu.publishBidAccepted(ctx, auctionEntity, *bidEntity)
+	bu.resolveProxyBids(ctx, bidEntity.AuctionId)
+
+	return bidEntity, nil
+}
+
+// placeClosingBid places a bid that wins the auction outright, closing it
+// through closeAuction.
+func (bu *BidUseCase) placeClosingBid(
+	ctx context.Context,
+	auctionEntity *auction_entity.Auction,
+	bidEntity *bid_entity.Bid,
+	closeAuction func(
+		ctx context.Context,
+		auctionId, bidId, userId string,
+		amount money.Amount) (*auction_entity.Auction, int64, *internal_error.InternalError),
+) (*bid_entity.Bid, *internal_error.InternalError) {
+	held := heldForBid(auctionEntity, bidEntity)
+	if err := bu.UserRepository.HoldFunds(ctx, bidEntity.UserId, bidEntity.AuctionId, held); err != nil {
+		return nil, err
+	}
+
+	_, sequence, err := closeAuction(ctx, auctionEntity.Id, bidEntity.Id, bidEntity.UserId, bidEntity.Amount)
+	if err != nil {
+		bu.releaseFunds(ctx, bidEntity.UserId, bidEntity.AuctionId, held)
+		if err.Err == internal_error.ErrConflict {
+			return nil, internal_error.NewBadRequestError("Auction is already closed")
+		}
+
+		return nil, err
+	}
+
+	bidEntity.Sequence = sequence
+	if auctionEntity.CurrentHighestUserId != bidEntity.UserId {
+		bu.releaseFunds(ctx, auctionEntity.CurrentHighestUserId,
+			bidEntity.AuctionId, auctionEntity.CurrentHighestAmount)
+	}
+	bu.publishOutbid(ctx, auctionEntity.Id,
+		auctionEntity.CurrentHighestUserId, bidEntity.UserId, bidEntity.Amount)
+	bu.publishBidAccepted(ctx, auctionEntity, *bidEntity)
+	return bidEntity, nil
+}
+
+// placeHighestBid holds the bidder's funds, claims the top position for the
+// bid, stamps its sequence and releases the hold of the bidder who lost the
+// lead, telling them they were outbid.
+func (bu *BidUseCase) placeHighestBid(
+	ctx context.Context,
+	auctionEntity *auction_entity.Auction,
+	bidEntity *bid_entity.Bid,
+	minIncrement money.Amount) *internal_error.InternalError {
+	held := heldForBid(auctionEntity, bidEntity)
+	if err := bu.UserRepository.HoldFunds(ctx, bidEntity.UserId, bidEntity.AuctionId, held); err != nil {
 		return err
 	}
 
-	bu.bidChannel <- *bidEntity
+	placedBid, err := bu.AuctionRepository.PlaceHighestBid(
+		ctx, bidEntity.AuctionId, bidEntity.UserId, bidEntity.Amount, minIncrement, bu.snipeExtension)
+	if err != nil {
+		bu.releaseFunds(ctx, bidEntity.UserId, bidEntity.AuctionId, held)
+		return err
+	}
 
+	bidEntity.Sequence = placedBid.Sequence
+	bu.publishExtended(ctx, bidEntity.AuctionId, placedBid)
+	if placedBid.PreviousLeaderUserId != bidEntity.UserId {
+		bu.releaseFunds(ctx, placedBid.PreviousLeaderUserId, bidEntity.AuctionId, placedBid.PreviousAmount)
+	}
+	bu.publishOutbid(ctx, bidEntity.AuctionId, placedBid.PreviousLeaderUserId, bidEntity.UserId, bidEntity.Amount)
 	return nil
 }
 
-func getMaxBatchSizeInterval() time.Duration {
-	batchInsertInterval := os.Getenv("BATCH_INSERT_INTERVAL")
-	duration, err := time.ParseDuration(batchInsertInterval)
+// placeSealedBid accepts any bid on a sealed auction, since rejecting a low one
+// would tell the bidder the highest amount. A bid that does not take the lead
+// is only counted, and nobody is told they were outbid.
+func (bu *BidUseCase) placeSealedBid(
+	ctx context.Context,
+	auctionEntity *auction_entity.Auction,
+	bidEntity *bid_entity.Bid) *internal_error.InternalError {
+	if auctionEntity.CurrentHighestUserId != bidEntity.UserId ||
+		bidEntity.Amount > auctionEntity.CurrentHighestAmount {
+		held := heldForBid(auctionEntity, bidEntity)
+		if err := bu.UserRepository.HoldFunds(ctx, bidEntity.UserId, bidEntity.AuctionId, held); err != nil {
+			return err
+		}
+
+		placedBid, err := bu.AuctionRepository.PlaceHighestBid(
+			ctx, bidEntity.AuctionId, bidEntity.UserId, bidEntity.Amount, 0, bu.snipeExtension)
+		if err == nil {
+			bidEntity.Sequence = placedBid.Sequence
+			bu.publishExtended(ctx, bidEntity.AuctionId, placedBid)
+			if placedBid.PreviousLeaderUserId != bidEntity.UserId {
+				bu.releaseFunds(ctx, placedBid.PreviousLeaderUserId, bidEntity.AuctionId, placedBid.PreviousAmount)
+			}
+			return nil
+		}
+
+		bu.releaseFunds(ctx, bidEntity.UserId, bidEntity.AuctionId, held)
+		if err.Err != internal_error.ErrBadRequest {
+			return err
+		}
+	}
+
+	placedBid, err := bu.AuctionRepository.RecordSealedBid(ctx, bidEntity.AuctionId, bu.snipeExtension)
 	if err != nil {
-		return 3 * time.Minute
+		return err
 	}
 
-	return duration
+	bidEntity.Sequence = placedBid.Sequence
+	bu.publishExtended(ctx, bidEntity.AuctionId, placedBid)
+	return nil
 }
 
-func getMaxBatchSize() int {
-	value, err := strconv.Atoi(os.Getenv("MAX_BATCH_SIZE"))
-	if err != nil {
-		return 5
+// heldForBid is what the bid adds to the bidder's holds. A leader raising
+// their own bid already holds the amount they are replacing.
+func heldForBid(auctionEntity *auction_entity.Auction, bidEntity *bid_entity.Bid) money.Amount {
+	if auctionEntity.CurrentHighestUserId == bidEntity.UserId {
+		return bidEntity.Amount - auctionEntity.CurrentHighestAmount
+	}
+
+	return bidEntity.Amount
+}
+
+func (bu *BidUseCase) releaseFunds(ctx context.Context, userId, auctionId string, amount money.Amount) {
+	if userId == "" {
+		return
+	}
+
+	if err := bu.UserRepository.ReleaseFunds(ctx, userId, auctionId, amount); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to release the hold of user %s", userId), err)
+	}
+}
+
+func (bu *BidUseCase) publishOutbid(
+	ctx context.Context,
+	auctionId, previousLeaderUserId, userId string,
+	amount money.Amount) {
+	if previousLeaderUserId == "" || previousLeaderUserId == userId {
+		return
+	}
+
+	bu.EventPublisher.Publish(ctx, event_entity.OutbidEvent{
+		AuctionId:            auctionId,
+		PreviousLeaderUserId: previousLeaderUserId,
+		NewAmount:            amount,
+		Timestamp:            bu.Clock.Now(),
+	})
+}
+
+func (bu *BidUseCase) publishBidAccepted(
+	ctx context.Context, auctionEntity *auction_entity.Auction, bidEntity bid_entity.Bid) {
+	bidAccepted := event_entity.BidAcceptedEvent{
+		AuctionId: bidEntity.AuctionId,
+		BidId:     bidEntity.Id,
+		Auto:      bidEntity.Auto,
+		Timestamp: bidEntity.Timestamp,
+	}
+	if !auctionEntity.IsSealed() {
+		bidAccepted.UserId = bidEntity.UserId
+		bidAccepted.Amount = bidEntity.Amount
+	}
+
+	bu.EventPublisher.Publish(ctx, bidAccepted)
+}
+
+// publishExtended tells followers of the auction about the new end time when
+// the bid landed inside the snipe window.
+func (bu *BidUseCase) publishExtended(ctx context.Context, auctionId string, placedBid auction_entity.PlacedBid) {
+	if !placedBid.Extended {
+		return
+	}
+
+	bu.EventPublisher.Publish(ctx, event_entity.AuctionExtendedEvent{
+		AuctionId: auctionId,
+		EndTime:   placedBid.EndTime,
+		Timestamp: bu.Clock.Now(),
+	})
+}
+
+func (bu *BidUseCase) minIncrementFor(auctionEntity auction_entity.Auction) money.Amount {
+	if auctionEntity.MinIncrement > 0 {
+		return auctionEntity.MinIncrement
 	}
 
-	return value
+	return bu.minIncrement
 }
@@ -0,0 +1,658 @@
+package bid_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/config"
+	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/entity/event_entity"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/infra/database/auction"
+	"fullcycle-auction_go/internal/infra/database/bid"
+	"fullcycle-auction_go/internal/infra/database/user"
+	"fullcycle-auction_go/internal/infra/event"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"fullcycle-auction_go/internal/ratelimit"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"path"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCloseFlushesBufferedBidsBeforeRestart(t *testing.T) {
+	cfg, err := config.Load(path.Join("./", "../../../cmd/auction/.env"))
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	ctx := context.Background()
+	conn, err := mongodb.NewMongoDBConnection(ctx, cfg.Mongo)
+	if err != nil {
+		log.Fatal("Error trying to connect mongodb")
+	}
+
+	auctionRepository := auction.NewAuctionRepository(conn)
+	auctionEntity, _ := auction_entity.CreateAuction(
+		"drone",
+		"electronics",
+		"camera drone with spare batteries",
+		auction_entity.Used,
+		auction_entity.WithDuration(time.Hour))
+	assert.Nil(t, auctionRepository.CreateAuction(ctx, auctionEntity))
+
+	bidRepository := bid.NewBidRepository(conn, auctionRepository)
+	userRepository := user.NewUserRepository(conn)
+	bidUseCase := NewBidUseCase(bidRepository, auctionRepository, userRepository, event.NewChannelPublisher(),
+		WithBatching(1000, time.Hour))
+
+	const acceptedBids = 50
+	for i := 1; i <= acceptedBids; i++ {
+		userId := uuid.New().String()
+		_, insertErr := userRepository.Collection.InsertOne(ctx, user.UserEntityMongo{Id: userId, Name: "bidder"})
+		assert.Nil(t, insertErr)
+
+		_, err := bidUseCase.CreateBid(ctx, BidInputDTO{
+			UserId:    userId,
+			AuctionId: auctionEntity.Id,
+			Amount:    money.Amount(i * 10),
+		})
+		assert.Nil(t, err)
+	}
+
+	buffered, _ := bidRepository.FindBidByAuctionId(ctx, auctionEntity.Id, bid_entity.FindBidsOptions{})
+	assert.Equal(t, int64(0), buffered.Total)
+
+	assert.Nil(t, bidUseCase.Close(ctx))
+	assert.Nil(t, auctionRepository.Shutdown(ctx))
+
+	restartedAuctionRepository := auction.NewAuctionRepository(conn)
+	defer restartedAuctionRepository.Shutdown(ctx)
+	restartedBidRepository := bid.NewBidRepository(conn, restartedAuctionRepository)
+
+	stored, storeErr := restartedBidRepository.FindBidByAuctionId(
+		ctx, auctionEntity.Id, bid_entity.FindBidsOptions{})
+	assert.Nil(t, storeErr)
+	assert.Equal(t, int64(acceptedBids), stored.Total)
+}
+
+type fakeSellerAuctionRepository struct {
+	auction_entity.AuctionRepositoryInterface
+	sellers map[string]string
+}
+
+func (f *fakeSellerAuctionRepository) GetAuctionSeller(
+	ctx context.Context, auctionId string) (string, *internal_error.InternalError) {
+	sellerId, ok := f.sellers[auctionId]
+	if !ok {
+		return "", internal_error.NewNotFoundError("Auction not found")
+	}
+
+	return sellerId, nil
+}
+
+func TestCreateBidRejectsTheSellerOfTheAuction(t *testing.T) {
+	sellerId := uuid.New().String()
+	auctionId := uuid.New().String()
+	bidUseCase := NewBidUseCase(nil, &fakeSellerAuctionRepository{
+		sellers: map[string]string{auctionId: sellerId},
+	}, nil, event.NewChannelPublisher())
+
+	_, err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId:    sellerId,
+		AuctionId: auctionId,
+		Amount:    100,
+	})
+	assert.Equal(t, internal_error.ErrBadRequest, err.Err)
+	assert.Equal(t, "cannot bid on your own auction", err.Message)
+	assert.Equal(t, "user_id", err.Failures[0].Field)
+
+	_, err = bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId:    sellerId,
+		AuctionId: uuid.New().String(),
+		Amount:    100,
+	})
+	assert.True(t, err.IsNotFound())
+}
+
+type fakeBalanceUserRepository struct {
+	user_entity.UserRepositoryInterface
+	mutex    sync.Mutex
+	balances map[string]money.Amount
+	held     map[string]money.Amount
+	users    map[string]*user_entity.User
+}
+
+func (f *fakeBalanceUserRepository) FindUserById(
+	ctx context.Context, userId string) (*user_entity.User, *internal_error.InternalError) {
+	if userEntity, ok := f.users[userId]; ok {
+		return userEntity, nil
+	}
+
+	return nil, internal_error.NewNotFoundError("User not found")
+}
+
+func (f *fakeBalanceUserRepository) HoldFunds(
+	ctx context.Context, userId, auctionId string, amount money.Amount) *internal_error.InternalError {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.held[userId]+amount > f.balances[userId] {
+		return internal_error.NewFieldBadRequestError("Insufficient balance to place this bid", "amount",
+			"available balance is "+(f.balances[userId]-f.held[userId]).String())
+	}
+	f.held[userId] += amount
+	return nil
+}
+
+func (f *fakeBalanceUserRepository) ReleaseFunds(
+	ctx context.Context, userId, auctionId string, amount money.Amount) *internal_error.InternalError {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.held[userId] -= amount
+	return nil
+}
+
+type fakeBiddingAuctionRepository struct {
+	auction_entity.AuctionRepositoryInterface
+	mutex   sync.Mutex
+	auction auction_entity.Auction
+	// now is when bids are accepted, for the snipe window.
+	now time.Time
+}
+
+func (f *fakeBiddingAuctionRepository) GetAuctionSeller(
+	ctx context.Context, auctionId string) (string, *internal_error.InternalError) {
+	return "", nil
+}
+
+func (f *fakeBiddingAuctionRepository) FindAuctionByIdFromPrimary(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	auctionEntity := f.auction
+	return &auctionEntity, nil
+}
+
+func (f *fakeBiddingAuctionRepository) PlaceHighestBid(
+	ctx context.Context,
+	auctionId, userId string,
+	amount, minIncrement money.Amount,
+	snipeExtension auction_entity.SnipeExtension) (auction_entity.PlacedBid, *internal_error.InternalError) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if amount <= f.auction.CurrentHighestAmount {
+		return auction_entity.PlacedBid{}, internal_error.NewBadRequestError("Bid is too low")
+	}
+
+	placedBid := f.acceptBid(snipeExtension)
+	placedBid.PreviousLeaderUserId = f.auction.CurrentHighestUserId
+	placedBid.PreviousAmount = f.auction.CurrentHighestAmount
+	f.auction.CurrentHighestUserId = userId
+	f.auction.CurrentHighestAmount = amount
+	return placedBid, nil
+}
+
+func (f *fakeBiddingAuctionRepository) acceptBid(
+	snipeExtension auction_entity.SnipeExtension) auction_entity.PlacedBid {
+	if !f.auction.EndTime.Before(f.now.Add(snipeExtension.Window)) {
+		return auction_entity.PlacedBid{EndTime: f.auction.EndTime}
+	}
+
+	f.auction.EndTime = f.auction.EndTime.Add(snipeExtension.Extension)
+	return auction_entity.PlacedBid{Extended: true, EndTime: f.auction.EndTime}
+}
+
+func (f *fakeBiddingAuctionRepository) FindAuctionById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	return f.FindAuctionByIdFromPrimary(ctx, id)
+}
+
+func (f *fakeBiddingAuctionRepository) RecordSealedBid(
+	ctx context.Context,
+	auctionId string,
+	snipeExtension auction_entity.SnipeExtension) (auction_entity.PlacedBid, *internal_error.InternalError) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.auction.BidCount++
+	placedBid := f.acceptBid(snipeExtension)
+	placedBid.Sequence = f.auction.BidCount
+	return placedBid, nil
+}
+
+func (f *fakeBiddingAuctionRepository) AcceptDutchPrice(
+	ctx context.Context,
+	auctionId, bidId, userId string,
+	amount money.Amount) (*auction_entity.Auction, int64, *internal_error.InternalError) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.auction.Status != auction_entity.Active {
+		return nil, 0, internal_error.NewConflictError("Auction is no longer available")
+	}
+
+	f.auction.Status = auction_entity.Completed
+	f.auction.WinnerUserId = userId
+	f.auction.WinningAmount = amount
+	f.auction.BidCount++
+	closedAuction := f.auction
+	return &closedAuction, f.auction.BidCount, nil
+}
+
+func (f *fakeBiddingAuctionRepository) BuyNow(
+	ctx context.Context,
+	auctionId, bidId, userId string,
+	amount money.Amount) (*auction_entity.Auction, int64, *internal_error.InternalError) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.auction.Status != auction_entity.Active || f.auction.CurrentHighestAmount >= amount {
+		return nil, 0, internal_error.NewConflictError("Auction is no longer available")
+	}
+
+	f.auction.Status = auction_entity.Completed
+	f.auction.WinnerUserId = userId
+	f.auction.WinningAmount = amount
+	f.auction.CurrentHighestUserId = userId
+	f.auction.CurrentHighestAmount = amount
+	f.auction.BidCount++
+	closedAuction := f.auction
+	return &closedAuction, f.auction.BidCount, nil
+}
+
+type fakeBatchBidRepository struct {
+	bid_entity.BidEntityRepository
+}
+
+func (f *fakeBatchBidRepository) CreateBid(
+	ctx context.Context, bidEntities []bid_entity.Bid) *internal_error.InternalError {
+	return nil
+}
+
+func (f *fakeBatchBidRepository) FindMaxBidsByAuctionId(
+	ctx context.Context, auctionId string) ([]bid_entity.MaxBid, *internal_error.InternalError) {
+	return nil, nil
+}
+
+func TestCreateBidHoldsTheLeadingAmountWithinTheBalance(t *testing.T) {
+	alice, bob := uuid.New().String(), uuid.New().String()
+	auctionId := uuid.New().String()
+	userRepository := &fakeBalanceUserRepository{
+		balances: map[string]money.Amount{alice: 10000, bob: 10000},
+		held:     map[string]money.Amount{},
+	}
+	auctionRepository := &fakeBiddingAuctionRepository{auction: auction_entity.Auction{
+		Id:      auctionId,
+		Status:  auction_entity.Active,
+		EndTime: time.Now().Add(time.Hour),
+	}}
+	bidUseCase := NewBidUseCase(
+		&fakeBatchBidRepository{}, auctionRepository, userRepository, event.NewChannelPublisher())
+	defer bidUseCase.Close(context.Background())
+
+	placeBid := func(userId string, amount money.Amount) *internal_error.InternalError {
+		_, err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+			UserId:    userId,
+			AuctionId: auctionId,
+			Amount:    amount,
+		})
+		return err
+	}
+
+	assert.Nil(t, placeBid(alice, 8000))
+	assert.Equal(t, money.Amount(8000), userRepository.held[alice])
+
+	assert.Nil(t, placeBid(bob, 9000))
+	assert.Equal(t, money.Amount(0), userRepository.held[alice])
+	assert.Equal(t, money.Amount(9000), userRepository.held[bob])
+
+	err := placeBid(alice, 12000)
+	assert.Equal(t, internal_error.ErrBadRequest, err.Err)
+	assert.Equal(t, "available balance is 100.00", err.Failures[0].Message)
+	assert.Equal(t, money.Amount(0), userRepository.held[alice])
+
+	assert.Nil(t, placeBid(bob, 9500))
+	assert.Equal(t, money.Amount(9500), userRepository.held[bob])
+
+	assert.NotNil(t, placeBid(alice, 9500))
+	assert.Equal(t, money.Amount(0), userRepository.held[alice])
+	assert.Equal(t, money.Amount(9500), userRepository.held[bob])
+}
+
+type fakeIdempotentBidRepository struct {
+	fakeBatchBidRepository
+	records map[string]*bid_entity.BidIdempotencyRecord
+}
+
+func (f *fakeIdempotentBidRepository) ReserveIdempotencyKey(
+	ctx context.Context,
+	userId, idempotencyKey string) (*bid_entity.BidIdempotencyRecord, *internal_error.InternalError) {
+	if record, ok := f.records[userId+idempotencyKey]; ok {
+		return record, nil
+	}
+
+	f.records[userId+idempotencyKey] = &bid_entity.BidIdempotencyRecord{}
+	return nil, nil
+}
+
+func (f *fakeIdempotentBidRepository) CompleteIdempotencyKey(
+	ctx context.Context,
+	userId, idempotencyKey string,
+	bidEntity *bid_entity.Bid) *internal_error.InternalError {
+	f.records[userId+idempotencyKey] = &bid_entity.BidIdempotencyRecord{Completed: true, Bid: bidEntity}
+	return nil
+}
+
+func (f *fakeIdempotentBidRepository) ReleaseIdempotencyKey(
+	ctx context.Context, userId, idempotencyKey string) *internal_error.InternalError {
+	delete(f.records, userId+idempotencyKey)
+	return nil
+}
+
+func TestCreateBidWithIdempotencyKeyReturnsTheOriginalBid(t *testing.T) {
+	bidderId := uuid.New().String()
+	auctionId := uuid.New().String()
+	bidRepository := &fakeIdempotentBidRepository{records: map[string]*bid_entity.BidIdempotencyRecord{}}
+	userRepository := &fakeBalanceUserRepository{
+		balances: map[string]money.Amount{bidderId: 100000},
+		held:     map[string]money.Amount{},
+	}
+	auctionRepository := &fakeBiddingAuctionRepository{auction: auction_entity.Auction{
+		Id:      auctionId,
+		Status:  auction_entity.Active,
+		EndTime: time.Now().Add(time.Hour),
+	}}
+	bidUseCase := NewBidUseCase(bidRepository, auctionRepository, userRepository, event.NewChannelPublisher())
+	defer bidUseCase.Close(context.Background())
+
+	bidInput := BidInputDTO{
+		UserId:         bidderId,
+		AuctionId:      auctionId,
+		Amount:         5000,
+		IdempotencyKey: "retry-me",
+	}
+
+	first, err := bidUseCase.CreateBid(context.Background(), bidInput)
+	assert.Nil(t, err)
+
+	retry, err := bidUseCase.CreateBid(context.Background(), bidInput)
+	assert.Nil(t, err)
+	assert.Equal(t, first.Id, retry.Id)
+	assert.Equal(t, money.Amount(5000), userRepository.held[bidderId])
+
+	bidInput.IdempotencyKey = "rejected"
+	_, err = bidUseCase.CreateBid(context.Background(), bidInput)
+	assert.NotNil(t, err)
+	assert.NotContains(t, bidRepository.records, bidderId+"rejected")
+}
+
+func TestSealedAuctionAcceptsBidsBelowTheHiddenHighest(t *testing.T) {
+	alice, bob := uuid.New().String(), uuid.New().String()
+	auctionId := uuid.New().String()
+	userRepository := &fakeBalanceUserRepository{
+		balances: map[string]money.Amount{alice: 10000, bob: 10000},
+		held:     map[string]money.Amount{},
+	}
+	auctionRepository := &fakeBiddingAuctionRepository{auction: auction_entity.Auction{
+		Id:      auctionId,
+		Type:    auction_entity.SealedAuction,
+		Status:  auction_entity.Active,
+		EndTime: time.Now().Add(time.Hour),
+	}}
+	bidUseCase := NewBidUseCase(
+		&fakeBatchBidRepository{}, auctionRepository, userRepository, event.NewChannelPublisher())
+	defer bidUseCase.Close(context.Background())
+
+	_, err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId: alice, AuctionId: auctionId, Amount: 8000,
+	})
+	assert.Nil(t, err)
+
+	bidOutput, err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId: bob, AuctionId: auctionId, Amount: 5000,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, money.Amount(5000), bidOutput.Amount)
+	assert.Equal(t, alice, auctionRepository.auction.CurrentHighestUserId)
+	assert.Equal(t, money.Amount(8000), userRepository.held[alice])
+	assert.Equal(t, money.Amount(0), userRepository.held[bob])
+
+	_, err = bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId: bob, AuctionId: auctionId, Amount: 5000, MaxAmount: 9000,
+	})
+	assert.Equal(t, "max_amount", err.Failures[0].Field)
+}
+
+func TestOpenAuctionStillRejectsBidsBelowTheHighest(t *testing.T) {
+	alice, bob := uuid.New().String(), uuid.New().String()
+	auctionId := uuid.New().String()
+	userRepository := &fakeBalanceUserRepository{
+		balances: map[string]money.Amount{alice: 10000, bob: 10000},
+		held:     map[string]money.Amount{},
+	}
+	auctionRepository := &fakeBiddingAuctionRepository{auction: auction_entity.Auction{
+		Id:      auctionId,
+		Type:    auction_entity.OpenAuction,
+		Status:  auction_entity.Active,
+		EndTime: time.Now().Add(time.Hour),
+	}}
+	bidUseCase := NewBidUseCase(
+		&fakeBatchBidRepository{}, auctionRepository, userRepository, event.NewChannelPublisher())
+	defer bidUseCase.Close(context.Background())
+
+	_, err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId: alice, AuctionId: auctionId, Amount: 8000,
+	})
+	assert.Nil(t, err)
+
+	_, err = bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId: bob, AuctionId: auctionId, Amount: 5000,
+	})
+	assert.Equal(t, internal_error.ErrBadRequest, err.Err)
+	assert.Equal(t, int64(0), auctionRepository.auction.BidCount)
+}
+
+func TestDutchAuctionIsWonByTheFirstBidAtTheCurrentPrice(t *testing.T) {
+	alice, bob := uuid.New().String(), uuid.New().String()
+	auctionId := uuid.New().String()
+	userRepository := &fakeBalanceUserRepository{
+		balances: map[string]money.Amount{alice: 10000, bob: 10000},
+		held:     map[string]money.Amount{},
+	}
+	auctionRepository := &fakeBiddingAuctionRepository{auction: auction_entity.Auction{
+		Id:                auctionId,
+		Type:              auction_entity.DutchAuction,
+		Status:            auction_entity.Active,
+		Timestamp:         time.Now().Add(-90 * time.Second),
+		EndTime:           time.Now().Add(time.Hour),
+		StartPrice:        9000,
+		FloorPrice:        5000,
+		PriceDecrement:    1000,
+		DecrementInterval: time.Minute,
+	}}
+	bidUseCase := NewBidUseCase(
+		&fakeBatchBidRepository{}, auctionRepository, userRepository, event.NewChannelPublisher())
+	defer bidUseCase.Close(context.Background())
+
+	_, err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId: alice, AuctionId: auctionId, Amount: 7500,
+	})
+	assert.Equal(t, "current price is 80.00", err.Failures[0].Message)
+
+	bidOutput, err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId: alice, AuctionId: auctionId, Amount: 8000,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), bidOutput.Sequence)
+	assert.Equal(t, auction_entity.Completed, auctionRepository.auction.Status)
+	assert.Equal(t, alice, auctionRepository.auction.WinnerUserId)
+	assert.Equal(t, money.Amount(8000), userRepository.held[alice])
+
+	_, err = bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId: bob, AuctionId: auctionId, Amount: 9000,
+	})
+	assert.Equal(t, "Auction is already closed", err.Message)
+	assert.Equal(t, money.Amount(0), userRepository.held[bob])
+}
+
+func TestBuyNowIsNotOfferedOnceTheLeadingBidReachesThePrice(t *testing.T) {
+	alice, bob := uuid.New().String(), uuid.New().String()
+	auctionId := uuid.New().String()
+	userRepository := &fakeBalanceUserRepository{
+		balances: map[string]money.Amount{alice: 20000, bob: 20000},
+		held:     map[string]money.Amount{alice: 12000},
+	}
+	auctionRepository := &fakeBiddingAuctionRepository{auction: auction_entity.Auction{
+		Id:                   auctionId,
+		Status:               auction_entity.Active,
+		EndTime:              time.Now().Add(time.Hour),
+		BuyNowPrice:          10000,
+		CurrentHighestUserId: alice,
+		CurrentHighestAmount: 12000,
+	}}
+	bidUseCase := NewBidUseCase(
+		&fakeBatchBidRepository{}, auctionRepository, userRepository, event.NewChannelPublisher())
+	defer bidUseCase.Close(context.Background())
+
+	_, err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId: bob, AuctionId: auctionId, Amount: 11000,
+	})
+	assert.Equal(t, internal_error.ErrBadRequest, err.Err)
+	assert.Equal(t, auction_entity.Active, auctionRepository.auction.Status)
+	assert.Equal(t, alice, auctionRepository.auction.CurrentHighestUserId)
+	assert.Equal(t, money.Amount(0), userRepository.held[bob])
+
+	_, err = bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId: bob, AuctionId: auctionId, Amount: 13000,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, auction_entity.Active, auctionRepository.auction.Status)
+	assert.Empty(t, auctionRepository.auction.WinnerUserId)
+	assert.Equal(t, bob, auctionRepository.auction.CurrentHighestUserId)
+}
+
+func TestCreateBidIsRateLimitedPerUser(t *testing.T) {
+	bot, person := uuid.New().String(), uuid.New().String()
+	auctionId := uuid.New().String()
+	fakeClock := fakeclock.New(time.Now())
+	bidUseCase := NewBidUseCase(
+		&fakeBatchBidRepository{},
+		&fakeBiddingAuctionRepository{auction: auction_entity.Auction{
+			Id:      auctionId,
+			Status:  auction_entity.Active,
+			EndTime: time.Now().Add(time.Hour),
+		}},
+		&fakeBalanceUserRepository{
+			balances: map[string]money.Amount{bot: 100000, person: 100000},
+			held:     map[string]money.Amount{},
+		},
+		event.NewChannelPublisher()).(*BidUseCase)
+	defer bidUseCase.Close(context.Background())
+	bidUseCase.RateLimiter = ratelimit.NewInMemoryLimiter(ratelimit.Rate{Events: 1, Per: time.Second}, 2, fakeClock)
+
+	placeBid := func(userId string, amount money.Amount) *internal_error.InternalError {
+		_, err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+			UserId:    userId,
+			AuctionId: auctionId,
+			Amount:    amount,
+		})
+		return err
+	}
+
+	assert.Nil(t, placeBid(bot, 100))
+	assert.Nil(t, placeBid(bot, 200))
+
+	err := placeBid(bot, 300)
+	assert.Equal(t, internal_error.ErrTooManyRequests, err.Err)
+	assert.Equal(t, time.Second, err.RetryAfter)
+
+	assert.Nil(t, placeBid(person, 400))
+
+	fakeClock.Advance(time.Second)
+	assert.Nil(t, placeBid(bot, 500))
+}
+
+func TestFirstBidMustReachTheStartingPrice(t *testing.T) {
+	alice, bob := uuid.New().String(), uuid.New().String()
+	auctionId := uuid.New().String()
+	userRepository := &fakeBalanceUserRepository{
+		balances: map[string]money.Amount{alice: 10000, bob: 10000},
+		held:     map[string]money.Amount{},
+	}
+	auctionRepository := &fakeBiddingAuctionRepository{auction: auction_entity.Auction{
+		Id:            auctionId,
+		Status:        auction_entity.Active,
+		EndTime:       time.Now().Add(time.Hour),
+		StartingPrice: 5000,
+	}}
+	bidUseCase := NewBidUseCase(
+		&fakeBatchBidRepository{}, auctionRepository, userRepository, event.NewChannelPublisher())
+	defer bidUseCase.Close(context.Background())
+
+	_, err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId: alice, AuctionId: auctionId, Amount: 4999,
+	})
+	assert.Equal(t, internal_error.ErrBadRequest, err.Err)
+	assert.Equal(t, "minimum acceptable bid is 50.00", err.Failures[0].Message)
+	assert.Empty(t, auctionRepository.auction.CurrentHighestUserId)
+	assert.Equal(t, money.Amount(0), userRepository.held[alice])
+
+	_, err = bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId: bob, AuctionId: auctionId, Amount: 100, MaxAmount: 4000,
+	})
+	assert.Equal(t, "amount", err.Failures[0].Field)
+
+	_, err = bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId: alice, AuctionId: auctionId, Amount: 5000,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, alice, auctionRepository.auction.CurrentHighestUserId)
+}
+
+func TestBidInsideTheSnipeWindowExtendsTheAuctionWithTheSameWrite(t *testing.T) {
+	alice, bob := uuid.New().String(), uuid.New().String()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	auctionRepository := &fakeBiddingAuctionRepository{now: now, auction: auction_entity.Auction{
+		Id:      uuid.New().String(),
+		Status:  auction_entity.Active,
+		EndTime: now.Add(10 * time.Second),
+	}}
+	userRepository := &fakeBalanceUserRepository{
+		balances: map[string]money.Amount{alice: 10000, bob: 10000},
+		held:     map[string]money.Amount{},
+	}
+	publisher := event.NewChannelPublisher()
+	bidUseCase := NewBidUseCase(&fakeBatchBidRepository{}, auctionRepository, userRepository, publisher,
+		WithClock(fakeclock.New(now)), WithSnipeExtension(30*time.Second, time.Minute))
+	defer bidUseCase.Close(context.Background())
+
+	_, err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId: alice, AuctionId: auctionRepository.auction.Id, Amount: 1000,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, now.Add(70*time.Second), auctionRepository.auction.EndTime)
+
+	_, err = bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId: bob, AuctionId: auctionRepository.auction.Id, Amount: 2000,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, now.Add(70*time.Second), auctionRepository.auction.EndTime)
+
+	var extensions []event_entity.AuctionExtendedEvent
+	for len(publisher.Events()) > 0 {
+		if extended, ok := (<-publisher.Events()).(event_entity.AuctionExtendedEvent); ok {
+			extensions = append(extensions, extended)
+		}
+	}
+	assert.Len(t, extensions, 1)
+	assert.Equal(t, now.Add(70*time.Second), extensions[0].EndTime)
+}
@@ -0,0 +1,124 @@
+package bid_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+)
+
+const (
+	BulkBidAccepted = "accepted"
+	BulkBidRejected = "rejected"
+	BulkBidErrored  = "errored"
+
+	bulkBidDeadlineExceeded = "deadline_exceeded"
+)
+
+// BulkBidInputDTO is one bid of a bulk request, placed on behalf of UserId.
+type BulkBidInputDTO struct {
+	UserId         string       `json:"user_id"`
+	AuctionId      string       `json:"auction_id"`
+	Amount         money.Amount `json:"amount"`
+	MaxAmount      money.Amount `json:"max_amount"`
+	IdempotencyKey string       `json:"idempotency_key"`
+}
+
+func (b BulkBidInputDTO) toBidInputDTO() BidInputDTO {
+	return BidInputDTO{
+		AuctionId:      b.AuctionId,
+		Amount:         b.Amount,
+		MaxAmount:      b.MaxAmount,
+		UserId:         b.UserId,
+		IdempotencyKey: b.IdempotencyKey,
+	}
+}
+
+// BulkBidResultDTO is the outcome of the bid at Index. Rejected bids broke a
+// rule of the auction, errored ones could not be processed.
+type BulkBidResultDTO struct {
+	Index  int           `json:"index"`
+	Result string        `json:"result"`
+	Bid    *BidOutputDTO `json:"bid,omitempty"`
+	Code   string        `json:"code,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// CreateBids validates every bid before placing any, then places them one
+// after the other in the order given. A bid that fails does not stop the
+// others. The whole request runs under the bulk timeout: the bid being placed
+// when it passes and those not started yet are reported as errored.
+func (bu *BidUseCase) CreateBids(
+	ctx context.Context,
+	bidInputs []BulkBidInputDTO) ([]BulkBidResultDTO, *internal_error.InternalError) {
+	maxItems := bu.maxBulkBids
+	if len(bidInputs) == 0 || len(bidInputs) > maxItems {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("Bulk requests must contain between 1 and %d bids", maxItems))
+	}
+
+	var violations []internal_error.FieldViolation
+	for index, bidInput := range bidInputs {
+		bidInputDTO := bidInput.toBidInputDTO()
+		bidViolations := append(bidInputDTO.violations(), (&bid_entity.Bid{
+			UserId: bidInputDTO.UserId, AuctionId: bidInputDTO.AuctionId, Amount: bidAmount(bidInputDTO),
+		}).Violations()...)
+
+		for _, violation := range bidViolations {
+			violation.Field = fmt.Sprintf("[%d].%s", index, violation.Field)
+			violations = append(violations, violation)
+		}
+	}
+
+	if len(violations) > 0 {
+		return nil, internal_error.NewValidationError("Some bids are invalid", violations)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, bu.bulkBidTimeout)
+	defer cancel()
+
+	results := make([]BulkBidResultDTO, len(bidInputs))
+	for index, bidInput := range bidInputs {
+		results[index].Index = index
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			results[index].Result = BulkBidErrored
+			results[index].Code = bulkBidDeadlineExceeded
+			results[index].Error = "The bulk request ran out of time before this bid was placed"
+			continue
+		}
+
+		bidOutput, err := bu.CreateBid(ctx, bidInput.toBidInputDTO())
+		switch {
+		case err == nil:
+			results[index].Result = BulkBidAccepted
+			results[index].Bid = bidOutput
+		case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+			results[index].Result = BulkBidErrored
+			results[index].Code = bulkBidDeadlineExceeded
+			results[index].Error = "The bulk request ran out of time while this bid was being placed"
+		case err.Err == internal_error.ErrInternalServer:
+			results[index].Result = BulkBidErrored
+			results[index].Code = err.Err
+			results[index].Error = err.Message
+		default:
+			results[index].Result = BulkBidRejected
+			results[index].Code = err.Err
+			results[index].Error = err.Message
+		}
+	}
+
+	return results, nil
+}
+
+// bidAmount is the amount the bid is checked against: its maximum for a
+// proxy bid.
+func bidAmount(bidInputDTO BidInputDTO) money.Amount {
+	if bidInputDTO.MaxAmount > 0 {
+		return bidInputDTO.MaxAmount
+	}
+
+	return bidInputDTO.Amount
+}
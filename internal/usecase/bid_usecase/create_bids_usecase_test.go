@@ -0,0 +1,161 @@
+package bid_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/event"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newBulkBidUseCase(
+	balances map[string]money.Amount, opts ...BidUseCaseOption) (BidUseCaseInterface, string) {
+	auctionId := uuid.New().String()
+	bidUseCase := NewBidUseCase(&fakeBatchBidRepository{}, &fakeBiddingAuctionRepository{
+		auction: auction_entity.Auction{
+			Id:      auctionId,
+			Status:  auction_entity.Active,
+			EndTime: time.Now().Add(time.Hour),
+		},
+	}, &fakeBalanceUserRepository{balances: balances, held: map[string]money.Amount{}}, event.NewChannelPublisher(),
+		opts...)
+
+	return bidUseCase, auctionId
+}
+
+func TestCreateBidsPlacesEveryBidInOrderAndReportsEachOutcome(t *testing.T) {
+	alice, bob := uuid.New().String(), uuid.New().String()
+	bidUseCase, auctionId := newBulkBidUseCase(map[string]money.Amount{alice: 10000, bob: 10000})
+	defer bidUseCase.Close(context.Background())
+
+	results, err := bidUseCase.CreateBids(context.Background(), []BulkBidInputDTO{
+		{UserId: alice, AuctionId: auctionId, Amount: 8000},
+		{UserId: bob, AuctionId: auctionId, Amount: 12000},
+		{UserId: bob, AuctionId: auctionId, Amount: 9000},
+		{UserId: alice, AuctionId: auctionId, Amount: 8500},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, results, 4)
+
+	assert.Equal(t, BulkBidAccepted, results[0].Result)
+	assert.Equal(t, alice, results[0].Bid.UserId)
+	assert.Equal(t, BulkBidRejected, results[1].Result)
+	assert.Equal(t, internal_error.ErrBadRequest, results[1].Code)
+	assert.Equal(t, BulkBidAccepted, results[2].Result)
+	assert.Equal(t, BulkBidRejected, results[3].Result)
+	assert.Equal(t, "Bid is too low", results[3].Error)
+	for index, result := range results {
+		assert.Equal(t, index, result.Index)
+	}
+}
+
+func TestCreateBidsValidatesEveryBidBeforePlacingAny(t *testing.T) {
+	alice := uuid.New().String()
+	bidUseCase, auctionId := newBulkBidUseCase(map[string]money.Amount{alice: 10000})
+	defer bidUseCase.Close(context.Background())
+
+	_, err := bidUseCase.CreateBids(context.Background(), []BulkBidInputDTO{
+		{UserId: alice, AuctionId: auctionId, Amount: 8000},
+		{UserId: "someone", AuctionId: auctionId, Amount: 0},
+	})
+	assert.True(t, err.IsValidation())
+	assert.ElementsMatch(t, []string{"[1].user_id", "[1].amount"}, violatedFields(err))
+
+	results, err := bidUseCase.CreateBids(context.Background(), []BulkBidInputDTO{
+		{UserId: alice, AuctionId: auctionId, Amount: 100},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, BulkBidAccepted, results[0].Result)
+
+	_, err = bidUseCase.CreateBids(context.Background(), nil)
+	assert.Equal(t, internal_error.ErrBadRequest, err.Err)
+}
+
+func TestCreateBidsReportsBidsLeftOverAtTheDeadlineAsErrored(t *testing.T) {
+	alice := uuid.New().String()
+	bidUseCase, auctionId := newBulkBidUseCase(
+		map[string]money.Amount{alice: 10000}, WithBulkLimits(defaultMaxBulkBids, time.Nanosecond))
+	defer bidUseCase.Close(context.Background())
+
+	results, err := bidUseCase.CreateBids(context.Background(), []BulkBidInputDTO{
+		{UserId: alice, AuctionId: auctionId, Amount: 100},
+		{UserId: alice, AuctionId: auctionId, Amount: 200},
+	})
+	assert.Nil(t, err)
+	for _, result := range results {
+		assert.Equal(t, BulkBidErrored, result.Result)
+		assert.Equal(t, bulkBidDeadlineExceeded, result.Code)
+	}
+}
+
+type slowBiddingAuctionRepository struct {
+	*fakeBiddingAuctionRepository
+}
+
+func (f *slowBiddingAuctionRepository) FindAuctionByIdFromPrimary(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	select {
+	case <-ctx.Done():
+		return nil, internal_error.NewInternalServerError("Error trying to find auction")
+	case <-time.After(time.Minute):
+		return f.fakeBiddingAuctionRepository.FindAuctionByIdFromPrimary(ctx, id)
+	}
+}
+
+func TestCreateBidsStopsABidStuckPastTheDeadline(t *testing.T) {
+	alice := uuid.New().String()
+	auctionId := uuid.New().String()
+	bidUseCase := NewBidUseCase(&fakeBatchBidRepository{}, &slowBiddingAuctionRepository{
+		&fakeBiddingAuctionRepository{auction: auction_entity.Auction{
+			Id:      auctionId,
+			Status:  auction_entity.Active,
+			EndTime: time.Now().Add(time.Hour),
+		}},
+	}, &fakeBalanceUserRepository{balances: map[string]money.Amount{alice: 10000}, held: map[string]money.Amount{}},
+		event.NewChannelPublisher(), WithBulkLimits(defaultMaxBulkBids, 50*time.Millisecond))
+	defer bidUseCase.Close(context.Background())
+
+	start := time.Now()
+	results, err := bidUseCase.CreateBids(context.Background(), []BulkBidInputDTO{
+		{UserId: alice, AuctionId: auctionId, Amount: 100},
+		{UserId: alice, AuctionId: auctionId, Amount: 200},
+	})
+	assert.Nil(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	for _, result := range results {
+		assert.Equal(t, BulkBidErrored, result.Result)
+		assert.Equal(t, bulkBidDeadlineExceeded, result.Code)
+	}
+}
+
+func TestCreateBidsStopsABidWaitingForTheAuctionLock(t *testing.T) {
+	alice := uuid.New().String()
+	bidUseCase, auctionId := newBulkBidUseCase(
+		map[string]money.Amount{alice: 10000}, WithBulkLimits(defaultMaxBulkBids, 50*time.Millisecond))
+	defer bidUseCase.Close(context.Background())
+
+	unlock, lockErr := bidUseCase.(*BidUseCase).auctionLocks.lock(context.Background(), auctionId)
+	assert.Nil(t, lockErr)
+	defer unlock()
+
+	results, err := bidUseCase.CreateBids(context.Background(), []BulkBidInputDTO{
+		{UserId: alice, AuctionId: auctionId, Amount: 100},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, BulkBidErrored, results[0].Result)
+	assert.Equal(t, bulkBidDeadlineExceeded, results[0].Code)
+}
+
+func violatedFields(err *internal_error.InternalError) []string {
+	fields := make([]string, len(err.Violations))
+	for index, violation := range err.Violations {
+		fields[index] = violation.Field
+	}
+
+	return fields
+}
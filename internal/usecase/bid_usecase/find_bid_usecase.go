@@ -2,28 +2,79 @@ package bid_usecase
 
 import (
 	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/bid_entity"
 	"fullcycle-auction_go/internal/internal_error"
 )
 
+const (
+	defaultBidPageSize = 20
+	maxBidPageSize     = 100
+)
+
 func (bu *BidUseCase) FindBidByAuctionId(
-	ctx context.Context, auctionId string) ([]BidOutputDTO, *internal_error.InternalError) {
-	bidList, err := bu.BidRepository.FindBidByAuctionId(ctx, auctionId)
+	ctx context.Context,
+	auctionId string,
+	findInput FindBidsInputDTO) (*BidPageOutputDTO, *internal_error.InternalError) {
+	if findInput.Limit == 0 {
+		findInput.Limit = defaultBidPageSize
+	}
+	if findInput.Order == "" {
+		findInput.Order = bid_entity.BidOrderAmount
+	}
+
+	if findInput.Limit < 1 || findInput.Limit > maxBidPageSize {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("limit must be between 1 and %d", maxBidPageSize))
+	}
+	if findInput.Offset < 0 {
+		return nil, internal_error.NewBadRequestError("offset must be greater than or equal to 0")
+	}
+	if findInput.Order != bid_entity.BidOrderAmount && findInput.Order != bid_entity.BidOrderChronological {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("order must be %s or %s", bid_entity.BidOrderAmount, bid_entity.BidOrderChronological))
+	}
+
+	findOptions := bid_entity.FindBidsOptions{
+		Limit:  int64(findInput.Limit),
+		Offset: int64(findInput.Offset),
+		Order:  findInput.Order,
+	}
+
+	auctionEntity, err := bu.AuctionRepository.FindAuctionById(ctx, auctionId)
+	if err != nil && !err.IsNotFound() {
+		return nil, err
+	}
+	if auctionEntity != nil && auctionEntity.HidesBids() {
+		if findInput.CallerId == "" {
+			return &BidPageOutputDTO{
+				Items:  []BidOutputDTO{},
+				Limit:  findInput.Limit,
+				Offset: findInput.Offset,
+				Order:  findInput.Order,
+			}, nil
+		}
+
+		findOptions.UserId = findInput.CallerId
+	}
+
+	bidPage, err := bu.BidRepository.FindBidByAuctionId(ctx, auctionId, findOptions)
 	if err != nil {
 		return nil, err
 	}
 
-	var bidOutputList []BidOutputDTO
-	for _, bid := range bidList {
-		bidOutputList = append(bidOutputList, BidOutputDTO{
-			Id:        bid.Id,
-			UserId:    bid.UserId,
-			AuctionId: bid.AuctionId,
-			Amount:    bid.Amount,
-			Timestamp: bid.Timestamp,
-		})
+	bidOutputList := make([]BidOutputDTO, 0, len(bidPage.Bids))
+	for _, bid := range bidPage.Bids {
+		bidOutputList = append(bidOutputList, *toBidOutputDTO(bid))
 	}
 
-	return bidOutputList, nil
+	return &BidPageOutputDTO{
+		Items:  bidOutputList,
+		Total:  bidPage.Total,
+		Limit:  findInput.Limit,
+		Offset: findInput.Offset,
+		Order:  findInput.Order,
+	}, nil
 }
 
 func (bu *BidUseCase) FindWinningBidByAuctionId(
@@ -33,13 +84,19 @@ func (bu *BidUseCase) FindWinningBidByAuctionId(
 		return nil, err
 	}
 
-	bidOutput := &BidOutputDTO{
+	return toBidOutputDTO(*bidEntity), nil
+}
+
+func toBidOutputDTO(bidEntity bid_entity.Bid) *BidOutputDTO {
+	return &BidOutputDTO{
 		Id:        bidEntity.Id,
 		UserId:    bidEntity.UserId,
 		AuctionId: bidEntity.AuctionId,
 		Amount:    bidEntity.Amount,
 		Timestamp: bidEntity.Timestamp,
+		Sequence:  bidEntity.Sequence,
+		Auto:      bidEntity.Auto,
+		Retracted: bidEntity.Retracted,
+		Voided:    bidEntity.Voided,
 	}
-
-	return bidOutput, nil
 }
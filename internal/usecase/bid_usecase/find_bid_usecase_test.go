@@ -0,0 +1,158 @@
+package bid_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type fakeFindBidRepository struct {
+	fakeBatchBidRepository
+	bids []bid_entity.Bid
+}
+
+func (f *fakeFindBidRepository) FindBidByAuctionId(
+	ctx context.Context,
+	auctionId string,
+	options bid_entity.FindBidsOptions) (*bid_entity.BidPage, *internal_error.InternalError) {
+	var bids []bid_entity.Bid
+	for _, bid := range f.bids {
+		if options.UserId == "" || bid.UserId == options.UserId {
+			bids = append(bids, bid)
+		}
+	}
+
+	return &bid_entity.BidPage{Bids: bids, Total: int64(len(bids))}, nil
+}
+
+func (f *fakeFindBidRepository) FindBidsByUserId(
+	ctx context.Context,
+	userId string,
+	options bid_entity.FindBidsOptions) (*bid_entity.BidPage, *internal_error.InternalError) {
+	var bids []bid_entity.Bid
+	for _, bid := range f.bids {
+		if bid.UserId == userId {
+			bids = append(bids, bid)
+		}
+	}
+
+	return &bid_entity.BidPage{Bids: bids, Total: int64(len(bids))}, nil
+}
+
+type fakeAuctionsByIdsRepository struct {
+	auction_entity.AuctionRepositoryInterface
+	auctions     []auction_entity.Auction
+	requestedIds []string
+}
+
+func (f *fakeAuctionsByIdsRepository) FindAuctionsByIds(
+	ctx context.Context, auctionIds []string) ([]auction_entity.Auction, *internal_error.InternalError) {
+	f.requestedIds = auctionIds
+	return f.auctions, nil
+}
+
+func TestFindBidsByUserIdJoinsTheAuctionOfEachBid(t *testing.T) {
+	alice, bob := uuid.New().String(), uuid.New().String()
+	leadingId, wonId, sealedId := uuid.New().String(), uuid.New().String(), uuid.New().String()
+
+	bidRepository := &fakeFindBidRepository{bids: []bid_entity.Bid{
+		{Id: "leading-raise", UserId: alice, AuctionId: leadingId, Amount: 9000},
+		{Id: "leading-first", UserId: alice, AuctionId: leadingId, Amount: 7000},
+		{Id: "won", UserId: alice, AuctionId: wonId, Amount: 5000},
+		{Id: "sealed", UserId: alice, AuctionId: sealedId, Amount: 3000},
+		{Id: "bob", UserId: bob, AuctionId: leadingId, Amount: 6000},
+	}}
+	auctionRepository := &fakeAuctionsByIdsRepository{auctions: []auction_entity.Auction{
+		{Id: leadingId, ProductName: "lamp", Status: auction_entity.Active, CurrentHighestUserId: alice},
+		{
+			Id: wonId, ProductName: "desk", Status: auction_entity.Completed,
+			CurrentHighestUserId: alice, WinnerUserId: alice,
+		},
+		{
+			Id: sealedId, ProductName: "chair", Type: auction_entity.SealedAuction,
+			Status: auction_entity.Active, CurrentHighestUserId: alice,
+		},
+	}}
+	bidUseCase := &BidUseCase{BidRepository: bidRepository, AuctionRepository: auctionRepository}
+
+	bidPage, err := bidUseCase.FindBidsByUserId(context.Background(), alice, FindBidsInputDTO{})
+	assert.Nil(t, err)
+	assert.Equal(t, bid_entity.BidOrderNewest, bidPage.Order)
+	assert.Equal(t, []string{leadingId, wonId, sealedId}, auctionRepository.requestedIds)
+	assert.Len(t, bidPage.Items, 4)
+
+	leading, won, sealed := bidPage.Items[0].Auction, bidPage.Items[2].Auction, bidPage.Items[3].Auction
+	assert.Equal(t, "lamp", leading.ProductName)
+	assert.True(t, *leading.Leading)
+	assert.False(t, leading.Won)
+	assert.False(t, *won.Leading)
+	assert.True(t, won.Won)
+	assert.Nil(t, sealed.Leading)
+
+	_, err = bidUseCase.FindBidsByUserId(context.Background(), alice, FindBidsInputDTO{Order: bid_entity.BidOrderAmount})
+	assert.Equal(t, internal_error.ErrBadRequest, err.Err)
+}
+
+func TestFindBidsOnlyShowsTheCallersBidsWhileASealedAuctionIsOpen(t *testing.T) {
+	alice, bob := uuid.New().String(), uuid.New().String()
+	auctionId := uuid.New().String()
+	bidRepository := &fakeFindBidRepository{bids: []bid_entity.Bid{
+		{Id: "alice-bid", UserId: alice, AuctionId: auctionId, Amount: 8000},
+		{Id: "bob-bid", UserId: bob, AuctionId: auctionId, Amount: 5000},
+	}}
+
+	tests := []struct {
+		name     string
+		auction  auction_entity.Auction
+		callerId string
+		expected []string
+	}{
+		{
+			name:     "open auction",
+			auction:  auction_entity.Auction{Type: auction_entity.OpenAuction, Status: auction_entity.Active},
+			callerId: bob,
+			expected: []string{"alice-bid", "bob-bid"},
+		},
+		{
+			name:     "sealed auction while active",
+			auction:  auction_entity.Auction{Type: auction_entity.SealedAuction, Status: auction_entity.Active},
+			callerId: bob,
+			expected: []string{"bob-bid"},
+		},
+		{
+			name:     "sealed auction without a caller",
+			auction:  auction_entity.Auction{Type: auction_entity.SealedAuction, Status: auction_entity.Active},
+			expected: []string{},
+		},
+		{
+			name:     "sealed auction once closed",
+			auction:  auction_entity.Auction{Type: auction_entity.SealedAuction, Status: auction_entity.Completed},
+			expected: []string{"alice-bid", "bob-bid"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.auction.Id = auctionId
+			bidUseCase := &BidUseCase{
+				BidRepository:     bidRepository,
+				AuctionRepository: &fakeBiddingAuctionRepository{auction: tt.auction},
+			}
+
+			bidPage, err := bidUseCase.FindBidByAuctionId(
+				context.Background(), auctionId, FindBidsInputDTO{CallerId: tt.callerId})
+			assert.Nil(t, err)
+
+			ids := []string{}
+			for _, bid := range bidPage.Items {
+				ids = append(ids, bid.Id)
+			}
+			assert.Equal(t, tt.expected, ids)
+			assert.Equal(t, int64(len(tt.expected)), bidPage.Total)
+		})
+	}
+}
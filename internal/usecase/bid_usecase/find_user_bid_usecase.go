@@ -0,0 +1,118 @@
+package bid_usecase
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+)
+
+type UserBidAuctionOutputDTO struct {
+	Id          string                       `json:"id"`
+	ProductName string                       `json:"product_name"`
+	Status      auction_entity.AuctionStatus `json:"status"`
+	Leading     *bool                        `json:"leading,omitempty"`
+	Won         bool                         `json:"won"`
+}
+
+type UserBidOutputDTO struct {
+	BidOutputDTO
+	Auction *UserBidAuctionOutputDTO `json:"auction,omitempty"`
+}
+
+type UserBidPageOutputDTO struct {
+	Items  []UserBidOutputDTO `json:"items"`
+	Total  int64              `json:"total"`
+	Limit  int                `json:"limit"`
+	Offset int                `json:"offset"`
+	Order  string             `json:"order"`
+}
+
+// FindBidsByUserId lists the user's bids with the auction each one was placed
+// on. Whether the user leads comes from the highest bid cached on the auction,
+// and is left out while a sealed auction is still running.
+func (bu *BidUseCase) FindBidsByUserId(
+	ctx context.Context,
+	userId string,
+	findInput FindBidsInputDTO) (*UserBidPageOutputDTO, *internal_error.InternalError) {
+	if findInput.Limit == 0 {
+		findInput.Limit = defaultBidPageSize
+	}
+	if findInput.Order == "" {
+		findInput.Order = bid_entity.BidOrderNewest
+	}
+
+	if findInput.Limit < 1 || findInput.Limit > maxBidPageSize {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("limit must be between 1 and %d", maxBidPageSize))
+	}
+	if findInput.Offset < 0 {
+		return nil, internal_error.NewBadRequestError("offset must be greater than or equal to 0")
+	}
+	if findInput.Order != bid_entity.BidOrderNewest && findInput.Order != bid_entity.BidOrderChronological {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("order must be %s or %s", bid_entity.BidOrderNewest, bid_entity.BidOrderChronological))
+	}
+
+	bidPage, err := bu.BidRepository.FindBidsByUserId(ctx, userId, bid_entity.FindBidsOptions{
+		Limit:  int64(findInput.Limit),
+		Offset: int64(findInput.Offset),
+		Order:  findInput.Order,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var auctionIds []string
+	seen := make(map[string]struct{})
+	for _, bid := range bidPage.Bids {
+		if _, ok := seen[bid.AuctionId]; !ok {
+			seen[bid.AuctionId] = struct{}{}
+			auctionIds = append(auctionIds, bid.AuctionId)
+		}
+	}
+
+	auctionEntities, err := bu.AuctionRepository.FindAuctionsByIds(ctx, auctionIds)
+	if err != nil {
+		return nil, err
+	}
+
+	auctionsById := make(map[string]*UserBidAuctionOutputDTO, len(auctionEntities))
+	for _, auctionEntity := range auctionEntities {
+		auctionsById[auctionEntity.Id] = toUserBidAuctionOutputDTO(auctionEntity, userId)
+	}
+
+	items := make([]UserBidOutputDTO, 0, len(bidPage.Bids))
+	for _, bid := range bidPage.Bids {
+		items = append(items, UserBidOutputDTO{
+			BidOutputDTO: *toBidOutputDTO(bid),
+			Auction:      auctionsById[bid.AuctionId],
+		})
+	}
+
+	return &UserBidPageOutputDTO{
+		Items:  items,
+		Total:  bidPage.Total,
+		Limit:  findInput.Limit,
+		Offset: findInput.Offset,
+		Order:  findInput.Order,
+	}, nil
+}
+
+func toUserBidAuctionOutputDTO(auctionEntity auction_entity.Auction, userId string) *UserBidAuctionOutputDTO {
+	auctionOutput := &UserBidAuctionOutputDTO{
+		Id:          auctionEntity.Id,
+		ProductName: auctionEntity.ProductName,
+		Status:      auctionEntity.Status,
+		Won:         auctionEntity.Status == auction_entity.Completed && auctionEntity.WinnerUserId == userId,
+	}
+
+	if !auctionEntity.HidesBids() {
+		leading := auctionEntity.CurrentHighestUserId == userId && auctionEntity.Status != auction_entity.Completed &&
+			auctionEntity.Status != auction_entity.Cancelled
+		auctionOutput.Leading = &leading
+	}
+
+	return auctionOutput
+}
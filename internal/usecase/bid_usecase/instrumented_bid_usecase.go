@@ -0,0 +1,52 @@
+package bid_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/metrics"
+)
+
+var (
+	bidsAccepted = metrics.Default.NewCounter("bids_accepted_total", "Bids accepted.")
+	bidsRejected = metrics.Default.NewCounterVec("bids_rejected_total", "Bids rejected, by error code.", "code")
+)
+
+// InstrumentedBidUseCase counts the outcome of every bid placed through it.
+type InstrumentedBidUseCase struct {
+	BidUseCaseInterface
+}
+
+func NewInstrumentedBidUseCase(bidUseCase BidUseCaseInterface) *InstrumentedBidUseCase {
+	return &InstrumentedBidUseCase{BidUseCaseInterface: bidUseCase}
+}
+
+func (iu *InstrumentedBidUseCase) CreateBid(
+	ctx context.Context, bidInputDTO BidInputDTO) (*BidOutputDTO, *internal_error.InternalError) {
+	bidOutput, err := iu.BidUseCaseInterface.CreateBid(ctx, bidInputDTO)
+	if err != nil {
+		bidsRejected.WithLabelValues(err.Err).Inc()
+		return nil, err
+	}
+
+	bidsAccepted.Inc()
+	return bidOutput, nil
+}
+
+func (iu *InstrumentedBidUseCase) CreateBids(
+	ctx context.Context, bidInputs []BulkBidInputDTO) ([]BulkBidResultDTO, *internal_error.InternalError) {
+	results, err := iu.BidUseCaseInterface.CreateBids(ctx, bidInputs)
+	if err != nil {
+		bidsRejected.WithLabelValues(err.Err).Add(int64(len(bidInputs)))
+		return nil, err
+	}
+
+	for _, result := range results {
+		if result.Result == BulkBidAccepted {
+			bidsAccepted.Inc()
+		} else {
+			bidsRejected.WithLabelValues(result.Code).Inc()
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,38 @@
+package bid_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type fakeCreateBidUseCase struct {
+	BidUseCaseInterface
+	err *internal_error.InternalError
+}
+
+func (f *fakeCreateBidUseCase) CreateBid(
+	ctx context.Context, bidInputDTO BidInputDTO) (*BidOutputDTO, *internal_error.InternalError) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &BidOutputDTO{}, nil
+}
+
+func TestInstrumentedBidUseCaseCountsBidOutcomes(t *testing.T) {
+	accepted := bidsAccepted.Value()
+	rejected := bidsRejected.WithLabelValues(internal_error.ErrConflict).Value()
+
+	_, err := NewInstrumentedBidUseCase(&fakeCreateBidUseCase{}).CreateBid(context.Background(), BidInputDTO{})
+	assert.Nil(t, err)
+
+	_, err = NewInstrumentedBidUseCase(&fakeCreateBidUseCase{
+		err: internal_error.NewConflictError("outbid"),
+	}).CreateBid(context.Background(), BidInputDTO{})
+	assert.NotNil(t, err)
+
+	assert.Equal(t, accepted+1, bidsAccepted.Value())
+	assert.Equal(t, rejected+1, bidsRejected.WithLabelValues(internal_error.ErrConflict).Value())
+}
@@ -0,0 +1,235 @@
+package bid_usecase
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"github.com/google/uuid"
+	"sync"
+)
+
+const (
+	maxProxyRounds  = 50
+	defaultProxyBid = money.Cent
+)
+
+type auctionLock struct {
+	held chan struct{}
+	refs int
+}
+
+type auctionLocker struct {
+	mutex sync.Mutex
+	locks map[string]*auctionLock
+}
+
+func newAuctionLocker() *auctionLocker {
+	return &auctionLocker{locks: make(map[string]*auctionLock)}
+}
+
+// lock waits for the lock of the auction until ctx is done.
+func (al *auctionLocker) lock(ctx context.Context, auctionId string) (func(), error) {
+	al.mutex.Lock()
+	lock, ok := al.locks[auctionId]
+	if !ok {
+		lock = &auctionLock{held: make(chan struct{}, 1)}
+		al.locks[auctionId] = lock
+	}
+	lock.refs++
+	al.mutex.Unlock()
+
+	select {
+	case lock.held <- struct{}{}:
+	case <-ctx.Done():
+		al.release(auctionId, lock)
+		return nil, ctx.Err()
+	}
+
+	return func() {
+		<-lock.held
+		al.release(auctionId, lock)
+	}, nil
+}
+
+func (al *auctionLocker) release(auctionId string, lock *auctionLock) {
+	al.mutex.Lock()
+	defer al.mutex.Unlock()
+
+	lock.refs--
+	if lock.refs == 0 {
+		delete(al.locks, auctionId)
+	}
+}
+
+func (bu *BidUseCase) lockAuction(ctx context.Context, auctionId string) (func(), *internal_error.InternalError) {
+	unlock, err := bu.auctionLocks.lock(ctx, auctionId)
+	if err != nil {
+		return nil, internal_error.NewInternalServerError(
+			"Gave up waiting for other bids on this auction").WithCause(err)
+	}
+
+	return unlock, nil
+}
+
+// placeProxyBid stores the bidder's proxy and, unless they already lead, opens
+// it with the lowest bid that takes the lead. It reports whether that opening
+// bid reached the buy-now price and closed the auction.
+func (bu *BidUseCase) placeProxyBid(
+	ctx context.Context,
+	auctionEntity *auction_entity.Auction,
+	bidEntity *bid_entity.Bid) (bool, *internal_error.InternalError) {
+	maxBid := bid_entity.MaxBid{
+		UserId:    bidEntity.UserId,
+		AuctionId: bidEntity.AuctionId,
+		MaxAmount: bidEntity.Amount,
+		Timestamp: bidEntity.Timestamp,
+	}
+
+	if auctionEntity.CurrentHighestUserId == bidEntity.UserId {
+		if maxBid.MaxAmount < auctionEntity.CurrentHighestAmount {
+			return false, internal_error.NewFieldBadRequestError(
+				"Invalid max amount", "max_amount",
+				fmt.Sprintf("must be at least the current highest bid of %s", auctionEntity.CurrentHighestAmount))
+		}
+
+		return false, bu.BidRepository.SaveMaxBid(ctx, maxBid)
+	}
+
+	increment := bu.minIncrementFor(*auctionEntity)
+	bidEntity.Amount = proxyAmount(
+		proxyLimit(*auctionEntity, maxBid.MaxAmount), auctionEntity.MinimumBid(proxyStep(increment)))
+
+	if auctionEntity.IsBuyNow(bidEntity.Amount) {
+		_, err := bu.placeClosingBid(ctx, auctionEntity, bidEntity, bu.AuctionRepository.BuyNow)
+		return err == nil, err
+	}
+
+	if err := bu.placeHighestBid(ctx, auctionEntity, bidEntity, increment); err != nil {
+		return false, err
+	}
+
+	return false, bu.BidRepository.SaveMaxBid(ctx, maxBid)
+}
+
+func (bu *BidUseCase) resolveProxyBids(ctx context.Context, auctionId string) {
+	for round := 0; round < maxProxyRounds; round++ {
+		auctionEntity, err := bu.AuctionRepository.FindAuctionByIdFromPrimary(ctx, auctionId)
+		if err != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf("Error trying to resolve proxy bids for auction %s", auctionId), err)
+			return
+		}
+		if auctionEntity.Status != auction_entity.Active || !auctionEntity.EndTime.After(bu.Clock.Now()) {
+			return
+		}
+
+		maxBids, err := bu.BidRepository.FindMaxBidsByAuctionId(ctx, auctionId)
+		if err != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf("Error trying to resolve proxy bids for auction %s", auctionId), err)
+			return
+		}
+
+		increment := bu.minIncrementFor(*auctionEntity)
+		userId, amount, ok := nextProxyBid(*auctionEntity, maxBids, increment)
+		if !ok {
+			return
+		}
+
+		autoBid := bid_entity.Bid{
+			Id:        uuid.New().String(),
+			UserId:    userId,
+			AuctionId: auctionId,
+			Amount:    amount,
+			Timestamp: bu.Clock.Now(),
+			Auto:      true,
+		}
+		if auctionEntity.IsBuyNow(amount) {
+			if _, err := bu.placeClosingBid(ctx, auctionEntity, &autoBid, bu.AuctionRepository.BuyNow); err != nil {
+				logger.ErrorContext(ctx, fmt.Sprintf("Error trying to place proxy bid for auction %s", auctionId), err)
+			}
+			return
+		}
+		if err := bu.placeHighestBid(ctx, auctionEntity, &autoBid, increment); err != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf("Error trying to place proxy bid for auction %s", auctionId), err)
+			return
+		}
+
+		bu.enqueueBid(autoBid)
+		bu.publishBidAccepted(ctx, auctionEntity, autoBid)
+	}
+}
+
+// nextProxyBid returns the automatic counter-bid the stored proxies produce
+// against the current leader, if any. Equal maximums go to the earliest proxy.
+// No proxy bids past the buy-now price; reaching it buys the auction instead.
+func nextProxyBid(
+	auctionEntity auction_entity.Auction,
+	maxBids []bid_entity.MaxBid,
+	increment money.Amount) (string, money.Amount, bool) {
+	step := proxyStep(increment)
+	minimum := auctionEntity.MinimumBid(step)
+
+	var leader, challenger *bid_entity.MaxBid
+	var challengerLimit money.Amount
+	for i := range maxBids {
+		maxBid := &maxBids[i]
+		if maxBid.UserId == auctionEntity.CurrentHighestUserId {
+			if leader == nil {
+				leader = maxBid
+			}
+			continue
+		}
+
+		// Proxies capped at the buy-now price tie there, whatever their maximum.
+		limit := proxyLimit(auctionEntity, maxBid.MaxAmount)
+		if limit >= minimum && (challenger == nil || limit > challengerLimit ||
+			(limit == challengerLimit && maxBid.Timestamp.Before(challenger.Timestamp))) {
+			challenger, challengerLimit = maxBid, limit
+		}
+	}
+
+	if challenger == nil {
+		return "", 0, false
+	}
+
+	if leader == nil {
+		return challenger.UserId, proxyAmount(challengerLimit, minimum), true
+	}
+
+	leaderLimit := proxyLimit(auctionEntity, leader.MaxAmount)
+	if challengerLimit > leaderLimit ||
+		(challengerLimit == leaderLimit && challenger.Timestamp.Before(leader.Timestamp)) {
+		return challenger.UserId, proxyAmount(challengerLimit, leaderLimit+step), true
+	}
+
+	return leader.UserId, proxyAmount(leaderLimit, challengerLimit+step), true
+}
+
+func proxyStep(increment money.Amount) money.Amount {
+	if increment > 0 {
+		return increment
+	}
+
+	return defaultProxyBid
+}
+
+func proxyAmount(maxAmount, needed money.Amount) money.Amount {
+	if needed < maxAmount {
+		return needed
+	}
+
+	return maxAmount
+}
+
+// proxyLimit is the most a proxy may bid on the auction: its maximum, capped
+// at the buy-now price when the auction has one.
+func proxyLimit(auctionEntity auction_entity.Auction, maxAmount money.Amount) money.Amount {
+	if auctionEntity.BuyNowPrice > 0 && maxAmount > auctionEntity.BuyNowPrice {
+		return auctionEntity.BuyNowPrice
+	}
+
+	return maxAmount
+}
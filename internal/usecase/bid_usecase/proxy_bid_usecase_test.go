@@ -0,0 +1,260 @@
+package bid_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/event"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNextProxyBid(t *testing.T) {
+	earlier := time.Now()
+	later := earlier.Add(time.Second)
+
+	tests := []struct {
+		name           string
+		leaderId       string
+		currentAmount  money.Amount
+		buyNowPrice    money.Amount
+		maxBids        []bid_entity.MaxBid
+		expectedOk     bool
+		expectedUserId string
+		expectedAmount money.Amount
+	}{
+		{
+			name:          "no proxies",
+			leaderId:      "alice",
+			currentAmount: 100,
+			expectedOk:    false,
+		},
+		{
+			name:          "only the leader has a proxy",
+			leaderId:      "alice",
+			currentAmount: 100,
+			maxBids: []bid_entity.MaxBid{
+				{UserId: "alice", MaxAmount: 200, Timestamp: earlier},
+			},
+			expectedOk: false,
+		},
+		{
+			name:          "challenger outbids a manual leader by one increment",
+			leaderId:      "alice",
+			currentAmount: 100,
+			maxBids: []bid_entity.MaxBid{
+				{UserId: "bob", MaxAmount: 200, Timestamp: earlier},
+			},
+			expectedOk:     true,
+			expectedUserId: "bob",
+			expectedAmount: 110,
+		},
+		{
+			name:          "leader defends up to the challenger max plus increment",
+			leaderId:      "alice",
+			currentAmount: 100,
+			maxBids: []bid_entity.MaxBid{
+				{UserId: "alice", MaxAmount: 300, Timestamp: later},
+				{UserId: "bob", MaxAmount: 200, Timestamp: earlier},
+			},
+			expectedOk:     true,
+			expectedUserId: "alice",
+			expectedAmount: 210,
+		},
+		{
+			name:          "challenger with a higher max takes the lead",
+			leaderId:      "alice",
+			currentAmount: 100,
+			maxBids: []bid_entity.MaxBid{
+				{UserId: "bob", MaxAmount: 300, Timestamp: later},
+				{UserId: "alice", MaxAmount: 200, Timestamp: earlier},
+			},
+			expectedOk:     true,
+			expectedUserId: "bob",
+			expectedAmount: 210,
+		},
+		{
+			name:          "equal maxes go to the earliest proxy",
+			leaderId:      "alice",
+			currentAmount: 150,
+			maxBids: []bid_entity.MaxBid{
+				{UserId: "bob", MaxAmount: 200, Timestamp: earlier},
+				{UserId: "alice", MaxAmount: 200, Timestamp: later},
+			},
+			expectedOk:     true,
+			expectedUserId: "bob",
+			expectedAmount: 200,
+		},
+		{
+			name:          "earliest leader keeps an equal max",
+			leaderId:      "alice",
+			currentAmount: 150,
+			maxBids: []bid_entity.MaxBid{
+				{UserId: "alice", MaxAmount: 200, Timestamp: earlier},
+				{UserId: "bob", MaxAmount: 200, Timestamp: later},
+			},
+			expectedOk:     true,
+			expectedUserId: "alice",
+			expectedAmount: 200,
+		},
+		{
+			name:          "challenger stops at the buy-now price",
+			leaderId:      "alice",
+			currentAmount: 100,
+			buyNowPrice:   250,
+			maxBids: []bid_entity.MaxBid{
+				{UserId: "bob", MaxAmount: 400, Timestamp: later},
+				{UserId: "alice", MaxAmount: 300, Timestamp: earlier},
+			},
+			expectedOk:     true,
+			expectedUserId: "alice",
+			expectedAmount: 250,
+		},
+		{
+			name:          "proxies past the buy-now price tie at it",
+			leaderId:      "alice",
+			currentAmount: 100,
+			buyNowPrice:   250,
+			maxBids: []bid_entity.MaxBid{
+				{UserId: "bob", MaxAmount: 400, Timestamp: earlier},
+				{UserId: "alice", MaxAmount: 300, Timestamp: later},
+			},
+			expectedOk:     true,
+			expectedUserId: "bob",
+			expectedAmount: 250,
+		},
+		{
+			name:          "earliest challenger wins a tie at the buy-now price",
+			leaderId:      "alice",
+			currentAmount: 100,
+			buyNowPrice:   250,
+			maxBids: []bid_entity.MaxBid{
+				{UserId: "carol", MaxAmount: 500, Timestamp: later},
+				{UserId: "bob", MaxAmount: 400, Timestamp: earlier},
+			},
+			expectedOk:     true,
+			expectedUserId: "bob",
+			expectedAmount: 110,
+		},
+		{
+			name:          "challenger below the next minimum is ignored",
+			leaderId:      "alice",
+			currentAmount: 195,
+			maxBids: []bid_entity.MaxBid{
+				{UserId: "bob", MaxAmount: 200, Timestamp: earlier},
+			},
+			expectedOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auctionEntity := auction_entity.Auction{
+				CurrentHighestUserId: tt.leaderId,
+				CurrentHighestAmount: tt.currentAmount,
+				BuyNowPrice:          tt.buyNowPrice,
+			}
+
+			userId, amount, ok := nextProxyBid(auctionEntity, tt.maxBids, 10)
+			assert.Equal(t, tt.expectedOk, ok)
+			assert.Equal(t, tt.expectedUserId, userId)
+			assert.Equal(t, tt.expectedAmount, amount)
+		})
+	}
+}
+
+func TestAuctionLockerReleasesIdleLocks(t *testing.T) {
+	locker := newAuctionLocker()
+
+	unlock, err := locker.lock(context.Background(), "auction")
+	assert.Nil(t, err)
+	assert.Len(t, locker.locks, 1)
+	unlock()
+
+	assert.Empty(t, locker.locks)
+}
+
+func TestAuctionLockerGivesUpWhenTheContextIsDone(t *testing.T) {
+	locker := newAuctionLocker()
+
+	unlock, err := locker.lock(context.Background(), "auction")
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = locker.lock(ctx, "auction")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, locker.locks["auction"].refs)
+
+	unlock()
+	assert.Empty(t, locker.locks)
+}
+
+type fakeProxyBidRepository struct {
+	fakeBatchBidRepository
+	mutex   sync.Mutex
+	maxBids []bid_entity.MaxBid
+}
+
+func (f *fakeProxyBidRepository) SaveMaxBid(
+	ctx context.Context, maxBid bid_entity.MaxBid) *internal_error.InternalError {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.maxBids = append(f.maxBids, maxBid)
+	return nil
+}
+
+func (f *fakeProxyBidRepository) FindMaxBidsByAuctionId(
+	ctx context.Context, auctionId string) ([]bid_entity.MaxBid, *internal_error.InternalError) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	maxBids := append([]bid_entity.MaxBid(nil), f.maxBids...)
+	sort.SliceStable(maxBids, func(i, j int) bool {
+		if maxBids[i].MaxAmount != maxBids[j].MaxAmount {
+			return maxBids[i].MaxAmount > maxBids[j].MaxAmount
+		}
+		return maxBids[i].Timestamp.Before(maxBids[j].Timestamp)
+	})
+	return maxBids, nil
+}
+
+func TestProxyBidsStopAtTheBuyNowPriceAndBuyTheAuction(t *testing.T) {
+	alice, bob := uuid.New().String(), uuid.New().String()
+	auctionId := uuid.New().String()
+	userRepository := &fakeBalanceUserRepository{
+		balances: map[string]money.Amount{alice: 30000, bob: 30000},
+		held:     map[string]money.Amount{alice: 8000},
+	}
+	auctionRepository := &fakeBiddingAuctionRepository{auction: auction_entity.Auction{
+		Id:                   auctionId,
+		Status:               auction_entity.Active,
+		EndTime:              time.Now().Add(time.Hour),
+		BuyNowPrice:          10000,
+		CurrentHighestUserId: alice,
+		CurrentHighestAmount: 8000,
+	}}
+	bidRepository := &fakeProxyBidRepository{maxBids: []bid_entity.MaxBid{
+		{UserId: alice, AuctionId: auctionId, MaxAmount: 20000, Timestamp: time.Now().Add(-time.Minute)},
+	}}
+	bidUseCase := NewBidUseCase(bidRepository, auctionRepository, userRepository, event.NewChannelPublisher())
+	defer bidUseCase.Close(context.Background())
+
+	_, err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId: bob, AuctionId: auctionId, Amount: 15000, MaxAmount: 15000,
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, auction_entity.Completed, auctionRepository.auction.Status)
+	assert.Equal(t, alice, auctionRepository.auction.WinnerUserId)
+	assert.Equal(t, money.Amount(10000), auctionRepository.auction.WinningAmount)
+	assert.Equal(t, money.Amount(10000), userRepository.held[alice])
+	assert.Equal(t, money.Amount(0), userRepository.held[bob])
+}
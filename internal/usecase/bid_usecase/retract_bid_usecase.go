@@ -0,0 +1,159 @@
+package bid_usecase
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/clock"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"sync"
+	"time"
+)
+
+// recentBids keeps the bids accepted within the retraction window, since they
+// may still be waiting in the insert batch.
+type recentBids struct {
+	mutex  sync.Mutex
+	window time.Duration
+	clock  clock.Clock
+	bids   map[string]bid_entity.Bid
+}
+
+func newRecentBids(window time.Duration, recentClock clock.Clock) *recentBids {
+	return &recentBids{window: window, clock: recentClock, bids: make(map[string]bid_entity.Bid)}
+}
+
+func (rb *recentBids) add(bidEntity bid_entity.Bid) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	for id, recent := range rb.bids {
+		if rb.clock.Now().Sub(recent.Timestamp) > rb.window {
+			delete(rb.bids, id)
+		}
+	}
+	rb.bids[bidEntity.Id] = bidEntity
+}
+
+func (rb *recentBids) get(bidId string) (bid_entity.Bid, bool) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	bidEntity, ok := rb.bids[bidId]
+	return bidEntity, ok
+}
+
+func (rb *recentBids) remove(bidId string) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	delete(rb.bids, bidId)
+}
+
+func (bu *BidUseCase) RetractBid(
+	ctx context.Context, bidId, callerId string) *internal_error.InternalError {
+	bidEntity, err := bu.findBidToRetract(ctx, bidId)
+	if err != nil {
+		return err
+	}
+
+	if bidEntity.UserId != callerId {
+		return internal_error.NewForbiddenError("Only the bidder can retract this bid")
+	}
+	if bidEntity.Retracted {
+		return internal_error.NewConflictError("Bid was already retracted")
+	}
+	if bu.Clock.Now().Sub(bidEntity.Timestamp) > bu.recentBids.window {
+		return internal_error.NewBadRequestError("Bid can no longer be retracted")
+	}
+
+	unlock, err := bu.lockAuction(ctx, bidEntity.AuctionId)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	auctionEntity, err := bu.AuctionRepository.FindAuctionByIdFromPrimary(ctx, bidEntity.AuctionId)
+	if err != nil {
+		return err
+	}
+
+	untilClose := auctionEntity.EndTime.Sub(bu.Clock.Now())
+	if auctionEntity.Status != auction_entity.Active || untilClose <= 0 {
+		return internal_error.NewBadRequestError("Bids can only be retracted while the auction is active")
+	}
+	if untilClose < bu.snipeExtension.Window {
+		return internal_error.NewBadRequestError("Bids cannot be retracted this close to the end of the auction")
+	}
+
+	if err := bu.BidRepository.RetractBid(ctx, *bidEntity); err != nil {
+		return err
+	}
+	bu.recentBids.remove(bidEntity.Id)
+
+	if err := bu.AuctionRepository.DecrementBidCount(ctx, bidEntity.AuctionId); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to update the bid count after retracting bid %s", bidEntity.Id), err)
+	}
+
+	if err := bu.BidRepository.DeleteMaxBid(ctx, bidEntity.AuctionId, bidEntity.UserId); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf("Error trying to drop the proxy of retracted bid %s", bidEntity.Id), err)
+	}
+
+	if auctionEntity.CurrentHighestUserId != bidEntity.UserId ||
+		auctionEntity.CurrentHighestAmount != bidEntity.Amount {
+		return nil
+	}
+
+	return bu.promoteRunnerUp(ctx, bidEntity.AuctionId, bidEntity.UserId, bidEntity.Amount)
+}
+
+// promoteRunnerUp hands the lead fromUserId holds with fromAmount to the
+// highest bid still counting for the auction, moving the hold along with it.
+// Buffered bids are written first so the runner-up may be one of them.
+func (bu *BidUseCase) promoteRunnerUp(
+	ctx context.Context,
+	auctionId, fromUserId string,
+	fromAmount money.Amount) *internal_error.InternalError {
+	if err := bu.Flush(ctx); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf(
+			"Error trying to flush bids before finding the runner-up of auction %s", auctionId), err)
+	}
+
+	var nextUserId string
+	var nextAmount money.Amount
+	nextBid, err := bu.BidRepository.FindHighestBidByAuctionId(ctx, auctionId)
+	if err != nil && !err.IsNotFound() {
+		return err
+	}
+	if nextBid != nil {
+		nextUserId, nextAmount = nextBid.UserId, nextBid.Amount
+	}
+
+	if err := bu.AuctionRepository.ReplaceHighestBid(ctx, auctionId,
+		fromUserId, fromAmount, nextUserId, nextAmount); err != nil {
+		return err
+	}
+
+	bu.releaseFunds(ctx, fromUserId, auctionId, fromAmount)
+	if nextUserId != "" {
+		if err := bu.UserRepository.HoldFunds(ctx, nextUserId, auctionId, nextAmount); err != nil {
+			logger.WarnContext(ctx, fmt.Sprintf(
+				"Could not hold funds of user %s who took back the lead of auction %s: %s",
+				nextUserId, auctionId, err.Message))
+		}
+	}
+
+	return nil
+}
+
+func (bu *BidUseCase) findBidToRetract(
+	ctx context.Context, bidId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	if bidEntity, ok := bu.recentBids.get(bidId); ok {
+		return &bidEntity, nil
+	}
+
+	return bu.BidRepository.FindBidById(ctx, bidId)
+}
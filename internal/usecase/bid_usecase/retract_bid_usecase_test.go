@@ -0,0 +1,147 @@
+package bid_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/event"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRecentBidsDropsBidsOutsideTheRetractionWindow(t *testing.T) {
+	fakeClock := fakeclock.New(time.Now())
+	recent := newRecentBids(time.Minute, fakeClock)
+
+	recent.add(bid_entity.Bid{Id: "old", Timestamp: fakeClock.Now()})
+	fakeClock.Advance(2 * time.Minute)
+	recent.add(bid_entity.Bid{Id: "new", Timestamp: fakeClock.Now()})
+
+	_, ok := recent.get("old")
+	assert.False(t, ok)
+
+	bidEntity, ok := recent.get("new")
+	assert.True(t, ok)
+	assert.Equal(t, "new", bidEntity.Id)
+
+	recent.remove("new")
+	_, ok = recent.get("new")
+	assert.False(t, ok)
+}
+
+type fakeRetractBidRepository struct {
+	fakeBatchBidRepository
+	mutex sync.Mutex
+	bids  map[string]bid_entity.Bid
+}
+
+func (f *fakeRetractBidRepository) CreateBid(
+	ctx context.Context, bidEntities []bid_entity.Bid) *internal_error.InternalError {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for _, bidEntity := range bidEntities {
+		if _, ok := f.bids[bidEntity.Id]; !ok {
+			f.bids[bidEntity.Id] = bidEntity
+		}
+	}
+	return nil
+}
+
+func (f *fakeRetractBidRepository) RetractBid(
+	ctx context.Context, bidEntity bid_entity.Bid) *internal_error.InternalError {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	bidEntity.Retracted = true
+	f.bids[bidEntity.Id] = bidEntity
+	return nil
+}
+
+func (f *fakeRetractBidRepository) DeleteMaxBid(
+	ctx context.Context, auctionId, userId string) *internal_error.InternalError {
+	return nil
+}
+
+func (f *fakeRetractBidRepository) FindHighestBidByAuctionId(
+	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var highest *bid_entity.Bid
+	for _, bidEntity := range f.bids {
+		if bidEntity.AuctionId == auctionId && !bidEntity.Retracted &&
+			(highest == nil || bidEntity.Amount > highest.Amount) {
+			found := bidEntity
+			highest = &found
+		}
+	}
+	if highest == nil {
+		return nil, internal_error.NewNotFoundError("No bids found")
+	}
+	return highest, nil
+}
+
+type fakeRetractAuctionRepository struct {
+	fakeBiddingAuctionRepository
+}
+
+func (f *fakeRetractAuctionRepository) DecrementBidCount(
+	ctx context.Context, auctionId string) *internal_error.InternalError {
+	return nil
+}
+
+func (f *fakeRetractAuctionRepository) ReplaceHighestBid(
+	ctx context.Context,
+	auctionId, fromUserId string,
+	fromAmount money.Amount,
+	toUserId string,
+	toAmount money.Amount) *internal_error.InternalError {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.auction.CurrentHighestUserId = toUserId
+	f.auction.CurrentHighestAmount = toAmount
+	return nil
+}
+
+func TestRetractingTheLeadingBidPromotesABufferedRunnerUp(t *testing.T) {
+	alice, bob := uuid.New().String(), uuid.New().String()
+	auctionId := uuid.New().String()
+	userRepository := &fakeBalanceUserRepository{
+		balances: map[string]money.Amount{alice: 10000, bob: 10000},
+		held:     map[string]money.Amount{},
+	}
+	auctionRepository := &fakeRetractAuctionRepository{fakeBiddingAuctionRepository{auction: auction_entity.Auction{
+		Id:      auctionId,
+		Status:  auction_entity.Active,
+		EndTime: time.Now().Add(time.Hour),
+	}}}
+	bidRepository := &fakeRetractBidRepository{bids: map[string]bid_entity.Bid{}}
+	bidUseCase := NewBidUseCase(bidRepository, auctionRepository, userRepository, event.NewChannelPublisher(),
+		WithBatching(100, time.Hour))
+	defer bidUseCase.Close(context.Background())
+
+	_, err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId: alice, AuctionId: auctionId, Amount: 8000,
+	})
+	assert.Nil(t, err)
+	bobBid, err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId: bob, AuctionId: auctionId, Amount: 9000,
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, bidRepository.bids)
+
+	assert.Nil(t, bidUseCase.RetractBid(context.Background(), bobBid.Id, bob))
+
+	assert.Equal(t, alice, auctionRepository.auction.CurrentHighestUserId)
+	assert.Equal(t, money.Amount(8000), auctionRepository.auction.CurrentHighestAmount)
+	assert.Equal(t, money.Amount(8000), userRepository.held[alice])
+	assert.Equal(t, money.Amount(0), userRepository.held[bob])
+}
@@ -0,0 +1,80 @@
+package user_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+)
+
+type UserInputDTO struct {
+	Name     string `json:"name" binding:"required,min=2,max=100"`
+	Email    string `json:"email" binding:"required,email,max=254"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+	Role     string `json:"role" binding:"omitempty,oneof=buyer seller"`
+}
+
+type RoleInputDTO struct {
+	Role string `json:"role" binding:"required,oneof=admin seller buyer partner"`
+}
+
+func (u *UserUseCase) CreateUser(
+	ctx context.Context, userInput UserInputDTO) (*UserOutputDTO, *internal_error.InternalError) {
+	userEntity, err := user_entity.CreateUser(
+		userInput.Name, userInput.Email, userInput.Password, user_entity.Role(userInput.Role))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.UserRepository.CreateUser(ctx, userEntity); err != nil {
+		return nil, err
+	}
+
+	return &UserOutputDTO{
+		Id:    userEntity.Id,
+		Name:  userEntity.Name,
+		Email: userEntity.Email,
+		Role:  string(userEntity.Role),
+	}, nil
+}
+
+func (u *UserUseCase) SetUserRole(
+	ctx context.Context, userId string, role user_entity.Role) (*UserOutputDTO, *internal_error.InternalError) {
+	if !role.IsValid() {
+		return nil, internal_error.NewFieldBadRequestError(
+			"Invalid user role", "role", "role must be admin, seller or buyer")
+	}
+
+	if err := u.UserRepository.SetRole(ctx, userId, role); err != nil {
+		return nil, err
+	}
+
+	return u.FindUserById(ctx, userId)
+}
+
+// SeedAdmin makes sure the user registered with email is an administrator.
+// When nobody is registered with it yet, the admin is created with password.
+func (u *UserUseCase) SeedAdmin(ctx context.Context, email, password string) *internal_error.InternalError {
+	userEntity, err := u.UserRepository.FindUserByEmail(ctx, email)
+	if err == nil {
+		if userEntity.Role == user_entity.AdminRole {
+			return nil
+		}
+
+		return u.UserRepository.SetRole(ctx, userEntity.Id, user_entity.AdminRole)
+	}
+	if !err.IsNotFound() {
+		return err
+	}
+
+	if password == "" {
+		return internal_error.NewBadRequestError(
+			"No user is registered with the admin email and no password was given to create one")
+	}
+
+	adminEntity, err := user_entity.CreateUser("admin", email, password, user_entity.AdminRole)
+	if err != nil {
+		return err
+	}
+
+	return u.UserRepository.CreateUser(ctx, adminEntity)
+}
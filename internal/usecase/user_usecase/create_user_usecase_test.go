@@ -0,0 +1,65 @@
+package user_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type fakeSeedUserRepository struct {
+	user_entity.UserRepositoryInterface
+	users map[string]*user_entity.User
+}
+
+func (f *fakeSeedUserRepository) FindUserByEmail(
+	ctx context.Context, email string) (*user_entity.User, *internal_error.InternalError) {
+	for _, userEntity := range f.users {
+		if userEntity.Email == email {
+			return userEntity, nil
+		}
+	}
+
+	return nil, internal_error.NewNotFoundError("User not found")
+}
+
+func (f *fakeSeedUserRepository) CreateUser(
+	ctx context.Context, userEntity *user_entity.User) *internal_error.InternalError {
+	f.users[userEntity.Id] = userEntity
+	return nil
+}
+
+func (f *fakeSeedUserRepository) SetRole(
+	ctx context.Context, userId string, role user_entity.Role) *internal_error.InternalError {
+	f.users[userId].Role = role
+	return nil
+}
+
+func TestSeedAdminPromotesTheRegisteredUser(t *testing.T) {
+	userRepository := &fakeSeedUserRepository{users: map[string]*user_entity.User{
+		"buyer": {Id: "buyer", Email: "admin@example.com", Role: user_entity.BuyerRole},
+	}}
+	userUseCase := NewUserUseCase(userRepository)
+
+	assert.Nil(t, userUseCase.SeedAdmin(context.Background(), "admin@example.com", ""))
+
+	assert.Len(t, userRepository.users, 1)
+	assert.Equal(t, user_entity.AdminRole, userRepository.users["buyer"].Role)
+}
+
+func TestSeedAdminCreatesTheAdminWhenNobodyIsRegistered(t *testing.T) {
+	userRepository := &fakeSeedUserRepository{users: map[string]*user_entity.User{}}
+	userUseCase := NewUserUseCase(userRepository)
+
+	assert.NotNil(t, userUseCase.SeedAdmin(context.Background(), "admin@example.com", ""))
+	assert.Empty(t, userRepository.users)
+
+	assert.Nil(t, userUseCase.SeedAdmin(context.Background(), "admin@example.com", "a-strong-password"))
+
+	assert.Len(t, userRepository.users, 1)
+	for _, userEntity := range userRepository.users {
+		assert.Equal(t, user_entity.AdminRole, userEntity.Role)
+		assert.True(t, userEntity.PasswordMatches("a-strong-password"))
+	}
+}
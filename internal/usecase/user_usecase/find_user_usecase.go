@@ -2,29 +2,108 @@ package user_usecase
 
 import (
 	"context"
+	"fullcycle-auction_go/internal/clock"
+	"fullcycle-auction_go/internal/entity/auction_entity"
 	"fullcycle-auction_go/internal/entity/user_entity"
 	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"math"
+	"time"
 )
 
-func NewUserUseCase(userRepository user_entity.UserRepositoryInterface) UserUseCaseInterface {
-	return &UserUseCase{
-		userRepository,
+type UserUseCaseOption func(userUseCase *UserUseCase)
+
+// WithClock sets the clock suspensions are checked against.
+func WithClock(userClock clock.Clock) UserUseCaseOption {
+	return func(userUseCase *UserUseCase) {
+		userUseCase.Clock = userClock
 	}
 }
 
+func NewUserUseCase(
+	userRepository user_entity.UserRepositoryInterface, opts ...UserUseCaseOption) UserUseCaseInterface {
+	userUseCase := &UserUseCase{
+		UserRepository: userRepository,
+		Clock:          clock.NewRealClock(),
+	}
+	for _, opt := range opts {
+		opt(userUseCase)
+	}
+
+	return userUseCase
+}
+
 type UserUseCase struct {
 	UserRepository user_entity.UserRepositoryInterface
+	Clock          clock.Clock
+
+	banListeners []func(ctx context.Context, userId string)
 }
 
 type UserOutputDTO struct {
-	Id   string `json:"id"`
-	Name string `json:"name"`
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+	Role  string `json:"role,omitempty"`
+
+	Status string `json:"status,omitempty"`
+
+	AverageRating *float64 `json:"average_rating"`
+	RatingCount   int64    `json:"rating_count"`
 }
 
 type UserUseCaseInterface interface {
+	CreateUser(
+		ctx context.Context,
+		userInput UserInputDTO) (*UserOutputDTO, *internal_error.InternalError)
+
 	FindUserById(
 		ctx context.Context,
 		id string) (*UserOutputDTO, *internal_error.InternalError)
+
+	UpdateUser(
+		ctx context.Context,
+		userId string,
+		updateInput UpdateUserInputDTO) (*UserProfileOutputDTO, *internal_error.InternalError)
+
+	CreditWallet(
+		ctx context.Context,
+		userId string,
+		amount money.Amount) (*WalletOutputDTO, *internal_error.InternalError)
+
+	DebitWallet(
+		ctx context.Context,
+		userId string,
+		amount money.Amount) (*WalletOutputDTO, *internal_error.InternalError)
+
+	FindWalletTransactions(
+		ctx context.Context,
+		userId string,
+		findInput FindWalletTransactionsInputDTO) (*WalletTransactionPageOutputDTO, *internal_error.InternalError)
+
+	SetUserRole(
+		ctx context.Context,
+		userId string,
+		role user_entity.Role) (*UserOutputDTO, *internal_error.InternalError)
+
+	SeedAdmin(ctx context.Context, email, password string) *internal_error.InternalError
+
+	Login(
+		ctx context.Context,
+		loginInput LoginInputDTO) (*UserOutputDTO, *internal_error.InternalError)
+
+	RenewLogin(
+		ctx context.Context,
+		userId string) (*UserOutputDTO, *internal_error.InternalError)
+
+	SuspendUser(
+		ctx context.Context,
+		userId string,
+		suspendInput SuspendUserInputDTO) (*UserStatusOutputDTO, *internal_error.InternalError)
+
+	RegisterBanListener(listener func(ctx context.Context, userId string))
+
+	OnAuctionClosed(ctx context.Context, auction auction_entity.Auction)
 }
 
 func (u *UserUseCase) FindUserById(
@@ -37,5 +116,22 @@ func (u *UserUseCase) FindUserById(
 	return &UserOutputDTO{
 		Id:   userEntity.Id,
 		Name: userEntity.Name,
+		Role: string(userEntity.Role),
+
+		Status: string(userEntity.StatusAt(time.Now())),
+
+		AverageRating: averageRating(*userEntity),
+		RatingCount:   userEntity.RatingCount,
 	}, nil
 }
+
+// averageRating is nil until the user is rated, so an unrated seller does not
+// look like one rated zero stars.
+func averageRating(userEntity user_entity.User) *float64 {
+	if userEntity.RatingCount == 0 {
+		return nil
+	}
+
+	average := math.Round(userEntity.AverageRating*100) / 100
+	return &average
+}
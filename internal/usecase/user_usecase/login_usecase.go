@@ -0,0 +1,65 @@
+package user_usecase
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"time"
+)
+
+type LoginInputDTO struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+const invalidCredentialsMessage = "Invalid email or password"
+
+// Login checks the credentials and returns the user they belong to. Unknown
+// emails and wrong passwords get the same error.
+func (u *UserUseCase) Login(
+	ctx context.Context, loginInput LoginInputDTO) (*UserOutputDTO, *internal_error.InternalError) {
+	userEntity, err := u.UserRepository.FindUserByEmail(ctx, user_entity.NormalizeEmail(loginInput.Email))
+	if err != nil {
+		if err.IsNotFound() {
+			return nil, internal_error.NewUnauthorizedError(invalidCredentialsMessage)
+		}
+		return nil, err
+	}
+
+	if !userEntity.PasswordMatches(loginInput.Password) {
+		return nil, internal_error.NewUnauthorizedError(invalidCredentialsMessage)
+	}
+
+	return loginOutput(*userEntity)
+}
+
+// RenewLogin returns the user a refresh token was issued to, unless the user
+// has been deleted or banned since.
+func (u *UserUseCase) RenewLogin(
+	ctx context.Context, userId string) (*UserOutputDTO, *internal_error.InternalError) {
+	userEntity, err := u.UserRepository.FindUserById(ctx, userId)
+	if err != nil {
+		if err.IsNotFound() {
+			return nil, internal_error.NewUnauthorizedError("The account no longer exists")
+		}
+		return nil, err
+	}
+
+	return loginOutput(*userEntity)
+}
+
+func loginOutput(userEntity user_entity.User) (*UserOutputDTO, *internal_error.InternalError) {
+	status := userEntity.StatusAt(time.Now())
+	if status == user_entity.BannedStatus {
+		return nil, internal_error.NewForbiddenError(fmt.Sprintf("Your account is banned: %s", userEntity.StatusReason))
+	}
+
+	return &UserOutputDTO{
+		Id:     userEntity.Id,
+		Name:   userEntity.Name,
+		Email:  userEntity.Email,
+		Role:   string(userEntity.Role),
+		Status: string(status),
+	}, nil
+}
@@ -0,0 +1,35 @@
+package user_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLoginChecksThePassword(t *testing.T) {
+	userEntity, _ := user_entity.CreateUser("Alice", "alice@example.com", "a-strong-password", user_entity.BuyerRole)
+	userUseCase := NewUserUseCase(&fakeSeedUserRepository{users: map[string]*user_entity.User{userEntity.Id: userEntity}})
+	ctx := context.Background()
+
+	userOutput, err := userUseCase.Login(ctx, LoginInputDTO{Email: "Alice@Example.com", Password: "a-strong-password"})
+	assert.Nil(t, err)
+	assert.Equal(t, userEntity.Id, userOutput.Id)
+
+	_, err = userUseCase.Login(ctx, LoginInputDTO{Email: "alice@example.com", Password: "wrong-password"})
+	assert.Equal(t, internal_error.ErrUnauthorized, err.Err)
+
+	_, unknownErr := userUseCase.Login(ctx, LoginInputDTO{Email: "bob@example.com", Password: "a-strong-password"})
+	assert.Equal(t, err, unknownErr)
+}
+
+func TestLoginRefusesBannedUsers(t *testing.T) {
+	userEntity, _ := user_entity.CreateUser("Alice", "alice@example.com", "a-strong-password", user_entity.BuyerRole)
+	userEntity.Status = user_entity.BannedStatus
+	userUseCase := NewUserUseCase(&fakeSeedUserRepository{users: map[string]*user_entity.User{userEntity.Id: userEntity}})
+
+	_, err := userUseCase.Login(context.Background(),
+		LoginInputDTO{Email: "alice@example.com", Password: "a-strong-password"})
+	assert.Equal(t, internal_error.ErrForbidden, err.Err)
+}
@@ -0,0 +1,72 @@
+package user_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"time"
+)
+
+type SuspendUserInputDTO struct {
+	Status    string     `json:"status" binding:"omitempty,oneof=suspended banned"`
+	Reason    string     `json:"reason" binding:"required,min=3,max=200"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type UserStatusOutputDTO struct {
+	UserId         string     `json:"user_id"`
+	Status         string     `json:"status"`
+	Reason         string     `json:"reason"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+}
+
+// RegisterBanListener runs listener every time a user is banned.
+func (u *UserUseCase) RegisterBanListener(listener func(ctx context.Context, userId string)) {
+	u.banListeners = append(u.banListeners, listener)
+}
+
+// SuspendUser suspends the user, until ExpiresAt when given, or bans them for
+// good. Banning also runs the ban listeners before returning.
+func (u *UserUseCase) SuspendUser(
+	ctx context.Context,
+	userId string,
+	suspendInput SuspendUserInputDTO) (*UserStatusOutputDTO, *internal_error.InternalError) {
+	status := user_entity.SuspendedStatus
+	if suspendInput.Status != "" {
+		status = user_entity.UserStatus(suspendInput.Status)
+	}
+
+	var until time.Time
+	if suspendInput.ExpiresAt != nil {
+		if status == user_entity.BannedStatus {
+			return nil, internal_error.NewFieldBadRequestError(
+				"Invalid fields", "expires_at", "bans do not expire, suspend the user instead")
+		}
+		if !suspendInput.ExpiresAt.After(u.Clock.Now()) {
+			return nil, internal_error.NewFieldBadRequestError(
+				"Invalid fields", "expires_at", "expires_at must be in the future")
+		}
+		until = *suspendInput.ExpiresAt
+	}
+
+	if err := u.UserRepository.SetStatus(ctx, userId, status, suspendInput.Reason, until); err != nil {
+		return nil, err
+	}
+
+	if status == user_entity.BannedStatus {
+		for _, listener := range u.banListeners {
+			listener(ctx, userId)
+		}
+	}
+
+	statusOutput := &UserStatusOutputDTO{
+		UserId: userId,
+		Status: string(status),
+		Reason: suspendInput.Reason,
+	}
+	if !until.IsZero() {
+		statusOutput.SuspendedUntil = &until
+	}
+
+	return statusOutput, nil
+}
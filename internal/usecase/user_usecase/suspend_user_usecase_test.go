@@ -0,0 +1,84 @@
+package user_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/testutil/fakeclock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type fakeStatusUserRepository struct {
+	user_entity.UserRepositoryInterface
+	status user_entity.UserStatus
+	reason string
+	until  time.Time
+}
+
+func (f *fakeStatusUserRepository) SetStatus(
+	ctx context.Context,
+	userId string,
+	status user_entity.UserStatus,
+	reason string,
+	until time.Time) *internal_error.InternalError {
+	f.status, f.reason, f.until = status, reason, until
+	return nil
+}
+
+func TestSuspendUserOnlyTellsTheBanListenersAboutBans(t *testing.T) {
+	userRepository := &fakeStatusUserRepository{}
+	userUseCase := NewUserUseCase(userRepository)
+
+	var banned []string
+	userUseCase.RegisterBanListener(func(ctx context.Context, userId string) {
+		banned = append(banned, userId)
+	})
+
+	expiresAt := time.Now().Add(24 * time.Hour)
+	statusOutput, err := userUseCase.SuspendUser(context.Background(), "user", SuspendUserInputDTO{
+		Reason:    "chargeback",
+		ExpiresAt: &expiresAt,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "suspended", statusOutput.Status)
+	assert.Equal(t, expiresAt, userRepository.until)
+	assert.Empty(t, banned)
+
+	_, err = userUseCase.SuspendUser(context.Background(), "user", SuspendUserInputDTO{
+		Status:    "banned",
+		Reason:    "shill bidding",
+		ExpiresAt: &expiresAt,
+	})
+	assert.Equal(t, "expires_at", err.Failures[0].Field)
+
+	statusOutput, err = userUseCase.SuspendUser(context.Background(), "user", SuspendUserInputDTO{
+		Status: "banned",
+		Reason: "shill bidding",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "banned", statusOutput.Status)
+	assert.Equal(t, user_entity.BannedStatus, userRepository.status)
+	assert.True(t, userRepository.until.IsZero())
+	assert.Equal(t, []string{"user"}, banned)
+}
+
+func TestSuspendUserChecksTheExpiryAgainstTheClock(t *testing.T) {
+	fakeClock := fakeclock.New(time.Now())
+	userUseCase := NewUserUseCase(&fakeStatusUserRepository{}, WithClock(fakeClock))
+
+	expiresAt := fakeClock.Now().Add(time.Hour)
+	_, err := userUseCase.SuspendUser(context.Background(), "user", SuspendUserInputDTO{
+		Reason:    "chargeback",
+		ExpiresAt: &expiresAt,
+	})
+	assert.Nil(t, err)
+
+	fakeClock.Advance(2 * time.Hour)
+	_, err = userUseCase.SuspendUser(context.Background(), "user", SuspendUserInputDTO{
+		Reason:    "chargeback",
+		ExpiresAt: &expiresAt,
+	})
+	assert.Equal(t, "expires_at", err.Failures[0].Field)
+}
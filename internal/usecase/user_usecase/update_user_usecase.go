@@ -0,0 +1,116 @@
+package user_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"strings"
+	"time"
+)
+
+type UpdateUserInputDTO struct {
+	Name                    *string                          `json:"name" binding:"omitempty,min=2,max=100"`
+	Email                   *string                          `json:"email" binding:"omitempty,email,max=254"`
+	NotificationPreferences *NotificationPreferencesInputDTO `json:"notification_preferences"`
+}
+
+type NotificationPreferencesInputDTO struct {
+	Outbid               *bool `json:"outbid"`
+	WatchedAuctionEnding *bool `json:"watched_auction_ending"`
+	AuctionWon           *bool `json:"auction_won"`
+}
+
+type NotificationPreferencesOutputDTO struct {
+	Outbid               bool `json:"outbid"`
+	WatchedAuctionEnding bool `json:"watched_auction_ending"`
+	AuctionWon           bool `json:"auction_won"`
+}
+
+type UserProfileOutputDTO struct {
+	UserOutputDTO
+	NotificationPreferences NotificationPreferencesOutputDTO `json:"notification_preferences"`
+	UpdatedAt               *time.Time                       `json:"updated_at,omitempty"`
+}
+
+// UpdateUser changes only the fields present in updateInput.
+func (u *UserUseCase) UpdateUser(
+	ctx context.Context,
+	userId string,
+	updateInput UpdateUserInputDTO) (*UserProfileOutputDTO, *internal_error.InternalError) {
+	fields := map[string]interface{}{}
+	if updateInput.Name != nil {
+		fields[user_entity.NameField] = strings.TrimSpace(*updateInput.Name)
+	}
+	if updateInput.Email != nil {
+		email := user_entity.NormalizeEmail(*updateInput.Email)
+		if err := u.ensureEmailIsFree(ctx, userId, email); err != nil {
+			return nil, err
+		}
+		fields[user_entity.EmailField] = email
+	}
+	if preferences := updateInput.NotificationPreferences; preferences != nil {
+		if preferences.Outbid != nil {
+			fields[user_entity.OutbidNotificationsField] = *preferences.Outbid
+		}
+		if preferences.WatchedAuctionEnding != nil {
+			fields[user_entity.WatchedAuctionEndingNotificationsField] = *preferences.WatchedAuctionEnding
+		}
+		if preferences.AuctionWon != nil {
+			fields[user_entity.AuctionWonNotificationsField] = *preferences.AuctionWon
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, internal_error.NewBadRequestError("At least one field must be provided")
+	}
+
+	userEntity, err := u.UserRepository.UpdateUser(ctx, userId, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserProfileOutputDTO(*userEntity), nil
+}
+
+// ensureEmailIsFree answers a taken email with the same conflict the unique
+// index reports, before the index has to.
+func (u *UserUseCase) ensureEmailIsFree(ctx context.Context, userId, email string) *internal_error.InternalError {
+	userEntity, err := u.UserRepository.FindUserByEmail(ctx, email)
+	if err != nil {
+		if err.IsNotFound() {
+			return nil
+		}
+
+		return err
+	}
+	if userEntity.Id != userId {
+		return internal_error.NewConflictError("A user with this email already exists")
+	}
+
+	return nil
+}
+
+func toUserProfileOutputDTO(userEntity user_entity.User) *UserProfileOutputDTO {
+	profileOutput := &UserProfileOutputDTO{
+		UserOutputDTO: UserOutputDTO{
+			Id:     userEntity.Id,
+			Name:   userEntity.Name,
+			Email:  userEntity.Email,
+			Role:   string(userEntity.Role),
+			Status: string(userEntity.StatusAt(time.Now())),
+
+			AverageRating: averageRating(userEntity),
+			RatingCount:   userEntity.RatingCount,
+		},
+		NotificationPreferences: NotificationPreferencesOutputDTO{
+			Outbid:               userEntity.NotificationPreferences.Outbid,
+			WatchedAuctionEnding: userEntity.NotificationPreferences.WatchedAuctionEnding,
+			AuctionWon:           userEntity.NotificationPreferences.AuctionWon,
+		},
+	}
+	if !userEntity.UpdatedAt.IsZero() {
+		profileOutput.UpdatedAt = &userEntity.UpdatedAt
+	}
+
+	return profileOutput
+}
@@ -0,0 +1,91 @@
+package user_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type fakeUpdateUserRepository struct {
+	user_entity.UserRepositoryInterface
+	users  map[string]*user_entity.User
+	fields map[string]interface{}
+}
+
+func (f *fakeUpdateUserRepository) FindUserByEmail(
+	ctx context.Context, email string) (*user_entity.User, *internal_error.InternalError) {
+	for _, userEntity := range f.users {
+		if userEntity.Email == email {
+			return userEntity, nil
+		}
+	}
+
+	return nil, internal_error.NewNotFoundError("User not found")
+}
+
+func (f *fakeUpdateUserRepository) UpdateUser(
+	ctx context.Context,
+	userId string,
+	fields map[string]interface{}) (*user_entity.User, *internal_error.InternalError) {
+	f.fields = fields
+
+	userEntity := *f.users[userId]
+	if name, ok := fields[user_entity.NameField]; ok {
+		userEntity.Name = name.(string)
+	}
+	if outbid, ok := fields[user_entity.OutbidNotificationsField]; ok {
+		userEntity.NotificationPreferences.Outbid = outbid.(bool)
+	}
+	return &userEntity, nil
+}
+
+func TestUpdateUserOnlySendsTheGivenFields(t *testing.T) {
+	userRepository := &fakeUpdateUserRepository{users: map[string]*user_entity.User{
+		"ana": {
+			Id: "ana", Name: "Ana", Email: "ana@example.com",
+			NotificationPreferences: user_entity.DefaultNotificationPreferences(),
+		},
+	}}
+	userUseCase := NewUserUseCase(userRepository)
+
+	outbid := false
+	profile, err := userUseCase.UpdateUser(context.Background(), "ana", UpdateUserInputDTO{
+		NotificationPreferences: &NotificationPreferencesInputDTO{Outbid: &outbid},
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, map[string]interface{}{user_entity.OutbidNotificationsField: false}, userRepository.fields)
+	assert.Equal(t, "Ana", profile.Name)
+	assert.Equal(t, "ana@example.com", profile.Email)
+	assert.False(t, profile.NotificationPreferences.Outbid)
+	assert.True(t, profile.NotificationPreferences.WatchedAuctionEnding)
+	assert.True(t, profile.NotificationPreferences.AuctionWon)
+
+	name := " Ana Maria "
+	_, err = userUseCase.UpdateUser(context.Background(), "ana", UpdateUserInputDTO{Name: &name})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{user_entity.NameField: "Ana Maria"}, userRepository.fields)
+
+	_, err = userUseCase.UpdateUser(context.Background(), "ana", UpdateUserInputDTO{})
+	assert.Equal(t, internal_error.ErrBadRequest, err.Err)
+}
+
+func TestUpdateUserRejectsAnEmailTakenByAnotherUser(t *testing.T) {
+	userRepository := &fakeUpdateUserRepository{users: map[string]*user_entity.User{
+		"ana": {Id: "ana", Email: "ana@example.com"},
+		"bia": {Id: "bia", Email: "bia@example.com"},
+	}}
+	userUseCase := NewUserUseCase(userRepository)
+
+	taken := "BIA@example.com"
+	_, err := userUseCase.UpdateUser(context.Background(), "ana", UpdateUserInputDTO{Email: &taken})
+	assert.Equal(t, internal_error.ErrConflict, err.Err)
+	assert.Nil(t, userRepository.fields)
+
+	own := "ana@example.com"
+	_, err = userUseCase.UpdateUser(context.Background(), "ana", UpdateUserInputDTO{Email: &own})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{user_entity.EmailField: "ana@example.com"}, userRepository.fields)
+}
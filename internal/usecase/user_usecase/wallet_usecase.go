@@ -0,0 +1,145 @@
+package user_usecase
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"time"
+)
+
+const (
+	defaultWalletTransactionPageSize = 20
+	maxWalletTransactionPageSize     = 100
+)
+
+type WalletAdjustmentInputDTO struct {
+	Amount money.Amount `json:"amount" binding:"required,gt=0"`
+}
+
+type WalletOutputDTO struct {
+	UserId     string        `json:"user_id"`
+	Balance    *money.Amount `json:"balance,omitempty"`
+	HeldAmount money.Amount  `json:"held_amount"`
+	Available  *money.Amount `json:"available,omitempty"`
+}
+
+type FindWalletTransactionsInputDTO struct {
+	Limit  int
+	Offset int
+}
+
+type WalletTransactionOutputDTO struct {
+	Id        string       `json:"id"`
+	Type      string       `json:"type"`
+	Amount    money.Amount `json:"amount"`
+	AuctionId string       `json:"auction_id,omitempty"`
+	Timestamp time.Time    `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+}
+
+type WalletTransactionPageOutputDTO struct {
+	Items  []WalletTransactionOutputDTO `json:"items"`
+	Total  int64                        `json:"total"`
+	Limit  int                          `json:"limit"`
+	Offset int                          `json:"offset"`
+}
+
+func (u *UserUseCase) CreditWallet(
+	ctx context.Context, userId string, amount money.Amount) (*WalletOutputDTO, *internal_error.InternalError) {
+	if err := u.UserRepository.Credit(ctx, userId, amount); err != nil {
+		return nil, err
+	}
+
+	return u.findWallet(ctx, userId)
+}
+
+func (u *UserUseCase) DebitWallet(
+	ctx context.Context, userId string, amount money.Amount) (*WalletOutputDTO, *internal_error.InternalError) {
+	if err := u.UserRepository.Debit(ctx, userId, amount); err != nil {
+		return nil, err
+	}
+
+	return u.findWallet(ctx, userId)
+}
+
+func (u *UserUseCase) FindWalletTransactions(
+	ctx context.Context,
+	userId string,
+	findInput FindWalletTransactionsInputDTO) (*WalletTransactionPageOutputDTO, *internal_error.InternalError) {
+	if findInput.Limit == 0 {
+		findInput.Limit = defaultWalletTransactionPageSize
+	}
+
+	if findInput.Limit < 1 || findInput.Limit > maxWalletTransactionPageSize {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("limit must be between 1 and %d", maxWalletTransactionPageSize))
+	}
+	if findInput.Offset < 0 {
+		return nil, internal_error.NewBadRequestError("offset must not be negative")
+	}
+
+	transactionPage, err := u.UserRepository.FindWalletTransactions(
+		ctx, userId, int64(findInput.Limit), int64(findInput.Offset))
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]WalletTransactionOutputDTO, 0, len(transactionPage.Transactions))
+	for _, transaction := range transactionPage.Transactions {
+		items = append(items, WalletTransactionOutputDTO{
+			Id:        transaction.Id,
+			Type:      string(transaction.Type),
+			Amount:    transaction.Amount,
+			AuctionId: transaction.AuctionId,
+			Timestamp: transaction.Timestamp,
+		})
+	}
+
+	return &WalletTransactionPageOutputDTO{
+		Items:  items,
+		Total:  transactionPage.Total,
+		Limit:  findInput.Limit,
+		Offset: findInput.Offset,
+	}, nil
+}
+
+// OnAuctionClosed settles the hold of the auction's last leader: the winner
+// pays from it, and a leader who missed the reserve gets it back.
+func (u *UserUseCase) OnAuctionClosed(ctx context.Context, auction auction_entity.Auction) {
+	if auction.WinnerUserId != "" {
+		if err := u.UserRepository.CaptureFunds(
+			ctx, auction.WinnerUserId, auction.Id, auction.WinningAmount); err != nil {
+			logger.ErrorContext(ctx, fmt.Sprintf(
+				"Error trying to charge user %s for winning auction %s", auction.WinnerUserId, auction.Id), err)
+		}
+		return
+	}
+
+	if auction.CurrentHighestUserId == "" {
+		return
+	}
+
+	if err := u.UserRepository.ReleaseFunds(
+		ctx, auction.CurrentHighestUserId, auction.Id, auction.CurrentHighestAmount); err != nil {
+		logger.ErrorContext(ctx, fmt.Sprintf(
+			"Error trying to release the hold of user %s on auction %s", auction.CurrentHighestUserId, auction.Id),
+			err)
+	}
+}
+
+func (u *UserUseCase) findWallet(
+	ctx context.Context, userId string) (*WalletOutputDTO, *internal_error.InternalError) {
+	userEntity, err := u.UserRepository.FindUserById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WalletOutputDTO{
+		UserId:     userEntity.Id,
+		Balance:    userEntity.Balance,
+		HeldAmount: userEntity.HeldAmount,
+		Available:  userEntity.Available(),
+	}, nil
+}
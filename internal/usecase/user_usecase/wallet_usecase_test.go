@@ -0,0 +1,75 @@
+package user_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/money"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type walletCall struct {
+	userId    string
+	auctionId string
+	amount    money.Amount
+}
+
+type fakeWalletUserRepository struct {
+	user_entity.UserRepositoryInterface
+	captured []walletCall
+	released []walletCall
+}
+
+func (f *fakeWalletUserRepository) CaptureFunds(
+	ctx context.Context, userId, auctionId string, amount money.Amount) *internal_error.InternalError {
+	f.captured = append(f.captured, walletCall{userId, auctionId, amount})
+	return nil
+}
+
+func (f *fakeWalletUserRepository) ReleaseFunds(
+	ctx context.Context, userId, auctionId string, amount money.Amount) *internal_error.InternalError {
+	f.released = append(f.released, walletCall{userId, auctionId, amount})
+	return nil
+}
+
+func TestOnAuctionClosedSettlesTheLeaderHold(t *testing.T) {
+	tests := []struct {
+		name         string
+		auction      auction_entity.Auction
+		wantCaptured []walletCall
+		wantReleased []walletCall
+	}{
+		{
+			name: "winner pays from the hold",
+			auction: auction_entity.Auction{
+				Id: "auction", CurrentHighestUserId: "winner", CurrentHighestAmount: 5000,
+				WinnerUserId: "winner", WinningAmount: 5000,
+			},
+			wantCaptured: []walletCall{{"winner", "auction", 5000}},
+		},
+		{
+			name: "reserve not met gives the hold back",
+			auction: auction_entity.Auction{
+				Id: "auction", CurrentHighestUserId: "leader", CurrentHighestAmount: 3000,
+			},
+			wantReleased: []walletCall{{"leader", "auction", 3000}},
+		},
+		{
+			name:    "no bids",
+			auction: auction_entity.Auction{Id: "auction"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepository := &fakeWalletUserRepository{}
+
+			NewUserUseCase(userRepository).OnAuctionClosed(context.Background(), tt.auction)
+
+			assert.Equal(t, tt.wantCaptured, userRepository.captured)
+			assert.Equal(t, tt.wantReleased, userRepository.released)
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package auction_v1 holds the messages and service stubs generated from
+// auction.proto.
+package auction_v1
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative auction.proto